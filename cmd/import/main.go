@@ -0,0 +1,145 @@
+// Command import uploads a CSV or JSON lines file of products and auctions
+// to the server and follows the import until it finishes.
+//
+//	go run ./cmd/import -token <JWT> -file lots.csv [-dry-run]
+//
+// The format is taken from the file extension unless -format is set. Row
+// errors are printed by line; the command exits with status 1 if the import
+// fails.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/handler"
+)
+
+func main() {
+	server := flag.String("server", "http://localhost:8080", "server base URL")
+	token := flag.String("token", "", "JWT access token")
+	file := flag.String("file", "", "CSV or JSON lines file to import")
+	format := flag.String("format", "", "csv or jsonl (default: from the file extension)")
+	dryRun := flag.Bool("dry-run", false, "only validate the rows")
+	flag.Parse()
+
+	if *token == "" || *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	client := &importClient{server: *server, token: *token, http: &http.Client{Timeout: time.Minute}}
+	job, err := client.upload(*file, *format, *dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("import %s: %d rows\n", job.ID, job.TotalRows)
+
+	for !job.Status.Finished() {
+		time.Sleep(time.Second)
+		if job, err = client.get(job.ID); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s: %d/%d rows validated\n", job.Status, job.ProcessedRows, job.TotalRows)
+	}
+
+	for _, e := range job.Errors {
+		if e.Field != "" {
+			fmt.Printf("line %d: %s: %s\n", e.Line, e.Field, e.Message)
+		} else {
+			fmt.Printf("line %d: %s\n", e.Line, e.Message)
+		}
+	}
+	if more := job.ErrorCount - len(job.Errors); more > 0 {
+		fmt.Printf("... and %d more errors\n", more)
+	}
+	if job.Error != "" {
+		fmt.Println(job.Error)
+	}
+	if job.Status == domain.ImportJobFailed {
+		fmt.Println("import failed; nothing was imported")
+		os.Exit(1)
+	}
+	if job.DryRun {
+		fmt.Printf("all rows are valid: %d products and %d auctions would be created\n", job.ProductCount, job.AuctionCount)
+		return
+	}
+	fmt.Printf("created %d products and %d auctions\n", job.ProductCount, job.AuctionCount)
+}
+
+type importClient struct {
+	server string
+	token  string
+	http   *http.Client
+}
+
+// upload starts an import of the file
+func (c *importClient) upload(path, format string, dryRun bool) (*handler.ImportJobResponse, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return nil, err
+	}
+	part.Write(data)
+	if format != "" {
+		form.WriteField("format", format)
+	}
+	form.WriteField("dry_run", strconv.FormatBool(dryRun))
+	form.Close()
+
+	req, err := http.NewRequest(http.MethodPost, c.server+"/imports", &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return c.do(req)
+}
+
+// get fetches the progress of an import
+func (c *importClient) get(id string) (*handler.ImportJobResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, c.server+"/imports/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req)
+}
+
+func (c *importClient) do(req *http.Request) (*handler.ImportJobResponse, error) {
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	var job handler.ImportJobResponse
+	if err := json.Unmarshal(body, &job); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &job, nil
+}
@@ -7,7 +7,6 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/saigenix/bidding-system/pkg/web"
 	"github.com/saigenix/bidding-system/sdk"
@@ -52,9 +51,27 @@ func main() {
 	// Setup router with all handlers
 	router := web.SetupRouter(
 		engine.AuthService,
+		engine.AccountService,
+		engine.TicketService,
+		engine.TwoFactorService,
+		engine.UserService,
+		engine.CategoryService,
 		engine.ProductService,
+		engine.AttachmentService,
 		engine.AuctionService,
+		engine.SearchService,
+		engine.ImportService,
+		engine.CatalogService,
 		engine.BidService,
+		engine.LiveService,
+		engine.RetractionService,
+		engine.FeeService,
+		engine.OrderService,
+		engine.EventService,
+		engine.Hub,
+		engine.Blobs,
+		engine.AllowedOrigins(),
+		engine.TrustedProxies(),
 	)
 
 	// Create HTTP server
@@ -88,9 +105,14 @@ func main() {
 	engine.GetLogger().Info().Msg("Shutting down server...")
 
 	// Graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), engine.ShutdownTimeout())
 	defer cancel()
 
+	// Close WebSocket connections first; srv.Shutdown does not wait for them
+	if err := engine.Drain(ctx); err != nil {
+		engine.GetLogger().Error().Err(err).Msg("Real-time connections did not drain in time")
+	}
+
 	if err := srv.Shutdown(ctx); err != nil {
 		engine.GetLogger().Fatal().Err(err).Msg("Server forced to shutdown")
 	}
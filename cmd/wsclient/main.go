@@ -0,0 +1,147 @@
+// Command wsclient is an interactive test client for the bidding WebSocket.
+//
+//	go run ./cmd/wsclient -token <JWT> [-auction <auction ID>]
+//	go run ./cmd/wsclient -ticket <stream ticket> [-auction <auction ID>]
+//
+// With -auction the client connects to that auction's socket; without it, to
+// the multiplexed /ws socket with no subscriptions. Commands read from stdin:
+// bid <amount> [auction ID], proxy <max> [auction ID], sub <auction ID>,
+// unsub <auction ID>, ping, quit. Server events are printed as they arrive.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/saigenix/bidding-system/pkg/wsclient"
+)
+
+func main() {
+	server := flag.String("server", "ws://localhost:8080", "server base URL")
+	token := flag.String("token", "", "JWT access token")
+	ticket := flag.String("ticket", "", "single-use stream ticket, instead of -token")
+	auctionID := flag.String("auction", "", "auction to connect to (default: multiplexed /ws)")
+	flag.Parse()
+
+	if (*token == "") == (*ticket == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	url := *server + "/ws"
+	if *auctionID != "" {
+		url = *server + "/auctions/" + *auctionID + "/bids/ws"
+	}
+	header := http.Header{}
+	if *token != "" {
+		header.Set("Authorization", "Bearer "+*token)
+	} else {
+		url += "?ticket=" + *ticket
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	client, err := wsclient.Dial(ctx, url, header)
+	cancel()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer client.Close()
+
+	go func() {
+		for msg := range client.Events() {
+			fmt.Printf("< %s %s %s\n", msg.Type, msg.AuctionID, msg.Payload)
+		}
+		fmt.Println("connection closed:", client.Err())
+		os.Exit(0)
+	}()
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "quit" {
+			return
+		}
+		if err := run(client, *auctionID, fields); err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+}
+
+func run(client *wsclient.Client, auctionID string, fields []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	arg := func() (string, error) {
+		if len(fields) < 2 {
+			return "", fmt.Errorf("usage: %s <value>", fields[0])
+		}
+		return fields[1], nil
+	}
+	amount := func() (float64, error) {
+		value, err := arg()
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseFloat(value, 64)
+	}
+	// bid and proxy take an optional auction ID after the amount
+	if len(fields) > 2 {
+		auctionID = fields[2]
+	}
+
+	switch fields[0] {
+	case "bid":
+		value, err := amount()
+		if err != nil {
+			return err
+		}
+		bid, err := client.PlaceBid(ctx, auctionID, value)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("ok: bid %s placed at %.2f as %s\n", bid.ID, bid.Amount, bid.Bidder)
+	case "proxy":
+		value, err := amount()
+		if err != nil {
+			return err
+		}
+		if err := client.SetProxy(ctx, auctionID, value); err != nil {
+			return err
+		}
+		fmt.Printf("ok: bidding automatically up to %.2f\n", value)
+	case "sub", "unsub":
+		id, err := arg()
+		if err != nil {
+			return err
+		}
+		if fields[0] == "sub" {
+			err = client.Subscribe(ctx, id)
+		} else {
+			err = client.Unsubscribe(ctx, id)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println("ok")
+	case "ping":
+		start := time.Now()
+		if err := client.Ping(ctx); err != nil {
+			return err
+		}
+		fmt.Printf("pong in %s\n", time.Since(start))
+	default:
+		return fmt.Errorf("unknown command %q", fields[0])
+	}
+	return nil
+}
@@ -1,21 +1,37 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"net"
+	"strings"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	Logger   LoggerConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	JWT        JWTConfig
+	Auth       AuthConfig
+	Mail       MailConfig
+	Blob       BlobConfig
+	Realtime   RealtimeConfig
+	Settlement SettlementConfig
+	Logger     LoggerConfig
 }
 
 type ServerConfig struct {
-	Port string
+	Port      string
+	PublicURL string
+	// AllowedOrigins lists the browser origins allowed to open WebSockets in
+	// addition to the server's own; "*" allows any origin
+	AllowedOrigins []string
+	// TrustedProxies lists the IPs and CIDRs of the reverse proxies whose
+	// X-Forwarded-For header gives the client IP; by default none are
+	// trusted and the connection's address is used
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -34,6 +50,82 @@ type JWTConfig struct {
 	ExpirationHour int
 }
 
+type AuthConfig struct {
+	VerificationTTLHour    int
+	PasswordResetTTLMinute int
+	TOTPIssuer             string
+
+	// Login brute-force protection
+	LockoutAccountThreshold int
+	LockoutIPThreshold      int
+	LockoutDurationMinute   int
+	LockoutWindowMinute     int
+	LoginDelayBaseMS        int
+	LoginDelayMaxMS         int
+
+	StreamTicketTTLSecond int
+}
+
+type MailConfig struct {
+	Driver       string // smtp, file or log
+	From         string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUser     string
+	SMTPPassword string
+	FileDir      string
+}
+
+type BlobConfig struct {
+	Driver string // local or s3
+	// URLTTLSecond is how long signed blob URLs in responses stay valid
+	URLTTLSecond int
+
+	// Local driver: files below LocalDir, served by the server itself under
+	// URLs signed with SigningSecret
+	LocalDir      string
+	SigningSecret string
+
+	// S3 driver, also for S3-compatible stores such as MinIO
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PathStyle bool
+}
+
+type RealtimeConfig struct {
+	SubscriberBuffer      int // events queued per connection before it is dropped as too slow
+	ShutdownTimeoutSecond int
+	TickIntervalSecond    int // how often countdown ticks are sent and expired auctions closed
+}
+
+type SettlementConfig struct {
+	PaymentDueDay int     // how long winners have to pay their orders
+	TaxPercent    float64 // charged on the hammer price and buyer's premium
+
+	// Default fee schedules, which categories may override; nil charges nothing
+	BuyerPremium     *FeeScheduleConfig
+	SellerCommission *FeeScheduleConfig
+}
+
+// FeeScheduleConfig is a fee schedule given as JSON, such as
+// {"type":"percentage","amount":15,"minimum":5} or
+// {"type":"tiered","tiers":[{"from":0,"percent":25},{"from":1000,"percent":20}]}
+type FeeScheduleConfig struct {
+	Type    string          `json:"type"` // flat, percentage or tiered
+	Amount  float64         `json:"amount"`
+	Tiers   []FeeTierConfig `json:"tiers"`
+	Minimum float64         `json:"minimum"`
+	Maximum float64         `json:"maximum"`
+}
+
+type FeeTierConfig struct {
+	From    float64 `json:"from"`
+	Percent float64 `json:"percent"`
+}
+
 type LoggerConfig struct {
 	Level string
 }
@@ -44,6 +136,7 @@ func Load() (*Config, error) {
 
 	// Set defaults
 	viper.SetDefault("SERVER_PORT", "8080")
+	viper.SetDefault("SERVER_PUBLIC_URL", "http://localhost:8080")
 	viper.SetDefault("DB_HOST", "localhost")
 	viper.SetDefault("DB_PORT", "5432")
 	viper.SetDefault("DB_USER", "postgres")
@@ -54,11 +147,40 @@ func Load() (*Config, error) {
 	viper.SetDefault("DB_MIN_CONNS", 5)
 	viper.SetDefault("JWT_SECRET", "your-secret-key-change-in-production")
 	viper.SetDefault("JWT_EXPIRATION_HOUR", 24)
+	viper.SetDefault("AUTH_VERIFICATION_TTL_HOUR", 48)
+	viper.SetDefault("AUTH_PASSWORD_RESET_TTL_MINUTE", 30)
+	viper.SetDefault("AUTH_TOTP_ISSUER", "Bidding System")
+	viper.SetDefault("AUTH_LOCKOUT_ACCOUNT_THRESHOLD", 5)
+	viper.SetDefault("AUTH_LOCKOUT_IP_THRESHOLD", 20)
+	viper.SetDefault("AUTH_LOCKOUT_DURATION_MINUTE", 15)
+	viper.SetDefault("AUTH_LOCKOUT_WINDOW_MINUTE", 15)
+	viper.SetDefault("AUTH_LOGIN_DELAY_BASE_MS", 500)
+	viper.SetDefault("AUTH_LOGIN_DELAY_MAX_MS", 30000)
+	viper.SetDefault("AUTH_STREAM_TICKET_TTL_SECOND", 30)
+	viper.SetDefault("MAIL_DRIVER", "log")
+	viper.SetDefault("MAIL_FROM", "no-reply@bidding.local")
+	viper.SetDefault("MAIL_SMTP_HOST", "localhost")
+	viper.SetDefault("MAIL_SMTP_PORT", "587")
+	viper.SetDefault("MAIL_FILE_DIR", "./tmp/mail")
+	viper.SetDefault("BLOB_DRIVER", "local")
+	viper.SetDefault("BLOB_URL_TTL_SECOND", 900)
+	viper.SetDefault("BLOB_LOCAL_DIR", "./tmp/blobs")
+	viper.SetDefault("BLOB_SIGNING_SECRET", "your-blob-secret-change-in-production")
+	viper.SetDefault("BLOB_S3_ENDPOINT", "https://s3.us-east-1.amazonaws.com")
+	viper.SetDefault("BLOB_S3_REGION", "us-east-1")
+	viper.SetDefault("REALTIME_SUBSCRIBER_BUFFER", 64)
+	viper.SetDefault("REALTIME_SHUTDOWN_TIMEOUT_SECOND", 10)
+	viper.SetDefault("REALTIME_TICK_INTERVAL_SECOND", 1)
+	viper.SetDefault("SETTLEMENT_PAYMENT_DUE_DAY", 7)
+	viper.SetDefault("SETTLEMENT_TAX_PERCENT", 0)
 	viper.SetDefault("LOG_LEVEL", "info")
 
 	cfg := &Config{
 		Server: ServerConfig{
-			Port: viper.GetString("SERVER_PORT"),
+			Port:           viper.GetString("SERVER_PORT"),
+			PublicURL:      viper.GetString("SERVER_PUBLIC_URL"),
+			AllowedOrigins: splitList(viper.GetString("SERVER_ALLOWED_ORIGINS")),
+			TrustedProxies: splitList(viper.GetString("SERVER_TRUSTED_PROXIES")),
 		},
 		Database: DatabaseConfig{
 			Host:     viper.GetString("DB_HOST"),
@@ -74,11 +196,71 @@ func Load() (*Config, error) {
 			Secret:         viper.GetString("JWT_SECRET"),
 			ExpirationHour: viper.GetInt("JWT_EXPIRATION_HOUR"),
 		},
+		Auth: AuthConfig{
+			VerificationTTLHour:    viper.GetInt("AUTH_VERIFICATION_TTL_HOUR"),
+			PasswordResetTTLMinute: viper.GetInt("AUTH_PASSWORD_RESET_TTL_MINUTE"),
+			TOTPIssuer:             viper.GetString("AUTH_TOTP_ISSUER"),
+
+			LockoutAccountThreshold: viper.GetInt("AUTH_LOCKOUT_ACCOUNT_THRESHOLD"),
+			LockoutIPThreshold:      viper.GetInt("AUTH_LOCKOUT_IP_THRESHOLD"),
+			LockoutDurationMinute:   viper.GetInt("AUTH_LOCKOUT_DURATION_MINUTE"),
+			LockoutWindowMinute:     viper.GetInt("AUTH_LOCKOUT_WINDOW_MINUTE"),
+			LoginDelayBaseMS:        viper.GetInt("AUTH_LOGIN_DELAY_BASE_MS"),
+			LoginDelayMaxMS:         viper.GetInt("AUTH_LOGIN_DELAY_MAX_MS"),
+
+			StreamTicketTTLSecond: viper.GetInt("AUTH_STREAM_TICKET_TTL_SECOND"),
+		},
+		Mail: MailConfig{
+			Driver:       viper.GetString("MAIL_DRIVER"),
+			From:         viper.GetString("MAIL_FROM"),
+			SMTPHost:     viper.GetString("MAIL_SMTP_HOST"),
+			SMTPPort:     viper.GetString("MAIL_SMTP_PORT"),
+			SMTPUser:     viper.GetString("MAIL_SMTP_USER"),
+			SMTPPassword: viper.GetString("MAIL_SMTP_PASSWORD"),
+			FileDir:      viper.GetString("MAIL_FILE_DIR"),
+		},
+		Blob: BlobConfig{
+			Driver:        viper.GetString("BLOB_DRIVER"),
+			URLTTLSecond:  viper.GetInt("BLOB_URL_TTL_SECOND"),
+			LocalDir:      viper.GetString("BLOB_LOCAL_DIR"),
+			SigningSecret: viper.GetString("BLOB_SIGNING_SECRET"),
+			S3Endpoint:    viper.GetString("BLOB_S3_ENDPOINT"),
+			S3Region:      viper.GetString("BLOB_S3_REGION"),
+			S3Bucket:      viper.GetString("BLOB_S3_BUCKET"),
+			S3AccessKey:   viper.GetString("BLOB_S3_ACCESS_KEY"),
+			S3SecretKey:   viper.GetString("BLOB_S3_SECRET_KEY"),
+			S3PathStyle:   viper.GetBool("BLOB_S3_PATH_STYLE"),
+		},
+		Realtime: RealtimeConfig{
+			SubscriberBuffer:      viper.GetInt("REALTIME_SUBSCRIBER_BUFFER"),
+			ShutdownTimeoutSecond: viper.GetInt("REALTIME_SHUTDOWN_TIMEOUT_SECOND"),
+			TickIntervalSecond:    viper.GetInt("REALTIME_TICK_INTERVAL_SECOND"),
+		},
+		Settlement: SettlementConfig{
+			PaymentDueDay: viper.GetInt("SETTLEMENT_PAYMENT_DUE_DAY"),
+			TaxPercent:    viper.GetFloat64("SETTLEMENT_TAX_PERCENT"),
+		},
 		Logger: LoggerConfig{
 			Level: viper.GetString("LOG_LEVEL"),
 		},
 	}
 
+	for _, proxy := range cfg.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return nil, fmt.Errorf("invalid SERVER_TRUSTED_PROXIES: %q is not an IP or CIDR", proxy)
+			}
+		}
+	}
+
+	var err error
+	if cfg.Settlement.BuyerPremium, err = parseFeeSchedule("SETTLEMENT_BUYER_PREMIUM"); err != nil {
+		return nil, err
+	}
+	if cfg.Settlement.SellerCommission, err = parseFeeSchedule("SETTLEMENT_SELLER_COMMISSION"); err != nil {
+		return nil, err
+	}
+
 	log.Printf("Configuration loaded successfully")
 	return cfg, nil
 }
@@ -89,4 +271,29 @@ func (c *DatabaseConfig) GetDSN() string {
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode,
 	)
-}
\ No newline at end of file
+}
+
+// parseFeeSchedule parses the JSON fee schedule of an environment variable,
+// returning nil if it is unset
+func parseFeeSchedule(key string) (*FeeScheduleConfig, error) {
+	value := strings.TrimSpace(viper.GetString(key))
+	if value == "" {
+		return nil, nil
+	}
+	var schedule FeeScheduleConfig
+	if err := json.Unmarshal([]byte(value), &schedule); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return &schedule, nil
+}
+
+// splitList parses a comma-separated environment value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,11 +1,14 @@
 package auth
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
 	"github.com/saigenix/bidding-system/internal/service"
+	"github.com/saigenix/bidding-system/pkg/wsproto"
 )
 
 // JWTMiddleware validates JWT tokens and sets user ID in context
@@ -14,31 +17,103 @@ func JWTMiddleware(authService *service.AuthService) gin.HandlerFunc {
 		// Get token from Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
-			c.Abort()
+			unauthorized(c, "authorization header required")
 			return
 		}
 
-		// Extract token (format: "Bearer <token>")
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
-			c.Abort()
+		authenticateBearer(c, authService, authHeader)
+	}
+}
+
+// StreamMiddleware authenticates SSE and WebSocket connections. Besides the
+// Authorization header accepted by JWTMiddleware, it accepts a stream ticket
+// in the "ticket" query parameter or as a "ticket.<value>" entry of the
+// Sec-WebSocket-Protocol header, since browsers cannot set headers on those
+// connections.
+func StreamMiddleware(authService *service.AuthService, ticketService *service.StreamTicketService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+			authenticateBearer(c, authService, authHeader)
 			return
 		}
 
-		token := parts[1]
+		ticket := c.Query("ticket")
+		if ticket == "" {
+			ticket = subprotocolTicket(c.Request)
+		}
+		if ticket == "" {
+			unauthorized(c, "authorization header or stream ticket required")
+			return
+		}
 
-		// Validate token
-		userID, err := authService.ValidateToken(token)
+		userID, err := ticketService.Redeem(c.Request.Context(), ticket)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			c.Abort()
+			unauthorized(c, "invalid stream ticket")
 			return
 		}
 
-		// Set user ID in context
 		c.Set("userID", userID)
 		c.Next()
 	}
 }
+
+// RequireRole only lets through users that have been granted role. It must
+// follow JWTMiddleware, which sets the user ID.
+func RequireRole(userService *service.UserService, role domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := userService.GetUser(c.Request.Context(), c.GetString("userID"))
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		if !user.HasRole(role) {
+			_ = c.Error(fmt.Errorf("%w: requires the %s role", domain.ErrForbidden, role))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// authenticateBearer validates a "Bearer <token>" header value and sets the user ID in context
+func authenticateBearer(c *gin.Context, authService *service.AuthService, authHeader string) {
+	// Extract token (format: "Bearer <token>")
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		unauthorized(c, "invalid authorization header format")
+		return
+	}
+
+	token := parts[1]
+
+	// Validate token
+	userID, err := authService.ValidateToken(token)
+	if err != nil {
+		unauthorized(c, "invalid token")
+		return
+	}
+
+	// Set user ID in context
+	c.Set("userID", userID)
+	c.Next()
+}
+
+// unauthorized stops the request with a 401, written as a problem response
+// by the handler package's ProblemMiddleware
+func unauthorized(c *gin.Context, detail string) {
+	_ = c.Error(fmt.Errorf("%w: %s", domain.ErrUnauthorized, detail))
+	c.Abort()
+}
+
+// subprotocolTicket returns the ticket offered as a WebSocket subprotocol, if any
+func subprotocolTicket(r *http.Request) string {
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			if ticket, ok := strings.CutPrefix(strings.TrimSpace(protocol), wsproto.TicketSubprotocolPrefix); ok {
+				return ticket
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrUnsupportedMediaType is returned when an uploaded file is not of a
+	// type accepted for its kind of attachment
+	ErrUnsupportedMediaType = errors.New("unsupported file type")
+	// ErrFileTooLarge is returned when an uploaded file exceeds the size limit
+	// of its kind of attachment
+	ErrFileTooLarge = errors.New("file is too large")
+)
+
+// AttachmentKind tells product images from documents
+type AttachmentKind string
+
+const (
+	// AttachmentImage is a photo of the product, shown with a thumbnail
+	AttachmentImage AttachmentKind = "image"
+	// AttachmentDocument is a file such as a manual or certificate
+	AttachmentDocument AttachmentKind = "document"
+)
+
+// Attachment is a file uploaded for a product. The file itself is kept in a
+// blob store under BlobKey.
+type Attachment struct {
+	ID        string
+	ProductID string
+	Kind      AttachmentKind
+	// Filename is the name the file was uploaded with, for display
+	Filename string
+	// ContentType is sniffed from the file's contents, not taken from the
+	// upload
+	ContentType string
+	Size        int64
+	BlobKey     string
+	// ThumbnailKey, Width and Height are only set for images
+	ThumbnailKey string
+	Width        int
+	Height       int
+	// Position orders a product's attachments of the same kind, from 0
+	Position int
+	// Primary marks the image listings show first; a product with images
+	// has exactly one
+	Primary   bool
+	CreatedAt time.Time
+}
@@ -1,16 +1,27 @@
 package domain
 
 import (
+	"math"
 	"time"
 )
 
-// AuctionStatus represents the current state of an auction
+// AuctionStatus represents the current state of an auction. Allowed changes
+// between statuses are listed in auctionTransitions.
 type AuctionStatus string
 
 const (
+	// AuctionStatusPending auctions have been created but not yet started or scheduled
 	AuctionStatusPending AuctionStatus = "pending"
-	AuctionStatusActive  AuctionStatus = "active"
-	AuctionStatusEnded   AuctionStatus = "ended"
+	// AuctionStatusScheduled auctions start automatically at their start time
+	AuctionStatusScheduled AuctionStatus = "scheduled"
+	AuctionStatusActive    AuctionStatus = "active"
+	// AuctionStatusPaused auctions accept no bids and their remaining time is frozen
+	AuctionStatusPaused AuctionStatus = "paused"
+	AuctionStatusEnded  AuctionStatus = "ended"
+	// AuctionStatusCancelled auctions were stopped without a result
+	AuctionStatusCancelled AuctionStatus = "cancelled"
+	// AuctionStatusSettled auctions have ended and their result has been settled
+	AuctionStatusSettled AuctionStatus = "settled"
 )
 
 // Auction represents an auction for a product
@@ -22,16 +33,102 @@ type Auction struct {
 	StartingPrice float64
 	CurrentPrice  float64
 	Status        AuctionStatus
-	CreatedAt     time.Time
+	// TwoFactorThreshold, when set, requires bidders to have two-factor
+	// authentication enabled to bid above this amount
+	TwoFactorThreshold *float64
+	// PausedAt is when a paused auction was paused
+	PausedAt *time.Time
+	// BidCount is the number of bids placed on the auction
+	BidCount int
+	// IncrementTable sets the minimum raise over the current price; without
+	// one, any higher bid is accepted
+	IncrementTable []IncrementStep
+	// SoftCloseSeconds, when positive, extends the auction on bids placed
+	// this close to its end, so that this much time remains
+	SoftCloseSeconds int
+	// Live auctions are run by an auctioneer rather than the clock: bidding
+	// stays open past EndTime until the lot is hammered or passed, and soft
+	// close does not apply
+	Live bool
+	// Version increases by one with every update of the auction, which only
+	// succeeds if the stored version still matches
+	Version   int
+	CreatedAt time.Time
 }
 
 // IsActive checks if the auction is currently active
 func (a *Auction) IsActive() bool {
 	now := time.Now()
-	return a.Status == AuctionStatusActive && now.After(a.StartTime) && now.Before(a.EndTime)
+	return a.Status == AuctionStatusActive && now.After(a.StartTime) && (a.Live || now.Before(a.EndTime))
 }
 
 // HasEnded checks if the auction has ended
 func (a *Auction) HasEnded() bool {
-	return a.Status == AuctionStatusEnded || time.Now().After(a.EndTime)
+	switch a.Status {
+	case AuctionStatusEnded, AuctionStatusCancelled, AuctionStatusSettled:
+		return true
+	case AuctionStatusPaused:
+		return false
+	}
+	return !a.Live && time.Now().After(a.EndTime)
+}
+
+// Remaining returns the time left until the auction ends as of now, or zero
+// once it has ended. The remaining time of a paused auction stays frozen at
+// its value when it was paused.
+func (a *Auction) Remaining(now time.Time) time.Duration {
+	switch a.Status {
+	case AuctionStatusEnded, AuctionStatusCancelled, AuctionStatusSettled:
+		return 0
+	case AuctionStatusPaused:
+		if a.PausedAt != nil {
+			now = *a.PausedAt
+		}
+	}
+	if !now.Before(a.EndTime) {
+		return 0
+	}
+	return a.EndTime.Sub(now)
+}
+
+// RequiresTwoFactor checks if a bid of amount needs a two-factor enabled account
+func (a *Auction) RequiresTwoFactor(amount float64) bool {
+	return a.TwoFactorThreshold != nil && amount > *a.TwoFactorThreshold
+}
+
+// MinimumBid returns the current price raised by the increment the auction's
+// increment table sets for it, in whole cents; without a table, it is the
+// current price itself, which bids must exceed
+func (a *Auction) MinimumBid() float64 {
+	return math.Round((a.CurrentPrice+IncrementFor(a.IncrementTable, a.CurrentPrice))*100) / 100
+}
+
+// NextAsk returns the lowest amount a new bid may offer: the minimum bid, or
+// the current price raised by ProxyBidIncrement if the auction has no
+// increment table
+func (a *Auction) NextAsk() float64 {
+	if minimum := a.MinimumBid(); minimum > a.CurrentPrice {
+		return minimum
+	}
+	return math.Round((a.CurrentPrice+ProxyBidIncrement)*100) / 100
+}
+
+// AcceptsAmount checks if amount is high enough to bid
+func (a *Auction) AcceptsAmount(amount float64) bool {
+	return amount > a.CurrentPrice && amount >= a.MinimumBid()
+}
+
+// ExtendForBid applies soft close to a bid placed at now: if less than the
+// soft close window remains, the end time moves so that the window remains.
+// It reports whether the end time changed.
+func (a *Auction) ExtendForBid(now time.Time) bool {
+	if a.SoftCloseSeconds <= 0 || a.Live {
+		return false
+	}
+	end := now.Add(time.Duration(a.SoftCloseSeconds) * time.Second)
+	if !end.After(a.EndTime) {
+		return false
+	}
+	a.EndTime = end
+	return true
 }
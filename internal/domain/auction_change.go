@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrAuctionNotEditable is returned when an auction's status or bids no
+	// longer allow the requested edit
+	ErrAuctionNotEditable = errors.New("auction can no longer be edited")
+	// ErrNotAuctionOwner is returned when someone other than the seller edits,
+	// schedules, pauses, resumes or cancels an auction
+	ErrNotAuctionOwner = errors.New("only the seller can change this auction")
+)
+
+// Names of the auction settings recorded in the change log
+const (
+	AuctionFieldStartTime          = "start_time"
+	AuctionFieldEndTime            = "end_time"
+	AuctionFieldStartingPrice      = "starting_price"
+	AuctionFieldTwoFactorThreshold = "two_factor_threshold"
+)
+
+// AuctionChange is one entry of an auction's change log: a single setting
+// changed by its seller. All changes made by one edit share the auction
+// version the edit produced. Values are formatted for display; times use
+// RFC 3339 and an unset value is empty.
+type AuctionChange struct {
+	ID        string
+	AuctionID string
+	Version   int
+	Field     string
+	OldValue  string
+	NewValue  string
+	ActorID   string
+	CreatedAt time.Time
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidTransition is matched by InvalidTransitionError
+var ErrInvalidTransition = errors.New("invalid auction status transition")
+
+// InvalidTransitionError is returned when an auction cannot move from its
+// current status to the requested one
+type InvalidTransitionError struct {
+	From AuctionStatus
+	To   AuctionStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("cannot move a %s auction to %s", e.From, e.To)
+}
+
+// Is makes errors.Is(err, ErrInvalidTransition) match
+func (e *InvalidTransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// auctionTransitions lists every allowed status change with the reason
+// recorded when the caller gives none
+var auctionTransitions = map[AuctionStatus]map[AuctionStatus]string{
+	AuctionStatusPending: {
+		AuctionStatusScheduled: "scheduled to start at its start time",
+		AuctionStatusActive:    "started",
+		AuctionStatusCancelled: "cancelled before starting",
+	},
+	AuctionStatusScheduled: {
+		AuctionStatusActive:    "started",
+		AuctionStatusCancelled: "cancelled before starting",
+	},
+	AuctionStatusActive: {
+		AuctionStatusPaused:    "paused",
+		AuctionStatusEnded:     "ended",
+		AuctionStatusCancelled: "cancelled while running",
+	},
+	AuctionStatusPaused: {
+		AuctionStatusActive:    "resumed",
+		AuctionStatusCancelled: "cancelled while paused",
+	},
+	AuctionStatusEnded: {
+		AuctionStatusSettled: "settled",
+	},
+}
+
+// CanTransition checks if an auction in status from may move to status to
+func CanTransition(from, to AuctionStatus) bool {
+	_, ok := auctionTransitions[from][to]
+	return ok
+}
+
+// AuctionStatusChange is one entry of an auction's status history
+type AuctionStatusChange struct {
+	ID        string
+	AuctionID string
+	From      AuctionStatus
+	To        AuctionStatus
+	Reason    string
+	// ActorID is the user who requested the change; empty for changes made by
+	// the server, such as starting at the start time
+	ActorID   string
+	CreatedAt time.Time
+}
+
+// Transition moves the auction to status to at now and returns the change to
+// record in its history. reason defaults to the transition's reason in the
+// table. Pausing freezes the remaining time; resuming extends EndTime by the
+// time spent paused.
+func (a *Auction) Transition(to AuctionStatus, now time.Time, reason, actorID string) (*AuctionStatusChange, error) {
+	defaultReason, ok := auctionTransitions[a.Status][to]
+	if !ok {
+		return nil, &InvalidTransitionError{From: a.Status, To: to}
+	}
+	if reason == "" {
+		reason = defaultReason
+	}
+
+	switch {
+	case to == AuctionStatusPaused:
+		a.PausedAt = &now
+	case a.Status == AuctionStatusPaused && a.PausedAt != nil:
+		if to == AuctionStatusActive {
+			a.EndTime = a.EndTime.Add(now.Sub(*a.PausedAt))
+		}
+		a.PausedAt = nil
+	}
+
+	change := &AuctionStatusChange{
+		AuctionID: a.ID,
+		From:      a.Status,
+		To:        to,
+		Reason:    reason,
+		ActorID:   actorID,
+		CreatedAt: now,
+	}
+	a.Status = to
+	return change, nil
+}
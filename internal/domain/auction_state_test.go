@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAuction_Transition_Table(t *testing.T) {
+	tests := []struct {
+		from    AuctionStatus
+		to      AuctionStatus
+		allowed bool
+	}{
+		{AuctionStatusPending, AuctionStatusScheduled, true},
+		{AuctionStatusPending, AuctionStatusActive, true},
+		{AuctionStatusPending, AuctionStatusEnded, false},
+		{AuctionStatusScheduled, AuctionStatusActive, true},
+		{AuctionStatusScheduled, AuctionStatusPaused, false},
+		{AuctionStatusActive, AuctionStatusPaused, true},
+		{AuctionStatusActive, AuctionStatusEnded, true},
+		{AuctionStatusActive, AuctionStatusSettled, false},
+		{AuctionStatusPaused, AuctionStatusActive, true},
+		{AuctionStatusPaused, AuctionStatusEnded, false},
+		{AuctionStatusPaused, AuctionStatusCancelled, true},
+		{AuctionStatusEnded, AuctionStatusSettled, true},
+		{AuctionStatusEnded, AuctionStatusCancelled, false},
+		{AuctionStatusCancelled, AuctionStatusActive, false},
+		{AuctionStatusSettled, AuctionStatusEnded, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			auction := Auction{ID: "auction-1", Status: tt.from, EndTime: time.Now().Add(time.Hour)}
+			change, err := auction.Transition(tt.to, time.Now(), "", "user-1")
+
+			if !tt.allowed {
+				var invalid *InvalidTransitionError
+				if !errors.As(err, &invalid) || !errors.Is(err, ErrInvalidTransition) {
+					t.Fatalf("Transition() error = %v, want InvalidTransitionError", err)
+				}
+				if auction.Status != tt.from {
+					t.Errorf("Transition() changed status to %q on error", auction.Status)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Transition() unexpected error: %v", err)
+			}
+			if auction.Status != tt.to {
+				t.Errorf("Transition() status = %q, want %q", auction.Status, tt.to)
+			}
+			if change.From != tt.from || change.To != tt.to || change.Reason == "" || change.ActorID != "user-1" {
+				t.Errorf("Transition() change = %+v, want %s -> %s with a reason by user-1", change, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestAuction_Transition_CustomReason(t *testing.T) {
+	auction := Auction{Status: AuctionStatusActive}
+	change, _ := auction.Transition(AuctionStatusCancelled, time.Now(), "lot withdrawn", "")
+	if change.Reason != "lot withdrawn" {
+		t.Errorf("Transition() reason = %q, want %q", change.Reason, "lot withdrawn")
+	}
+}
+
+func TestAuction_Transition_PauseFreezesRemainingTime(t *testing.T) {
+	start := time.Now()
+	end := start.Add(10 * time.Minute)
+	auction := Auction{Status: AuctionStatusActive, EndTime: end}
+
+	auction.Transition(AuctionStatusPaused, start, "", "")
+	later := start.Add(3 * time.Minute)
+	if got := auction.Remaining(later); got != 10*time.Minute {
+		t.Errorf("Remaining() while paused = %v, want 10m0s", got)
+	}
+
+	auction.Transition(AuctionStatusActive, later, "", "")
+	if !auction.EndTime.Equal(end.Add(3 * time.Minute)) {
+		t.Errorf("EndTime after resume = %v, want %v", auction.EndTime, end.Add(3*time.Minute))
+	}
+	if auction.PausedAt != nil {
+		t.Error("PausedAt after resume is set, want nil")
+	}
+	if got := auction.Remaining(later); got != 10*time.Minute {
+		t.Errorf("Remaining() after resume = %v, want 10m0s", got)
+	}
+}
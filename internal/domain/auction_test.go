@@ -117,3 +117,51 @@ func TestAuction_HasEnded(t *testing.T) {
 		})
 	}
 }
+
+func TestAuction_RequiresTwoFactor(t *testing.T) {
+	threshold := 1000.00
+
+	tests := []struct {
+		name     string
+		auction  Auction
+		amount   float64
+		expected bool
+	}{
+		{name: "no threshold", auction: Auction{}, amount: 5000, expected: false},
+		{name: "below threshold", auction: Auction{TwoFactorThreshold: &threshold}, amount: 999.99, expected: false},
+		{name: "at threshold", auction: Auction{TwoFactorThreshold: &threshold}, amount: 1000, expected: false},
+		{name: "above threshold", auction: Auction{TwoFactorThreshold: &threshold}, amount: 1000.01, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.auction.RequiresTwoFactor(tt.amount)
+			if result != tt.expected {
+				t.Errorf("RequiresTwoFactor(%v) = %v, want %v", tt.amount, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAuction_Remaining(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		auction  Auction
+		expected time.Duration
+	}{
+		{name: "running", auction: Auction{Status: AuctionStatusActive, EndTime: now.Add(90 * time.Second)}, expected: 90 * time.Second},
+		{name: "past end time", auction: Auction{Status: AuctionStatusActive, EndTime: now.Add(-time.Second)}, expected: 0},
+		{name: "ended early", auction: Auction{Status: AuctionStatusEnded, EndTime: now.Add(time.Hour)}, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.auction.Remaining(now)
+			if result != tt.expected {
+				t.Errorf("Remaining() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
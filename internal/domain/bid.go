@@ -1,14 +1,68 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 )
 
+// BidSource tells where a bid was placed
+type BidSource string
+
+const (
+	// BidSourceOnline bids were placed by a signed-in user
+	BidSourceOnline BidSource = "online"
+	// BidSourceFloor bids were made in the sale room of a live auction and
+	// entered by the auctioneer or a clerk
+	BidSourceFloor BidSource = "floor"
+)
+
 // Bid represents a bid placed on an auction
 type Bid struct {
 	ID        string
 	AuctionID string
-	UserID    string
+	// UserID is the bidder of an online bid; floor bids have none
+	UserID string
+	Amount float64
+	Source BidSource
+	// Paddle is the paddle number of the room bidder of a floor bid
+	Paddle string
+	// ClerkID is the user who entered a floor bid
+	ClerkID   string
+	CreatedAt time.Time
+	// RetractedAt is when an approved retraction withdrew the bid. Retracted
+	// bids stay in the bid history but no longer count towards the price.
+	RetractedAt *time.Time
+}
+
+// IsRetracted checks if the bid has been withdrawn
+func (b *Bid) IsRetracted() bool {
+	return b.RetractedAt != nil
+}
+
+// IsFloor checks if the bid was made in the sale room
+func (b *Bid) IsFloor() bool {
+	return b.Source == BidSourceFloor
+}
+
+// PublicBid is a bid as shown to other users: the bidder's identity is replaced
+// by a pseudonym that is stable within the auction
+type PublicBid struct {
+	ID        string
+	AuctionID string
+	Bidder    string
+	IsMine    bool
 	Amount    float64
+	Source    BidSource
+	Retracted bool
 	CreatedAt time.Time
 }
+
+// BidderPseudonym returns the public name of the nth distinct bidder in an auction
+func BidderPseudonym(number int) string {
+	return fmt.Sprintf("Bidder %d", number)
+}
+
+// FloorBidder returns the public name of the room bidder holding paddle
+func FloorBidder(paddle string) string {
+	return "Paddle " + paddle
+}
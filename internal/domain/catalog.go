@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotCatalogOwner is returned when someone other than its owner
+	// changes a catalog
+	ErrNotCatalogOwner = errors.New("only the owner can change this catalog")
+	// ErrCatalogNotEditable is returned when changing the lots of a catalog
+	// whose sale has started
+	ErrCatalogNotEditable = errors.New("catalog can no longer be changed")
+)
+
+// Catalog groups the auctions of a themed sale as numbered lots. Bidding on
+// every lot opens at StartTime; the lots close one after another, lot 1 at
+// FirstLotEndTime and every later lot LotIntervalSeconds after the one
+// before it.
+type Catalog struct {
+	ID          string
+	OwnerID     string
+	Title       string
+	Description string
+	// Terms are the conditions of sale bidders agree to
+	Terms              string
+	StartTime          time.Time
+	FirstLotEndTime    time.Time
+	LotIntervalSeconds int
+	// LotCount is the number of lots in the catalog
+	LotCount  int
+	CreatedAt time.Time
+}
+
+// LotInterval returns the time between the scheduled closes of two lots
+func (c *Catalog) LotInterval() time.Duration {
+	return time.Duration(c.LotIntervalSeconds) * time.Second
+}
+
+// LotEndTime returns the scheduled close of lot number, counting from 1
+func (c *Catalog) LotEndTime(number int) time.Time {
+	return c.FirstLotEndTime.Add(time.Duration(number-1) * c.LotInterval())
+}
+
+// EndTime returns the scheduled close of the last lot, or of lot 1 while
+// the catalog has no lots. Soft close extensions may close lots later.
+func (c *Catalog) EndTime() time.Time {
+	return c.LotEndTime(max(c.LotCount, 1))
+}
+
+// HasStarted checks if the sale has opened by now
+func (c *Catalog) HasStarted(now time.Time) bool {
+	return !now.Before(c.StartTime)
+}
+
+// CatalogLot is an auction of a catalog, in order of Number
+type CatalogLot struct {
+	CatalogID string
+	AuctionID string
+	// Number is the lot's position in the catalog, counting from 1
+	Number int
+	// Auction is the lot's auction as of when the lot was read
+	Auction *Auction
+}
+
+// AlignLotEnds keeps the lots of a catalog closing in order. Lots close no
+// earlier than interval after the lot before them, which soft close may have
+// extended: a later lot is pushed back just far enough to keep that gap, and
+// lots unaffected by an extension keep their schedule. Cancelled lots hold
+// back no one, and paused lots, whose remaining time is frozen, count as
+// closing that long after now but are not moved themselves.
+//
+// lots must be in order of Number. AlignLotEnds moves the end times of the
+// lots' auctions and returns those it moved.
+func AlignLotEnds(lots []*CatalogLot, interval time.Duration, now time.Time) []*Auction {
+	var moved []*Auction
+	var previousEnd time.Time
+	for _, lot := range lots {
+		auction := lot.Auction
+		if auction == nil || auction.Status == AuctionStatusCancelled {
+			continue
+		}
+
+		earliest := previousEnd.Add(interval)
+		switch auction.Status {
+		case AuctionStatusPending, AuctionStatusScheduled, AuctionStatusActive:
+			if !previousEnd.IsZero() && auction.EndTime.Before(earliest) {
+				auction.EndTime = earliest
+				moved = append(moved, auction)
+			}
+		}
+
+		previousEnd = auction.EndTime
+		if auction.Status == AuctionStatusPaused {
+			previousEnd = now.Add(auction.Remaining(now))
+		}
+	}
+	return moved
+}
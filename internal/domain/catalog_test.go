@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCatalog_LotEndTime(t *testing.T) {
+	first := time.Date(2026, 3, 8, 18, 0, 0, 0, time.UTC)
+	catalog := Catalog{FirstLotEndTime: first, LotIntervalSeconds: 60, LotCount: 3}
+
+	if got := catalog.LotEndTime(1); !got.Equal(first) {
+		t.Errorf("LotEndTime(1) = %v, want %v", got, first)
+	}
+	if got := catalog.LotEndTime(3); !got.Equal(first.Add(2 * time.Minute)) {
+		t.Errorf("LotEndTime(3) = %v, want 18:02", got)
+	}
+	if got := catalog.EndTime(); !got.Equal(first.Add(2 * time.Minute)) {
+		t.Errorf("EndTime() = %v, want the close of lot 3", got)
+	}
+	if got := (&Catalog{FirstLotEndTime: first, LotIntervalSeconds: 60}).EndTime(); !got.Equal(first) {
+		t.Errorf("EndTime() without lots = %v, want %v", got, first)
+	}
+}
+
+func TestAlignLotEnds(t *testing.T) {
+	now := time.Date(2026, 3, 8, 18, 0, 0, 0, time.UTC)
+	minute := time.Minute
+	lots := func(auctions ...*Auction) []*CatalogLot {
+		result := make([]*CatalogLot, len(auctions))
+		for i, auction := range auctions {
+			result[i] = &CatalogLot{Number: i + 1, Auction: auction}
+		}
+		return result
+	}
+	active := func(end time.Time) *Auction {
+		return &Auction{Status: AuctionStatusActive, EndTime: end}
+	}
+
+	t.Run("on schedule", func(t *testing.T) {
+		a, b, c := active(now), active(now.Add(minute)), active(now.Add(2*minute))
+		if moved := AlignLotEnds(lots(a, b, c), minute, now); len(moved) != 0 {
+			t.Errorf("AlignLotEnds() moved %d lots, want none", len(moved))
+		}
+	})
+
+	t.Run("extension cascades only as far as needed", func(t *testing.T) {
+		// Lot 1 was extended by 90 seconds; lot 4 already closes late enough
+		a, b, c, d := active(now.Add(90*time.Second)), active(now.Add(minute)), active(now.Add(2*minute)), active(now.Add(10*minute))
+		moved := AlignLotEnds(lots(a, b, c, d), minute, now)
+
+		if len(moved) != 2 || moved[0] != b || moved[1] != c {
+			t.Fatalf("AlignLotEnds() moved %v, want lots 2 and 3", moved)
+		}
+		if !b.EndTime.Equal(now.Add(150*time.Second)) || !c.EndTime.Equal(now.Add(210*time.Second)) {
+			t.Errorf("end times = %v, %v; want a minute apart after lot 1", b.EndTime, c.EndTime)
+		}
+		if !d.EndTime.Equal(now.Add(10 * minute)) {
+			t.Errorf("lot 4 end time = %v, want it unchanged", d.EndTime)
+		}
+	})
+
+	t.Run("cancelled lots hold back no one", func(t *testing.T) {
+		cancelled := &Auction{Status: AuctionStatusCancelled, EndTime: now.Add(time.Hour)}
+		b := active(now.Add(minute))
+		if moved := AlignLotEnds(lots(active(now), cancelled, b), minute, now); len(moved) != 0 {
+			t.Errorf("AlignLotEnds() moved %d lots, want none", len(moved))
+		}
+	})
+
+	t.Run("paused lots hold back later lots but do not move", func(t *testing.T) {
+		pausedAt := now.Add(-5 * minute)
+		// Paused with 30 seconds left, so it closes 30 seconds after resuming
+		paused := &Auction{Status: AuctionStatusPaused, EndTime: pausedAt.Add(30 * time.Second), PausedAt: &pausedAt}
+		b := active(now.Add(minute))
+		moved := AlignLotEnds(lots(active(now.Add(-10*minute)), paused, b), minute, now)
+
+		if len(moved) != 1 || moved[0] != b || !b.EndTime.Equal(now.Add(90*time.Second)) {
+			t.Errorf("AlignLotEnds() moved %v to %v, want lot 3 to 90 seconds from now", moved, b.EndTime)
+		}
+		if !paused.EndTime.Equal(pausedAt.Add(30 * time.Second)) {
+			t.Errorf("paused lot end time = %v, want it unchanged", paused.EndTime)
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ErrCategoryInUse is returned when deleting a category that still has
+// subcategories or products
+var ErrCategoryInUse = errors.New("category is in use")
+
+// AttributeType is the kind of value an attribute holds. Values are stored as
+// text in every case.
+type AttributeType string
+
+const (
+	AttributeTypeText AttributeType = "text"
+	// AttributeTypeNumber values parse as decimal numbers within Min and Max
+	AttributeTypeNumber AttributeType = "number"
+	// AttributeTypeEnum values are one of the attribute's Options
+	AttributeTypeEnum AttributeType = "enum"
+	// AttributeTypeBoolean values are "true" or "false"
+	AttributeTypeBoolean AttributeType = "boolean"
+)
+
+// AttributeDefinition describes an attribute that products of a category
+// may, or if Required must, describe themselves with
+type AttributeDefinition struct {
+	Name     string
+	Type     AttributeType
+	Required bool
+	// Options lists the allowed values of an enum attribute
+	Options []string
+	// Min and Max bound the values of a number attribute, inclusive
+	Min *float64
+	Max *float64
+}
+
+// IncrementStep sets the minimum bid increment for current prices from From
+// up to the From of the next step
+type IncrementStep struct {
+	From      float64
+	Increment float64
+}
+
+// AuctionDefaults are the settings new auctions of a category's products
+// start with. Unset fields are inherited from the parent category.
+type AuctionDefaults struct {
+	// IncrementTable lists steps by ascending From, the first from zero
+	IncrementTable []IncrementStep
+	// SoftCloseSeconds extends an auction that receives a bid this close to
+	// its end so that this much time remains; zero disables soft close
+	SoftCloseSeconds *int
+}
+
+// Category groups products in a tree. A category's products are described by
+// its own attributes and those of its ancestors.
+type Category struct {
+	ID string
+	// ParentID is empty for top-level categories
+	ParentID   string
+	Name       string
+	Attributes []AttributeDefinition
+	Defaults   AuctionDefaults
+	// Fees override the fee schedules of the category's auctions; unset
+	// schedules are inherited from the parent category
+	Fees      FeeSchedules
+	CreatedAt time.Time
+}
+
+// ProductCondition is the state a product is offered in
+type ProductCondition string
+
+const (
+	ConditionNew         ProductCondition = "new"
+	ConditionLikeNew     ProductCondition = "like_new"
+	ConditionUsed        ProductCondition = "used"
+	ConditionRefurbished ProductCondition = "refurbished"
+	ConditionForParts    ProductCondition = "for_parts"
+)
+
+// Valid checks if c is a known condition
+func (c ProductCondition) Valid() bool {
+	switch c {
+	case ConditionNew, ConditionLikeNew, ConditionUsed, ConditionRefurbished, ConditionForParts:
+		return true
+	}
+	return false
+}
+
+// Validate checks that the definition is complete and consistent
+func (d *AttributeDefinition) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("%w: attribute name is required", ErrValidation)
+	}
+	switch d.Type {
+	case AttributeTypeText, AttributeTypeBoolean:
+	case AttributeTypeNumber:
+		if d.Min != nil && d.Max != nil && *d.Min > *d.Max {
+			return fmt.Errorf("%w: attribute %q: min must not exceed max", ErrValidation, d.Name)
+		}
+	case AttributeTypeEnum:
+		if len(d.Options) == 0 {
+			return fmt.Errorf("%w: attribute %q: enum attributes need options", ErrValidation, d.Name)
+		}
+	default:
+		return fmt.Errorf("%w: attribute %q: unknown type %q", ErrValidation, d.Name, d.Type)
+	}
+	return nil
+}
+
+// Check validates a product's value of the attribute
+func (d *AttributeDefinition) Check(value string) error {
+	switch d.Type {
+	case AttributeTypeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return fmt.Errorf("%w: attribute %q must be a number", ErrValidation, d.Name)
+		}
+		if d.Min != nil && number < *d.Min || d.Max != nil && number > *d.Max {
+			return fmt.Errorf("%w: attribute %q is out of range", ErrValidation, d.Name)
+		}
+	case AttributeTypeEnum:
+		if !slices.Contains(d.Options, value) {
+			return fmt.Errorf("%w: attribute %q must be one of %v", ErrValidation, d.Name, d.Options)
+		}
+	case AttributeTypeBoolean:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("%w: attribute %q must be true or false", ErrValidation, d.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateAttributes checks product attribute values against the attribute
+// definitions of its category: required attributes must be set, and only
+// defined attributes may be
+func ValidateAttributes(definitions []AttributeDefinition, values map[string]string) error {
+	for _, definition := range definitions {
+		value, ok := values[definition.Name]
+		if !ok || value == "" {
+			if definition.Required {
+				return fmt.Errorf("%w: attribute %q is required", ErrValidation, definition.Name)
+			}
+			continue
+		}
+		if err := definition.Check(value); err != nil {
+			return err
+		}
+	}
+	for name := range values {
+		if !slices.ContainsFunc(definitions, func(d AttributeDefinition) bool { return d.Name == name }) {
+			return fmt.Errorf("%w: unknown attribute %q", ErrValidation, name)
+		}
+	}
+	return nil
+}
+
+// ValidateIncrementTable checks that a table starts at zero, with positive
+// increments at strictly ascending prices
+func ValidateIncrementTable(table []IncrementStep) error {
+	for i, step := range table {
+		switch {
+		case i == 0 && step.From != 0:
+			return fmt.Errorf("%w: the first increment step must start from 0", ErrValidation)
+		case i > 0 && step.From <= table[i-1].From:
+			return fmt.Errorf("%w: increment steps must be in ascending order", ErrValidation)
+		case step.Increment <= 0:
+			return fmt.Errorf("%w: increments must be positive", ErrValidation)
+		}
+	}
+	return nil
+}
+
+// IncrementFor returns the increment the table sets for a current price, or
+// zero for an empty table
+func IncrementFor(table []IncrementStep, price float64) float64 {
+	increment := 0.0
+	for _, step := range table {
+		if price < step.From {
+			break
+		}
+		increment = step.Increment
+	}
+	return increment
+}
+
+// CategoryAttributes returns the attribute definitions of the products of the
+// last category in path, which runs from a top-level category down: those of
+// every category on the path, where a subcategory's definition replaces an
+// ancestor's of the same name
+func CategoryAttributes(path []*Category) []AttributeDefinition {
+	var definitions []AttributeDefinition
+	for _, category := range path {
+		for _, definition := range category.Attributes {
+			i := slices.IndexFunc(definitions, func(d AttributeDefinition) bool { return d.Name == definition.Name })
+			if i >= 0 {
+				definitions[i] = definition
+			} else {
+				definitions = append(definitions, definition)
+			}
+		}
+	}
+	return definitions
+}
+
+// CategoryDefaults returns the auction defaults of the last category in path,
+// which runs from a top-level category down, taking each setting from the
+// nearest category that sets it
+func CategoryDefaults(path []*Category) AuctionDefaults {
+	var defaults AuctionDefaults
+	for _, category := range path {
+		if category.Defaults.IncrementTable != nil {
+			defaults.IncrementTable = category.Defaults.IncrementTable
+		}
+		if category.Defaults.SoftCloseSeconds != nil {
+			defaults.SoftCloseSeconds = category.Defaults.SoftCloseSeconds
+		}
+	}
+	return defaults
+}
@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateAttributes(t *testing.T) {
+	low, high := 10.0, 20.0
+	definitions := []AttributeDefinition{
+		{Name: "brand", Type: AttributeTypeText, Required: true},
+		{Name: "screen", Type: AttributeTypeNumber, Min: &low, Max: &high},
+		{Name: "color", Type: AttributeTypeEnum, Options: []string{"black", "silver"}},
+		{Name: "touch", Type: AttributeTypeBoolean},
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]string
+		wantErr bool
+	}{
+		{"all valid", map[string]string{"brand": "Acme", "screen": "15.6", "color": "black", "touch": "true"}, false},
+		{"only required", map[string]string{"brand": "Acme"}, false},
+		{"missing required", map[string]string{"screen": "15"}, true},
+		{"empty required", map[string]string{"brand": ""}, true},
+		{"number not a number", map[string]string{"brand": "Acme", "screen": "big"}, true},
+		{"number out of range", map[string]string{"brand": "Acme", "screen": "21"}, true},
+		{"enum not an option", map[string]string{"brand": "Acme", "color": "red"}, true},
+		{"boolean not a boolean", map[string]string{"brand": "Acme", "touch": "yes"}, true},
+		{"unknown attribute", map[string]string{"brand": "Acme", "weight": "2kg"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAttributes(definitions, tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAttributes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrValidation) {
+				t.Errorf("ValidateAttributes() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestValidateIncrementTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		table   []IncrementStep
+		wantErr bool
+	}{
+		{"empty", nil, false},
+		{"ascending", []IncrementStep{{0, 1}, {100, 5}, {1000, 25}}, false},
+		{"not from zero", []IncrementStep{{10, 1}}, true},
+		{"not ascending", []IncrementStep{{0, 1}, {100, 5}, {100, 10}}, true},
+		{"zero increment", []IncrementStep{{0, 0}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateIncrementTable(tt.table); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateIncrementTable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIncrementFor(t *testing.T) {
+	table := []IncrementStep{{0, 1}, {100, 5}, {1000, 25}}
+	tests := []struct {
+		price float64
+		want  float64
+	}{
+		{0, 1},
+		{99.99, 1},
+		{100, 5},
+		{999, 5},
+		{5000, 25},
+	}
+	for _, tt := range tests {
+		if got := IncrementFor(table, tt.price); got != tt.want {
+			t.Errorf("IncrementFor(%v) = %v, want %v", tt.price, got, tt.want)
+		}
+	}
+	if got := IncrementFor(nil, 100); got != 0 {
+		t.Errorf("IncrementFor() without a table = %v, want 0", got)
+	}
+}
+
+func TestCategoryAttributes_SubcategoryOverridesAncestor(t *testing.T) {
+	path := []*Category{
+		{Attributes: []AttributeDefinition{{Name: "brand", Type: AttributeTypeText}, {Name: "color", Type: AttributeTypeText}}},
+		{Attributes: []AttributeDefinition{{Name: "color", Type: AttributeTypeEnum, Options: []string{"black"}}, {Name: "ram", Type: AttributeTypeNumber}}},
+	}
+
+	got := CategoryAttributes(path)
+	if len(got) != 3 {
+		t.Fatalf("CategoryAttributes() returned %d definitions, want 3", len(got))
+	}
+	if got[0].Name != "brand" || got[1].Name != "color" || got[2].Name != "ram" {
+		t.Errorf("CategoryAttributes() = %v, want brand, color, ram", got)
+	}
+	if got[1].Type != AttributeTypeEnum {
+		t.Errorf("CategoryAttributes() color type = %q, want the subcategory's %q", got[1].Type, AttributeTypeEnum)
+	}
+}
+
+func TestCategoryDefaults_InheritsUnsetSettings(t *testing.T) {
+	window := 120
+	table := []IncrementStep{{0, 5}}
+	path := []*Category{
+		{Defaults: AuctionDefaults{IncrementTable: []IncrementStep{{0, 1}}, SoftCloseSeconds: &window}},
+		{Defaults: AuctionDefaults{IncrementTable: table}},
+	}
+
+	got := CategoryDefaults(path)
+	if len(got.IncrementTable) != 1 || got.IncrementTable[0].Increment != 5 {
+		t.Errorf("CategoryDefaults() IncrementTable = %v, want the subcategory's %v", got.IncrementTable, table)
+	}
+	if got.SoftCloseSeconds == nil || *got.SoftCloseSeconds != window {
+		t.Errorf("CategoryDefaults() SoftCloseSeconds = %v, want the parent's %d", got.SoftCloseSeconds, window)
+	}
+}
+
+func TestAuction_AcceptsAmount(t *testing.T) {
+	auction := &Auction{CurrentPrice: 100}
+	if !auction.AcceptsAmount(100.01) {
+		t.Error("AcceptsAmount() without a table rejected a higher bid")
+	}
+	if auction.AcceptsAmount(100) {
+		t.Error("AcceptsAmount() accepted a bid equal to the current price")
+	}
+
+	auction.IncrementTable = []IncrementStep{{0, 1}, {100, 5}}
+	if auction.AcceptsAmount(104.99) {
+		t.Error("AcceptsAmount() accepted a bid below the increment")
+	}
+	if !auction.AcceptsAmount(105) {
+		t.Error("AcceptsAmount() rejected a bid of exactly the increment")
+	}
+}
+
+func TestAuction_ExtendForBid(t *testing.T) {
+	now := time.Now()
+	auction := &Auction{EndTime: now.Add(30 * time.Second), SoftCloseSeconds: 120}
+
+	if !auction.ExtendForBid(now) {
+		t.Fatal("ExtendForBid() did not extend for a bid inside the window")
+	}
+	if want := now.Add(2 * time.Minute); !auction.EndTime.Equal(want) {
+		t.Errorf("ExtendForBid() EndTime = %v, want %v", auction.EndTime, want)
+	}
+	if auction.ExtendForBid(now.Add(-time.Minute)) {
+		t.Error("ExtendForBid() extended for a bid outside the window")
+	}
+
+	auction.SoftCloseSeconds = 0
+	if auction.ExtendForBid(now.Add(time.Minute + 59*time.Second)) {
+		t.Error("ExtendForBid() extended without soft close")
+	}
+}
+
+func TestAuction_MinimumBid_RoundsToCents(t *testing.T) {
+	auction := &Auction{CurrentPrice: 10.10, IncrementTable: []IncrementStep{{0, 0.25}}}
+	if got := auction.MinimumBid(); got != 10.35 {
+		t.Errorf("MinimumBid() = %v, want 10.35", got)
+	}
+	if !auction.AcceptsAmount(10.35) {
+		t.Error("AcceptsAmount() rejected the minimum bid")
+	}
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors shared by all entities. Services wrap them with detail, e.g.
+// fmt.Errorf("%w: end time must be after start time", ErrValidation), and
+// handlers map them to HTTP responses with errors.Is.
+var (
+	// ErrNotFound is matched by NotFoundError
+	ErrNotFound = errors.New("not found")
+	// ErrValidation is returned when input breaks a business rule
+	ErrValidation = errors.New("invalid input")
+	// ErrUnauthorized is returned when the caller's credentials are missing or invalid
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrInvalidCredentials is returned when a login's email, password or
+	// second factor is wrong
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrForbidden is returned when the caller may not perform the action
+	ErrForbidden = errors.New("forbidden")
+	// ErrConflict is matched by ConflictError
+	ErrConflict = errors.New("record was modified concurrently")
+	// ErrAuctionClosed is returned when bidding on an auction that is not active
+	ErrAuctionClosed = errors.New("auction is not accepting bids")
+	// ErrBidTooLow is matched by BidTooLowError
+	ErrBidTooLow = errors.New("bid is too low")
+)
+
+// NotFoundError is returned when a record does not exist
+type NotFoundError struct {
+	Entity string
+	ID     string
+}
+
+func (e *NotFoundError) Error() string {
+	if e.ID == "" {
+		return e.Entity + " not found"
+	}
+	return fmt.Sprintf("%s %s not found", e.Entity, e.ID)
+}
+
+// Is makes errors.Is(err, ErrNotFound) match
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// ConflictError is returned when a conditional update finds that the record
+// no longer has the version it was read at, because someone else changed it
+// in the meantime
+type ConflictError struct {
+	Entity  string
+	ID      string
+	Version int
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %s is no longer at version %d", e.Entity, e.ID, e.Version)
+}
+
+// Is makes errors.Is(err, ErrConflict) match
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// BidTooLowError is returned when a bid or proxy maximum does not exceed the
+// auction's current price, or falls short of its minimum increment
+type BidTooLowError struct {
+	CurrentPrice float64
+	// MinimumBid is set when the auction's increment table requires more
+	// than beating the current price
+	MinimumBid float64
+}
+
+func (e *BidTooLowError) Error() string {
+	if e.MinimumBid > e.CurrentPrice {
+		return fmt.Sprintf("bid must be at least %.2f", e.MinimumBid)
+	}
+	return fmt.Sprintf("bid must be higher than the current price (%.2f)", e.CurrentPrice)
+}
+
+// Is makes errors.Is(err, ErrBidTooLow) match
+func (e *BidTooLowError) Is(target error) bool {
+	return target == ErrBidTooLow
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTypedErrors_MatchSentinels(t *testing.T) {
+	tests := []struct {
+		err      error
+		sentinel error
+		message  string
+	}{
+		{&NotFoundError{Entity: "auction", ID: "a-1"}, ErrNotFound, "auction a-1 not found"},
+		{&NotFoundError{Entity: "recovery code"}, ErrNotFound, "recovery code not found"},
+		{&ConflictError{Entity: "auction", ID: "a-1", Version: 3}, ErrConflict, "auction a-1 is no longer at version 3"},
+		{&BidTooLowError{CurrentPrice: 150}, ErrBidTooLow, "bid must be higher than the current price (150.00)"},
+	}
+
+	for _, tt := range tests {
+		wrapped := fmt.Errorf("place bid: %w", tt.err)
+		if !errors.Is(wrapped, tt.sentinel) {
+			t.Errorf("errors.Is(%v, %v) = false, want true", wrapped, tt.sentinel)
+		}
+		if got := tt.err.Error(); got != tt.message {
+			t.Errorf("Error() = %q, want %q", got, tt.message)
+		}
+	}
+
+	if errors.Is(&NotFoundError{Entity: "auction"}, ErrConflict) {
+		t.Error("NotFoundError matched ErrConflict")
+	}
+}
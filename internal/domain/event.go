@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"time"
+)
+
+// AuctionEventType identifies what happened in an auction
+type AuctionEventType string
+
+const (
+	AuctionEventBid AuctionEventType = "bid"
+	// AuctionEventTick periodically reports the auction clock to live
+	// subscribers. Ticks are transient: they are not logged or replayed.
+	AuctionEventTick AuctionEventType = "tick"
+	// AuctionEventClosing announces that bidding has stopped and the result is
+	// being finalized
+	AuctionEventClosing AuctionEventType = "closing"
+	// AuctionEventClosed announces that the auction has ended with its final price
+	AuctionEventClosed AuctionEventType = "closed"
+	// AuctionEventStatus announces any other status change, such as pausing,
+	// resuming or cancelling
+	AuctionEventStatus AuctionEventType = "status"
+	// AuctionEventUpdated announces that the seller edited the auction's
+	// settings, such as its end time or starting price
+	AuctionEventUpdated AuctionEventType = "updated"
+	// AuctionEventExtended announces that a late bid extended the auction's
+	// end time under soft close
+	AuctionEventExtended AuctionEventType = "extended"
+	// AuctionEventLive announces an auctioneer's action on a live auction,
+	// such as a new asking price or fair warning
+	AuctionEventLive AuctionEventType = "live"
+	// AuctionEventRetracted announces that an approved retraction withdrew a
+	// bid and the auction's price was recomputed without it
+	AuctionEventRetracted AuctionEventType = "retracted"
+)
+
+// AuctionEvent is a real-time notification about an auction. Sequence numbers
+// increase by one per auction and are assigned when the event is logged;
+// transient events keep sequence 0. Bid carries the full bid; Bidder is the
+// bidder's pseudonym, the only identity shown to others. Auction is a snapshot
+// of the auction's state, set on every event type other than bid. Live is a
+// snapshot of the live lot, set on live events.
+type AuctionEvent struct {
+	AuctionID string
+	Sequence  int64
+	Type      AuctionEventType
+	Bid       *Bid
+	Bidder    string
+	Auction   *Auction
+	Live      *LiveLot
+	CreatedAt time.Time
+}
+
+// IsLogged checks if the event was recorded in the persistent event log
+func (e *AuctionEvent) IsLogged() bool {
+	return e.Sequence > 0
+}
+
+// EventPublisher delivers auction events to live subscribers. Publish must not
+// block on slow subscribers.
+type EventPublisher interface {
+	Publish(event *AuctionEvent)
+	// WatchedAuctions lists the auctions that currently have live subscribers
+	WatchedAuctions() []string
+}
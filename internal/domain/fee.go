@@ -0,0 +1,234 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// FeeType is how a fee schedule computes its fee from the hammer price
+type FeeType string
+
+const (
+	// FeeTypeFlat schedules charge Amount whatever the hammer price
+	FeeTypeFlat FeeType = "flat"
+	// FeeTypePercentage schedules charge Amount percent of the hammer price
+	FeeTypePercentage FeeType = "percentage"
+	// FeeTypeTiered schedules charge each tier's percentage on the part of
+	// the hammer price within that tier
+	FeeTypeTiered FeeType = "tiered"
+)
+
+// FeeKind is what a line of a fee breakdown charges for
+type FeeKind string
+
+const (
+	// FeeKindBuyerPremium is charged to the buyer on top of the hammer price
+	FeeKindBuyerPremium FeeKind = "buyer_premium"
+	// FeeKindSellerCommission is deducted from the seller's proceeds
+	FeeKindSellerCommission FeeKind = "seller_commission"
+	// FeeKindTax is charged to the buyer on the hammer price and premium
+	FeeKindTax FeeKind = "tax"
+)
+
+// FeeTier charges Percent on the part of the hammer price from From up to the
+// From of the next tier
+type FeeTier struct {
+	From    float64
+	Percent float64
+}
+
+// FeeSchedule computes a fee from the hammer price. The computed fee is raised
+// to Minimum and lowered to Maximum when they are set.
+type FeeSchedule struct {
+	Type FeeType
+	// Amount is the fee of a flat schedule, or the percentage of the hammer
+	// price charged by a percentage schedule
+	Amount float64
+	// Tiers of a tiered schedule, by ascending From, the first from zero
+	Tiers []FeeTier
+	// Minimum is the lowest fee charged; zero for none
+	Minimum float64
+	// Maximum caps the fee; zero for no cap
+	Maximum float64
+}
+
+// FeeSchedules are the fees charged when an auction is won. A nil schedule
+// charges nothing, or in a category, inherits its parent's schedule.
+type FeeSchedules struct {
+	BuyerPremium     *FeeSchedule
+	SellerCommission *FeeSchedule
+}
+
+// FeeItem is one line of a fee breakdown
+type FeeItem struct {
+	Kind FeeKind
+	// Description explains how the line was computed, such as "15% of 1000.00"
+	Description string
+	// Amount is negative for lines lowering a fee to its cap
+	Amount float64
+}
+
+// FeeBreakdown itemizes what the buyer pays and the seller receives for a
+// hammer price
+type FeeBreakdown struct {
+	HammerPrice      float64
+	BuyerPremium     float64
+	SellerCommission float64
+	Tax              float64
+	// Items add up to the buyer's premium, seller commission and tax
+	Items []FeeItem
+}
+
+// BuyerTotal returns what the buyer owes: hammer price, buyer's premium and tax
+func (b *FeeBreakdown) BuyerTotal() float64 {
+	return roundCents(b.HammerPrice + b.BuyerPremium + b.Tax)
+}
+
+// SellerProceeds returns what the seller receives: hammer price less
+// commission
+func (b *FeeBreakdown) SellerProceeds() float64 {
+	return roundCents(b.HammerPrice - b.SellerCommission)
+}
+
+// Validate checks that the schedule is complete and consistent
+func (s *FeeSchedule) Validate() error {
+	switch s.Type {
+	case FeeTypeFlat, FeeTypePercentage:
+		if s.Amount < 0 {
+			return fmt.Errorf("%w: fee amount must be non-negative", ErrValidation)
+		}
+		if len(s.Tiers) > 0 {
+			return fmt.Errorf("%w: only tiered fees have tiers", ErrValidation)
+		}
+	case FeeTypeTiered:
+		if len(s.Tiers) == 0 {
+			return fmt.Errorf("%w: tiered fees need tiers", ErrValidation)
+		}
+		for i, tier := range s.Tiers {
+			switch {
+			case i == 0 && tier.From != 0:
+				return fmt.Errorf("%w: the first fee tier must start from 0", ErrValidation)
+			case i > 0 && tier.From <= s.Tiers[i-1].From:
+				return fmt.Errorf("%w: fee tiers must be in ascending order", ErrValidation)
+			case tier.Percent < 0:
+				return fmt.Errorf("%w: fee percentages must be non-negative", ErrValidation)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unknown fee type %q", ErrValidation, s.Type)
+	}
+	switch {
+	case s.Minimum < 0 || s.Maximum < 0:
+		return fmt.Errorf("%w: fee minimum and maximum must be non-negative", ErrValidation)
+	case s.Maximum > 0 && s.Minimum > s.Maximum:
+		return fmt.Errorf("%w: fee minimum must not exceed its maximum", ErrValidation)
+	}
+	return nil
+}
+
+// Items returns the lines of the fee of kind on a hammer price, in cents,
+// adding up to the fee. A schedule charging nothing returns no lines.
+func (s *FeeSchedule) Items(kind FeeKind, hammer float64) []FeeItem {
+	var items []FeeItem
+	add := func(description string, amount float64) {
+		if amount = roundCents(amount); amount != 0 {
+			items = append(items, FeeItem{Kind: kind, Description: description, Amount: amount})
+		}
+	}
+
+	switch s.Type {
+	case FeeTypeFlat:
+		add("flat fee", s.Amount)
+	case FeeTypePercentage:
+		add(fmt.Sprintf("%s of %.2f", formatPercent(s.Amount), hammer), hammer*s.Amount/100)
+	case FeeTypeTiered:
+		for i, tier := range s.Tiers {
+			if hammer <= tier.From {
+				break
+			}
+			portion := hammer - tier.From
+			if i+1 < len(s.Tiers) {
+				portion = math.Min(portion, s.Tiers[i+1].From-tier.From)
+			}
+			description := fmt.Sprintf("%s of %.2f", formatPercent(tier.Percent), portion)
+			if tier.From > 0 {
+				description += fmt.Sprintf(" above %.2f", tier.From)
+			}
+			add(description, portion*tier.Percent/100)
+		}
+	}
+
+	fee := 0.0
+	for _, item := range items {
+		fee += item.Amount
+	}
+	fee = roundCents(fee)
+	switch {
+	case fee < s.Minimum:
+		add(fmt.Sprintf("minimum fee %.2f", s.Minimum), s.Minimum-fee)
+	case s.Maximum > 0 && fee > s.Maximum:
+		add(fmt.Sprintf("capped at %.2f", s.Maximum), s.Maximum-fee)
+	}
+	return items
+}
+
+// ComputeFees itemizes the buyer's premium and seller commission schedules
+// charge on a hammer price, and taxPercent tax on the hammer price and
+// buyer's premium
+func ComputeFees(schedules FeeSchedules, taxPercent, hammer float64) *FeeBreakdown {
+	breakdown := &FeeBreakdown{HammerPrice: hammer, Items: []FeeItem{}}
+	if schedules.BuyerPremium != nil {
+		for _, item := range schedules.BuyerPremium.Items(FeeKindBuyerPremium, hammer) {
+			breakdown.BuyerPremium += item.Amount
+			breakdown.Items = append(breakdown.Items, item)
+		}
+	}
+	if schedules.SellerCommission != nil {
+		for _, item := range schedules.SellerCommission.Items(FeeKindSellerCommission, hammer) {
+			breakdown.SellerCommission += item.Amount
+			breakdown.Items = append(breakdown.Items, item)
+		}
+	}
+	breakdown.BuyerPremium = roundCents(breakdown.BuyerPremium)
+	breakdown.SellerCommission = roundCents(breakdown.SellerCommission)
+
+	if taxPercent > 0 {
+		taxable := roundCents(hammer + breakdown.BuyerPremium)
+		breakdown.Tax = roundCents(taxable * taxPercent / 100)
+		if breakdown.Tax != 0 {
+			breakdown.Items = append(breakdown.Items, FeeItem{
+				Kind:        FeeKindTax,
+				Description: fmt.Sprintf("%s of %.2f", formatPercent(taxPercent), taxable),
+				Amount:      breakdown.Tax,
+			})
+		}
+	}
+	return breakdown
+}
+
+// CategoryFees returns the fee schedules of the last category in path, which
+// runs from a top-level category down, taking each schedule from the nearest
+// category that sets it and otherwise from defaults
+func CategoryFees(path []*Category, defaults FeeSchedules) FeeSchedules {
+	fees := defaults
+	for _, category := range path {
+		if category.Fees.BuyerPremium != nil {
+			fees.BuyerPremium = category.Fees.BuyerPremium
+		}
+		if category.Fees.SellerCommission != nil {
+			fees.SellerCommission = category.Fees.SellerCommission
+		}
+	}
+	return fees
+}
+
+// roundCents rounds an amount to whole cents
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// formatPercent formats a percentage without trailing zeros, such as "12.5%"
+func formatPercent(percent float64) string {
+	return strconv.FormatFloat(percent, 'f', -1, 64) + "%"
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFeeSchedule_Items(t *testing.T) {
+	tiered := FeeSchedule{Type: FeeTypeTiered, Tiers: []FeeTier{{0, 25}, {1000, 20}, {5000, 10}}}
+	tests := []struct {
+		name      string
+		schedule  FeeSchedule
+		hammer    float64
+		want      float64
+		wantLines int
+	}{
+		{"flat", FeeSchedule{Type: FeeTypeFlat, Amount: 10}, 500, 10, 1},
+		{"percentage", FeeSchedule{Type: FeeTypePercentage, Amount: 15}, 150, 22.5, 1},
+		{"percentage rounds to cents", FeeSchedule{Type: FeeTypePercentage, Amount: 12.5}, 99.99, 12.5, 1},
+		{"minimum", FeeSchedule{Type: FeeTypePercentage, Amount: 10, Minimum: 5}, 20, 5, 2},
+		{"cap", FeeSchedule{Type: FeeTypePercentage, Amount: 10, Maximum: 50}, 1000, 50, 2},
+		{"first tier", tiered, 800, 200, 1},
+		{"spanning tiers", tiered, 6000, 250 + 800 + 100, 3},
+		{"tier boundary", tiered, 1000, 250, 1},
+		{"nothing charged", FeeSchedule{Type: FeeTypePercentage, Amount: 0}, 100, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := tt.schedule.Items(FeeKindBuyerPremium, tt.hammer)
+			total := 0.0
+			for _, item := range items {
+				total += item.Amount
+				if item.Kind != FeeKindBuyerPremium || item.Description == "" {
+					t.Errorf("item = %+v, want a described buyer's premium line", item)
+				}
+			}
+			if roundCents(total) != tt.want || len(items) != tt.wantLines {
+				t.Errorf("Items() = %+v, want %d lines adding up to %v", items, tt.wantLines, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeeSchedule_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule FeeSchedule
+		wantErr  bool
+	}{
+		{"flat", FeeSchedule{Type: FeeTypeFlat, Amount: 10}, false},
+		{"percentage with bounds", FeeSchedule{Type: FeeTypePercentage, Amount: 15, Minimum: 5, Maximum: 500}, false},
+		{"tiered", FeeSchedule{Type: FeeTypeTiered, Tiers: []FeeTier{{0, 25}, {1000, 20}}}, false},
+		{"unknown type", FeeSchedule{Type: "sliding", Amount: 10}, true},
+		{"negative amount", FeeSchedule{Type: FeeTypeFlat, Amount: -1}, true},
+		{"tiers on a percentage", FeeSchedule{Type: FeeTypePercentage, Amount: 15, Tiers: []FeeTier{{0, 25}}}, true},
+		{"no tiers", FeeSchedule{Type: FeeTypeTiered}, true},
+		{"first tier above zero", FeeSchedule{Type: FeeTypeTiered, Tiers: []FeeTier{{100, 25}}}, true},
+		{"tiers out of order", FeeSchedule{Type: FeeTypeTiered, Tiers: []FeeTier{{0, 25}, {1000, 20}, {1000, 10}}}, true},
+		{"minimum above maximum", FeeSchedule{Type: FeeTypeFlat, Amount: 10, Minimum: 50, Maximum: 20}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schedule.Validate()
+			if tt.wantErr != errors.Is(err, ErrValidation) || (!tt.wantErr && err != nil) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestComputeFees(t *testing.T) {
+	schedules := FeeSchedules{
+		BuyerPremium:     &FeeSchedule{Type: FeeTypePercentage, Amount: 15},
+		SellerCommission: &FeeSchedule{Type: FeeTypePercentage, Amount: 10},
+	}
+	breakdown := ComputeFees(schedules, 10, 150)
+
+	if breakdown.BuyerPremium != 22.5 || breakdown.SellerCommission != 15 || breakdown.Tax != 17.25 {
+		t.Errorf("breakdown = %+v, want premium 22.50, commission 15, tax 17.25", breakdown)
+	}
+	if total := breakdown.BuyerTotal(); total != 189.75 {
+		t.Errorf("BuyerTotal() = %v, want 189.75", total)
+	}
+	if proceeds := breakdown.SellerProceeds(); proceeds != 135 {
+		t.Errorf("SellerProceeds() = %v, want 135", proceeds)
+	}
+	kinds := []FeeKind{FeeKindBuyerPremium, FeeKindSellerCommission, FeeKindTax}
+	if len(breakdown.Items) != len(kinds) {
+		t.Fatalf("items = %+v, want one per kind", breakdown.Items)
+	}
+	for i, kind := range kinds {
+		if breakdown.Items[i].Kind != kind {
+			t.Errorf("item %d kind = %s, want %s", i, breakdown.Items[i].Kind, kind)
+		}
+	}
+
+	if none := ComputeFees(FeeSchedules{}, 0, 150); none.BuyerTotal() != 150 || len(none.Items) != 0 {
+		t.Errorf("ComputeFees() without schedules = %+v, want the hammer price alone", none)
+	}
+}
+
+func TestCategoryFees(t *testing.T) {
+	defaults := FeeSchedules{
+		BuyerPremium:     &FeeSchedule{Type: FeeTypePercentage, Amount: 15},
+		SellerCommission: &FeeSchedule{Type: FeeTypePercentage, Amount: 10},
+	}
+	art := &Category{ID: "art", Fees: FeeSchedules{BuyerPremium: &FeeSchedule{Type: FeeTypePercentage, Amount: 25}}}
+	prints := &Category{ID: "prints", ParentID: "art", Fees: FeeSchedules{SellerCommission: &FeeSchedule{Type: FeeTypeFlat, Amount: 5}}}
+
+	fees := CategoryFees([]*Category{art, prints}, defaults)
+	if fees.BuyerPremium.Amount != 25 {
+		t.Errorf("buyer's premium = %+v, want the parent's 25%%", fees.BuyerPremium)
+	}
+	if fees.SellerCommission.Type != FeeTypeFlat {
+		t.Errorf("seller commission = %+v, want the category's flat fee", fees.SellerCommission)
+	}
+	if fees := CategoryFees(nil, defaults); fees != defaults {
+		t.Errorf("CategoryFees() without categories = %+v, want the defaults", fees)
+	}
+}
@@ -0,0 +1,81 @@
+package domain
+
+import "time"
+
+// ImportFormat is the file format of a bulk import
+type ImportFormat string
+
+const (
+	// ImportFormatCSV files have a header row naming their columns
+	ImportFormatCSV ImportFormat = "csv"
+	// ImportFormatJSONLines files hold one JSON object per line
+	ImportFormatJSONLines ImportFormat = "jsonl"
+)
+
+// Valid checks if f is a known import format
+func (f ImportFormat) Valid() bool {
+	return f == ImportFormatCSV || f == ImportFormatJSONLines
+}
+
+// ImportJobStatus is the progress of an import job
+type ImportJobStatus string
+
+const (
+	ImportJobQueued     ImportJobStatus = "queued"
+	ImportJobValidating ImportJobStatus = "validating"
+	// ImportJobImporting jobs passed validation and are storing their lots
+	ImportJobImporting ImportJobStatus = "importing"
+	// ImportJobSucceeded jobs created all of their lots, or for dry runs,
+	// found every row valid
+	ImportJobSucceeded ImportJobStatus = "succeeded"
+	// ImportJobFailed jobs created nothing
+	ImportJobFailed ImportJobStatus = "failed"
+)
+
+// Finished checks if a job in status s has stopped
+func (s ImportJobStatus) Finished() bool {
+	return s == ImportJobSucceeded || s == ImportJobFailed
+}
+
+// ImportRowError is a problem with one row of an import file
+type ImportRowError struct {
+	// Line is the line of the file the row starts on, counting from 1
+	Line int
+	// Field is the column the problem is in; empty for the whole row
+	Field   string
+	Message string
+}
+
+// ImportJob is a bulk import of products and their auctions from a file.
+// Imports are all or nothing: a single invalid row fails the whole job.
+type ImportJob struct {
+	ID     string
+	UserID string
+	Format ImportFormat
+	// DryRun jobs only validate their rows
+	DryRun bool
+	Status ImportJobStatus
+	// TotalRows is the number of rows in the file; ProcessedRows the number
+	// validated so far
+	TotalRows     int
+	ProcessedRows int
+	// ProductCount and AuctionCount are the products and auctions the job
+	// created, or for dry runs, would create
+	ProductCount int
+	AuctionCount int
+	// Errors holds the first row errors; ErrorCount counts all of them
+	Errors     []ImportRowError
+	ErrorCount int
+	// Error explains why a job with valid rows failed
+	Error      string
+	CreatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// ImportLot is a product to import, with its auction unless the row had no
+// auction settings
+type ImportLot struct {
+	Product *Product
+	Auction *Auction
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrLotClosed is returned when an auctioneer acts on a live lot that has
+// already been sold or passed
+var ErrLotClosed = errors.New("lot has already been sold or passed")
+
+// LiveLotState is where a live lot stands in the sale room
+type LiveLotState string
+
+const (
+	// LiveLotOpen lots take bids at the asking price
+	LiveLotOpen LiveLotState = "open"
+	// LiveLotFairWarning lots are about to be sold; a new bid reopens them
+	LiveLotFairWarning LiveLotState = "fair_warning"
+	// LiveLotSold lots were hammered down to the highest bidder
+	LiveLotSold LiveLotState = "sold"
+	// LiveLotPassed lots were withdrawn unsold
+	LiveLotPassed LiveLotState = "passed"
+)
+
+// LiveAction is an auctioneer's action on a live lot, or a bid on it
+type LiveAction string
+
+const (
+	LiveActionOpened      LiveAction = "opened"
+	LiveActionAsked       LiveAction = "asked"
+	LiveActionBid         LiveAction = "bid"
+	LiveActionFairWarning LiveAction = "fair_warning"
+	LiveActionSold        LiveAction = "sold"
+	LiveActionPassed      LiveAction = "passed"
+)
+
+// LiveLot is the sale room state of a live auction. Bids, online or from the
+// floor, must offer at least AskingPrice; after each one the asking price
+// moves up to the auction's next ask until the auctioneer calls another.
+type LiveLot struct {
+	AuctionID string
+	// AuctioneerID is the user who opened the lot
+	AuctioneerID string
+	State        LiveLotState
+	AskingPrice  float64
+	// LastAction is what last changed the lot
+	LastAction LiveAction
+	OpenedAt   time.Time
+	UpdatedAt  time.Time
+}
+
+// IsOpen checks if the lot still takes bids
+func (l *LiveLot) IsOpen() bool {
+	return l.State == LiveLotOpen || l.State == LiveLotFairWarning
+}
+
+// Ask sets the asking price of an open lot, taking it out of fair warning
+func (l *LiveLot) Ask(amount float64, now time.Time) error {
+	return l.apply(LiveActionAsked, LiveLotOpen, amount, now)
+}
+
+// Bid records a bid on an open lot, reopening it and asking nextAsk
+func (l *LiveLot) Bid(nextAsk float64, now time.Time) error {
+	return l.apply(LiveActionBid, LiveLotOpen, nextAsk, now)
+}
+
+// FairWarning announces that an open lot is about to be sold
+func (l *LiveLot) FairWarning(now time.Time) error {
+	return l.apply(LiveActionFairWarning, LiveLotFairWarning, l.AskingPrice, now)
+}
+
+// Sell hammers an open lot down to the highest bidder
+func (l *LiveLot) Sell(now time.Time) error {
+	return l.apply(LiveActionSold, LiveLotSold, l.AskingPrice, now)
+}
+
+// Pass withdraws an open lot unsold
+func (l *LiveLot) Pass(now time.Time) error {
+	return l.apply(LiveActionPassed, LiveLotPassed, l.AskingPrice, now)
+}
+
+func (l *LiveLot) apply(action LiveAction, state LiveLotState, askingPrice float64, now time.Time) error {
+	if !l.IsOpen() {
+		return ErrLotClosed
+	}
+	l.State = state
+	l.AskingPrice = askingPrice
+	l.LastAction = action
+	l.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLiveLot(t *testing.T) {
+	now := time.Now()
+	lot := &LiveLot{State: LiveLotOpen, AskingPrice: 100}
+
+	if err := lot.FairWarning(now); err != nil || lot.State != LiveLotFairWarning {
+		t.Fatalf("FairWarning() = %v, state %s; want fair_warning", err, lot.State)
+	}
+	if err := lot.Bid(110, now); err != nil || lot.State != LiveLotOpen || lot.AskingPrice != 110 {
+		t.Fatalf("Bid() = %v, lot %+v; want it reopened asking 110", err, lot)
+	}
+	if err := lot.Sell(now); err != nil || lot.State != LiveLotSold || lot.LastAction != LiveActionSold {
+		t.Fatalf("Sell() = %v, lot %+v; want it sold", err, lot)
+	}
+
+	for name, action := range map[string]func(time.Time) error{
+		"Ask":  func(now time.Time) error { return lot.Ask(120, now) },
+		"Pass": lot.Pass,
+		"Sell": lot.Sell,
+	} {
+		if err := action(now); !errors.Is(err, ErrLotClosed) {
+			t.Errorf("%s() on a sold lot error = %v, want %v", name, err, ErrLotClosed)
+		}
+	}
+}
+
+func TestAuction_NextAsk(t *testing.T) {
+	tests := []struct {
+		name    string
+		auction Auction
+		want    float64
+	}{
+		{"without increment table", Auction{CurrentPrice: 100}, 101},
+		{"with increment table", Auction{CurrentPrice: 100, IncrementTable: []IncrementStep{{From: 0, Increment: 5}}}, 105},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.auction.NextAsk(); got != tt.want {
+				t.Errorf("NextAsk() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// OrderStatus is where the settlement of a won auction stands. Allowed
+// changes between statuses are listed in orderTransitions.
+type OrderStatus string
+
+const (
+	// OrderStatusAwaitingPayment orders were created when their auction closed
+	OrderStatusAwaitingPayment OrderStatus = "awaiting_payment"
+	OrderStatusPaid            OrderStatus = "paid"
+	OrderStatusShipped         OrderStatus = "shipped"
+	// OrderStatusCompleted orders were received by the buyer, settling their
+	// auction
+	OrderStatusCompleted OrderStatus = "completed"
+	OrderStatusCancelled OrderStatus = "cancelled"
+)
+
+// OrderParty is the side of an order a user is on
+type OrderParty string
+
+const (
+	OrderPartyBuyer  OrderParty = "buyer"
+	OrderPartySeller OrderParty = "seller"
+)
+
+// orderTransitions lists every allowed status change with the party who may
+// make it: the seller confirms payment, ships and cancels unpaid or unshipped
+// orders, and the buyer confirms receipt
+var orderTransitions = map[OrderStatus]map[OrderStatus]OrderParty{
+	OrderStatusAwaitingPayment: {
+		OrderStatusPaid:      OrderPartySeller,
+		OrderStatusCancelled: OrderPartySeller,
+	},
+	OrderStatusPaid: {
+		OrderStatusShipped:   OrderPartySeller,
+		OrderStatusCancelled: OrderPartySeller,
+	},
+	OrderStatusShipped: {
+		OrderStatusCompleted: OrderPartyBuyer,
+	},
+}
+
+// Order is what the winner of an auction owes its seller, created when the
+// auction closes
+type Order struct {
+	ID        string
+	AuctionID string
+	ProductID string
+	// BidID is the winning bid
+	BidID    string
+	BuyerID  string
+	SellerID string
+	// HammerPrice is the winning bid's amount
+	HammerPrice float64
+	// BuyerPremium is charged to the buyer on top of the hammer price
+	BuyerPremium float64
+	// SellerCommission is deducted from the seller's proceeds
+	SellerCommission float64
+	// Tax is charged to the buyer on the hammer price and buyer's premium
+	Tax float64
+	// Total is what the buyer owes: hammer price, buyer's premium and tax
+	Total float64
+	// Fees itemize the buyer's premium, seller commission and tax
+	Fees   []FeeItem
+	Status OrderStatus
+	// DueAt is when payment is due
+	DueAt     time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SellerProceeds returns what the seller receives once the buyer has paid
+func (o *Order) SellerProceeds() float64 {
+	return roundCents(o.HammerPrice - o.SellerCommission)
+}
+
+// PartyOf returns the side of the order userID is on, and false if the user
+// is neither its buyer nor its seller
+func (o *Order) PartyOf(userID string) (OrderParty, bool) {
+	switch userID {
+	case o.BuyerID:
+		return OrderPartyBuyer, true
+	case o.SellerID:
+		return OrderPartySeller, true
+	}
+	return "", false
+}
+
+// Transition moves the order to status to at now on behalf of party. It
+// returns an error matching ErrInvalidTransition if the order cannot move to
+// to, and ErrForbidden if the other party makes that change.
+func (o *Order) Transition(to OrderStatus, party OrderParty, now time.Time) error {
+	allowed, ok := orderTransitions[o.Status][to]
+	if !ok {
+		return fmt.Errorf("%w: cannot move a %s order to %s", ErrInvalidTransition, o.Status, to)
+	}
+	if party != allowed {
+		return fmt.Errorf("%w: only the %s can mark an order %s", ErrForbidden, allowed, to)
+	}
+	o.Status = to
+	o.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOrder_Transition(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    OrderStatus
+		to      OrderStatus
+		party   OrderParty
+		wantErr error
+	}{
+		{"seller confirms payment", OrderStatusAwaitingPayment, OrderStatusPaid, OrderPartySeller, nil},
+		{"buyer confirms payment", OrderStatusAwaitingPayment, OrderStatusPaid, OrderPartyBuyer, ErrForbidden},
+		{"seller cancels unpaid", OrderStatusAwaitingPayment, OrderStatusCancelled, OrderPartySeller, nil},
+		{"ship before payment", OrderStatusAwaitingPayment, OrderStatusShipped, OrderPartySeller, ErrInvalidTransition},
+		{"seller ships", OrderStatusPaid, OrderStatusShipped, OrderPartySeller, nil},
+		{"buyer receives", OrderStatusShipped, OrderStatusCompleted, OrderPartyBuyer, nil},
+		{"seller completes", OrderStatusShipped, OrderStatusCompleted, OrderPartySeller, ErrForbidden},
+		{"cancel shipped", OrderStatusShipped, OrderStatusCancelled, OrderPartySeller, ErrInvalidTransition},
+		{"reopen cancelled", OrderStatusCancelled, OrderStatusAwaitingPayment, OrderPartySeller, ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &Order{Status: tt.from}
+			err := order.Transition(tt.to, tt.party, time.Now())
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Transition() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && order.Status != tt.to {
+				t.Errorf("status = %s, want %s", order.Status, tt.to)
+			}
+			if tt.wantErr != nil && order.Status != tt.from {
+				t.Errorf("status = %s after a refused change, want %s", order.Status, tt.from)
+			}
+		})
+	}
+}
+
+func TestOrder_PartyOf(t *testing.T) {
+	order := &Order{BuyerID: "user-1", SellerID: "seller-1"}
+	if party, ok := order.PartyOf("user-1"); !ok || party != OrderPartyBuyer {
+		t.Errorf("PartyOf(buyer) = %s, %v", party, ok)
+	}
+	if party, ok := order.PartyOf("seller-1"); !ok || party != OrderPartySeller {
+		t.Errorf("PartyOf(seller) = %s, %v", party, ok)
+	}
+	if _, ok := order.PartyOf("user-2"); ok {
+		t.Error("PartyOf(stranger) reported a party")
+	}
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrPreBidsClosed is returned when pre-bids are placed, changed or withdrawn
+// after the auction has opened
+var ErrPreBidsClosed = errors.New("pre-bids are closed: the auction has opened")
+
+// PreBid is an absentee bid left on an auction before it opens. When the
+// auction opens, pre-bids are executed as proxy bids up to MaxAmount, so the
+// strongest one opens the bidding.
+type PreBid struct {
+	ID        string
+	AuctionID string
+	UserID    string
+	MaxAmount float64
+	// ExecutedAt is when the auction opened and the pre-bid was executed
+	ExecutedAt *time.Time
+	CreatedAt  time.Time
+	// UpdatedAt is when MaxAmount was last set, which decides ties
+	UpdatedAt time.Time
+}
+
+// AcceptsPreBids checks if the auction has not opened yet, so pre-bids can be
+// placed, changed and withdrawn
+func (a *Auction) AcceptsPreBids() bool {
+	return a.Status == AuctionStatusPending || a.Status == AuctionStatusScheduled
+}
@@ -1,14 +1,63 @@
 package domain
 
 import (
+	"errors"
 	"time"
 )
 
+var (
+	// ErrNotProductOwner is returned when someone other than the owner
+	// changes a product
+	ErrNotProductOwner = errors.New("only the owner can change this product")
+	// ErrProductNotEditable is returned when a product's status or auctions
+	// no longer allow the requested change
+	ErrProductNotEditable = errors.New("product can no longer be changed")
+)
+
+// ProductStatus tells listed products from those their owner withdrew
+type ProductStatus string
+
+const (
+	ProductStatusActive ProductStatus = "active"
+	// ProductStatusArchived products are left out of lists and search unless
+	// asked for, and cannot be auctioned until they are restored
+	ProductStatusArchived ProductStatus = "archived"
+	// ProductStatusDeleted products are left out of every list and search but
+	// can still be looked up by ID, so their past auctions and bids keep
+	// showing what was sold
+	ProductStatusDeleted ProductStatus = "deleted"
+)
+
+// CanChangeTo checks if a product in status s may move to status to. Active
+// products may be archived or deleted, archived ones deleted or restored, and
+// deleted ones restored.
+func (s ProductStatus) CanChangeTo(to ProductStatus) bool {
+	switch s {
+	case ProductStatusActive:
+		return to == ProductStatusArchived || to == ProductStatusDeleted
+	case ProductStatusArchived:
+		return to == ProductStatusDeleted || to == ProductStatusActive
+	case ProductStatusDeleted:
+		return to == ProductStatusActive
+	}
+	return false
+}
+
 // Product represents a product that can be auctioned
 type Product struct {
 	ID          string
 	Name        string
 	Description string
 	OwnerID     string
-	CreatedAt   time.Time
+	// CategoryID is empty for uncategorized products
+	CategoryID string
+	// Condition is empty when the owner did not state it
+	Condition ProductCondition
+	// Attributes holds the values of the category's attributes by name
+	Attributes map[string]string
+	Status     ProductStatus
+	// Version increases by one with every update of the product, which only
+	// succeeds if the stored version still matches
+	Version   int
+	CreatedAt time.Time
 }
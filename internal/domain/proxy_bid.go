@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProxyBidIncrement is the step by which proxy bids outbid competing bids
+const ProxyBidIncrement = 1.00
+
+// ProxyBid is a bidder's standing instruction to bid on their behalf, one
+// increment at a time, up to MaxAmount
+type ProxyBid struct {
+	ID        string
+	AuctionID string
+	UserID    string
+	MaxAmount float64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
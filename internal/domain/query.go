@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Page sizes of list endpoints
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// SortNewest names the newest-first order, the only order of product and bid
+// lists and the default order of auction lists
+const SortNewest = "newest"
+
+// NewestCursor returns the position of an item in a newest-first list
+func NewestCursor(createdAt time.Time, id string) *Cursor {
+	return &Cursor{Sort: SortNewest, Time: createdAt, ID: id}
+}
+
+// AuctionSort orders auction lists. Every order breaks ties by ID, so pages
+// never skip or repeat auctions that share a sort key.
+type AuctionSort string
+
+const (
+	// AuctionSortNewest lists the most recently created auctions first
+	AuctionSortNewest AuctionSort = SortNewest
+	// AuctionSortEndingSoonest lists auctions by end time, earliest first
+	AuctionSortEndingSoonest AuctionSort = "ending_soonest"
+	// AuctionSortHighestPrice lists auctions by current price, highest first
+	AuctionSortHighestPrice AuctionSort = "highest_price"
+	// AuctionSortMostBids lists auctions by number of bids, most first
+	AuctionSortMostBids AuctionSort = "most_bids"
+)
+
+// Valid checks if s is a known sort order
+func (s AuctionSort) Valid() bool {
+	switch s {
+	case AuctionSortNewest, AuctionSortEndingSoonest, AuctionSortHighestPrice, AuctionSortMostBids:
+		return true
+	}
+	return false
+}
+
+// Cursor returns the position of auction in a list sorted by s
+func (s AuctionSort) Cursor(auction *Auction) *Cursor {
+	switch s {
+	case AuctionSortEndingSoonest:
+		return &Cursor{Sort: string(s), Time: auction.EndTime, ID: auction.ID}
+	case AuctionSortHighestPrice:
+		return &Cursor{Sort: string(s), Value: auction.CurrentPrice, ID: auction.ID}
+	case AuctionSortMostBids:
+		return &Cursor{Sort: string(s), Value: float64(auction.BidCount), ID: auction.ID}
+	}
+	return NewestCursor(auction.CreatedAt, auction.ID)
+}
+
+// AuctionQuery filters, sorts and pages an auction list. Zero-valued filters
+// match every auction.
+type AuctionQuery struct {
+	Status    AuctionStatus
+	ProductID string
+	// OwnerID matches auctions of products owned by this user
+	OwnerID string
+	// MinPrice and MaxPrice bound the current price, inclusive
+	MinPrice *float64
+	MaxPrice *float64
+	// EndingAfter and EndingBefore bound the end time, exclusive
+	EndingAfter  *time.Time
+	EndingBefore *time.Time
+	// Sort defaults to AuctionSortNewest
+	Sort AuctionSort
+	// Limit caps the number of auctions returned; zero returns all of them
+	Limit int
+	// After continues the list after the auction it points to
+	After *Cursor
+}
+
+// ProductQuery filters and pages a product list, newest first
+type ProductQuery struct {
+	OwnerID string
+	// CategoryIDs matches products in any of these categories
+	CategoryIDs []string
+	// Attributes matches products having all of these attribute values
+	Attributes map[string]string
+	// Only active products match unless archived or deleted ones are included
+	IncludeArchived bool
+	IncludeDeleted  bool
+	// Limit caps the number of products returned; zero returns all of them
+	Limit int
+	// After continues the list after the product it points to
+	After *Cursor
+}
+
+// CatalogQuery filters and pages a catalog list, newest first
+type CatalogQuery struct {
+	OwnerID string
+	// Limit caps the number of catalogs returned; zero returns all of them
+	Limit int
+	// After continues the list after the catalog it points to
+	After *Cursor
+}
+
+// OrderQuery filters and pages a user's orders, newest first
+type OrderQuery struct {
+	// UserID is the buyer or seller of the orders
+	UserID string
+	// Party, when set, only returns the orders where the user is on that side
+	Party  OrderParty
+	Status OrderStatus
+	// Limit caps the number of orders returned; zero returns all of them
+	Limit int
+	// After continues the list after the order it points to
+	After *Cursor
+}
+
+// BidQuery pages an auction's bids, newest first
+type BidQuery struct {
+	// Limit caps the number of bids returned; zero returns all of them
+	Limit int
+	// After continues the list after the bid it points to
+	After *Cursor
+}
+
+// Cursor is the position of an item in a keyset-paginated list: the item's
+// sort key, either a time or a number, and its ID, which breaks ties. Clients
+// only see it as the opaque string returned by Encode.
+type Cursor struct {
+	// Sort names the list order the cursor belongs to
+	Sort  string
+	Time  time.Time
+	Value float64
+	ID    string
+}
+
+// cursorSeparator separates the fields of an encoded cursor
+const cursorSeparator = "|"
+
+// Encode returns the cursor as an opaque, URL-safe string
+func (c *Cursor) Encode() string {
+	key := "n" + strconv.FormatFloat(c.Value, 'f', -1, 64)
+	if !c.Time.IsZero() {
+		key = "t" + c.Time.UTC().Format(time.RFC3339Nano)
+	}
+	raw := strings.Join([]string{c.Sort, key, c.ID}, cursorSeparator)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor returned by Encode for a list sorted by sort.
+// An empty string decodes to nil, the start of the list.
+func DecodeCursor(encoded, sort string) (*Cursor, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrValidation)
+	}
+	parts := strings.Split(string(raw), cursorSeparator)
+	if len(parts) != 3 || len(parts[1]) < 2 || parts[2] == "" {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrValidation)
+	}
+	if parts[0] != sort {
+		return nil, fmt.Errorf("%w: cursor belongs to a list sorted by %s", ErrValidation, parts[0])
+	}
+
+	cursor := &Cursor{Sort: parts[0], ID: parts[2]}
+	switch key := parts[1]; key[0] {
+	case 't':
+		cursor.Time, err = time.Parse(time.RFC3339Nano, key[1:])
+	case 'n':
+		cursor.Value, err = strconv.ParseFloat(key[1:], 64)
+	default:
+		err = fmt.Errorf("unknown key kind %q", key[0])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrValidation)
+	}
+	return cursor, nil
+}
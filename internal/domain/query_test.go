@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	created := time.Date(2026, 3, 1, 10, 0, 0, 123456000, time.UTC)
+	cursors := []*Cursor{
+		NewestCursor(created, "550e8400-e29b-41d4-a716-446655440000"),
+		{Sort: string(AuctionSortHighestPrice), Value: 149.99, ID: "550e8400-e29b-41d4-a716-446655440000"},
+		{Sort: string(AuctionSortMostBids), Value: 0, ID: "550e8400-e29b-41d4-a716-446655440000"},
+	}
+
+	for _, want := range cursors {
+		got, err := DecodeCursor(want.Encode(), want.Sort)
+		if err != nil {
+			t.Fatalf("DecodeCursor(%+v) unexpected error: %v", want, err)
+		}
+		if !got.Time.Equal(want.Time) || got.Value != want.Value || got.ID != want.ID || got.Sort != want.Sort {
+			t.Errorf("DecodeCursor() = %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	if cursor, err := DecodeCursor("", SortNewest); cursor != nil || err != nil {
+		t.Errorf("DecodeCursor(\"\") = %v, %v; want the start of the list", cursor, err)
+	}
+
+	newest := NewestCursor(time.Now(), "550e8400-e29b-41d4-a716-446655440000").Encode()
+	for _, encoded := range []string{"%%%", "bm90IGEgY3Vyc29y", newest} {
+		if _, err := DecodeCursor(encoded, string(AuctionSortEndingSoonest)); !errors.Is(err, ErrValidation) {
+			t.Errorf("DecodeCursor(%q) error = %v, want %v", encoded, err, ErrValidation)
+		}
+	}
+}
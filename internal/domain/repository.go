@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 // UserRepository defines the interface for user data operations
@@ -9,26 +10,262 @@ type UserRepository interface {
 	Create(ctx context.Context, user *User) error
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	GetByID(ctx context.Context, id string) (*User, error)
+	Update(ctx context.Context, user *User) error
+	// UpdateProfile replaces the user's public profile, leaving the account's
+	// other fields as they are
+	UpdateProfile(ctx context.Context, userID string, profile Profile) error
+	// IncrementBidRetractions counts one more approved retraction of the user
+	IncrementBidRetractions(ctx context.Context, userID string) error
+}
+
+// UserTokenRepository defines the interface for single-use user token operations
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *UserToken) error
+	GetByHash(ctx context.Context, purpose TokenPurpose, tokenHash string) (*UserToken, error)
+	// MarkUsed atomically redeems a token; it fails if the token was already used
+	MarkUsed(ctx context.Context, id string) error
+	// DeleteByUser removes all outstanding tokens of a purpose for a user
+	DeleteByUser(ctx context.Context, userID string, purpose TokenPurpose) error
+}
+
+// TwoFactorRepository defines the interface for TOTP enrollment and recovery code operations
+type TwoFactorRepository interface {
+	// GetByUserID returns nil without error when the user has no enrollment
+	GetByUserID(ctx context.Context, userID string) (*TwoFactor, error)
+	Save(ctx context.Context, tf *TwoFactor) error
+	// Delete removes the enrollment together with its recovery codes
+	Delete(ctx context.Context, userID string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codes []*RecoveryCode) error
+	// UseRecoveryCode atomically redeems an unused code; it fails if no such code exists
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) error
+}
+
+// LoginAttemptRepository defines the interface for failed login tracking
+type LoginAttemptRepository interface {
+	// Get returns nil without error when the key has no recorded failures
+	Get(ctx context.Context, key string) (*LoginAttempt, error)
+	// RecordFailure atomically increments the failure count, restarting it if the
+	// previous failure is older than window, and returns the updated attempt
+	RecordFailure(ctx context.Context, key string, window time.Duration) (*LoginAttempt, error)
+	Save(ctx context.Context, attempt *LoginAttempt) error
+	Delete(ctx context.Context, key string) error
+}
+
+// SecurityEventRepository defines the interface for security audit events
+type SecurityEventRepository interface {
+	Create(ctx context.Context, event *SecurityEvent) error
 }
 
 // ProductRepository defines the interface for product data operations
 type ProductRepository interface {
 	Create(ctx context.Context, product *Product) error
 	GetByID(ctx context.Context, id string) (*Product, error)
-	List(ctx context.Context) ([]*Product, error)
+	// List returns the products matching query, newest first
+	List(ctx context.Context, query ProductQuery) ([]*Product, error)
+	// Update stores the product only if its stored version still equals
+	// product.Version, then increments product.Version. It returns a
+	// *ConflictError if the product was updated in the meantime.
+	Update(ctx context.Context, product *Product) error
+}
+
+// CategoryRepository defines the interface for category data operations
+type CategoryRepository interface {
+	Create(ctx context.Context, category *Category) error
+	GetByID(ctx context.Context, id string) (*Category, error)
+	// List returns every category, by name
+	List(ctx context.Context) ([]*Category, error)
+	Update(ctx context.Context, category *Category) error
+	// Delete returns ErrCategoryInUse if the category still has subcategories
+	// or products
+	Delete(ctx context.Context, id string) error
+}
+
+// AttachmentRepository stores the records of product images and documents;
+// their files are kept in a blob store
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *Attachment) error
+	GetByID(ctx context.Context, id string) (*Attachment, error)
+	// ListByProducts returns the attachments of the given products, by
+	// product, kind and position
+	ListByProducts(ctx context.Context, productIDs []string) ([]*Attachment, error)
+	// UpdateOrder stores the Position and Primary fields of attachments of
+	// one product, all at once
+	UpdateOrder(ctx context.Context, attachments []*Attachment) error
+	Delete(ctx context.Context, id string) error
+}
+
+// ImportRepository stores bulk import jobs and the lots they create
+type ImportRepository interface {
+	CreateJob(ctx context.Context, job *ImportJob) error
+	GetJob(ctx context.Context, id string) (*ImportJob, error)
+	// UpdateJob stores the progress and result of a job
+	UpdateJob(ctx context.Context, job *ImportJob) error
+	// CreateLots stores the products of lots and their auctions in one
+	// transaction, so either all of them are created or none
+	CreateLots(ctx context.Context, lots []*ImportLot) error
+}
+
+// SearchRepository runs full-text searches over products
+type SearchRepository interface {
+	// Search returns up to query.Limit hits, best match first, with the total
+	// number of hits and the facets of the search text
+	Search(ctx context.Context, query SearchQuery) (*SearchResults, error)
 }
 
 // AuctionRepository defines the interface for auction data operations
 type AuctionRepository interface {
 	Create(ctx context.Context, auction *Auction) error
 	GetByID(ctx context.Context, id string) (*Auction, error)
-	List(ctx context.Context) ([]*Auction, error)
+	// List returns the auctions matching query in the order it asks for
+	List(ctx context.Context, query AuctionQuery) ([]*Auction, error)
+	// ListExpired returns active auctions whose end time is not after now
+	ListExpired(ctx context.Context, now time.Time) ([]*Auction, error)
+	// ListDueToStart returns scheduled auctions whose start time is not after now
+	ListDueToStart(ctx context.Context, now time.Time) ([]*Auction, error)
+	// Update stores the auction only if its stored version still equals
+	// auction.Version, then increments auction.Version. It returns a
+	// *ConflictError if the auction was updated in the meantime.
 	Update(ctx context.Context, auction *Auction) error
 }
 
+// CatalogRepository stores catalogs and their lots
+type CatalogRepository interface {
+	Create(ctx context.Context, catalog *Catalog) error
+	GetByID(ctx context.Context, id string) (*Catalog, error)
+	// List returns the catalogs matching query, newest first
+	List(ctx context.Context, query CatalogQuery) ([]*Catalog, error)
+	// ListLive returns the catalogs with an active or paused lot
+	ListLive(ctx context.Context) ([]*Catalog, error)
+	// AddLot stores a lot under its number. It fails with ErrConflict if the
+	// catalog already has a lot of that number or the auction is a lot already.
+	AddLot(ctx context.Context, lot *CatalogLot) error
+	// RemoveLot deletes a lot and moves every later lot of the catalog up by
+	// one number
+	RemoveLot(ctx context.Context, catalogID, auctionID string) error
+	// GetLotByAuction returns the lot of an auction, wherever it is
+	GetLotByAuction(ctx context.Context, auctionID string) (*CatalogLot, error)
+	// ListLots returns the lots of a catalog with their auctions, by number
+	ListLots(ctx context.Context, catalogID string) ([]*CatalogLot, error)
+}
+
+// AuctionChangeRepository stores the change log of auction settings
+type AuctionChangeRepository interface {
+	Create(ctx context.Context, change *AuctionChange) error
+	// ListByAuction returns an auction's changes, oldest first
+	ListByAuction(ctx context.Context, auctionID string) ([]*AuctionChange, error)
+}
+
+// AuctionStatusChangeRepository stores the status history of auctions
+type AuctionStatusChangeRepository interface {
+	Create(ctx context.Context, change *AuctionStatusChange) error
+	// ListByAuction returns an auction's status changes, oldest first
+	ListByAuction(ctx context.Context, auctionID string) ([]*AuctionStatusChange, error)
+}
+
 // BidRepository defines the interface for bid data operations
 type BidRepository interface {
 	Create(ctx context.Context, bid *Bid) error
-	GetByAuctionID(ctx context.Context, auctionID string) ([]*Bid, error)
+	GetByID(ctx context.Context, id string) (*Bid, error)
+	// GetByAuctionID returns a page of an auction's bids, newest first,
+	// retracted bids included
+	GetByAuctionID(ctx context.Context, auctionID string, query BidQuery) ([]*Bid, error)
+	// GetHighestBid returns the highest bid that has not been retracted, or
+	// nil if there is none
 	GetHighestBid(ctx context.Context, auctionID string) (*Bid, error)
+	// Retract marks the bid withdrawn at at; retracting it again keeps the
+	// first time
+	Retract(ctx context.Context, id string, at time.Time) error
+}
+
+// ProxyBidRepository defines the interface for proxy bid operations
+type ProxyBidRepository interface {
+	// Save creates the user's proxy bid for the auction or replaces its maximum
+	Save(ctx context.Context, proxy *ProxyBid) error
+	// GetByAuctionID returns proxy bids ordered by maximum (highest first), ties
+	// broken by the earliest proxy
+	GetByAuctionID(ctx context.Context, auctionID string) ([]*ProxyBid, error)
+	// Delete removes the user's proxy bid for the auction, if any
+	Delete(ctx context.Context, auctionID, userID string) error
+}
+
+// RetractionRepository defines the interface for bid retraction operations
+type RetractionRepository interface {
+	// Create fails with ErrConflict if the bid already has a pending retraction
+	Create(ctx context.Context, retraction *BidRetraction) error
+	GetByID(ctx context.Context, id string) (*BidRetraction, error)
+	// Update stores the review of a pending retraction. It fails with
+	// ErrConflict if the retraction has already been reviewed.
+	Update(ctx context.Context, retraction *BidRetraction) error
+	// ListByUser returns the user's retractions, newest first
+	ListByUser(ctx context.Context, userID string) ([]*BidRetraction, error)
+	// ListPending returns the retractions awaiting review, oldest first
+	ListPending(ctx context.Context) ([]*BidRetraction, error)
+}
+
+// PreBidRepository defines the interface for pre-bid operations
+type PreBidRepository interface {
+	// Save creates the user's pre-bid for the auction or replaces its maximum.
+	// It fails with ErrPreBidsClosed if the pre-bid has been executed.
+	Save(ctx context.Context, preBid *PreBid) error
+	GetByUser(ctx context.Context, auctionID, userID string) (*PreBid, error)
+	// Delete removes the user's pre-bid unless it has been executed
+	Delete(ctx context.Context, auctionID, userID string) error
+	// ListPending returns the auction's pre-bids that have not been executed,
+	// highest maximum first, ties broken by the maximum set earliest. Within a
+	// transaction they stay locked until it ends, so a concurrent caller only
+	// sees the pre-bids it leaves pending.
+	ListPending(ctx context.Context, auctionID string) ([]*PreBid, error)
+	// MarkExecuted marks the pre-bids of the auction with the given IDs
+	// executed at now, unless they already are
+	MarkExecuted(ctx context.Context, auctionID string, ids []string, now time.Time) error
+	// ListDue returns the IDs of active auctions with pending pre-bids
+	ListDue(ctx context.Context) ([]string, error)
+}
+
+// LiveLotRepository stores the sale room state of live auctions
+type LiveLotRepository interface {
+	// Create fails with ErrConflict if the auction already has a live lot
+	Create(ctx context.Context, lot *LiveLot) error
+	GetByAuction(ctx context.Context, auctionID string) (*LiveLot, error)
+	Update(ctx context.Context, lot *LiveLot) error
+}
+
+// OrderRepository stores the orders created when auctions close
+type OrderRepository interface {
+	// Create fails with ErrConflict if the auction already has an order
+	Create(ctx context.Context, order *Order) error
+	GetByID(ctx context.Context, id string) (*Order, error)
+	GetByAuction(ctx context.Context, auctionID string) (*Order, error)
+	// List returns the orders matching query, newest first
+	List(ctx context.Context, query OrderQuery) ([]*Order, error)
+	Update(ctx context.Context, order *Order) error
+	// ListUnsettled returns the IDs of ended auctions without an order whose
+	// winning bid was placed online
+	ListUnsettled(ctx context.Context) ([]string, error)
+}
+
+// AuctionBidderRepository defines the interface for per-auction bidder pseudonyms
+type AuctionBidderRepository interface {
+	// Assign returns the user's bidder number in the auction, assigning the next
+	// free number on their first bid
+	Assign(ctx context.Context, auctionID, userID string) (int, error)
+	// ListByAuction returns bidder numbers keyed by user ID
+	ListByAuction(ctx context.Context, auctionID string) (map[string]int, error)
+}
+
+// AuctionEventRepository defines the interface for the persistent auction event log
+type AuctionEventRepository interface {
+	// Append stores the event under the auction's next sequence number and sets
+	// event.Sequence
+	Append(ctx context.Context, event *AuctionEvent) error
+	// ListAfter returns up to limit events with a sequence greater than afterSeq, in order
+	ListAfter(ctx context.Context, auctionID string, afterSeq int64, limit int) ([]*AuctionEvent, error)
+}
+
+// Transactor runs a unit of work against several repositories atomically
+type Transactor interface {
+	// WithinTx calls fn with a context carrying a transaction, committing it if
+	// fn returns nil and rolling it back otherwise. Repository calls made with
+	// that context join the transaction, as does a nested WithinTx.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
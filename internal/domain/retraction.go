@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrRetractionNotAllowed is returned when a bid may not be retracted,
+	// such as in the final hour of its auction
+	ErrRetractionNotAllowed = errors.New("bid cannot be retracted")
+	// ErrRetractionReviewed is returned when reviewing a retraction that has
+	// already been approved or rejected
+	ErrRetractionReviewed = errors.New("retraction has already been reviewed")
+)
+
+// RetractionCutoff is how long before its auction ends a bid can no longer be
+// retracted
+const RetractionCutoff = time.Hour
+
+// RetractionReason is why a bidder asks to retract a bid
+type RetractionReason string
+
+const (
+	// RetractionReasonWrongAmount bids were entered with a typo, such as
+	// 10000 instead of 100.00
+	RetractionReasonWrongAmount RetractionReason = "wrong_amount"
+	// RetractionReasonDescriptionChanged bids no longer fit the item, whose
+	// description changed significantly after the bid
+	RetractionReasonDescriptionChanged RetractionReason = "description_changed"
+	// RetractionReasonSellerUnreachable bids were placed with a seller who
+	// cannot be contacted
+	RetractionReasonSellerUnreachable RetractionReason = "seller_unreachable"
+)
+
+// IsValid checks if r is one of the allowed reasons
+func (r RetractionReason) IsValid() bool {
+	switch r {
+	case RetractionReasonWrongAmount, RetractionReasonDescriptionChanged, RetractionReasonSellerUnreachable:
+		return true
+	}
+	return false
+}
+
+// RetractionStatus is where a retraction request stands in review
+type RetractionStatus string
+
+const (
+	RetractionStatusPending  RetractionStatus = "pending"
+	RetractionStatusApproved RetractionStatus = "approved"
+	RetractionStatusRejected RetractionStatus = "rejected"
+)
+
+// BidRetraction is a bidder's request to withdraw one of their bids, which
+// takes effect once an admin approves it
+type BidRetraction struct {
+	ID        string
+	BidID     string
+	AuctionID string
+	UserID    string
+	Reason    RetractionReason
+	Note      string
+	Status    RetractionStatus
+	// ReviewerID and ReviewNote are set once an admin has reviewed the request
+	ReviewerID string
+	ReviewNote string
+	CreatedAt  time.Time
+	ReviewedAt *time.Time
+}
+
+// Review approves or rejects a pending retraction at now
+func (r *BidRetraction) Review(approve bool, reviewerID, note string, now time.Time) error {
+	if r.Status != RetractionStatusPending {
+		return fmt.Errorf("%w: it was %s", ErrRetractionReviewed, r.Status)
+	}
+	r.Status = RetractionStatusRejected
+	if approve {
+		r.Status = RetractionStatusApproved
+	}
+	r.ReviewerID = reviewerID
+	r.ReviewNote = note
+	r.ReviewedAt = &now
+	return nil
+}
+
+// CheckRetractable checks that bids on the auction can still be retracted at
+// now: it is running, is not run live by an auctioneer, and is not in its
+// final RetractionCutoff
+func (a *Auction) CheckRetractable(now time.Time) error {
+	switch {
+	case a.Status != AuctionStatusActive && a.Status != AuctionStatusPaused:
+		return fmt.Errorf("%w: the auction is %s", ErrRetractionNotAllowed, a.Status)
+	case a.Live:
+		return fmt.Errorf("%w: bids on live auctions are settled by the auctioneer", ErrRetractionNotAllowed)
+	case a.Remaining(now) <= RetractionCutoff:
+		return fmt.Errorf("%w: the auction ends within %.0f minutes", ErrRetractionNotAllowed, RetractionCutoff.Minutes())
+	}
+	return nil
+}
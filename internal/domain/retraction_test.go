@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAuction_CheckRetractable(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		auction Auction
+		wantErr bool
+	}{
+		{"active", Auction{Status: AuctionStatusActive, EndTime: now.Add(2 * time.Hour)}, false},
+		{"paused", Auction{Status: AuctionStatusPaused, EndTime: now.Add(2 * time.Hour)}, false},
+		{"final hour", Auction{Status: AuctionStatusActive, EndTime: now.Add(RetractionCutoff)}, true},
+		{"ended", Auction{Status: AuctionStatusEnded, EndTime: now.Add(-time.Hour)}, true},
+		{"scheduled", Auction{Status: AuctionStatusScheduled, EndTime: now.Add(2 * time.Hour)}, true},
+		{"live", Auction{Status: AuctionStatusActive, Live: true, EndTime: now.Add(2 * time.Hour)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.auction.CheckRetractable(now)
+			if tt.wantErr != errors.Is(err, ErrRetractionNotAllowed) || (!tt.wantErr && err != nil) {
+				t.Errorf("CheckRetractable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBidRetraction_Review(t *testing.T) {
+	now := time.Now()
+	retraction := &BidRetraction{Status: RetractionStatusPending}
+
+	if err := retraction.Review(false, "admin-1", "bid stands", now); err != nil {
+		t.Fatalf("Review() unexpected error: %v", err)
+	}
+	if retraction.Status != RetractionStatusRejected || retraction.ReviewerID != "admin-1" || retraction.ReviewedAt == nil {
+		t.Errorf("reviewed retraction = %+v, want rejected by admin-1", retraction)
+	}
+	if err := retraction.Review(true, "admin-2", "", now); !errors.Is(err, ErrRetractionReviewed) {
+		t.Errorf("second Review() error = %v, want %v", err, ErrRetractionReviewed)
+	}
+}
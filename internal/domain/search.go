@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SortRelevance names the order of search results: best match first
+const SortRelevance = "relevance"
+
+// PriceBucketBounds are the upper bounds of the price facet's buckets; the
+// last bucket has no upper bound
+var PriceBucketBounds = []float64{50, 100, 500, 1000}
+
+// SearchQuery is a full-text search over product names and descriptions.
+// Zero-valued filters match every product.
+type SearchQuery struct {
+	// Text is matched word by word; the last word also matches as a prefix,
+	// so results follow the user while they type
+	Text string
+	// Status matches products whose latest auction is in this status
+	Status AuctionStatus
+	// CategoryIDs matches products in any of these categories
+	CategoryIDs []string
+	// MinPrice and MaxPrice bound the current price of the latest auction,
+	// inclusive
+	MinPrice *float64
+	MaxPrice *float64
+	// Limit caps the number of hits returned; zero returns all of them
+	Limit int
+	// After continues the results after the hit it points to
+	After *Cursor
+}
+
+// SearchHit is a product matching a search with the product's latest auction
+type SearchHit struct {
+	Product *Product
+	// Auction is the product's most recently created auction; nil if the
+	// product was never auctioned
+	Auction *Auction
+	// Rank is higher for better matches; name matches weigh more than
+	// description matches
+	Rank float64
+	// NameHighlight and Snippet are the name and the best matching fragments
+	// of the description as HTML-escaped text, with matched words wrapped in
+	// <mark> tags
+	NameHighlight string
+	Snippet       string
+}
+
+// Cursor returns the position of the hit in search results
+func (h *SearchHit) Cursor() *Cursor {
+	return &Cursor{Sort: SortRelevance, Value: h.Rank, ID: h.Product.ID}
+}
+
+// SearchFacets counts all products matching the search text, ignoring the
+// status, category and price filters, so clients can show how narrowing
+// would change the results
+type SearchFacets struct {
+	// Statuses counts products by the status of their latest auction
+	Statuses map[AuctionStatus]int
+	// PriceBuckets counts auctioned products by current price, one entry per
+	// bucket of PriceBucketBounds
+	PriceBuckets []PriceBucket
+	// Categories counts products by their category ID; uncategorized
+	// products are not counted
+	Categories map[string]int
+}
+
+// PriceBucket is the number of products priced from Min up to, but not
+// including, Max. Max is nil for the last bucket.
+type PriceBucket struct {
+	Min   float64
+	Max   *float64
+	Count int
+}
+
+// NewSearchFacets returns facets with nothing counted
+func NewSearchFacets() SearchFacets {
+	return SearchFacets{
+		Statuses:     map[AuctionStatus]int{},
+		PriceBuckets: NewPriceBuckets(),
+		Categories:   map[string]int{},
+	}
+}
+
+// NewPriceBuckets returns the empty buckets of PriceBucketBounds
+func NewPriceBuckets() []PriceBucket {
+	buckets := make([]PriceBucket, 0, len(PriceBucketBounds)+1)
+	lower := 0.0
+	for _, bound := range PriceBucketBounds {
+		upper := bound
+		buckets = append(buckets, PriceBucket{Min: lower, Max: &upper})
+		lower = bound
+	}
+	return append(buckets, PriceBucket{Min: lower})
+}
+
+// PriceBucketIndex returns the index of the bucket holding price
+func PriceBucketIndex(price float64) int {
+	for i, bound := range PriceBucketBounds {
+		if price < bound {
+			return i
+		}
+	}
+	return len(PriceBucketBounds)
+}
+
+// SearchResults is a page of search hits with the total number of hits and
+// the facets of the search
+type SearchResults struct {
+	Hits   []*SearchHit
+	Total  int
+	Facets SearchFacets
+}
+
+// SearchTerms splits search text into lower-case words of letters and
+// digits, dropping punctuation and operators
+func SearchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
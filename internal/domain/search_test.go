@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	got := SearchTerms("  Gaming-Laptop & RTX4090!! ")
+	want := []string{"gaming", "laptop", "rtx4090"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchTerms() = %v, want %v", got, want)
+	}
+}
+
+func TestPriceBuckets(t *testing.T) {
+	buckets := NewPriceBuckets()
+	if len(buckets) != len(PriceBucketBounds)+1 || buckets[len(buckets)-1].Max != nil {
+		t.Fatalf("NewPriceBuckets() = %+v, want one more bucket than bounds, the last open-ended", buckets)
+	}
+
+	for _, price := range []float64{0, 49.99, 50, 999.99, 1000, 25000} {
+		bucket := buckets[PriceBucketIndex(price)]
+		if price < bucket.Min || (bucket.Max != nil && price >= *bucket.Max) {
+			t.Errorf("PriceBucketIndex(%v) picked bucket %v-%v", price, bucket.Min, bucket.Max)
+		}
+	}
+}
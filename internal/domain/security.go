@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// SecurityEventType classifies security-relevant events
+type SecurityEventType string
+
+const (
+	SecurityEventAccountLocked SecurityEventType = "account_locked"
+	SecurityEventIPLocked      SecurityEventType = "ip_locked"
+)
+
+// SecurityEvent is an audit record of a security-relevant occurrence
+type SecurityEvent struct {
+	ID        string
+	Type      SecurityEventType
+	UserID    string // empty when the event is not tied to a known user
+	IPAddress string
+	Details   string
+	CreatedAt time.Time
+}
+
+// LoginAttempt tracks recent failed logins for a throttling key (an account or an IP address)
+type LoginAttempt struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	NextAttemptAt *time.Time // progressive delay: attempts before this time are rejected
+	LockedUntil   *time.Time // temporary lockout after too many failures
+}
+
+// IsLocked checks if the key is temporarily locked out
+func (a *LoginAttempt) IsLocked() bool {
+	return a.LockedUntil != nil && time.Now().Before(*a.LockedUntil)
+}
+
+// RetryAfter returns how long the caller must wait before the next attempt, or zero
+func (a *LoginAttempt) RetryAfter() time.Duration {
+	now := time.Now()
+	if a.LockedUntil != nil && now.Before(*a.LockedUntil) {
+		return a.LockedUntil.Sub(now)
+	}
+	if a.NextAttemptAt != nil && now.Before(*a.NextAttemptAt) {
+		return a.NextAttemptAt.Sub(now)
+	}
+	return 0
+}
+
+// LoginThrottledError is returned when login attempts are rejected because of
+// too many recent failures
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("too many failed login attempts, temporarily locked for %s", e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("too many failed login attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidToken is returned when a user token is unknown, expired or already used
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// TokenPurpose identifies what a single-use user token may be redeemed for
+type TokenPurpose string
+
+const (
+	TokenPurposeEmailVerification TokenPurpose = "email_verification"
+	TokenPurposePasswordReset     TokenPurpose = "password_reset"
+	// TokenPurposeStreamTicket authenticates one real-time connection from a
+	// browser, which cannot set an Authorization header on EventSource or WebSocket
+	TokenPurposeStreamTicket TokenPurpose = "stream_ticket"
+)
+
+// UserToken is a single-use, expiring token issued to a user.
+// Only a hash of the token is stored; the raw value is sent to the user once.
+type UserToken struct {
+	ID        string
+	UserID    string
+	Purpose   TokenPurpose
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// IsExpired checks if the token can no longer be redeemed because of its age
+func (t *UserToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// IsUsed checks if the token has already been redeemed
+func (t *UserToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
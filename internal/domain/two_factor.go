@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrTwoFactorRequired is returned when an action needs an account with two-factor authentication enabled
+	ErrTwoFactorRequired = errors.New("two-factor authentication required")
+	// ErrInvalidTwoFactorCode is returned when a TOTP or recovery code does not match
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	// ErrTwoFactorAlreadyEnabled is returned when enrolling an account that is already enrolled
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	// ErrTwoFactorNotEnabled is returned when managing two-factor settings of an account without them
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication not enabled")
+)
+
+// TwoFactor holds a user's TOTP enrollment
+type TwoFactor struct {
+	UserID      string
+	Secret      string
+	ConfirmedAt *time.Time // nil until the user proves they can generate codes
+	LastUsedAt  int64      // last accepted TOTP time step, used to reject replays
+	CreatedAt   time.Time
+}
+
+// IsEnabled checks if the enrollment has been confirmed
+func (t *TwoFactor) IsEnabled() bool {
+	return t.ConfirmedAt != nil
+}
+
+// RecoveryCode is a single-use backup code for when the authenticator is unavailable.
+// Only a hash of the code is stored.
+type RecoveryCode struct {
+	ID        string
+	UserID    string
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -1,13 +1,56 @@
 package domain
 
 import (
+	"errors"
 	"time"
 )
 
+var (
+	// ErrEmailNotVerified is returned when an action requires a verified email address
+	ErrEmailNotVerified = errors.New("email address not verified")
+	// ErrInvalidProfile is returned when profile fields fail validation
+	ErrInvalidProfile = errors.New("invalid profile")
+)
+
+// Role grants a user permissions beyond those of every user
+type Role string
+
+const (
+	RoleUser Role = "user"
+	// RoleAdmin users manage the category tree
+	RoleAdmin Role = "admin"
+	// RoleAuctioneer users run live auctions from the clerk console and
+	// enter bids from the sale room
+	RoleAuctioneer Role = "auctioneer"
+)
+
 // User represents a user in the bidding system
 type User struct {
-	ID           string
-	Email        string
-	PasswordHash string
-	CreatedAt    time.Time
+	ID              string
+	Email           string
+	PasswordHash    string
+	EmailVerifiedAt *time.Time
+	Role            Role
+	Profile         Profile
+	// BidRetractions counts the user's approved bid retractions
+	BidRetractions int
+	CreatedAt      time.Time
+}
+
+// Profile holds the user's self-described public details
+type Profile struct {
+	DisplayName string
+	AvatarURL   string
+	Location    string
+	Bio         string
+}
+
+// IsEmailVerified checks if the user has proven ownership of their email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// HasRole checks if the user has been granted role. Every user has RoleUser.
+func (u *User) HasRole(role Role) bool {
+	return role == RoleUser || u.Role == role
 }
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/service"
+)
+
+type AttachmentHandler struct {
+	attachmentService *service.AttachmentService
+}
+
+func NewAttachmentHandler(attachmentService *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachmentService: attachmentService}
+}
+
+// AttachmentResponse is a product image or document. Its URLs are signed and
+// stop working at url_expires_at; fetch the product again for fresh ones.
+type AttachmentResponse struct {
+	ID          string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Filename    string `json:"filename" example:"front.jpg"`
+	ContentType string `json:"content_type" example:"image/jpeg"`
+	Size        int64  `json:"size" example:"482133"`
+	URL         string `json:"url" example:"http://localhost:8080/blobs/products/550e8400-e29b-41d4-a716-446655440001/550e8400-e29b-41d4-a716-446655440000.jpg?expires=1772360100&signature=9f86d0"`
+	// ThumbnailURL, Width and Height are only set for images
+	ThumbnailURL string    `json:"thumbnail_url,omitempty" example:"http://localhost:8080/blobs/products/550e8400-e29b-41d4-a716-446655440001/550e8400-e29b-41d4-a716-446655440000_thumb.jpg?expires=1772360100&signature=2c26b4"`
+	Width        int       `json:"width,omitempty" example:"1600"`
+	Height       int       `json:"height,omitempty" example:"1200"`
+	Position     int       `json:"position" example:"0"`
+	Primary      bool      `json:"primary" example:"true"`
+	URLExpiresAt time.Time `json:"url_expires_at" example:"2026-03-01T10:15:00Z"`
+	CreatedAt    time.Time `json:"created_at" example:"2026-03-01T10:00:00Z"`
+}
+
+// ReorderImagesRequest lists all images of a product in their new order
+type ReorderImagesRequest struct {
+	IDs []string `json:"ids" binding:"required"`
+}
+
+func newAttachmentResponse(link *service.AttachmentLink) AttachmentResponse {
+	return AttachmentResponse{
+		ID:           link.ID,
+		Filename:     link.Filename,
+		ContentType:  link.ContentType,
+		Size:         link.Size,
+		URL:          link.URL,
+		ThumbnailURL: link.ThumbnailURL,
+		Width:        link.Width,
+		Height:       link.Height,
+		Position:     link.Position,
+		Primary:      link.Primary,
+		URLExpiresAt: link.ExpiresAt,
+		CreatedAt:    link.CreatedAt,
+	}
+}
+
+func newAttachmentResponses(links []*service.AttachmentLink) []AttachmentResponse {
+	responses := make([]AttachmentResponse, 0, len(links))
+	for _, link := range links {
+		responses = append(responses, newAttachmentResponse(link))
+	}
+	return responses
+}
+
+// UploadImage godoc
+// @Summary      Upload a product image
+// @Description  Upload a JPEG, PNG or GIF image of up to 10 MB and 25 megapixels as multipart form field "file". The type is sniffed from the contents. A 320 pixel thumbnail is generated, and the product's first image becomes its primary image. Products may have up to 20 images. Only the product's owner may upload.
+// @Tags         Products
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id    path      string  true  "Product ID"
+// @Param        file  formData  file    true  "Image"
+// @Success      201   {object}  AttachmentResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      413   {object}  ErrorResponse
+// @Failure      415   {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /products/{id}/images [post]
+func (h *AttachmentHandler) UploadImage(c *gin.Context) {
+	h.upload(c, domain.AttachmentImage)
+}
+
+// UploadDocument godoc
+// @Summary      Upload a product document
+// @Description  Upload a PDF of up to 20 MB, such as a manual or certificate, as multipart form field "file". The type is sniffed from the contents. Products may have up to 10 documents. Only the product's owner may upload.
+// @Tags         Products
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id    path      string  true  "Product ID"
+// @Param        file  formData  file    true  "Document"
+// @Success      201   {object}  AttachmentResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      413   {object}  ErrorResponse
+// @Failure      415   {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /products/{id}/documents [post]
+func (h *AttachmentHandler) UploadDocument(c *gin.Context) {
+	h.upload(c, domain.AttachmentDocument)
+}
+
+func (h *AttachmentHandler) upload(c *gin.Context, kind domain.AttachmentKind) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxUploadSize)
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(c, fmt.Errorf("%w: uploads may be at most %d MB", domain.ErrFileTooLarge, service.MaxUploadSize>>20))
+			return
+		}
+		respondInvalid(c, fmt.Errorf("multipart form field \"file\" is required: %w", err))
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to read upload: %w", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	link, err := h.attachmentService.Upload(c.Request.Context(), c.Param("id"), userID.(string), kind, header.Filename, data)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newAttachmentResponse(link))
+}
+
+// ReorderImages godoc
+// @Summary      Reorder product images
+// @Description  Put the product's images in the given order, which must list each of them once. Only the product's owner may reorder.
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                true  "Product ID"
+// @Param        request  body      ReorderImagesRequest  true  "Image IDs in their new order"
+// @Success      200      {array}   AttachmentResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /products/{id}/images/order [put]
+func (h *AttachmentHandler) ReorderImages(c *gin.Context) {
+	var req ReorderImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	links, err := h.attachmentService.ReorderImages(c.Request.Context(), c.Param("id"), userID.(string), req.IDs)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAttachmentResponses(links))
+}
+
+// SetPrimaryImage godoc
+// @Summary      Set the primary product image
+// @Description  Make an image the one listings show first. Only the product's owner may change it.
+// @Tags         Products
+// @Produce      json
+// @Param        id             path      string  true  "Product ID"
+// @Param        attachment_id  path      string  true  "Image ID"
+// @Success      200            {array}   AttachmentResponse
+// @Failure      400            {object}  ErrorResponse
+// @Failure      401            {object}  ErrorResponse
+// @Failure      403            {object}  ErrorResponse
+// @Failure      404            {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /products/{id}/images/{attachment_id}/primary [post]
+func (h *AttachmentHandler) SetPrimaryImage(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	links, err := h.attachmentService.SetPrimaryImage(c.Request.Context(), c.Param("id"), userID.(string), c.Param("attachment_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAttachmentResponses(links))
+}
+
+// Delete godoc
+// @Summary      Delete a product image or document
+// @Description  Delete an attachment and its files. Deleting the primary image makes the next image primary. Only the product's owner may delete.
+// @Tags         Products
+// @Param        id             path  string  true  "Product ID"
+// @Param        attachment_id  path  string  true  "Image or document ID"
+// @Success      204
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /products/{id}/attachments/{attachment_id} [delete]
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	if err := h.attachmentService.Delete(c.Request.Context(), c.Param("id"), userID.(string), c.Param("attachment_id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
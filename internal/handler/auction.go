@@ -1,19 +1,23 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
 	"github.com/saigenix/bidding-system/internal/service"
 )
 
 type AuctionHandler struct {
 	auctionService *service.AuctionService
+	feeService     *service.FeeService
 }
 
-func NewAuctionHandler(auctionService *service.AuctionService) *AuctionHandler {
-	return &AuctionHandler{auctionService: auctionService}
+func NewAuctionHandler(auctionService *service.AuctionService, feeService *service.FeeService) *AuctionHandler {
+	return &AuctionHandler{auctionService: auctionService, feeService: feeService}
 }
 
 type CreateAuctionRequest struct {
@@ -21,43 +25,131 @@ type CreateAuctionRequest struct {
 	StartTime     time.Time `json:"start_time" binding:"required" example:"2026-03-01T10:00:00Z"`
 	EndTime       time.Time `json:"end_time" binding:"required" example:"2026-03-02T10:00:00Z"`
 	StartingPrice float64   `json:"starting_price" binding:"required,min=0" example:"100.00"`
+	// Bids above this amount require a two-factor enabled account
+	TwoFactorThreshold *float64 `json:"two_factor_threshold,omitempty" binding:"omitempty,min=0" example:"5000.00"`
+}
+
+// UpdateAuctionRequest lists the settings to change; omitted fields are left
+// unchanged. Once the auction is active, only a later end time and a lower
+// starting price are accepted, and only until the first bid.
+type UpdateAuctionRequest struct {
+	StartTime          *time.Time `json:"start_time" example:"2026-03-01T12:00:00Z"`
+	EndTime            *time.Time `json:"end_time" example:"2026-03-02T12:00:00Z"`
+	StartingPrice      *float64   `json:"starting_price" binding:"omitempty,min=0" example:"80.00"`
+	TwoFactorThreshold *float64   `json:"two_factor_threshold" binding:"omitempty,min=0" example:"5000.00"`
+}
+
+// AuctionResponse is an auction together with the server's clock, so clients
+// can compute their clock offset before counting down to EndTime
+type AuctionResponse struct {
+	*domain.Auction
+	ServerTime time.Time `json:"server_time" example:"2026-03-01T10:00:00.123Z"`
+	// EstimatedCost is what winning with the lowest acceptable bid would
+	// cost, including buyer's premium and tax; only set on a single auction
+	// that has not ended
+	EstimatedCost *CostEstimateResponse `json:"estimated_cost,omitempty"`
+}
+
+// CostEstimateQuery holds the bid amount of GET /auctions/:id/cost-estimate
+type CostEstimateQuery struct {
+	// Amount is the bid to estimate; absent for the lowest acceptable bid
+	Amount *float64 `form:"amount" binding:"omitempty,min=0" example:"150.00"`
+}
+
+// ListAuctionsQuery holds the filter, sort and paging query parameters of
+// GET /auctions
+type ListAuctionsQuery struct {
+	PageQuery
+	Status       string     `form:"status" example:"active"`
+	ProductID    string     `form:"product_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OwnerID      string     `form:"owner_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	MinPrice     *float64   `form:"min_price" binding:"omitempty,min=0" example:"50.00"`
+	MaxPrice     *float64   `form:"max_price" binding:"omitempty,min=0" example:"500.00"`
+	EndingAfter  *time.Time `form:"ending_after" example:"2026-03-01T10:00:00Z"`
+	EndingBefore *time.Time `form:"ending_before" example:"2026-03-02T10:00:00Z"`
+	Sort         string     `form:"sort" example:"ending_soonest"`
+}
+
+// AuctionListResponse is one page of auctions
+type AuctionListResponse struct {
+	Items      []AuctionResponse `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty" example:"bmV3ZXN0fHQyMDI2LTAzLTAxVDEwOjAwOjAwWnw1NTBlODQwMA"`
+}
+
+// StatusChangeRequest optionally explains a pause or cancellation
+type StatusChangeRequest struct {
+	Reason string `json:"reason,omitempty" binding:"max=500" example:"Lot withdrawn by the seller"`
+}
+
+type StatusChangeResponse struct {
+	From      domain.AuctionStatus `json:"from" example:"active"`
+	To        domain.AuctionStatus `json:"to" example:"paused"`
+	Reason    string               `json:"reason" example:"paused"`
+	ActorID   string               `json:"actor_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CreatedAt time.Time            `json:"created_at" example:"2026-03-01T10:00:00Z"`
+}
+
+// AuctionChangeResponse is one setting changed by the seller. Changes made
+// together share a version.
+type AuctionChangeResponse struct {
+	Version   int       `json:"version" example:"2"`
+	Field     string    `json:"field" example:"starting_price"`
+	OldValue  string    `json:"old_value" example:"100"`
+	NewValue  string    `json:"new_value" example:"80"`
+	CreatedAt time.Time `json:"created_at" example:"2026-03-01T10:00:00Z"`
+}
+
+type ServerTimeResponse struct {
+	ServerTime time.Time `json:"server_time" example:"2026-03-01T10:00:00.123Z"`
+	UnixMillis int64     `json:"unix_ms" example:"1772359200123"`
+}
+
+func newAuctionResponse(auction *domain.Auction, now time.Time) AuctionResponse {
+	return AuctionResponse{Auction: auction, ServerTime: now}
 }
 
 // Create godoc
 // @Summary      Create an auction
-// @Description  Create a new auction for a product with a time window and starting price
+// @Description  Create a new auction for a product with a time window and starting price. The auction takes its bid increment table and soft close window from the product's category.
 // @Tags         Auctions
 // @Accept       json
 // @Produce      json
 // @Param        request  body      CreateAuctionRequest  true  "Auction details"
-// @Success      201      {object}  domain.Auction
+// @Success      201      {object}  AuctionResponse
 // @Failure      400      {object}  ErrorResponse
 // @Failure      401      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
 // @Security     BearerAuth
 // @Router       /auctions [post]
 func (h *AuctionHandler) Create(c *gin.Context) {
 	var req CreateAuctionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalid(c, err)
 		return
 	}
 
-	auction, err := h.auctionService.CreateAuction(c.Request.Context(), req.ProductID, req.StartTime, req.EndTime, req.StartingPrice)
+	var opts []service.AuctionOption
+	if req.TwoFactorThreshold != nil {
+		opts = append(opts, service.WithTwoFactorThreshold(*req.TwoFactorThreshold))
+	}
+
+	auction, err := h.auctionService.CreateAuction(c.Request.Context(), req.ProductID, req.StartTime, req.EndTime, req.StartingPrice, opts...)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, auction)
+	c.JSON(http.StatusCreated, newAuctionResponse(auction, time.Now()))
 }
 
 // Get godoc
 // @Summary      Get an auction
-// @Description  Get an auction by its ID
+// @Description  Get an auction by its ID, with the server time for computing clock offset. Until the auction ends, estimated_cost shows what winning with the lowest acceptable bid would cost, including buyer's premium and tax; it is omitted if the fees cannot be computed.
 // @Tags         Auctions
 // @Produce      json
 // @Param        id   path      string  true  "Auction ID"
-// @Success      200  {object}  domain.Auction
+// @Success      200  {object}  AuctionResponse
+// @Header       200  {string}  ETag  "Current version, for If-Match"
 // @Failure      401  {object}  ErrorResponse
 // @Failure      404  {object}  ErrorResponse
 // @Security     BearerAuth
@@ -66,48 +158,208 @@ func (h *AuctionHandler) Get(c *gin.Context) {
 	id := c.Param("id")
 	auction, err := h.auctionService.GetAuction(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "auction not found"})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, auction)
+	// The estimate is a convenience: the auction is still shown without it
+	response := newAuctionResponse(auction, time.Now())
+	if !auction.HasEnded() {
+		if estimate, err := h.feeService.EstimateFor(c.Request.Context(), auction, nil); err == nil {
+			response.EstimatedCost = newCostEstimateResponse(estimate)
+		}
+	}
+
+	setETag(c, auction.Version)
+	c.JSON(http.StatusOK, response)
+}
+
+// CostEstimate godoc
+// @Summary      Estimate the cost of a bid
+// @Description  Get what winning the auction with a bid of amount would cost: the bid, the buyer's premium and tax of the auction's category, itemized. Without an amount, the lowest bid the auction accepts next is estimated.
+// @Tags         Auctions
+// @Produce      json
+// @Param        id      path      string  true   "Auction ID"
+// @Param        amount  query     number  false  "Bid amount"
+// @Success      200     {object}  CostEstimateResponse
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/cost-estimate [get]
+func (h *AuctionHandler) CostEstimate(c *gin.Context) {
+	var req CostEstimateQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	estimate, err := h.feeService.Estimate(c.Request.Context(), c.Param("id"), req.Amount)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCostEstimateResponse(estimate))
 }
 
 // List godoc
-// @Summary      List all auctions
-// @Description  Get a list of all auctions
+// @Summary      List auctions
+// @Description  Get a page of auctions matching the filters, each with the server time for computing clock offset. Pass the response's next_cursor as cursor, with the same filters and sort, to get the next page; it is omitted on the last page.
 // @Tags         Auctions
 // @Produce      json
-// @Success      200  {array}   domain.Auction
-// @Failure      401  {object}  ErrorResponse
-// @Failure      500  {object}  ErrorResponse
+// @Param        status         query     string   false  "Only auctions in this status"
+// @Param        product_id     query     string   false  "Only auctions of this product"
+// @Param        owner_id       query     string   false  "Only auctions of products owned by this user"
+// @Param        min_price      query     number   false  "Minimum current price"
+// @Param        max_price      query     number   false  "Maximum current price"
+// @Param        ending_after   query     string   false  "Only auctions ending after this time (RFC 3339)"
+// @Param        ending_before  query     string   false  "Only auctions ending before this time (RFC 3339)"
+// @Param        sort           query     string   false  "Sort order"  Enums(newest, ending_soonest, highest_price, most_bids)
+// @Param        limit          query     int      false  "Page size (1-100, default 20)"
+// @Param        cursor         query     string   false  "next_cursor of the previous page"
+// @Success      200            {object}  AuctionListResponse
+// @Failure      400            {object}  ErrorResponse
+// @Failure      401            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
 // @Security     BearerAuth
 // @Router       /auctions [get]
 func (h *AuctionHandler) List(c *gin.Context) {
-	auctions, err := h.auctionService.ListAuctions(c.Request.Context())
+	var req ListAuctionsQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	query := domain.AuctionQuery{
+		Status:       domain.AuctionStatus(req.Status),
+		ProductID:    req.ProductID,
+		OwnerID:      req.OwnerID,
+		MinPrice:     req.MinPrice,
+		MaxPrice:     req.MaxPrice,
+		EndingAfter:  req.EndingAfter,
+		EndingBefore: req.EndingBefore,
+		Sort:         domain.AuctionSort(req.Sort),
+		Limit:        req.Limit,
+	}
+	page, err := h.auctionService.ListAuctions(c.Request.Context(), query, req.Cursor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	now := time.Now()
+	response := AuctionListResponse{
+		Items:      make([]AuctionResponse, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+	}
+	for _, auction := range page.Items {
+		response.Items = append(response.Items, newAuctionResponse(auction, now))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// Update godoc
+// @Summary      Edit an auction
+// @Description  Change the settings of your own auction. Any setting may change while it is pending or scheduled; once active and until the first bid, only extending the end time and lowering the starting price are allowed. Changes are listed in the auction's change log.
+// @Tags         Auctions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                true  "Auction ID"
+// @Param        request  body      UpdateAuctionRequest  true  "Settings to change"
+// @Param        If-Match  header    string                false  "ETag of the version last read"
+// @Success      200      {object}  AuctionResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      412      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id} [patch]
+func (h *AuctionHandler) Update(c *gin.Context) {
+	var req UpdateAuctionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	auction, err := h.auctionService.UpdateAuction(c.Request.Context(), c.Param("id"), version, userID.(string), service.AuctionUpdate{
+		StartTime:          req.StartTime,
+		EndTime:            req.EndTime,
+		StartingPrice:      req.StartingPrice,
+		TwoFactorThreshold: req.TwoFactorThreshold,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list auctions"})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, auctions)
+	setETag(c, auction.Version)
+	c.JSON(http.StatusOK, newAuctionResponse(auction, time.Now()))
+}
+
+// Changes godoc
+// @Summary      Get auction change log
+// @Description  Every setting the seller changed, oldest first, so bidders can see how the auction was edited
+// @Tags         Auctions
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID"
+// @Success      200  {array}   AuctionChangeResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/changes [get]
+func (h *AuctionHandler) Changes(c *gin.Context) {
+	changes, err := h.auctionService.GetChangeLog(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]AuctionChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		response = append(response, AuctionChangeResponse{
+			Version:   change.Version,
+			Field:     change.Field,
+			OldValue:  change.OldValue,
+			NewValue:  change.NewValue,
+			CreatedAt: change.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // Start godoc
 // @Summary      Start an auction
-// @Description  Transition an auction from pending to active status
+// @Description  Transition a pending or scheduled auction to active status
 // @Tags         Auctions
 // @Produce      json
 // @Param        id   path      string  true  "Auction ID"
+// @Param        If-Match  header  string  false  "ETag of the version last read"
 // @Success      200  {object}  map[string]string
 // @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      412  {object}  ErrorResponse
 // @Security     BearerAuth
 // @Router       /auctions/{id}/start [post]
 func (h *AuctionHandler) Start(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	id := c.Param("id")
-	if err := h.auctionService.StartAuction(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	userID, _ := c.Get("userID")
+	if err := h.auctionService.StartAuction(c.Request.Context(), id, version, userID.(string)); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -116,21 +368,233 @@ func (h *AuctionHandler) Start(c *gin.Context) {
 
 // End godoc
 // @Summary      End an auction
-// @Description  Transition an auction to ended status, no more bids accepted
+// @Description  Transition an active auction to ended status, no more bids accepted
 // @Tags         Auctions
 // @Produce      json
 // @Param        id   path      string  true  "Auction ID"
+// @Param        If-Match  header  string  false  "ETag of the version last read"
 // @Success      200  {object}  map[string]string
 // @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      412  {object}  ErrorResponse
 // @Security     BearerAuth
 // @Router       /auctions/{id}/end [post]
 func (h *AuctionHandler) End(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	id := c.Param("id")
-	if err := h.auctionService.EndAuction(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	userID, _ := c.Get("userID")
+	if err := h.auctionService.EndAuction(c.Request.Context(), id, version, userID.(string)); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "auction ended"})
 }
+
+// Schedule godoc
+// @Summary      Schedule an auction
+// @Description  Transition a pending auction to scheduled status; it starts automatically at its start time. Only the seller may schedule it.
+// @Tags         Auctions
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID"
+// @Param        If-Match  header  string  false  "ETag of the version last read"
+// @Success      200  {object}  AuctionResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      412  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/schedule [post]
+func (h *AuctionHandler) Schedule(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	auction, err := h.auctionService.ScheduleAuction(c.Request.Context(), c.Param("id"), version, userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, auction.Version)
+	c.JSON(http.StatusOK, newAuctionResponse(auction, time.Now()))
+}
+
+// Pause godoc
+// @Summary      Pause an auction
+// @Description  Stop bidding on an active auction. Only the seller may pause it. Its remaining time is frozen until it is resumed.
+// @Tags         Auctions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string               true   "Auction ID"
+// @Param        request  body      StatusChangeRequest  false  "Reason for pausing"
+// @Param        If-Match  header    string                false  "ETag of the version last read"
+// @Success      200      {object}  AuctionResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      412      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/pause [post]
+func (h *AuctionHandler) Pause(c *gin.Context) {
+	var req StatusChangeRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	auction, err := h.auctionService.PauseAuction(c.Request.Context(), c.Param("id"), version, userID.(string), req.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, auction.Version)
+	c.JSON(http.StatusOK, newAuctionResponse(auction, time.Now()))
+}
+
+// Resume godoc
+// @Summary      Resume an auction
+// @Description  Reopen a paused auction for bidding (seller only). Its end time is extended by the time it spent paused.
+// @Tags         Auctions
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID"
+// @Param        If-Match  header  string  false  "ETag of the version last read"
+// @Success      200  {object}  AuctionResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      412  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/resume [post]
+func (h *AuctionHandler) Resume(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	auction, err := h.auctionService.ResumeAuction(c.Request.Context(), c.Param("id"), version, userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, auction.Version)
+	c.JSON(http.StatusOK, newAuctionResponse(auction, time.Now()))
+}
+
+// Cancel godoc
+// @Summary      Cancel an auction
+// @Description  Stop an auction that has not ended, without a result. Only the seller may cancel it.
+// @Tags         Auctions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string               true   "Auction ID"
+// @Param        request  body      StatusChangeRequest  false  "Reason for cancelling"
+// @Param        If-Match  header    string                false  "ETag of the version last read"
+// @Success      200      {object}  AuctionResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      412      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/cancel [post]
+func (h *AuctionHandler) Cancel(c *gin.Context) {
+	var req StatusChangeRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	auction, err := h.auctionService.CancelAuction(c.Request.Context(), c.Param("id"), version, userID.(string), req.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, auction.Version)
+	c.JSON(http.StatusOK, newAuctionResponse(auction, time.Now()))
+}
+
+// History godoc
+// @Summary      Get auction status history
+// @Description  Every status change of an auction, oldest first, with its reason and the user who made it
+// @Tags         Auctions
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID"
+// @Success      200  {array}   StatusChangeResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/history [get]
+func (h *AuctionHandler) History(c *gin.Context) {
+	changes, err := h.auctionService.GetStatusHistory(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]StatusChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		response = append(response, StatusChangeResponse{
+			From:      change.From,
+			To:        change.To,
+			Reason:    change.Reason,
+			ActorID:   change.ActorID,
+			CreatedAt: change.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ServerTime godoc
+// @Summary      Get server time
+// @Description  Current server time. Clients subtract their own clock reading (ideally the midpoint of the request's round trip) to get their clock offset, and correct auction countdowns with it.
+// @Tags         Auctions
+// @Produce      json
+// @Success      200  {object}  ServerTimeResponse
+// @Router       /time [get]
+func (h *AuctionHandler) ServerTime(c *gin.Context) {
+	now := time.Now()
+	c.JSON(http.StatusOK, ServerTimeResponse{
+		ServerTime: now,
+		UnixMillis: now.UnixMilli(),
+	})
+}
+
+// bindOptionalJSON binds the request body into req if there is one, writing
+// a 400 and returning false if it is invalid
+func bindOptionalJSON(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil && !errors.Is(err, io.EOF) {
+		respondInvalid(c, err)
+		return false
+	}
+	return true
+}
@@ -2,17 +2,24 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/saigenix/bidding-system/internal/service"
 )
 
 type AuthHandler struct {
-	authService *service.AuthService
+	authService    *service.AuthService
+	accountService *service.AccountService
+	ticketService  *service.StreamTicketService
 }
 
-func NewAuthHandler(authService *service.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
+func NewAuthHandler(authService *service.AuthService, accountService *service.AccountService, ticketService *service.StreamTicketService) *AuthHandler {
+	return &AuthHandler{
+		authService:    authService,
+		accountService: accountService,
+		ticketService:  ticketService,
+	}
 }
 
 type RegisterRequest struct {
@@ -26,7 +33,14 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIs..."`
+	Token             string `json:"token,omitempty" example:"eyJhbGciOiJIUzI1NiIs..."`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty" example:"false"`
+	ChallengeToken    string `json:"challenge_token,omitempty" example:"eyJhbGciOiJIUzI1NiIs..."`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIs..."`
+	Code           string `json:"code" binding:"required" example:"123456"`
 }
 
 type UserResponse struct {
@@ -34,13 +48,31 @@ type UserResponse struct {
 	Email string `json:"email" example:"user@example.com"`
 }
 
-type ErrorResponse struct {
-	Error string `json:"error" example:"error message"`
+type StreamTicketResponse struct {
+	Ticket    string    `json:"ticket" example:"Yp1uX0a8t3K9c2Qe..."`
+	ExpiresAt time.Time `json:"expires_at" example:"2024-01-01T00:00:30Z"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required" example:"kq3S0n6mJ3w7hZ1..."`
+}
+
+type EmailRequest struct {
+	Email string `json:"email" binding:"required,email" example:"user@example.com"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required" example:"kq3S0n6mJ3w7hZ1..."`
+	Password string `json:"password" binding:"required,min=6" example:"newpassword123"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message" example:"ok"`
 }
 
 // Register godoc
 // @Summary      Register a new user
-// @Description  Create a new user account with email and password
+// @Description  Create a new user account with email and password. A verification link is emailed to the user; bidding is blocked until the address is verified.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
@@ -52,16 +84,21 @@ type ErrorResponse struct {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalid(c, err)
 		return
 	}
 
 	user, err := h.authService.Register(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to register user"})
+		respondError(c, err)
 		return
 	}
 
+	// The account exists even if delivery fails; the user can request a new link
+	if err := h.accountService.SendVerificationEmail(c.Request.Context(), user.ID); err != nil {
+		_ = c.Error(err)
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"id":    user.ID,
 		"email": user.Email,
@@ -70,7 +107,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary      Login user
-// @Description  Authenticate with email and password to receive a JWT token
+// @Description  Authenticate with email and password to receive a JWT token. If the account has two-factor authentication enabled, a short-lived challenge token is returned instead and must be exchanged at /auth/login/2fa.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
@@ -78,19 +115,176 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Success      200      {object}  LoginResponse
 // @Failure      400      {object}  ErrorResponse
 // @Failure      401      {object}  ErrorResponse
+// @Failure      429      {object}  ErrorResponse
 // @Router       /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalid(c, err)
+		return
+	}
+
+	result, err := h.authService.Login(c.Request.Context(), req.Email, req.Password, c.ClientIP())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, LoginResponse{
+		Token:             result.Token,
+		TwoFactorRequired: result.TwoFactorRequired,
+		ChallengeToken:    result.ChallengeToken,
+	})
+}
+
+// LoginTwoFactor godoc
+// @Summary      Complete two-factor login
+// @Description  Exchange a challenge token from /auth/login and a TOTP or recovery code for a JWT token
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      TwoFactorLoginRequest  true  "Challenge token and code"
+// @Success      200      {object}  LoginResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      429      {object}  ErrorResponse
+// @Router       /auth/login/2fa [post]
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
 		return
 	}
 
-	token, err := h.authService.Login(c.Request.Context(), req.Email, req.Password)
+	token, err := h.authService.CompleteTwoFactorLogin(c.Request.Context(), req.ChallengeToken, req.Code, c.ClientIP())
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, LoginResponse{Token: token})
 }
+
+// VerifyEmail godoc
+// @Summary      Verify email address
+// @Description  Redeem a single-use email verification token
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      VerifyEmailRequest  true  "Verification token"
+// @Success      200      {object}  MessageResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /auth/verify [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.accountService.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "email verified"})
+}
+
+// ResendVerification godoc
+// @Summary      Resend verification email
+// @Description  Send a new verification link. Always responds 202 so that registered addresses cannot be discovered.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      EmailRequest  true  "Account email"
+// @Success      202      {object}  MessageResponse
+// @Failure      400      {object}  ErrorResponse
+// @Router       /auth/verify/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.accountService.ResendVerificationEmail(c.Request.Context(), req.Email); err != nil {
+		_ = c.Error(err)
+	}
+
+	c.JSON(http.StatusAccepted, MessageResponse{Message: "if the account exists and is unverified, a verification email has been sent"})
+}
+
+// ForgotPassword godoc
+// @Summary      Request a password reset
+// @Description  Email a single-use password reset link. Always responds 202 so that registered addresses cannot be discovered.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      EmailRequest  true  "Account email"
+// @Success      202      {object}  MessageResponse
+// @Failure      400      {object}  ErrorResponse
+// @Router       /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.accountService.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		_ = c.Error(err)
+	}
+
+	c.JSON(http.StatusAccepted, MessageResponse{Message: "if the account exists, a password reset email has been sent"})
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Redeem a single-use password reset token and set a new password
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ResetPasswordRequest  true  "Reset token and new password"
+// @Success      200      {object}  MessageResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.accountService.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "password updated"})
+}
+
+// IssueStreamTicket godoc
+// @Summary      Get a stream ticket
+// @Description  Exchange the JWT for a short-lived, single-use ticket that authenticates one SSE or WebSocket connection. Browsers cannot set an Authorization header on EventSource or WebSocket, so they pass the ticket as the ticket query parameter or, for WebSockets, as a "ticket.<value>" subprotocol alongside "bidding.v1".
+// @Tags         Auth
+// @Produce      json
+// @Success      201  {object}  StreamTicketResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auth/stream-ticket [post]
+func (h *AuthHandler) IssueStreamTicket(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	ticket, expiresAt, err := h.ticketService.Issue(c.Request.Context(), userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, StreamTicketResponse{
+		Ticket:    ticket,
+		ExpiresAt: expiresAt,
+	})
+}
@@ -3,31 +3,75 @@ package handler
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/realtime"
 	"github.com/saigenix/bidding-system/internal/service"
+	"github.com/saigenix/bidding-system/pkg/wsproto"
 )
 
 type BidHandler struct {
-	bidService *service.BidService
-	upgrader   websocket.Upgrader
+	bidService   *service.BidService
+	eventService *service.EventService
+	hub          *realtime.Hub
+	upgrader     websocket.Upgrader
 }
 
-func NewBidHandler(bidService *service.BidService) *BidHandler {
+// NewBidHandler creates a bid handler. allowedOrigins lists the browser
+// origins (e.g. "https://app.example.com") that may open WebSockets; see
+// checkOrigin.
+func NewBidHandler(bidService *service.BidService, eventService *service.EventService, hub *realtime.Hub, allowedOrigins []string) *BidHandler {
 	return &BidHandler{
-		bidService: bidService,
+		bidService:   bidService,
+		eventService: eventService,
+		hub:          hub,
 		upgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
-			CheckOrigin: func(r *http.Request) bool {
-				return true // Allow all origins for demo (restrict in production)
-			},
+			Subprotocols:    []string{wsproto.Subprotocol},
+			CheckOrigin:     checkOrigin(allowedOrigins),
 		},
 	}
 }
 
+// checkOrigin accepts WebSocket handshakes without an Origin header (non-browser
+// clients), from the server's own origin, and from the allowed origins. A "*"
+// entry allows every origin.
+func checkOrigin(allowedOrigins []string) func(r *http.Request) bool {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))] = true
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" || allowed["*"] || allowed[strings.ToLower(origin)] {
+			return true
+		}
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		return strings.EqualFold(u.Host, r.Host)
+	}
+}
+
+type WatchersResponse struct {
+	AuctionID string `json:"auction_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Watchers  int    `json:"watchers" example:"12"`
+}
+
+// BidListResponse is one page of an auction's bids
+type BidListResponse struct {
+	Items      []*domain.PublicBid `json:"items"`
+	NextCursor string              `json:"next_cursor,omitempty" example:"bmV3ZXN0fHQyMDI2LTAzLTAxVDEwOjAwOjAwWnw1NTBlODQwMA"`
+}
+
 type PlaceBidRequest struct {
 	AuctionID string  `json:"auction_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
 	Amount    float64 `json:"amount" binding:"required,min=0" example:"150.00"`
@@ -35,28 +79,32 @@ type PlaceBidRequest struct {
 
 // PlaceBid godoc
 // @Summary      Place a bid
-// @Description  Place a bid on an active auction. Amount must exceed the current price.
+// @Description  Place a bid on an active auction. Amount must exceed the current price. Requires a verified email address, and two-factor authentication for bids above the auction's two-factor threshold.
 // @Tags         Bids
 // @Accept       json
 // @Produce      json
-// @Param        auction_id  path      string          true  "Auction ID"
+// @Param        id          path      string          true  "Auction ID"
 // @Param        request     body      PlaceBidRequest true  "Bid details"
 // @Success      201         {object}  domain.Bid
 // @Failure      400         {object}  ErrorResponse
 // @Failure      401         {object}  ErrorResponse
+// @Failure      403         {object}  ErrorResponse
+// @Failure      404         {object}  ErrorResponse
+// @Failure      409         {object}  ErrorResponse
+// @Failure      422         {object}  ErrorResponse
 // @Security     BearerAuth
-// @Router       /auctions/{auction_id}/bids [post]
+// @Router       /auctions/{id}/bids [post]
 func (h *BidHandler) PlaceBid(c *gin.Context) {
 	var req PlaceBidRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalid(c, err)
 		return
 	}
 
 	userID, _ := c.Get("userID")
 	bid, err := h.bidService.PlaceBid(c.Request.Context(), req.AuctionID, userID.(string), req.Amount)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -65,127 +113,192 @@ func (h *BidHandler) PlaceBid(c *gin.Context) {
 
 // GetBids godoc
 // @Summary      Get bids for an auction
-// @Description  Retrieve all bids placed on a specific auction
+// @Description  Retrieve a page of the bids placed on a specific auction, newest first. Bidders are identified by per-auction pseudonyms; the caller's own bids are flagged with is_mine. Pass the response's next_cursor as cursor to get the next page.
 // @Tags         Bids
 // @Produce      json
-// @Param        auction_id  path      string  true  "Auction ID"
-// @Success      200         {array}   domain.Bid
+// @Param        id          path      string  true   "Auction ID"
+// @Param        limit       query     int     false  "Page size (1-100, default 20)"
+// @Param        cursor      query     string  false  "next_cursor of the previous page"
+// @Success      200         {object}  BidListResponse
+// @Failure      400         {object}  ErrorResponse
 // @Failure      401         {object}  ErrorResponse
 // @Failure      500         {object}  ErrorResponse
 // @Security     BearerAuth
-// @Router       /auctions/{auction_id}/bids [get]
+// @Router       /auctions/{id}/bids [get]
 func (h *BidHandler) GetBids(c *gin.Context) {
-	auctionID := c.Param("auction_id")
-	bids, err := h.bidService.GetBids(c.Request.Context(), auctionID)
+	var req PageQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	auctionID := c.Param("id")
+	userID, _ := c.Get("userID")
+	page, err := h.bidService.GetPublicBids(c.Request.Context(), auctionID, userID.(string), domain.BidQuery{Limit: req.Limit}, req.Cursor)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get bids"})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, bids)
+	c.JSON(http.StatusOK, BidListResponse{Items: page.Items, NextCursor: page.NextCursor})
 }
 
 // StreamBids godoc
 // @Summary      Stream bid updates (SSE)
-// @Description  Server-Sent Events stream of real-time bid updates for an auction
+// @Description  Server-Sent Events stream of real-time events for an auction: bid, closing and closed events, which carry the auction's event sequence number as their SSE id, and periodic tick events with the server time, remaining seconds and current end time, which carry no id. Reconnecting clients send it back in the Last-Event-ID header (or the last_event_id query parameter) and receive every event they missed from the persistent event log before live events resume. The stream suggests a reconnection delay and sends a keepalive comment every 15 seconds.
 // @Tags         Bids
 // @Produce      text/event-stream
-// @Param        auction_id  path  string  true  "Auction ID"
+// @Param        id             path    string  true   "Auction ID"
+// @Param        Last-Event-ID  header  string  false  "Sequence number of the last event received"
+// @Param        last_event_id  query   string  false  "Alternative to the Last-Event-ID header"
+// @Param        ticket         query   string  false  "Stream ticket from POST /auth/stream-ticket, instead of the Authorization header"
 // @Success      200  {string}  string  "SSE event stream"
+// @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
 // @Security     BearerAuth
-// @Router       /auctions/{auction_id}/bids/stream [get]
+// @Router       /auctions/{id}/bids/stream [get]
 func (h *BidHandler) StreamBids(c *gin.Context) {
-	auctionID := c.Param("auction_id")
+	auctionID := c.Param("id")
+	userID, _ := c.Get("userID")
+	ctx := c.Request.Context()
+
+	lastSeq, replay, err := lastEventID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Subscribe before replaying so live events published meanwhile are
+	// buffered; those already replayed are skipped by sequence number
+	sub, err := h.hub.Register(userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer h.hub.Unregister(sub)
+	h.hub.Subscribe(sub, auctionID)
 
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
 	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetry.Milliseconds())
 
-	// In a real implementation, you'd use a pub/sub system (Redis, NATS, etc.)
-	// For now, we'll poll every 2 seconds (simplified for demo)
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+	for replay {
+		events, err := h.eventService.ListAfter(ctx, auctionID, lastSeq, sseReplayBatch)
+		if err != nil {
+			return // the client reconnects with the same Last-Event-ID
+		}
+		for _, event := range events {
+			if err := writeSSEEvent(c.Writer, event, userID.(string)); err != nil {
+				return
+			}
+			lastSeq = event.Sequence
+		}
+		replay = len(events) == sseReplayBatch
+	}
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
 
 	for {
 		select {
-		case <-c.Request.Context().Done():
+		case <-ctx.Done():
+			return
+		case <-sub.Done():
 			return
-		case <-ticker.C:
-			bids, err := h.bidService.GetBids(c.Request.Context(), auctionID)
-			if err != nil {
+		case event := <-sub.Events():
+			if event.IsLogged() && event.Sequence <= lastSeq {
 				continue
 			}
-
-			if len(bids) > 0 {
-				latestBid := bids[0]
-				c.SSEvent("bid", gin.H{
-					"id":         latestBid.ID,
-					"user_id":    latestBid.UserID,
-					"amount":     latestBid.Amount,
-					"created_at": latestBid.CreatedAt,
-				})
-				c.Writer.Flush()
+			if err := writeSSEEvent(c.Writer, event, userID.(string)); err != nil {
+				return
 			}
+			if event.IsLogged() {
+				lastSeq = event.Sequence
+			}
+			c.Writer.Flush()
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(c.Writer, ": keepalive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
 		}
 	}
 }
 
+// GetWatchers godoc
+// @Summary      Get live watcher count
+// @Description  Number of real-time connections currently subscribed to an auction on this server
+// @Tags         Bids
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID"
+// @Success      200  {object}  WatchersResponse
+// @Failure      401  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/watchers [get]
+func (h *BidHandler) GetWatchers(c *gin.Context) {
+	auctionID := c.Param("id")
+	c.JSON(http.StatusOK, WatchersResponse{
+		AuctionID: auctionID,
+		Watchers:  h.hub.SubscriberCount(auctionID),
+	})
+}
+
 // WebSocketHandler handles WebSocket connections for real-time bidding
-// @Summary      WebSocket bid stream
-// @Description  Bi-directional WebSocket connection for real-time bid updates
+// @Summary      WebSocket bidding
+// @Description  Bi-directional WebSocket speaking the versioned JSON bidding protocol (see pkg/wsproto). The connection starts subscribed to the auction in the path. Clients may subscribe/unsubscribe to other auctions, place bids and set proxy maxima; every request carrying a request_id is answered with an ack or error carrying the same request_id. Browsers authenticate with a stream ticket, passed in the ticket query parameter or offered as a "ticket.<value>" subprotocol alongside "bidding.v1". Handshakes from origins outside SERVER_ALLOWED_ORIGINS are rejected with 403.
 // @Tags         Bids
-// @Param        auction_id  path  string  true  "Auction ID"
+// @Param        id          path  string  true   "Auction ID"
+// @Param        ticket      query string  false  "Stream ticket from POST /auth/stream-ticket, instead of the Authorization header"
 // @Success      101  {string}  string  "WebSocket upgrade"
 // @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
 // @Security     BearerAuth
-// @Router       /auctions/{auction_id}/bids/ws [get]
+// @Router       /auctions/{id}/bids/ws [get]
 func (h *BidHandler) WebSocketHandler(c *gin.Context) {
-	auctionID := c.Param("auction_id")
+	h.serveWebSocket(c, c.Param("id"))
+}
 
-	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+// HubWebSocket godoc
+// @Summary      Multiplexed WebSocket
+// @Description  WebSocket speaking the same bidding protocol as /auctions/{id}/bids/ws, but starting with no subscriptions so one connection can follow many auctions. Connections that fall behind are closed with code 1013; on shutdown they are closed with code 1001.
+// @Tags         Bids
+// @Param        ticket  query  string  false  "Stream ticket from POST /auth/stream-ticket, instead of the Authorization header"
+// @Success      101  {string}  string  "WebSocket upgrade"
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /ws [get]
+func (h *BidHandler) HubWebSocket(c *gin.Context) {
+	h.serveWebSocket(c, "")
+}
+
+// serveWebSocket upgrades the connection and serves the bidding protocol,
+// subscribing to auctionID first if it is set
+func (h *BidHandler) serveWebSocket(c *gin.Context, auctionID string) {
+	userID, _ := c.Get("userID")
+	sub, err := h.hub.Register(userID.(string))
 	if err != nil {
+		respondError(c, err)
 		return
 	}
-	defer conn.Close()
-
-	// Send initial bids
-	bids, _ := h.bidService.GetBids(c.Request.Context(), auctionID)
-	conn.WriteJSON(gin.H{"type": "initial", "bids": bids})
-
-	// In a real implementation, use pub/sub for real-time updates
-	// For demo, we'll poll every 2 seconds
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+	defer h.hub.Unregister(sub)
 
-	done := make(chan struct{})
-
-	// Read messages from client (e.g., new bid placement via WS)
-	go func() {
-		defer close(done)
-		for {
-			var msg map[string]interface{}
-			if err := conn.ReadJSON(&msg); err != nil {
-				return
-			}
-			// Handle incoming messages (e.g., bid placement)
-			fmt.Printf("Received message: %v\n", msg)
-		}
-	}()
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
 
-	// Send updates to client
-	for {
-		select {
-		case <-done:
-			return
-		case <-ticker.C:
-			bids, err := h.bidService.GetBids(c.Request.Context(), auctionID)
-			if err != nil {
-				continue
-			}
-			if len(bids) > 0 {
-				conn.WriteJSON(gin.H{"type": "update", "latest_bid": bids[0]})
-			}
+	session := newBidSession(h.bidService, h.hub, sub, conn)
+	if auctionID != "" {
+		for _, msg := range session.subscribe(c.Request.Context(), "", auctionID) {
+			session.send <- msg
 		}
 	}
+	session.run(c.Request.Context())
 }
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/pkg/wsproto"
+)
+
+const (
+	// sseRetry is the reconnection delay suggested to EventSource clients
+	sseRetry = 3 * time.Second
+	// sseKeepAlive is how often a comment is sent to keep idle proxies from closing the stream
+	sseKeepAlive = 15 * time.Second
+	// sseReplayBatch is the number of logged events read per query when replaying
+	sseReplayBatch = 500
+)
+
+// lastEventID returns the sequence number the client last received, taken
+// from the Last-Event-ID header set by reconnecting EventSources or the
+// last_event_id query parameter. ok is false when neither is present.
+func lastEventID(c *gin.Context) (seq int64, ok bool, err error) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, false, nil
+	}
+	seq, err = strconv.ParseInt(value, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, false, fmt.Errorf("%w: invalid Last-Event-ID %q", domain.ErrValidation, value)
+	}
+	return seq, true, nil
+}
+
+// writeSSEEvent writes an auction event as viewed by userID, using its
+// sequence number as the SSE id. Transient events carry no id, so they do not
+// move the client's Last-Event-ID.
+func writeSSEEvent(w io.Writer, event *domain.AuctionEvent, userID string) error {
+	data, err := json.Marshal(eventPayload(event, userID))
+	if err != nil {
+		return err
+	}
+	if event.IsLogged() {
+		_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, event.Type, data)
+	} else {
+		_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	}
+	return err
+}
+
+// eventPayload converts an auction event into its public form for userID
+func eventPayload(event *domain.AuctionEvent, userID string) any {
+	switch event.Type {
+	case domain.AuctionEventBid:
+		return eventBidPayload(event, userID)
+	case domain.AuctionEventLive:
+		return eventLivePayload(event)
+	case domain.AuctionEventRetracted:
+		return eventRetractedPayload(event, userID)
+	}
+	return eventClockPayload(event)
+}
+
+// eventBidPayload converts a bid event into its public form for userID
+func eventBidPayload(event *domain.AuctionEvent, userID string) wsproto.BidPayload {
+	return wsproto.BidPayload{
+		ID:        event.Bid.ID,
+		Seq:       event.Sequence,
+		Bidder:    event.Bidder,
+		IsMine:    event.Bid.UserID != "" && event.Bid.UserID == userID,
+		Amount:    event.Bid.Amount,
+		Source:    string(event.Bid.Source),
+		CreatedAt: event.Bid.CreatedAt,
+	}
+}
+
+// eventLivePayload converts a live event into its public form
+func eventLivePayload(event *domain.AuctionEvent) wsproto.LivePayload {
+	return wsproto.LivePayload{
+		Seq:          event.Sequence,
+		ServerTime:   event.CreatedAt,
+		Action:       string(event.Live.LastAction),
+		State:        string(event.Live.State),
+		AskingPrice:  event.Live.AskingPrice,
+		Status:       string(event.Auction.Status),
+		CurrentPrice: event.Auction.CurrentPrice,
+	}
+}
+
+// eventRetractedPayload converts a retraction event into its public form for
+// userID
+func eventRetractedPayload(event *domain.AuctionEvent, userID string) wsproto.RetractedPayload {
+	return wsproto.RetractedPayload{
+		Seq:          event.Sequence,
+		ServerTime:   event.CreatedAt,
+		BidID:        event.Bid.ID,
+		Bidder:       event.Bidder,
+		IsMine:       event.Bid.UserID != "" && event.Bid.UserID == userID,
+		Amount:       event.Bid.Amount,
+		Status:       string(event.Auction.Status),
+		CurrentPrice: event.Auction.CurrentPrice,
+	}
+}
+
+// eventClockPayload converts any event other than a bid into its public form
+func eventClockPayload(event *domain.AuctionEvent) wsproto.ClockPayload {
+	return wsproto.ClockPayload{
+		Seq:              event.Sequence,
+		ServerTime:       event.CreatedAt,
+		EndTime:          event.Auction.EndTime,
+		RemainingSeconds: int64(math.Ceil(event.Auction.Remaining(event.CreatedAt).Seconds())),
+		Status:           string(event.Auction.Status),
+		CurrentPrice:     event.Auction.CurrentPrice,
+	}
+}
@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/realtime"
+	"github.com/saigenix/bidding-system/internal/service"
+	"github.com/saigenix/bidding-system/pkg/wsproto"
+)
+
+const (
+	// wsWriteWait is the time allowed to write a single frame
+	wsWriteWait = 10 * time.Second
+	// wsPongWait is the time allowed between pongs (or any other frame) from the client
+	wsPongWait = 60 * time.Second
+	// wsPingPeriod must be shorter than wsPongWait so pings arrive in time
+	wsPingPeriod = wsPongWait * 9 / 10
+	// wsMaxMessageSize is the largest frame accepted from the client
+	wsMaxMessageSize = 4096
+	// wsSendBuffer is the number of replies queued for the writer
+	wsSendBuffer = 32
+)
+
+// bidSession serves the bidding protocol on one WebSocket connection. The
+// read loop handles requests; the write loop is the connection's only writer
+// and forwards the hub events of every subscribed auction.
+type bidSession struct {
+	bidService *service.BidService
+	hub        *realtime.Hub
+	sub        *realtime.Subscriber
+	conn       *websocket.Conn
+	userID     string
+	send       chan *wsproto.Message
+}
+
+func newBidSession(bidService *service.BidService, hub *realtime.Hub, sub *realtime.Subscriber, conn *websocket.Conn) *bidSession {
+	return &bidSession{
+		bidService: bidService,
+		hub:        hub,
+		sub:        sub,
+		conn:       conn,
+		userID:     sub.UserID(),
+		send:       make(chan *wsproto.Message, wsSendBuffer),
+	}
+}
+
+// run serves the connection until the client disconnects, the hub drops the
+// subscriber or ctx is done
+func (s *bidSession) run(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer cancel()
+		s.writeLoop(ctx)
+	}()
+
+	s.readLoop(ctx)
+	cancel()
+	<-done
+}
+
+func (s *bidSession) readLoop(ctx context.Context) {
+	s.conn.SetReadLimit(wsMaxMessageSize)
+	s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+		for _, reply := range s.handle(ctx, data) {
+			if !s.enqueue(ctx, reply) {
+				return
+			}
+		}
+	}
+}
+
+func (s *bidSession) enqueue(ctx context.Context, msg *wsproto.Message) bool {
+	select {
+	case s.send <- msg:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s *bidSession) writeLoop(ctx context.Context) {
+	ping := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ping.Stop()
+		s.conn.Close()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.close(websocket.CloseNormalClosure, "")
+			return
+		case <-s.sub.Done():
+			if errors.Is(s.sub.Err(), realtime.ErrSlowConsumer) {
+				s.close(websocket.CloseTryAgainLater, s.sub.Err().Error())
+			} else {
+				s.close(websocket.CloseGoingAway, s.sub.Err().Error())
+			}
+			return
+		case msg := <-s.send:
+			if err := s.write(msg); err != nil {
+				return
+			}
+		case event := <-s.sub.Events():
+			if err := s.write(s.eventMessage(event)); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *bidSession) close(code int, reason string) {
+	s.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(wsWriteWait))
+}
+
+func (s *bidSession) write(msg *wsproto.Message) error {
+	s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return s.conn.WriteJSON(msg)
+}
+
+// handle processes one client frame and returns the replies to send
+func (s *bidSession) handle(ctx context.Context, data []byte) []*wsproto.Message {
+	var req wsproto.Message
+	if err := json.Unmarshal(data, &req); err != nil {
+		return []*wsproto.Message{wsproto.NewError("", "", wsproto.CodeBadRequest, "malformed message")}
+	}
+	if req.Version != wsproto.Version {
+		return []*wsproto.Message{wsproto.NewError(req.RequestID, req.AuctionID, wsproto.CodeUnsupportedVersion, "unsupported protocol version")}
+	}
+	if req.Type != wsproto.TypePing && req.AuctionID == "" {
+		return []*wsproto.Message{wsproto.NewError(req.RequestID, "", wsproto.CodeBadRequest, "auction_id is required")}
+	}
+
+	switch req.Type {
+	case wsproto.TypePing:
+		return []*wsproto.Message{s.reply(wsproto.TypePong, &req, nil)}
+	case wsproto.TypeSubscribe:
+		return s.subscribe(ctx, req.RequestID, req.AuctionID)
+	case wsproto.TypeUnsubscribe:
+		s.hub.Unsubscribe(s.sub, req.AuctionID)
+		return []*wsproto.Message{s.reply(wsproto.TypeAck, &req, nil)}
+	case wsproto.TypePlaceBid:
+		return []*wsproto.Message{s.placeBid(ctx, &req)}
+	case wsproto.TypeSetProxy:
+		return []*wsproto.Message{s.setProxy(ctx, &req)}
+	default:
+		return []*wsproto.Message{wsproto.NewError(req.RequestID, req.AuctionID, wsproto.CodeUnknownType, "unknown message type")}
+	}
+}
+
+func (s *bidSession) reply(typ wsproto.Type, req *wsproto.Message, payload any) *wsproto.Message {
+	msg, err := wsproto.New(typ, req.RequestID, req.AuctionID, payload)
+	if err != nil {
+		return wsproto.NewError(req.RequestID, req.AuctionID, wsproto.CodeInternal, "failed to encode reply")
+	}
+	return msg
+}
+
+// subscribe starts streaming an auction's bids. requestID is empty for the
+// implicit subscription to the auction in the connection URL.
+func (s *bidSession) subscribe(ctx context.Context, requestID, auctionID string) []*wsproto.Message {
+	req := &wsproto.Message{RequestID: requestID, AuctionID: auctionID}
+
+	// Subscribe before taking the snapshot so no bid falls in between; a bid
+	// may then appear both in the snapshot and as an event
+	s.hub.Subscribe(s.sub, auctionID)
+	bids, err := s.bidService.GetPublicBids(ctx, auctionID, s.userID, domain.BidQuery{Limit: domain.MaxPageLimit}, "")
+	if err != nil {
+		s.hub.Unsubscribe(s.sub, auctionID)
+		return []*wsproto.Message{wsproto.NewError(requestID, auctionID, wsproto.CodeInternal, "failed to get bids")}
+	}
+
+	snapshot := wsproto.SnapshotPayload{Bids: make([]wsproto.BidPayload, 0, len(bids.Items))}
+	for _, bid := range bids.Items {
+		snapshot.Bids = append(snapshot.Bids, newBidPayload(bid))
+	}
+
+	var replies []*wsproto.Message
+	if requestID != "" {
+		replies = append(replies, s.reply(wsproto.TypeAck, req, nil))
+	}
+	return append(replies, s.reply(wsproto.TypeSnapshot, req, snapshot))
+}
+
+func (s *bidSession) placeBid(ctx context.Context, req *wsproto.Message) *wsproto.Message {
+	var payload wsproto.PlaceBidPayload
+	if err := req.Decode(&payload); err != nil {
+		return wsproto.NewError(req.RequestID, req.AuctionID, wsproto.CodeBadRequest, err.Error())
+	}
+
+	bid, err := s.bidService.PlaceBid(ctx, req.AuctionID, s.userID, payload.Amount)
+	if err != nil {
+		return bidError(req, err)
+	}
+
+	ack := wsproto.BidPayload{ID: bid.ID, IsMine: true, Amount: bid.Amount, Source: string(bid.Source), CreatedAt: bid.CreatedAt}
+	if bids, err := s.bidService.GetPublicBids(ctx, req.AuctionID, s.userID, domain.BidQuery{Limit: domain.MaxPageLimit}, ""); err == nil {
+		for _, public := range bids.Items {
+			if public.ID == bid.ID {
+				ack.Bidder = public.Bidder
+				break
+			}
+		}
+	}
+	return s.reply(wsproto.TypeAck, req, ack)
+}
+
+func (s *bidSession) setProxy(ctx context.Context, req *wsproto.Message) *wsproto.Message {
+	var payload wsproto.SetProxyPayload
+	if err := req.Decode(&payload); err != nil {
+		return wsproto.NewError(req.RequestID, req.AuctionID, wsproto.CodeBadRequest, err.Error())
+	}
+
+	proxy, err := s.bidService.SetProxyBid(ctx, req.AuctionID, s.userID, payload.MaxAmount)
+	if err != nil {
+		return bidError(req, err)
+	}
+	return s.reply(wsproto.TypeAck, req, wsproto.SetProxyPayload{MaxAmount: proxy.MaxAmount})
+}
+
+// bidError replies to a failed bid with the protocol code of its domain error.
+// Errors of no known kind are reported without detail, like the HTTP API does.
+func bidError(req *wsproto.Message, err error) *wsproto.Message {
+	kind, ok := findProblemKind(err)
+	switch {
+	case !ok || kind.status >= http.StatusInternalServerError:
+		// Unknown errors may carry database and other internal messages
+		return wsproto.NewError(req.RequestID, req.AuctionID, wsproto.CodeInternal, "failed to place bid")
+	case kind.status == http.StatusUnauthorized || kind.status == http.StatusForbidden:
+		return wsproto.NewError(req.RequestID, req.AuctionID, wsproto.CodeForbidden, err.Error())
+	}
+	return wsproto.NewError(req.RequestID, req.AuctionID, wsproto.CodeBidRejected, err.Error())
+}
+
+// eventMessage converts a hub event into a protocol message for this connection
+func (s *bidSession) eventMessage(event *domain.AuctionEvent) *wsproto.Message {
+	msg, err := wsproto.New(wsproto.Type(event.Type), "", event.AuctionID, eventPayload(event, s.userID))
+	if err != nil {
+		return wsproto.NewError("", event.AuctionID, wsproto.CodeInternal, "failed to encode event")
+	}
+	return msg
+}
+
+func newBidPayload(bid *domain.PublicBid) wsproto.BidPayload {
+	return wsproto.BidPayload{
+		ID:        bid.ID,
+		Bidder:    bid.Bidder,
+		IsMine:    bid.IsMine,
+		Amount:    bid.Amount,
+		Source:    string(bid.Source),
+		Retracted: bid.Retracted,
+		CreatedAt: bid.CreatedAt,
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/pkg/blobstore"
+)
+
+// BlobHandler serves the files of a local blob store to holders of URLs it
+// signed. Other stores serve their signed URLs themselves.
+type BlobHandler struct {
+	store *blobstore.LocalStore
+}
+
+func NewBlobHandler(store *blobstore.LocalStore) *BlobHandler {
+	return &BlobHandler{store: store}
+}
+
+// Serve godoc
+// @Summary      Download a file
+// @Description  Download a product image, thumbnail or document through a signed URL from a product response. The signature is the only authorization, so the URL works without a token until it expires.
+// @Tags         Products
+// @Param        key        path  string  true  "Blob key"
+// @Param        expires    query string  true  "Expiry time, Unix seconds"
+// @Param        signature  query string  true  "Signature"
+// @Success      200
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Router       /blobs/{key} [get]
+func (h *BlobHandler) Serve(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := h.store.Verify(key, c.Query("expires"), c.Query("signature")); err != nil {
+		respondError(c, fmt.Errorf("%w: %s", domain.ErrForbidden, err.Error()))
+		return
+	}
+	path, err := h.store.Path(key)
+	if err != nil {
+		respondError(c, &domain.NotFoundError{Entity: "file"})
+		return
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		respondError(c, &domain.NotFoundError{Entity: "file"})
+		return
+	}
+
+	// Files never change under a key, so browsers may cache them
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.File(path)
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/service"
+)
+
+type CatalogHandler struct {
+	catalogService *service.CatalogService
+}
+
+func NewCatalogHandler(catalogService *service.CatalogService) *CatalogHandler {
+	return &CatalogHandler{catalogService: catalogService}
+}
+
+// CreateCatalogRequest sets up a sale whose lots close one after another:
+// lot 1 at first_lot_end_time, every later lot lot_interval_seconds after the
+// one before it
+type CreateCatalogRequest struct {
+	Title       string `json:"title" binding:"required,max=200" example:"Vintage Cameras Spring Sale"`
+	Description string `json:"description" example:"Rangefinders and SLRs from a private collection"`
+	// Terms are the conditions of sale bidders agree to
+	Terms              string    `json:"terms" example:"All lots are sold as seen. Payment within 7 days."`
+	StartTime          time.Time `json:"start_time" binding:"required" example:"2026-03-01T10:00:00Z"`
+	FirstLotEndTime    time.Time `json:"first_lot_end_time" binding:"required" example:"2026-03-08T18:00:00Z"`
+	LotIntervalSeconds int       `json:"lot_interval_seconds" binding:"required,min=1,max=3600" example:"60"`
+}
+
+// AddLotRequest names the auction to add as the catalog's next lot
+type AddLotRequest struct {
+	AuctionID string `json:"auction_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// ListCatalogsQuery holds the filter and paging query parameters of GET /catalogs
+type ListCatalogsQuery struct {
+	PageQuery
+	OwnerID string `form:"owner_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+type CatalogResponse struct {
+	ID                 string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OwnerID            string    `json:"owner_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Title              string    `json:"title" example:"Vintage Cameras Spring Sale"`
+	Description        string    `json:"description" example:"Rangefinders and SLRs from a private collection"`
+	Terms              string    `json:"terms" example:"All lots are sold as seen. Payment within 7 days."`
+	StartTime          time.Time `json:"start_time" example:"2026-03-01T10:00:00Z"`
+	FirstLotEndTime    time.Time `json:"first_lot_end_time" example:"2026-03-08T18:00:00Z"`
+	LotIntervalSeconds int       `json:"lot_interval_seconds" example:"60"`
+	// EndTime is the scheduled close of the last lot; soft close may extend it
+	EndTime   time.Time `json:"end_time" example:"2026-03-08T18:24:00Z"`
+	LotCount  int       `json:"lot_count" example:"25"`
+	CreatedAt time.Time `json:"created_at" example:"2026-02-20T10:00:00Z"`
+}
+
+// LotResponse is a lot with its auction as of now. scheduled_end_time is the
+// lot's slot in the catalog; the auction's EndTime is later if soft close
+// extended the lot or a lot before it.
+type LotResponse struct {
+	Number           int             `json:"number" example:"1"`
+	ScheduledEndTime time.Time       `json:"scheduled_end_time" example:"2026-03-08T18:00:00Z"`
+	Auction          *domain.Auction `json:"auction"`
+}
+
+// CatalogDetailResponse is a catalog with its lots and the server's clock,
+// for counting down to each lot's close
+type CatalogDetailResponse struct {
+	CatalogResponse
+	Lots       []LotResponse `json:"lots"`
+	ServerTime time.Time     `json:"server_time" example:"2026-03-08T17:59:30.123Z"`
+}
+
+// CatalogListResponse is one page of catalogs
+type CatalogListResponse struct {
+	Items      []CatalogResponse `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty" example:"bmV3ZXN0fHQyMDI2LTAzLTAxVDEwOjAwOjAwWnw1NTBlODQwMA"`
+}
+
+func newCatalogResponse(catalog *domain.Catalog) CatalogResponse {
+	return CatalogResponse{
+		ID:                 catalog.ID,
+		OwnerID:            catalog.OwnerID,
+		Title:              catalog.Title,
+		Description:        catalog.Description,
+		Terms:              catalog.Terms,
+		StartTime:          catalog.StartTime,
+		FirstLotEndTime:    catalog.FirstLotEndTime,
+		LotIntervalSeconds: catalog.LotIntervalSeconds,
+		EndTime:            catalog.EndTime(),
+		LotCount:           catalog.LotCount,
+		CreatedAt:          catalog.CreatedAt,
+	}
+}
+
+func newLotResponse(catalog *domain.Catalog, lot *domain.CatalogLot) LotResponse {
+	return LotResponse{Number: lot.Number, ScheduledEndTime: catalog.LotEndTime(lot.Number), Auction: lot.Auction}
+}
+
+func newCatalogDetailResponse(detail *service.CatalogDetail, now time.Time) CatalogDetailResponse {
+	lots := make([]LotResponse, 0, len(detail.Lots))
+	for _, lot := range detail.Lots {
+		lots = append(lots, newLotResponse(detail.Catalog, lot))
+	}
+	return CatalogDetailResponse{CatalogResponse: newCatalogResponse(detail.Catalog), Lots: lots, ServerTime: now}
+}
+
+// Create godoc
+// @Summary      Create a catalog
+// @Description  Create a catalog for a themed sale. Bidding on every lot opens at start_time; lot 1 closes at first_lot_end_time and every later lot lot_interval_seconds after the one before it. The caller owns the catalog.
+// @Tags         Catalogs
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateCatalogRequest  true  "Catalog details"
+// @Success      201      {object}  CatalogResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /catalogs [post]
+func (h *CatalogHandler) Create(c *gin.Context) {
+	var req CreateCatalogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	catalog, err := h.catalogService.CreateCatalog(c.Request.Context(), userID.(string), service.CatalogInput{
+		Title:              req.Title,
+		Description:        req.Description,
+		Terms:              req.Terms,
+		StartTime:          req.StartTime,
+		FirstLotEndTime:    req.FirstLotEndTime,
+		LotIntervalSeconds: req.LotIntervalSeconds,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newCatalogResponse(catalog))
+}
+
+// Get godoc
+// @Summary      Get a catalog
+// @Description  Get a catalog with its lots in order, each with its auction's live price, status and end time, and the server time for computing clock offset. A lot never closes before the lot ahead of it: when soft close extends a lot, later lots are pushed back just enough to keep lot_interval_seconds between closes.
+// @Tags         Catalogs
+// @Produce      json
+// @Param        id   path      string  true  "Catalog ID"
+// @Success      200  {object}  CatalogDetailResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /catalogs/{id} [get]
+func (h *CatalogHandler) Get(c *gin.Context) {
+	detail, err := h.catalogService.GetCatalog(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCatalogDetailResponse(detail, time.Now()))
+}
+
+// List godoc
+// @Summary      List catalogs
+// @Description  Get a page of catalogs, newest first. Pass the response's next_cursor as cursor to get the next page; it is omitted on the last page.
+// @Tags         Catalogs
+// @Produce      json
+// @Param        owner_id  query     string  false  "Only catalogs of this owner"
+// @Param        limit     query     int     false  "Page size (1-100, default 20)"
+// @Param        cursor    query     string  false  "next_cursor of the previous page"
+// @Success      200       {object}  CatalogListResponse
+// @Failure      400       {object}  ErrorResponse
+// @Failure      401       {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /catalogs [get]
+func (h *CatalogHandler) List(c *gin.Context) {
+	var req ListCatalogsQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	page, err := h.catalogService.ListCatalogs(c.Request.Context(), domain.CatalogQuery{OwnerID: req.OwnerID, Limit: req.Limit}, req.Cursor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	items := make([]CatalogResponse, 0, len(page.Items))
+	for _, catalog := range page.Items {
+		items = append(items, newCatalogResponse(catalog))
+	}
+	c.JSON(http.StatusOK, CatalogListResponse{Items: items, NextCursor: page.NextCursor})
+}
+
+// AddLot godoc
+// @Summary      Add a lot to a catalog
+// @Description  Add a pending or scheduled auction of the catalog's owner as the catalog's next lot. The auction's start time moves to the catalog's and its end time to the lot's closing slot. Lots can only be added before the sale starts.
+// @Tags         Catalogs
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string         true  "Catalog ID"
+// @Param        request  body      AddLotRequest  true  "Auction to add"
+// @Success      201      {object}  LotResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /catalogs/{id}/lots [post]
+func (h *CatalogHandler) AddLot(c *gin.Context) {
+	var req AddLotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	lot, err := h.catalogService.AddLot(c.Request.Context(), c.Param("id"), userID.(string), req.AuctionID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// The lot was just moved into its slot
+	c.JSON(http.StatusCreated, LotResponse{Number: lot.Number, ScheduledEndTime: lot.Auction.EndTime, Auction: lot.Auction})
+}
+
+// RemoveLot godoc
+// @Summary      Remove a lot from a catalog
+// @Description  Take an auction out of a catalog before the sale starts. The auction keeps its times; later lots move up one number and into that number's closing slot.
+// @Tags         Catalogs
+// @Param        id          path  string  true  "Catalog ID"
+// @Param        auction_id  path  string  true  "Auction ID of the lot"
+// @Success      204
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /catalogs/{id}/lots/{auction_id} [delete]
+func (h *CatalogHandler) RemoveLot(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	if err := h.catalogService.RemoveLot(c.Request.Context(), c.Param("id"), userID.(string), c.Param("auction_id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Schedule godoc
+// @Summary      Schedule a catalog
+// @Description  Schedule every pending lot of a catalog to open for bidding automatically at the catalog's start time. Only the catalog's owner may schedule it.
+// @Tags         Catalogs
+// @Produce      json
+// @Param        id   path      string  true  "Catalog ID"
+// @Success      200  {object}  CatalogDetailResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /catalogs/{id}/schedule [post]
+func (h *CatalogHandler) Schedule(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	detail, err := h.catalogService.ScheduleCatalog(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCatalogDetailResponse(detail, time.Now()))
+}
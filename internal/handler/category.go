@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/service"
+)
+
+type CategoryHandler struct {
+	categoryService *service.CategoryService
+}
+
+func NewCategoryHandler(categoryService *service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{categoryService: categoryService}
+}
+
+// AttributeDefinitionJSON describes a product attribute of a category
+type AttributeDefinitionJSON struct {
+	Name string `json:"name" binding:"required" example:"screen_size"`
+	// Type is text, number, enum or boolean
+	Type     domain.AttributeType `json:"type" binding:"required" example:"number"`
+	Required bool                 `json:"required" example:"true"`
+	// Options lists the values of an enum attribute
+	Options []string `json:"options,omitempty" example:"black,silver"`
+	// Min and Max bound a number attribute, inclusive
+	Min *float64 `json:"min,omitempty" example:"10"`
+	Max *float64 `json:"max,omitempty" example:"20"`
+}
+
+// IncrementStepJSON sets the minimum bid increment for current prices from
+// "from" up to the next step
+type IncrementStepJSON struct {
+	From      float64 `json:"from" binding:"min=0" example:"100"`
+	Increment float64 `json:"increment" binding:"gt=0" example:"5"`
+}
+
+// AuctionDefaultsJSON are the settings new auctions of the category's products
+// start with. Omitted settings are inherited from the parent category.
+type AuctionDefaultsJSON struct {
+	// IncrementTable lists steps by ascending from, the first from 0; an empty
+	// table accepts any higher bid
+	IncrementTable []IncrementStepJSON `json:"increment_table,omitempty"`
+	// SoftCloseSeconds extends auctions on bids this close to their end; 0
+	// disables soft close
+	SoftCloseSeconds *int `json:"soft_close_seconds,omitempty" binding:"omitempty,min=0" example:"120"`
+}
+
+// CategoryRequest holds the settings of a category to create, or to replace
+// all settings of an existing category with
+type CategoryRequest struct {
+	ParentID   string                    `json:"parent_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name       string                    `json:"name" binding:"required,max=100" example:"Laptops"`
+	Attributes []AttributeDefinitionJSON `json:"attributes" binding:"dive"`
+	Defaults   AuctionDefaultsJSON       `json:"auction_defaults"`
+	// Fees override the fee schedules of the category's auctions
+	Fees FeeSchedulesJSON `json:"fees"`
+}
+
+type CategoryResponse struct {
+	ID         string                    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ParentID   string                    `json:"parent_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Name       string                    `json:"name" example:"Laptops"`
+	Attributes []AttributeDefinitionJSON `json:"attributes"`
+	Defaults   AuctionDefaultsJSON       `json:"auction_defaults"`
+	Fees       FeeSchedulesJSON          `json:"fees"`
+	CreatedAt  time.Time                 `json:"created_at" example:"2026-03-01T10:00:00Z"`
+}
+
+// CategoryTreeNode is a category with its subcategories
+type CategoryTreeNode struct {
+	CategoryResponse
+	Children []*CategoryTreeNode `json:"children"`
+}
+
+// CategoryDetailResponse is a category with what its products inherit from
+// it and its ancestors
+type CategoryDetailResponse struct {
+	CategoryResponse
+	// Path lists the ancestors from the top of the tree, then the category
+	Path []CategoryResponse `json:"path"`
+	// EffectiveAttributes are the attributes products of the category are
+	// validated against
+	EffectiveAttributes []AttributeDefinitionJSON `json:"effective_attributes"`
+	// EffectiveDefaults are the settings its new auctions start with
+	EffectiveDefaults AuctionDefaultsJSON `json:"effective_auction_defaults"`
+}
+
+func (r *CategoryRequest) input() service.CategoryInput {
+	input := service.CategoryInput{
+		ParentID:   r.ParentID,
+		Name:       r.Name,
+		Attributes: make([]domain.AttributeDefinition, 0, len(r.Attributes)),
+		Defaults:   r.Defaults.defaults(),
+		Fees:       r.Fees.schedules(),
+	}
+	for _, a := range r.Attributes {
+		input.Attributes = append(input.Attributes, domain.AttributeDefinition(a))
+	}
+	return input
+}
+
+func (d *AuctionDefaultsJSON) defaults() domain.AuctionDefaults {
+	defaults := domain.AuctionDefaults{SoftCloseSeconds: d.SoftCloseSeconds}
+	if d.IncrementTable != nil {
+		defaults.IncrementTable = make([]domain.IncrementStep, 0, len(d.IncrementTable))
+		for _, step := range d.IncrementTable {
+			defaults.IncrementTable = append(defaults.IncrementTable, domain.IncrementStep(step))
+		}
+	}
+	return defaults
+}
+
+func newAttributesJSON(definitions []domain.AttributeDefinition) []AttributeDefinitionJSON {
+	attributes := make([]AttributeDefinitionJSON, 0, len(definitions))
+	for _, d := range definitions {
+		attributes = append(attributes, AttributeDefinitionJSON(d))
+	}
+	return attributes
+}
+
+func newAuctionDefaultsJSON(defaults domain.AuctionDefaults) AuctionDefaultsJSON {
+	response := AuctionDefaultsJSON{SoftCloseSeconds: defaults.SoftCloseSeconds}
+	for _, step := range defaults.IncrementTable {
+		response.IncrementTable = append(response.IncrementTable, IncrementStepJSON(step))
+	}
+	return response
+}
+
+func newCategoryResponse(category *domain.Category) CategoryResponse {
+	return CategoryResponse{
+		ID:         category.ID,
+		ParentID:   category.ParentID,
+		Name:       category.Name,
+		Attributes: newAttributesJSON(category.Attributes),
+		Defaults:   newAuctionDefaultsJSON(category.Defaults),
+		Fees:       newFeeSchedulesJSON(category.Fees),
+		CreatedAt:  category.CreatedAt,
+	}
+}
+
+// newCategoryTree nests categories under their parents, keeping their order
+func newCategoryTree(categories []*domain.Category) []*CategoryTreeNode {
+	nodes := make(map[string]*CategoryTreeNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &CategoryTreeNode{
+			CategoryResponse: newCategoryResponse(category),
+			Children:         []*CategoryTreeNode{},
+		}
+	}
+	roots := []*CategoryTreeNode{}
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if parent, ok := nodes[category.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
+
+// Create godoc
+// @Summary      Create a category
+// @Description  Create a category, optionally below a parent. Products of the category must satisfy its attribute definitions and those of its ancestors; its auction defaults apply to new auctions of its products, and its fee schedules to their settlement. Requires the admin role.
+// @Tags         Categories
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CategoryRequest  true  "Category settings"
+// @Success      201      {object}  CategoryResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /categories [post]
+func (h *CategoryHandler) Create(c *gin.Context) {
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(c.Request.Context(), req.input())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newCategoryResponse(category))
+}
+
+// Get godoc
+// @Summary      Get a category
+// @Description  Get a category with its path from the top of the tree and the attribute definitions and auction defaults its products inherit
+// @Tags         Categories
+// @Produce      json
+// @Param        id   path      string  true  "Category ID"
+// @Success      200  {object}  CategoryDetailResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /categories/{id} [get]
+func (h *CategoryHandler) Get(c *gin.Context) {
+	schema, err := h.categoryService.GetSchema(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := CategoryDetailResponse{
+		CategoryResponse:    newCategoryResponse(schema.Path[len(schema.Path)-1]),
+		Path:                make([]CategoryResponse, 0, len(schema.Path)),
+		EffectiveAttributes: newAttributesJSON(schema.Attributes),
+		EffectiveDefaults:   newAuctionDefaultsJSON(schema.Defaults),
+	}
+	for _, category := range schema.Path {
+		response.Path = append(response.Path, newCategoryResponse(category))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// List godoc
+// @Summary      List categories
+// @Description  Get the whole category tree: top-level categories with their subcategories nested as children, siblings by name
+// @Tags         Categories
+// @Produce      json
+// @Success      200  {array}   CategoryTreeNode
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /categories [get]
+func (h *CategoryHandler) List(c *gin.Context) {
+	categories, err := h.categoryService.ListCategories(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCategoryTree(categories))
+}
+
+// Update godoc
+// @Summary      Replace a category
+// @Description  Replace all settings of a category, which may move it below another parent. Existing products and auctions are not revalidated or changed. Requires the admin role.
+// @Tags         Categories
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string           true  "Category ID"
+// @Param        request  body      CategoryRequest  true  "Category settings"
+// @Success      200      {object}  CategoryResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /categories/{id} [put]
+func (h *CategoryHandler) Update(c *gin.Context) {
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(c.Request.Context(), c.Param("id"), req.input())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCategoryResponse(category))
+}
+
+// Delete godoc
+// @Summary      Delete a category
+// @Description  Delete a category that has no subcategories or products. Requires the admin role.
+// @Tags         Categories
+// @Param        id   path  string  true  "Category ID"
+// @Success      204
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /categories/{id} [delete]
+func (h *CategoryHandler) Delete(c *gin.Context) {
+	if err := h.categoryService.DeleteCategory(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
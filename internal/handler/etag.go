@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/service"
+)
+
+// setETag sets the ETag header to the version of the returned resource
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatchVersion returns the version named by the If-Match header, or
+// service.AnyVersion if there is none or it is "*". If the header names
+// something other than a single version issued in an ETag, it responds with
+// 412 and returns false.
+func ifMatchVersion(c *gin.Context) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return service.AnyVersion, true
+	}
+
+	if unquoted, err := strconv.Unquote(header); err == nil {
+		if version, err := strconv.Atoi(unquoted); err == nil && version > 0 {
+			return version, true
+		}
+	}
+	respondError(c, errPreconditionFailed)
+	return 0, false
+}
@@ -0,0 +1,115 @@
+package handler
+
+import "github.com/saigenix/bidding-system/internal/domain"
+
+// FeeTierJSON charges percent on the part of the hammer price from "from" up
+// to the next tier
+type FeeTierJSON struct {
+	From    float64 `json:"from" binding:"min=0" example:"1000"`
+	Percent float64 `json:"percent" binding:"min=0" example:"20"`
+}
+
+// FeeScheduleJSON computes a fee from the hammer price
+type FeeScheduleJSON struct {
+	// Type is flat, percentage or tiered
+	Type domain.FeeType `json:"type" binding:"required" example:"percentage"`
+	// Amount is the fee of a flat schedule, or the percentage of the hammer
+	// price charged by a percentage schedule
+	Amount float64 `json:"amount,omitempty" binding:"min=0" example:"15"`
+	// Tiers of a tiered schedule, by ascending from, the first from 0
+	Tiers []FeeTierJSON `json:"tiers,omitempty" binding:"dive"`
+	// Minimum is the lowest fee charged; 0 for none
+	Minimum float64 `json:"minimum,omitempty" binding:"min=0" example:"5"`
+	// Maximum caps the fee; 0 for no cap
+	Maximum float64 `json:"maximum,omitempty" binding:"min=0" example:"500"`
+}
+
+// FeeSchedulesJSON are the fees charged when an auction is won. Omitted
+// schedules are inherited from the parent category, or the server defaults.
+type FeeSchedulesJSON struct {
+	BuyerPremium     *FeeScheduleJSON `json:"buyer_premium,omitempty"`
+	SellerCommission *FeeScheduleJSON `json:"seller_commission,omitempty"`
+}
+
+// FeeItemJSON is one line of a fee breakdown
+type FeeItemJSON struct {
+	// Kind is buyer_premium, seller_commission or tax
+	Kind        domain.FeeKind `json:"kind" example:"buyer_premium"`
+	Description string         `json:"description" example:"15% of 150.00"`
+	// Amount is negative for lines lowering a fee to its cap
+	Amount float64 `json:"amount" example:"22.50"`
+}
+
+// CostEstimateResponse is what winning an auction with a bid would cost the
+// bidder
+type CostEstimateResponse struct {
+	// Amount is the bid estimated for
+	Amount       float64 `json:"amount" example:"150.00"`
+	BuyerPremium float64 `json:"buyer_premium" example:"22.50"`
+	Tax          float64 `json:"tax" example:"17.25"`
+	// Total is the bid, buyer's premium and tax
+	Total float64       `json:"total" example:"189.75"`
+	Items []FeeItemJSON `json:"items"`
+}
+
+func (s *FeeScheduleJSON) schedule() *domain.FeeSchedule {
+	if s == nil {
+		return nil
+	}
+	schedule := &domain.FeeSchedule{Type: s.Type, Amount: s.Amount, Minimum: s.Minimum, Maximum: s.Maximum}
+	for _, tier := range s.Tiers {
+		schedule.Tiers = append(schedule.Tiers, domain.FeeTier(tier))
+	}
+	return schedule
+}
+
+func (f *FeeSchedulesJSON) schedules() domain.FeeSchedules {
+	return domain.FeeSchedules{
+		BuyerPremium:     f.BuyerPremium.schedule(),
+		SellerCommission: f.SellerCommission.schedule(),
+	}
+}
+
+func newFeeScheduleJSON(schedule *domain.FeeSchedule) *FeeScheduleJSON {
+	if schedule == nil {
+		return nil
+	}
+	response := &FeeScheduleJSON{Type: schedule.Type, Amount: schedule.Amount, Minimum: schedule.Minimum, Maximum: schedule.Maximum}
+	for _, tier := range schedule.Tiers {
+		response.Tiers = append(response.Tiers, FeeTierJSON(tier))
+	}
+	return response
+}
+
+func newFeeSchedulesJSON(fees domain.FeeSchedules) FeeSchedulesJSON {
+	return FeeSchedulesJSON{
+		BuyerPremium:     newFeeScheduleJSON(fees.BuyerPremium),
+		SellerCommission: newFeeScheduleJSON(fees.SellerCommission),
+	}
+}
+
+func newFeeItemsJSON(items []domain.FeeItem) []FeeItemJSON {
+	response := make([]FeeItemJSON, 0, len(items))
+	for _, item := range items {
+		response = append(response, FeeItemJSON(item))
+	}
+	return response
+}
+
+// newCostEstimateResponse shows the bidder's side of a breakdown; the seller's
+// commission is not theirs to see
+func newCostEstimateResponse(breakdown *domain.FeeBreakdown) *CostEstimateResponse {
+	response := &CostEstimateResponse{
+		Amount:       breakdown.HammerPrice,
+		BuyerPremium: breakdown.BuyerPremium,
+		Tax:          breakdown.Tax,
+		Total:        breakdown.BuyerTotal(),
+		Items:        []FeeItemJSON{},
+	}
+	for _, item := range breakdown.Items {
+		if item.Kind != domain.FeeKindSellerCommission {
+			response.Items = append(response.Items, FeeItemJSON(item))
+		}
+	}
+	return response
+}
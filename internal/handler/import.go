@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/service"
+)
+
+type ImportHandler struct {
+	importService *service.ImportService
+}
+
+func NewImportHandler(importService *service.ImportService) *ImportHandler {
+	return &ImportHandler{importService: importService}
+}
+
+// ImportRowErrorResponse is a problem with one row of an import file
+type ImportRowErrorResponse struct {
+	Line    int    `json:"line" example:"3"`
+	Field   string `json:"field,omitempty" example:"starting_price"`
+	Message string `json:"message" example:"\"abc\" is not a number"`
+}
+
+// ImportJobResponse is the progress and outcome of an import
+type ImportJobResponse struct {
+	ID            string                   `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Status        domain.ImportJobStatus   `json:"status" example:"validating"`
+	Format        domain.ImportFormat      `json:"format" example:"csv"`
+	DryRun        bool                     `json:"dry_run" example:"false"`
+	TotalRows     int                      `json:"total_rows" example:"1200"`
+	ProcessedRows int                      `json:"processed_rows" example:"300"`
+	ProductCount  int                      `json:"product_count" example:"0"`
+	AuctionCount  int                      `json:"auction_count" example:"0"`
+	Errors        []ImportRowErrorResponse `json:"errors"`
+	// ErrorCount counts all row errors; errors lists the first 100
+	ErrorCount int        `json:"error_count" example:"0"`
+	Error      string     `json:"error,omitempty" example:""`
+	CreatedAt  time.Time  `json:"created_at" example:"2026-03-01T10:00:00Z"`
+	StartedAt  *time.Time `json:"started_at,omitempty" example:"2026-03-01T10:00:00Z"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+func newImportJobResponse(job *domain.ImportJob) ImportJobResponse {
+	errs := make([]ImportRowErrorResponse, 0, len(job.Errors))
+	for _, e := range job.Errors {
+		errs = append(errs, ImportRowErrorResponse{Line: e.Line, Field: e.Field, Message: e.Message})
+	}
+	return ImportJobResponse{
+		ID:            job.ID,
+		Status:        job.Status,
+		Format:        job.Format,
+		DryRun:        job.DryRun,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		ProductCount:  job.ProductCount,
+		AuctionCount:  job.AuctionCount,
+		Errors:        errs,
+		ErrorCount:    job.ErrorCount,
+		Error:         job.Error,
+		CreatedAt:     job.CreatedAt,
+		StartedAt:     job.StartedAt,
+		FinishedAt:    job.FinishedAt,
+	}
+}
+
+// Create godoc
+// @Summary      Import products and auctions
+// @Description  Import up to 5000 products, each with an optional auction, from a CSV or JSON lines file of up to 10 MB sent as multipart form field "file". The format is taken from form field "format" or else the file extension (.csv, .jsonl or .ndjson). Every row is validated like a single product and auction; a single invalid row fails the whole import, and the errors of the first 100 invalid values are listed by line. Auctions are created pending. With dry_run=true the rows are only validated. Files of up to 100 rows are imported before responding with 201; larger files are imported in the background, responding 202 with the queued job, whose progress GET /imports/{id} reports.
+// @Tags         Imports
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file     formData  file    true   "CSV or JSON lines file"
+// @Param        format   formData  string  false  "csv or jsonl"
+// @Param        dry_run  formData  bool    false  "Only validate the rows"
+// @Success      201      {object}  ImportJobResponse
+// @Success      202      {object}  ImportJobResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      413      {object}  ErrorResponse
+// @Failure      415      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /imports [post]
+func (h *ImportHandler) Create(c *gin.Context) {
+	// Leave room for the other form fields
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxImportSize+1<<20)
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(c, fmt.Errorf("%w: import files may be at most %d MB", domain.ErrFileTooLarge, service.MaxImportSize>>20))
+			return
+		}
+		respondInvalid(c, fmt.Errorf("multipart form field \"file\" is required: %w", err))
+		return
+	}
+	defer file.Close()
+
+	format := domain.ImportFormat(c.Request.FormValue("format"))
+	if format == "" {
+		format = importFormatOf(header.Filename)
+	}
+	dryRun := false
+	if value := c.Request.FormValue("dry_run"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			respondInvalid(c, fmt.Errorf("dry_run must be true or false"))
+			return
+		}
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to read import file: %w", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	job, err := h.importService.Import(c.Request.Context(), userID.(string), format, dryRun, data)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if !job.Status.Finished() {
+		c.Header("Location", "/imports/"+job.ID)
+		c.JSON(http.StatusAccepted, newImportJobResponse(job))
+		return
+	}
+	c.JSON(http.StatusCreated, newImportJobResponse(job))
+}
+
+// Get godoc
+// @Summary      Get an import
+// @Description  Get the progress of an import, and once it has finished, its outcome. Users only see their own imports.
+// @Tags         Imports
+// @Produce      json
+// @Param        id   path      string  true  "Import job ID"
+// @Success      200  {object}  ImportJobResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /imports/{id} [get]
+func (h *ImportHandler) Get(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	job, err := h.importService.GetJob(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newImportJobResponse(job))
+}
+
+// importFormatOf guesses the format of an import file from its name
+func importFormatOf(filename string) domain.ImportFormat {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return domain.ImportFormatCSV
+	case ".jsonl", ".ndjson":
+		return domain.ImportFormatJSONLines
+	}
+	return ""
+}
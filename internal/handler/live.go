@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/service"
+)
+
+// LiveHandler serves the clerk console of live auctions
+type LiveHandler struct {
+	liveService *service.LiveService
+}
+
+func NewLiveHandler(liveService *service.LiveService) *LiveHandler {
+	return &LiveHandler{liveService: liveService}
+}
+
+// OpenLotRequest optionally sets the first asking price of a live lot
+type OpenLotRequest struct {
+	AskingPrice float64 `json:"asking_price" binding:"min=0" example:"100.00"`
+}
+
+// AskRequest calls a new asking price
+type AskRequest struct {
+	AskingPrice float64 `json:"asking_price" binding:"required,gt=0" example:"120.00"`
+}
+
+// FloorBidRequest is a bid made in the sale room, entered by a clerk
+type FloorBidRequest struct {
+	Paddle string  `json:"paddle" binding:"required,max=20" example:"42"`
+	Amount float64 `json:"amount" binding:"required,gt=0" example:"120.00"`
+}
+
+// LiveLotResponse is a live lot with its auction and the server's clock
+type LiveLotResponse struct {
+	AuctionID    string              `json:"auction_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	AuctioneerID string              `json:"auctioneer_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	State        domain.LiveLotState `json:"state" example:"open"`
+	AskingPrice  float64             `json:"asking_price" example:"120.00"`
+	LastAction   domain.LiveAction   `json:"last_action" example:"asked"`
+	OpenedAt     time.Time           `json:"opened_at" example:"2026-03-01T18:00:00Z"`
+	UpdatedAt    time.Time           `json:"updated_at" example:"2026-03-01T18:02:10Z"`
+	Auction      *domain.Auction     `json:"auction"`
+	ServerTime   time.Time           `json:"server_time" example:"2026-03-01T18:02:11.123Z"`
+}
+
+func newLiveLotResponse(detail *service.LiveLotDetail, now time.Time) LiveLotResponse {
+	return LiveLotResponse{
+		AuctionID:    detail.AuctionID,
+		AuctioneerID: detail.AuctioneerID,
+		State:        detail.State,
+		AskingPrice:  detail.AskingPrice,
+		LastAction:   detail.LastAction,
+		OpenedAt:     detail.OpenedAt,
+		UpdatedAt:    detail.UpdatedAt,
+		Auction:      detail.Auction,
+		ServerTime:   now,
+	}
+}
+
+// Get godoc
+// @Summary      Get a live lot
+// @Description  Get the sale room state of a live auction: its asking price, whether it is open, under fair warning, sold or passed, and the auction with its current price.
+// @Tags         Live
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID"
+// @Success      200  {object}  LiveLotResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/live [get]
+func (h *LiveHandler) Get(c *gin.Context) {
+	detail, err := h.liveService.GetLot(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newLiveLotResponse(detail, time.Now()))
+}
+
+// Open godoc
+// @Summary      Open a live lot
+// @Description  Start a pending or scheduled auction as a live lot run by the caller. Bidding stays open past the auction's end time until the lot is sold or passed, and soft close does not apply. The asking price defaults to the auction's next valid bid. Requires the auctioneer role.
+// @Tags         Live
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string          true   "Auction ID"
+// @Param        request  body      OpenLotRequest  false  "First asking price"
+// @Success      201      {object}  LiveLotResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/live/open [post]
+func (h *LiveHandler) Open(c *gin.Context) {
+	var req OpenLotRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	detail, err := h.liveService.OpenLot(c.Request.Context(), c.Param("id"), userID.(string), req.AskingPrice)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newLiveLotResponse(detail, time.Now()))
+}
+
+// Ask godoc
+// @Summary      Call an asking price
+// @Description  Set the asking price of an open live lot, which bids online and from the floor must meet. It must be a valid bid and takes the lot out of fair warning. Every bid moves the asking price up to the next valid bid. Requires the auctioneer role.
+// @Tags         Live
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string      true  "Auction ID"
+// @Param        request  body      AskRequest  true  "Asking price"
+// @Success      200      {object}  LiveLotResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/live/ask [post]
+func (h *LiveHandler) Ask(c *gin.Context) {
+	var req AskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	detail, err := h.liveService.Ask(c.Request.Context(), c.Param("id"), req.AskingPrice)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newLiveLotResponse(detail, time.Now()))
+}
+
+// FloorBid godoc
+// @Summary      Enter a floor bid
+// @Description  Record a bid made in the sale room by a paddle holder. Floor bids are kept apart from online bids: their source is floor, they carry the paddle instead of a user, and they are shown as "Paddle <number>". The amount must meet the asking price and beat the current price. Requires the auctioneer role.
+// @Tags         Live
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string           true  "Auction ID"
+// @Param        request  body      FloorBidRequest  true  "Floor bid"
+// @Success      201      {object}  domain.Bid
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      422      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/live/floor-bids [post]
+func (h *LiveHandler) FloorBid(c *gin.Context) {
+	var req FloorBidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	bid, err := h.liveService.FloorBid(c.Request.Context(), c.Param("id"), userID.(string), req.Paddle, req.Amount)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, bid)
+}
+
+// FairWarning godoc
+// @Summary      Give fair warning
+// @Description  Announce that an open live lot is about to be sold. A new bid or asking price reopens it. Requires the auctioneer role.
+// @Tags         Live
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID"
+// @Success      200  {object}  LiveLotResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/live/fair-warning [post]
+func (h *LiveHandler) FairWarning(c *gin.Context) {
+	detail, err := h.liveService.FairWarning(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newLiveLotResponse(detail, time.Now()))
+}
+
+// Sell godoc
+// @Summary      Hammer a live lot
+// @Description  Sell an open live lot to its highest bidder, online or on the floor, ending the auction. A lot without bids can only be passed. Requires the auctioneer role.
+// @Tags         Live
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID"
+// @Success      200  {object}  LiveLotResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/live/sold [post]
+func (h *LiveHandler) Sell(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	detail, err := h.liveService.Sell(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newLiveLotResponse(detail, time.Now()))
+}
+
+// Pass godoc
+// @Summary      Pass a live lot
+// @Description  Withdraw an open live lot unsold, cancelling the auction. Requires the auctioneer role.
+// @Tags         Live
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID"
+// @Success      200  {object}  LiveLotResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/live/pass [post]
+func (h *LiveHandler) Pass(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	detail, err := h.liveService.Pass(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newLiveLotResponse(detail, time.Now()))
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/service"
+)
+
+type OrderHandler struct {
+	orderService *service.OrderService
+}
+
+func NewOrderHandler(orderService *service.OrderService) *OrderHandler {
+	return &OrderHandler{orderService: orderService}
+}
+
+// ListOrdersQuery holds the filter and paging query parameters of GET /orders
+type ListOrdersQuery struct {
+	PageQuery
+	// Role only lists the orders where the caller is the buyer or the seller
+	Role   domain.OrderParty  `form:"role" enums:"buyer,seller" example:"buyer"`
+	Status domain.OrderStatus `form:"status" enums:"awaiting_payment,paid,shipped,completed,cancelled" example:"awaiting_payment"`
+}
+
+type OrderResponse struct {
+	ID               string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440005"`
+	AuctionID        string  `json:"auction_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ProductID        string  `json:"product_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	BidID            string  `json:"bid_id" example:"550e8400-e29b-41d4-a716-446655440003"`
+	BuyerID          string  `json:"buyer_id" example:"550e8400-e29b-41d4-a716-446655440002"`
+	SellerID         string  `json:"seller_id" example:"550e8400-e29b-41d4-a716-446655440004"`
+	HammerPrice      float64 `json:"hammer_price" example:"150.00"`
+	BuyerPremium     float64 `json:"buyer_premium" example:"22.50"`
+	SellerCommission float64 `json:"seller_commission" example:"15.00"`
+	Tax              float64 `json:"tax" example:"17.25"`
+	// Total is what the buyer owes: hammer price, buyer's premium and tax
+	Total float64 `json:"total" example:"189.75"`
+	// Fees itemize the buyer's premium, seller commission and tax
+	Fees []FeeItemJSON `json:"fees"`
+	// SellerProceeds is what the seller receives: hammer price less commission
+	SellerProceeds float64            `json:"seller_proceeds" example:"135.00"`
+	Status         domain.OrderStatus `json:"status" example:"awaiting_payment"`
+	DueAt          time.Time          `json:"due_at" example:"2026-03-15T18:00:00Z"`
+	CreatedAt      time.Time          `json:"created_at" example:"2026-03-08T18:00:00Z"`
+	UpdatedAt      time.Time          `json:"updated_at" example:"2026-03-08T18:00:00Z"`
+}
+
+type OrderListResponse struct {
+	Items      []OrderResponse `json:"items"`
+	NextCursor string          `json:"next_cursor,omitempty" example:"bmV3ZXN0fHQyMDI2LTAzLTAxVDEwOjAwOjAwWnw1NTBlODQwMA"`
+}
+
+func newOrderResponse(order *domain.Order) OrderResponse {
+	return OrderResponse{
+		ID:               order.ID,
+		AuctionID:        order.AuctionID,
+		ProductID:        order.ProductID,
+		BidID:            order.BidID,
+		BuyerID:          order.BuyerID,
+		SellerID:         order.SellerID,
+		HammerPrice:      order.HammerPrice,
+		BuyerPremium:     order.BuyerPremium,
+		SellerCommission: order.SellerCommission,
+		Tax:              order.Tax,
+		Total:            order.Total,
+		Fees:             newFeeItemsJSON(order.Fees),
+		SellerProceeds:   order.SellerProceeds(),
+		Status:           order.Status,
+		DueAt:            order.DueAt,
+		CreatedAt:        order.CreatedAt,
+		UpdatedAt:        order.UpdatedAt,
+	}
+}
+
+// List godoc
+// @Summary      List my orders
+// @Description  Get a page of the orders the caller won or sold, newest first. Orders are created when auctions with a winning bid close. Pass the response's next_cursor as cursor to get the next page; it is omitted on the last page.
+// @Tags         Orders
+// @Produce      json
+// @Param        role    query     string  false  "Only orders where the caller is the buyer or the seller"  Enums(buyer, seller)
+// @Param        status  query     string  false  "Only orders in this status"  Enums(awaiting_payment, paid, shipped, completed, cancelled)
+// @Param        limit   query     int     false  "Page size (1-100, default 20)"
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
+// @Success      200     {object}  OrderListResponse
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /orders [get]
+func (h *OrderHandler) List(c *gin.Context) {
+	var req ListOrdersQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	query := domain.OrderQuery{UserID: userID.(string), Party: req.Role, Status: req.Status, Limit: req.Limit}
+	page, err := h.orderService.ListOrders(c.Request.Context(), query, req.Cursor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	items := make([]OrderResponse, 0, len(page.Items))
+	for _, order := range page.Items {
+		items = append(items, newOrderResponse(order))
+	}
+	c.JSON(http.StatusOK, OrderListResponse{Items: items, NextCursor: page.NextCursor})
+}
+
+// Get godoc
+// @Summary      Get an order
+// @Description  Get an order the caller won or sold
+// @Tags         Orders
+// @Produce      json
+// @Param        id   path      string  true  "Order ID"
+// @Success      200  {object}  OrderResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /orders/{id} [get]
+func (h *OrderHandler) Get(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	order, err := h.orderService.GetOrder(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newOrderResponse(order))
+}
+
+// MarkPaid godoc
+// @Summary      Mark an order paid
+// @Description  Confirm that the buyer has paid an order awaiting payment. Only the seller can mark an order paid.
+// @Tags         Orders
+// @Produce      json
+// @Param        id   path      string  true  "Order ID"
+// @Success      200  {object}  OrderResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /orders/{id}/mark-paid [post]
+func (h *OrderHandler) MarkPaid(c *gin.Context) {
+	h.updateStatus(c, domain.OrderStatusPaid)
+}
+
+// Ship godoc
+// @Summary      Ship an order
+// @Description  Mark a paid order shipped. Only the seller can ship an order.
+// @Tags         Orders
+// @Produce      json
+// @Param        id   path      string  true  "Order ID"
+// @Success      200  {object}  OrderResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /orders/{id}/ship [post]
+func (h *OrderHandler) Ship(c *gin.Context) {
+	h.updateStatus(c, domain.OrderStatusShipped)
+}
+
+// Complete godoc
+// @Summary      Complete an order
+// @Description  Confirm receipt of a shipped order, which settles its auction. Only the buyer can complete an order.
+// @Tags         Orders
+// @Produce      json
+// @Param        id   path      string  true  "Order ID"
+// @Success      200  {object}  OrderResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /orders/{id}/complete [post]
+func (h *OrderHandler) Complete(c *gin.Context) {
+	h.updateStatus(c, domain.OrderStatusCompleted)
+}
+
+// Cancel godoc
+// @Summary      Cancel an order
+// @Description  Cancel an order that has not been shipped, such as when the buyer does not pay. Only the seller can cancel an order.
+// @Tags         Orders
+// @Produce      json
+// @Param        id   path      string  true  "Order ID"
+// @Success      200  {object}  OrderResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /orders/{id}/cancel [post]
+func (h *OrderHandler) Cancel(c *gin.Context) {
+	h.updateStatus(c, domain.OrderStatusCancelled)
+}
+
+// updateStatus moves the order in the path to status to for the caller
+func (h *OrderHandler) updateStatus(c *gin.Context, to domain.OrderStatus) {
+	userID, _ := c.Get("userID")
+	order, err := h.orderService.UpdateStatus(c.Request.Context(), c.Param("id"), userID.(string), to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newOrderResponse(order))
+}
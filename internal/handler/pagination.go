@@ -0,0 +1,9 @@
+package handler
+
+// PageQuery holds the paging query parameters of list endpoints
+type PageQuery struct {
+	// Limit is the page size; zero or absent means 20
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+	// Cursor is the next_cursor of the previous page; absent for the first page
+	Cursor string `form:"cursor" example:"bmV3ZXN0fHQyMDI2LTAzLTAxVDEwOjAwOjAwWnw1NTBlODQwMA"`
+}
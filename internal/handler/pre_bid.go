@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// PreBidRequest sets the most a pre-bid may bid when the auction opens
+type PreBidRequest struct {
+	MaxAmount float64 `json:"max_amount" binding:"required,gt=0" example:"250.00"`
+}
+
+// PreBidResponse is the caller's pre-bid on an auction
+type PreBidResponse struct {
+	ID         string     `json:"id" example:"550e8400-e29b-41d4-a716-446655440002"`
+	AuctionID  string     `json:"auction_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	MaxAmount  float64    `json:"max_amount" example:"250.00"`
+	ExecutedAt *time.Time `json:"executed_at,omitempty" example:"2026-03-01T18:00:01Z"`
+	CreatedAt  time.Time  `json:"created_at" example:"2026-02-25T09:30:00Z"`
+	UpdatedAt  time.Time  `json:"updated_at" example:"2026-02-26T14:05:00Z"`
+}
+
+func newPreBidResponse(preBid *domain.PreBid) PreBidResponse {
+	return PreBidResponse{
+		ID:         preBid.ID,
+		AuctionID:  preBid.AuctionID,
+		MaxAmount:  preBid.MaxAmount,
+		ExecutedAt: preBid.ExecutedAt,
+		CreatedAt:  preBid.CreatedAt,
+		UpdatedAt:  preBid.UpdatedAt,
+	}
+}
+
+// SetPreBid godoc
+// @Summary      Place or edit a pre-bid
+// @Description  Leave an absentee bid on a pending or scheduled auction, or change its maximum. When the auction opens, pre-bids bid before anyone else, highest maximum first with the maximum set earliest winning ties: the strongest opens at one increment over the runner-up's maximum (or over the starting price if it is alone) and keeps bidding for its owner up to its maximum like a proxy bid. Editing a maximum counts as setting it anew for ties. Requires a verified email address, and two-factor authentication for maxima above the auction's two-factor threshold.
+// @Tags         Bids
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string         true  "Auction ID"
+// @Param        request  body      PreBidRequest  true  "Pre-bid maximum"
+// @Success      200      {object}  PreBidResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      422      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/pre-bid [put]
+func (h *BidHandler) SetPreBid(c *gin.Context) {
+	var req PreBidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	preBid, err := h.bidService.SetPreBid(c.Request.Context(), c.Param("id"), userID.(string), req.MaxAmount)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newPreBidResponse(preBid))
+}
+
+// GetPreBid godoc
+// @Summary      Get my pre-bid
+// @Description  Get the caller's pre-bid on an auction. Once the auction has opened, executed_at is set.
+// @Tags         Bids
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID"
+// @Success      200  {object}  PreBidResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/pre-bid [get]
+func (h *BidHandler) GetPreBid(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	preBid, err := h.bidService.GetPreBid(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newPreBidResponse(preBid))
+}
+
+// WithdrawPreBid godoc
+// @Summary      Withdraw my pre-bid
+// @Description  Remove the caller's pre-bid from an auction that has not opened yet.
+// @Tags         Bids
+// @Param        id   path  string  true  "Auction ID"
+// @Success      204
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auctions/{id}/pre-bid [delete]
+func (h *BidHandler) WithdrawPreBid(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	if err := h.bidService.WithdrawPreBid(c.Request.Context(), c.Param("id"), userID.(string)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/realtime"
+)
+
+// ProblemContentType is the media type of every error response
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix turns a problem code into the problem's type URI
+const problemTypePrefix = "urn:bidding-system:problem:"
+
+// ErrorResponse is the body of every error response: an RFC 7807 problem
+// details object. Code is stable and meant for programs; Detail is meant for
+// people and may change.
+type ErrorResponse struct {
+	Type     string `json:"type" example:"urn:bidding-system:problem:not_found"`
+	Title    string `json:"title" example:"Not Found"`
+	Status   int    `json:"status" example:"404"`
+	Detail   string `json:"detail,omitempty" example:"auction 550e8400-e29b-41d4-a716-446655440000 not found"`
+	Instance string `json:"instance,omitempty" example:"/auctions/550e8400-e29b-41d4-a716-446655440000"`
+	Code     string `json:"code" example:"not_found"`
+}
+
+// errPreconditionFailed is returned when If-Match names no current version
+var errPreconditionFailed = errors.New("If-Match does not match the current version")
+
+// problemKind is the status and code of the errors matching err
+type problemKind struct {
+	err    error
+	status int
+	code   string
+}
+
+// problemKinds is searched in order, so specific errors come before the
+// generic ones they may wrap
+var problemKinds = []problemKind{
+	{errPreconditionFailed, http.StatusPreconditionFailed, "precondition_failed"},
+	{domain.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials"},
+	{domain.ErrEmailNotVerified, http.StatusForbidden, "email_not_verified"},
+	{domain.ErrTwoFactorRequired, http.StatusForbidden, "two_factor_required"},
+	{domain.ErrInvalidTwoFactorCode, http.StatusBadRequest, "invalid_two_factor_code"},
+	{domain.ErrTwoFactorNotEnabled, http.StatusBadRequest, "two_factor_not_enabled"},
+	{domain.ErrTwoFactorAlreadyEnabled, http.StatusConflict, "two_factor_already_enabled"},
+	{domain.ErrInvalidToken, http.StatusBadRequest, "invalid_token"},
+	{domain.ErrInvalidProfile, http.StatusBadRequest, "invalid_profile"},
+	{domain.ErrNotAuctionOwner, http.StatusForbidden, "not_auction_owner"},
+	{domain.ErrNotProductOwner, http.StatusForbidden, "not_product_owner"},
+	{domain.ErrNotCatalogOwner, http.StatusForbidden, "not_catalog_owner"},
+	{domain.ErrFileTooLarge, http.StatusRequestEntityTooLarge, "file_too_large"},
+	{domain.ErrUnsupportedMediaType, http.StatusUnsupportedMediaType, "unsupported_media_type"},
+	{domain.ErrInvalidTransition, http.StatusConflict, "invalid_transition"},
+	{domain.ErrAuctionNotEditable, http.StatusConflict, "auction_not_editable"},
+	{domain.ErrProductNotEditable, http.StatusConflict, "product_not_editable"},
+	{domain.ErrCatalogNotEditable, http.StatusConflict, "catalog_not_editable"},
+	{domain.ErrLotClosed, http.StatusConflict, "lot_closed"},
+	{domain.ErrPreBidsClosed, http.StatusConflict, "pre_bids_closed"},
+	{domain.ErrRetractionNotAllowed, http.StatusConflict, "retraction_not_allowed"},
+	{domain.ErrRetractionReviewed, http.StatusConflict, "retraction_reviewed"},
+	{domain.ErrAuctionClosed, http.StatusConflict, "auction_closed"},
+	{domain.ErrBidTooLow, http.StatusUnprocessableEntity, "bid_too_low"},
+	{domain.ErrCategoryInUse, http.StatusConflict, "category_in_use"},
+	{domain.ErrConflict, http.StatusConflict, "conflict"},
+	{domain.ErrNotFound, http.StatusNotFound, "not_found"},
+	{domain.ErrValidation, http.StatusBadRequest, "validation_failed"},
+	{domain.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{domain.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{realtime.ErrHubClosed, http.StatusServiceUnavailable, "shutting_down"},
+}
+
+// ProblemMiddleware writes the last error attached by respondError as an
+// application/problem+json response. Errors attached after a response was
+// written, such as a failed notification email, are only logged by gin.
+// Errors that map to no known kind become a 500 without detail, so database
+// and other internal messages never reach clients.
+func ProblemMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Written() || len(c.Errors) == 0 {
+			return
+		}
+		writeProblem(c, c.Errors.Last().Err)
+	}
+}
+
+// respondError stops the request with err, which ProblemMiddleware turns into
+// the response
+func respondError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// respondInvalid stops the request with a validation error, such as a body
+// that failed to bind
+func respondInvalid(c *gin.Context, err error) {
+	respondError(c, fmt.Errorf("%w: %s", domain.ErrValidation, err.Error()))
+}
+
+func writeProblem(c *gin.Context, err error) {
+	status, code := http.StatusInternalServerError, "internal_error"
+	detail := ""
+
+	var throttled *domain.LoginThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		status, code, detail = http.StatusTooManyRequests, "too_many_attempts", throttled.Error()
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	case errors.Is(err, domain.ErrConflict) && c.GetHeader("If-Match") != "":
+		// The client asked for the change only if nothing changed since its read
+		status, code, detail = http.StatusPreconditionFailed, "precondition_failed", err.Error()
+	default:
+		if kind, ok := findProblemKind(err); ok {
+			status, code, detail = kind.status, kind.code, err.Error()
+		}
+	}
+
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(status, ErrorResponse{
+		Type:     problemTypePrefix + code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+		Code:     code,
+	})
+}
+
+// findProblemKind returns the first kind in problemKinds matching err
+func findProblemKind(err error) (problemKind, bool) {
+	for _, kind := range problemKinds {
+		if errors.Is(err, kind.err) {
+			return kind, true
+		}
+	}
+	return problemKind{}, false
+}
@@ -1,33 +1,103 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
 	"github.com/saigenix/bidding-system/internal/service"
 )
 
 type ProductHandler struct {
-	productService *service.ProductService
+	productService    *service.ProductService
+	attachmentService *service.AttachmentService
 }
 
-func NewProductHandler(productService *service.ProductService) *ProductHandler {
-	return &ProductHandler{productService: productService}
+func NewProductHandler(productService *service.ProductService, attachmentService *service.AttachmentService) *ProductHandler {
+	return &ProductHandler{productService: productService, attachmentService: attachmentService}
 }
 
 type CreateProductRequest struct {
 	Name        string `json:"name" binding:"required" example:"Gaming Laptop"`
 	Description string `json:"description" example:"High-performance gaming laptop"`
+	CategoryID  string `json:"category_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	// Condition is new, like_new, used, refurbished or for_parts
+	Condition domain.ProductCondition `json:"condition,omitempty" example:"used"`
+	// Attributes holds values of the category's attributes by name
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// UpdateProductRequest holds the product fields to change; absent fields are
+// left untouched
+type UpdateProductRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1" example:"Gaming Laptop (2025)"`
+	Description *string `json:"description" example:"High-performance gaming laptop, barely used"`
+	// CategoryID moves the product to another category; "" removes its category
+	CategoryID *string                  `json:"category_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Condition  *domain.ProductCondition `json:"condition" example:"like_new"`
+	// Attributes replaces all attribute values
+	Attributes map[string]string `json:"attributes"`
+}
+
+// ListProductsQuery holds the query parameters of GET /products. Attribute
+// filters are passed as attr[name]=value and read separately.
+type ListProductsQuery struct {
+	PageQuery
+	OwnerID    string `form:"owner_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CategoryID string `form:"category_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	// IncludeArchived also lists archived products
+	IncludeArchived bool `form:"include_archived" example:"true"`
+}
+
+// ProductResponse is a product with its images and documents, each in order
+type ProductResponse struct {
+	*domain.Product
+	Images    []AttachmentResponse `json:"images"`
+	Documents []AttachmentResponse `json:"documents"`
+}
+
+// ProductListResponse is one page of products
+type ProductListResponse struct {
+	Items      []ProductResponse `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty" example:"bmV3ZXN0fHQyMDI2LTAzLTAxVDEwOjAwOjAwWnw1NTBlODQwMA"`
+}
+
+// newProductResponses loads the attachments of products, with fresh signed
+// URLs, in one query
+func newProductResponses(ctx context.Context, attachmentService *service.AttachmentService, products []*domain.Product) ([]ProductResponse, error) {
+	ids := make([]string, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+	links, err := attachmentService.Links(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]ProductResponse, 0, len(products))
+	for _, product := range products {
+		response := ProductResponse{Product: product, Images: []AttachmentResponse{}, Documents: []AttachmentResponse{}}
+		for _, link := range links[product.ID] {
+			if link.Kind == domain.AttachmentImage {
+				response.Images = append(response.Images, newAttachmentResponse(link))
+			} else {
+				response.Documents = append(response.Documents, newAttachmentResponse(link))
+			}
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
 }
 
 // Create godoc
 // @Summary      Create a product
-// @Description  Create a new product that can be listed for auction
+// @Description  Create a new product that can be listed for auction. With a category, its attributes are validated against the attribute definitions of the category and its ancestors.
 // @Tags         Products
 // @Accept       json
 // @Produce      json
 // @Param        request  body      CreateProductRequest  true  "Product details"
-// @Success      201      {object}  domain.Product
+// @Success      201      {object}  ProductResponse
 // @Failure      400      {object}  ErrorResponse
 // @Failure      401      {object}  ErrorResponse
 // @Failure      500      {object}  ErrorResponse
@@ -36,27 +106,33 @@ type CreateProductRequest struct {
 func (h *ProductHandler) Create(c *gin.Context) {
 	var req CreateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInvalid(c, err)
 		return
 	}
 
 	userID, _ := c.Get("userID")
-	product, err := h.productService.CreateProduct(c.Request.Context(), req.Name, req.Description, userID.(string))
+	details := service.ProductDetails{
+		CategoryID: req.CategoryID,
+		Condition:  req.Condition,
+		Attributes: req.Attributes,
+	}
+	product, err := h.productService.CreateProduct(c.Request.Context(), req.Name, req.Description, userID.(string), details)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create product"})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, product)
+	c.JSON(http.StatusCreated, ProductResponse{Product: product, Images: []AttachmentResponse{}, Documents: []AttachmentResponse{}})
 }
 
 // Get godoc
 // @Summary      Get a product
-// @Description  Get a product by its ID
+// @Description  Get a product by its ID, with its images and documents. Their URLs are signed and expire; fetch the product again for fresh ones. Archived and deleted products can still be fetched, so past auctions and bids keep resolving them.
 // @Tags         Products
 // @Produce      json
 // @Param        id   path      string  true  "Product ID"
-// @Success      200  {object}  domain.Product
+// @Success      200  {object}  ProductResponse
+// @Header       200  {string}  ETag  "Current version, for If-Match"
 // @Failure      401  {object}  ErrorResponse
 // @Failure      404  {object}  ErrorResponse
 // @Security     BearerAuth
@@ -65,29 +141,198 @@ func (h *ProductHandler) Get(c *gin.Context) {
 	id := c.Param("id")
 	product, err := h.productService.GetProduct(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "product not found"})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, product)
+	h.respondProduct(c, product)
 }
 
 // List godoc
-// @Summary      List all products
-// @Description  Get a list of all available products
+// @Summary      List products
+// @Description  Get a page of active products, newest first. Pass the response's next_cursor as cursor to get the next page; it is omitted on the last page. Filter by attribute values with attr[name]=value, e.g. attr[color]=black; products must match all of them. Deleted products are never listed.
 // @Tags         Products
 // @Produce      json
-// @Success      200  {array}   domain.Product
-// @Failure      401  {object}  ErrorResponse
-// @Failure      500  {object}  ErrorResponse
+// @Param        owner_id     query     string  false  "Only products of this owner"
+// @Param        category_id  query     string  false  "Only products of this category or its subcategories"
+// @Param        include_archived  query  bool  false  "Also list archived products"
+// @Param        limit        query     int     false  "Page size (1-100, default 20)"
+// @Param        cursor       query     string  false  "next_cursor of the previous page"
+// @Success      200          {object}  ProductListResponse
+// @Failure      400          {object}  ErrorResponse
+// @Failure      401          {object}  ErrorResponse
+// @Failure      500          {object}  ErrorResponse
 // @Security     BearerAuth
 // @Router       /products [get]
 func (h *ProductHandler) List(c *gin.Context) {
-	products, err := h.productService.ListProducts(c.Request.Context())
+	var req ListProductsQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	query := domain.ProductQuery{
+		OwnerID:         req.OwnerID,
+		Attributes:      c.QueryMap("attr"),
+		IncludeArchived: req.IncludeArchived,
+		Limit:           req.Limit,
+	}
+	if req.CategoryID != "" {
+		query.CategoryIDs = []string{req.CategoryID}
+	}
+	page, err := h.productService.ListProducts(c.Request.Context(), query, req.Cursor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	items, err := newProductResponses(c.Request.Context(), h.attachmentService, page.Items)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ProductListResponse{Items: items, NextCursor: page.NextCursor})
+}
+
+// Update godoc
+// @Summary      Edit a product
+// @Description  Change your own product. Deleted products cannot be edited, nor can products whose active or paused auction already has bids. Attributes are validated against the resulting category.
+// @Tags         Products
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string                true   "Product ID"
+// @Param        request   body      UpdateProductRequest  true   "Fields to change"
+// @Param        If-Match  header    string                false  "ETag of the version last read"
+// @Success      200       {object}  ProductResponse
+// @Failure      400       {object}  ErrorResponse
+// @Failure      401       {object}  ErrorResponse
+// @Failure      403       {object}  ErrorResponse
+// @Failure      404       {object}  ErrorResponse
+// @Failure      409       {object}  ErrorResponse
+// @Failure      412       {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /products/{id} [patch]
+func (h *ProductHandler) Update(c *gin.Context) {
+	var req UpdateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	product, err := h.productService.UpdateProduct(c.Request.Context(), c.Param("id"), version, userID.(string), service.ProductUpdate{
+		Name:        req.Name,
+		Description: req.Description,
+		CategoryID:  req.CategoryID,
+		Condition:   req.Condition,
+		Attributes:  req.Attributes,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.respondProduct(c, product)
+}
+
+// Archive godoc
+// @Summary      Archive a product
+// @Description  Withdraw your own active product. Archived products are left out of lists unless include_archived is set, and out of search, and cannot be auctioned until restored. A product with an auction that has not finished cannot be archived; cancel the auction first.
+// @Tags         Products
+// @Produce      json
+// @Param        id        path      string  true   "Product ID"
+// @Param        If-Match  header    string  false  "ETag of the version last read"
+// @Success      200       {object}  ProductResponse
+// @Failure      401       {object}  ErrorResponse
+// @Failure      403       {object}  ErrorResponse
+// @Failure      404       {object}  ErrorResponse
+// @Failure      409       {object}  ErrorResponse
+// @Failure      412       {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /products/{id}/archive [post]
+func (h *ProductHandler) Archive(c *gin.Context) {
+	h.changeStatus(c, h.productService.ArchiveProduct)
+}
+
+// Restore godoc
+// @Summary      Restore a product
+// @Description  Make your own archived or deleted product active again
+// @Tags         Products
+// @Produce      json
+// @Param        id        path      string  true   "Product ID"
+// @Param        If-Match  header    string  false  "ETag of the version last read"
+// @Success      200       {object}  ProductResponse
+// @Failure      401       {object}  ErrorResponse
+// @Failure      403       {object}  ErrorResponse
+// @Failure      404       {object}  ErrorResponse
+// @Failure      409       {object}  ErrorResponse
+// @Failure      412       {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /products/{id}/restore [post]
+func (h *ProductHandler) Restore(c *gin.Context) {
+	h.changeStatus(c, h.productService.RestoreProduct)
+}
+
+// Delete godoc
+// @Summary      Delete a product
+// @Description  Delete your own product. It is only marked as deleted: it disappears from lists and search, but can still be fetched by ID for its past auctions and bids, and can be restored. A product with an auction that has not finished cannot be deleted; cancel the auction first.
+// @Tags         Products
+// @Param        id        path    string  true   "Product ID"
+// @Param        If-Match  header  string  false  "ETag of the version last read"
+// @Success      204
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      412  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /products/{id} [delete]
+func (h *ProductHandler) Delete(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if _, err := h.productService.DeleteProduct(c.Request.Context(), c.Param("id"), version, userID.(string)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// changeStatus applies a status change of the product named by the path
+func (h *ProductHandler) changeStatus(c *gin.Context, change func(ctx context.Context, id string, version int, actorID string) (*domain.Product, error)) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	product, err := change(c.Request.Context(), c.Param("id"), version, userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.respondProduct(c, product)
+}
+
+// respondProduct writes the product with its attachments and its version as ETag
+func (h *ProductHandler) respondProduct(c *gin.Context, product *domain.Product) {
+	responses, err := newProductResponses(c.Request.Context(), h.attachmentService, []*domain.Product{product})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list products"})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, products)
+	setETag(c, product.Version)
+	c.JSON(http.StatusOK, responses[0])
 }
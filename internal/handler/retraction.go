@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/service"
+)
+
+type RetractionHandler struct {
+	retractionService *service.RetractionService
+}
+
+func NewRetractionHandler(retractionService *service.RetractionService) *RetractionHandler {
+	return &RetractionHandler{retractionService: retractionService}
+}
+
+// RetractionRequest asks to retract one of the caller's bids
+type RetractionRequest struct {
+	BidID  string                  `json:"bid_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440003"`
+	Reason domain.RetractionReason `json:"reason" binding:"required" enums:"wrong_amount,description_changed,seller_unreachable" example:"wrong_amount"`
+	Note   string                  `json:"note" binding:"max=1000" example:"Meant to bid 100.00, not 10000"`
+}
+
+// ReviewRetractionRequest optionally explains an admin's decision
+type ReviewRetractionRequest struct {
+	Note string `json:"note" binding:"max=1000" example:"Obvious typo"`
+}
+
+type RetractionResponse struct {
+	ID         string                  `json:"id" example:"550e8400-e29b-41d4-a716-446655440004"`
+	BidID      string                  `json:"bid_id" example:"550e8400-e29b-41d4-a716-446655440003"`
+	AuctionID  string                  `json:"auction_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID     string                  `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Reason     domain.RetractionReason `json:"reason" example:"wrong_amount"`
+	Note       string                  `json:"note" example:"Meant to bid 100.00, not 10000"`
+	Status     domain.RetractionStatus `json:"status" example:"pending"`
+	ReviewerID string                  `json:"reviewer_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
+	ReviewNote string                  `json:"review_note,omitempty" example:"Obvious typo"`
+	CreatedAt  time.Time               `json:"created_at" example:"2026-03-01T10:00:00Z"`
+	ReviewedAt *time.Time              `json:"reviewed_at,omitempty" example:"2026-03-01T10:15:00Z"`
+}
+
+func newRetractionResponse(retraction *domain.BidRetraction) RetractionResponse {
+	return RetractionResponse{
+		ID:         retraction.ID,
+		BidID:      retraction.BidID,
+		AuctionID:  retraction.AuctionID,
+		UserID:     retraction.UserID,
+		Reason:     retraction.Reason,
+		Note:       retraction.Note,
+		Status:     retraction.Status,
+		ReviewerID: retraction.ReviewerID,
+		ReviewNote: retraction.ReviewNote,
+		CreatedAt:  retraction.CreatedAt,
+		ReviewedAt: retraction.ReviewedAt,
+	}
+}
+
+func newRetractionListResponse(retractions []*domain.BidRetraction) []RetractionResponse {
+	response := make([]RetractionResponse, 0, len(retractions))
+	for _, retraction := range retractions {
+		response = append(response, newRetractionResponse(retraction))
+	}
+	return response
+}
+
+// Create godoc
+// @Summary      Request a bid retraction
+// @Description  Ask to retract one of your bids, giving one of the allowed reasons: wrong_amount (a typo such as 10000 instead of 100.00), description_changed or seller_unreachable. Bids can only be retracted while their auction runs and not in its final hour. The bid stands until an admin approves the request.
+// @Tags         Retractions
+// @Accept       json
+// @Produce      json
+// @Param        request  body      RetractionRequest  true  "Retraction request"
+// @Success      201      {object}  RetractionResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /retractions [post]
+func (h *RetractionHandler) Create(c *gin.Context) {
+	var req RetractionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	retraction, err := h.retractionService.RequestRetraction(c.Request.Context(), userID.(string), req.BidID, req.Reason, req.Note)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newRetractionResponse(retraction))
+}
+
+// ListMine godoc
+// @Summary      List my bid retractions
+// @Description  List the caller's retraction requests, newest first, with their review status
+// @Tags         Retractions
+// @Produce      json
+// @Success      200  {array}   RetractionResponse
+// @Failure      401  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /retractions [get]
+func (h *RetractionHandler) ListMine(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	retractions, err := h.retractionService.ListMine(c.Request.Context(), userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newRetractionListResponse(retractions))
+}
+
+// ListPending godoc
+// @Summary      List pending bid retractions
+// @Description  List the retraction requests awaiting review, oldest first. Requires the admin role.
+// @Tags         Retractions
+// @Produce      json
+// @Success      200  {array}   RetractionResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /retractions/pending [get]
+func (h *RetractionHandler) ListPending(c *gin.Context) {
+	retractions, err := h.retractionService.ListPending(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newRetractionListResponse(retractions))
+}
+
+// Approve godoc
+// @Summary      Approve a bid retraction
+// @Description  Retract the bid of a pending request whose auction has not ended. The bid stays in the bid history marked retracted, the bidder's proxy bid is dropped, the price falls back to the highest remaining bid (or the starting price), and the retraction counts against the bidder's record. Subscribers receive a retracted event. Requires the admin role.
+// @Tags         Retractions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                   true   "Retraction ID"
+// @Param        request  body      ReviewRetractionRequest  false  "Review note"
+// @Success      200      {object}  RetractionResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /retractions/{id}/approve [post]
+func (h *RetractionHandler) Approve(c *gin.Context) {
+	h.review(c, h.retractionService.Approve)
+}
+
+// Reject godoc
+// @Summary      Reject a bid retraction
+// @Description  Turn down a pending retraction request; the bid stands. Requires the admin role.
+// @Tags         Retractions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                   true   "Retraction ID"
+// @Param        request  body      ReviewRetractionRequest  false  "Review note"
+// @Success      200      {object}  RetractionResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /retractions/{id}/reject [post]
+func (h *RetractionHandler) Reject(c *gin.Context) {
+	h.review(c, h.retractionService.Reject)
+}
+
+// review applies an admin's decision on the retraction in the path
+func (h *RetractionHandler) review(c *gin.Context, decide func(ctx context.Context, id, reviewerID, note string) (*domain.BidRetraction, error)) {
+	var req ReviewRetractionRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	retraction, err := decide(c.Request.Context(), c.Param("id"), userID.(string), req.Note)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newRetractionResponse(retraction))
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/service"
+)
+
+type SearchHandler struct {
+	searchService     *service.SearchService
+	attachmentService *service.AttachmentService
+}
+
+func NewSearchHandler(searchService *service.SearchService, attachmentService *service.AttachmentService) *SearchHandler {
+	return &SearchHandler{searchService: searchService, attachmentService: attachmentService}
+}
+
+// SearchQuery holds the query parameters of GET /search
+type SearchQuery struct {
+	PageQuery
+	Q          string   `form:"q" binding:"required" example:"gaming lap"`
+	Status     string   `form:"status" example:"active"`
+	CategoryID string   `form:"category_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	MinPrice   *float64 `form:"min_price" binding:"omitempty,min=0" example:"50.00"`
+	MaxPrice   *float64 `form:"max_price" binding:"omitempty,min=0" example:"500.00"`
+}
+
+// SearchHitResponse is a matching product with its latest auction. The
+// highlights are HTML-escaped with matched words wrapped in <mark> tags.
+type SearchHitResponse struct {
+	Product       ProductResponse `json:"product"`
+	Auction       *domain.Auction `json:"auction,omitempty"`
+	Rank          float64         `json:"rank" example:"0.6079"`
+	NameHighlight string          `json:"name_highlight" example:"<mark>Gaming</mark> <mark>Laptop</mark>"`
+	Snippet       string          `json:"snippet" example:"High-performance <mark>gaming</mark> <mark>laptop</mark> with RTX graphics"`
+}
+
+// PriceBucketResponse counts matching products priced from min up to, but not
+// including, max; the last bucket has no max
+type PriceBucketResponse struct {
+	Min   float64  `json:"min" example:"100"`
+	Max   *float64 `json:"max,omitempty" example:"500"`
+	Count int      `json:"count" example:"3"`
+}
+
+// SearchFacetsResponse counts all matches of the search text, regardless of
+// the status, category and price filters
+type SearchFacetsResponse struct {
+	Statuses     map[domain.AuctionStatus]int `json:"statuses"`
+	PriceBuckets []PriceBucketResponse        `json:"price_buckets"`
+	// Categories counts matches by category ID, without subcategories
+	Categories map[string]int `json:"categories"`
+}
+
+// SearchResponse is one page of search hits
+type SearchResponse struct {
+	Items      []SearchHitResponse  `json:"items"`
+	NextCursor string               `json:"next_cursor,omitempty" example:"cmVsZXZhbmNlfG4wLjYwNzl8NTUwZTg0MDA"`
+	Total      int                  `json:"total" example:"12"`
+	Facets     SearchFacetsResponse `json:"facets"`
+}
+
+// Search godoc
+// @Summary      Search products
+// @Description  Full-text search over product names and descriptions, best match first. The last word also matches as a prefix, for type-ahead. Each hit includes the product, with its images and documents, and its latest auction; pass status=active to find only products being auctioned now. Facets count all matches of q by auction status, price bucket and category, ignoring the status, category and price filters.
+// @Tags         Search
+// @Produce      json
+// @Param        q            query     string  true   "Search text"
+// @Param        status       query     string  false  "Only products whose latest auction is in this status"
+// @Param        category_id  query     string  false  "Only products of this category or its subcategories"
+// @Param        min_price    query     number  false  "Minimum current price"
+// @Param        max_price    query     number  false  "Maximum current price"
+// @Param        limit        query     int     false  "Page size (1-100, default 20)"
+// @Param        cursor       query     string  false  "next_cursor of the previous page"
+// @Success      200          {object}  SearchResponse
+// @Failure      400          {object}  ErrorResponse
+// @Failure      401          {object}  ErrorResponse
+// @Failure      500          {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	var req SearchQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	query := domain.SearchQuery{
+		Text:     req.Q,
+		Status:   domain.AuctionStatus(req.Status),
+		MinPrice: req.MinPrice,
+		MaxPrice: req.MaxPrice,
+		Limit:    req.Limit,
+	}
+	if req.CategoryID != "" {
+		query.CategoryIDs = []string{req.CategoryID}
+	}
+	page, err := h.searchService.Search(c.Request.Context(), query, req.Cursor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	products := make([]*domain.Product, 0, len(page.Items))
+	for _, hit := range page.Items {
+		products = append(products, hit.Product)
+	}
+	productResponses, err := newProductResponses(c.Request.Context(), h.attachmentService, products)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := SearchResponse{
+		Items:      make([]SearchHitResponse, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+		Facets: SearchFacetsResponse{
+			Statuses:     page.Facets.Statuses,
+			PriceBuckets: make([]PriceBucketResponse, 0, len(page.Facets.PriceBuckets)),
+			Categories:   page.Facets.Categories,
+		},
+	}
+	for i, hit := range page.Items {
+		response.Items = append(response.Items, SearchHitResponse{
+			Product:       productResponses[i],
+			Auction:       hit.Auction,
+			Rank:          hit.Rank,
+			NameHighlight: hit.NameHighlight,
+			Snippet:       hit.Snippet,
+		})
+	}
+	for _, bucket := range page.Facets.PriceBuckets {
+		response.Facets.PriceBuckets = append(response.Facets.PriceBuckets, PriceBucketResponse{
+			Min:   bucket.Min,
+			Max:   bucket.Max,
+			Count: bucket.Count,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
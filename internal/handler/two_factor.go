@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/service"
+)
+
+type TwoFactorHandler struct {
+	twoFactorService *service.TwoFactorService
+}
+
+func NewTwoFactorHandler(twoFactorService *service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{twoFactorService: twoFactorService}
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"k3j9a-pq2mz,7hx4c-wt8nd"`
+}
+
+// Enroll godoc
+// @Summary      Start two-factor enrollment
+// @Description  Generate a TOTP secret and otpauth:// provisioning URI (render it as a QR code). Two-factor is not enforced until confirmed.
+// @Tags         Two-Factor
+// @Produce      json
+// @Success      200  {object}  service.TwoFactorEnrollment
+// @Failure      401  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auth/2fa/enroll [post]
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	enrollment, err := h.twoFactorService.BeginEnrollment(c.Request.Context(), userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// Confirm godoc
+// @Summary      Confirm two-factor enrollment
+// @Description  Verify a code from the authenticator app to enable two-factor authentication. Returns single-use recovery codes, which are shown only once.
+// @Tags         Two-Factor
+// @Accept       json
+// @Produce      json
+// @Param        request  body      TwoFactorCodeRequest  true  "TOTP code"
+// @Success      200      {object}  RecoveryCodesResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auth/2fa/confirm [post]
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	codes, err := h.twoFactorService.ConfirmEnrollment(c.Request.Context(), userID.(string), req.Code)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable godoc
+// @Summary      Disable two-factor authentication
+// @Description  Turn off two-factor authentication using a current TOTP or recovery code
+// @Tags         Two-Factor
+// @Accept       json
+// @Produce      json
+// @Param        request  body      TwoFactorCodeRequest  true  "TOTP or recovery code"
+// @Success      200      {object}  MessageResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auth/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.twoFactorService.Disable(c.Request.Context(), userID.(string), req.Code); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Regenerate recovery codes
+// @Description  Replace all recovery codes after verifying a current TOTP code
+// @Tags         Two-Factor
+// @Accept       json
+// @Produce      json
+// @Param        request  body      TwoFactorCodeRequest  true  "TOTP code"
+// @Success      200      {object}  RecoveryCodesResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auth/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(c.Request.Context(), userID.(string), req.Code)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/service"
+)
+
+type UserHandler struct {
+	userService *service.UserService
+}
+
+func NewUserHandler(userService *service.UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"display_name" example:"Ada L."`
+	AvatarURL   *string `json:"avatar_url" example:"https://example.com/avatar.png"`
+	Location    *string `json:"location" example:"London, UK"`
+	Bio         *string `json:"bio" example:"Collector of vintage watches"`
+}
+
+type PublicProfileResponse struct {
+	ID          string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DisplayName string `json:"display_name" example:"Ada L."`
+	AvatarURL   string `json:"avatar_url" example:"https://example.com/avatar.png"`
+	Location    string `json:"location" example:"London, UK"`
+	Bio         string `json:"bio" example:"Collector of vintage watches"`
+	// BidRetractions counts the user's approved bid retractions
+	BidRetractions int       `json:"bid_retractions" example:"0"`
+	CreatedAt      time.Time `json:"created_at" example:"2026-01-01T00:00:00Z"`
+}
+
+type ProfileResponse struct {
+	PublicProfileResponse
+	Email         string      `json:"email" example:"user@example.com"`
+	EmailVerified bool        `json:"email_verified" example:"true"`
+	Role          domain.Role `json:"role" example:"user"`
+}
+
+func newPublicProfileResponse(user *domain.User) PublicProfileResponse {
+	return PublicProfileResponse{
+		ID:             user.ID,
+		DisplayName:    user.Profile.DisplayName,
+		AvatarURL:      user.Profile.AvatarURL,
+		Location:       user.Profile.Location,
+		Bio:            user.Profile.Bio,
+		BidRetractions: user.BidRetractions,
+		CreatedAt:      user.CreatedAt,
+	}
+}
+
+func newProfileResponse(user *domain.User) ProfileResponse {
+	return ProfileResponse{
+		PublicProfileResponse: newPublicProfileResponse(user),
+		Email:                 user.Email,
+		EmailVerified:         user.IsEmailVerified(),
+		Role:                  user.Role,
+	}
+}
+
+// GetMe godoc
+// @Summary      Get my profile
+// @Description  Get the authenticated user's profile, including private fields
+// @Tags         Users
+// @Produce      json
+// @Success      200  {object}  ProfileResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me [get]
+func (h *UserHandler) GetMe(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	user, err := h.userService.GetUser(c.Request.Context(), userID.(string))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newProfileResponse(user))
+}
+
+// UpdateMe godoc
+// @Summary      Update my profile
+// @Description  Partially update the authenticated user's profile. Omitted fields are left unchanged; empty strings clear a field.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        request  body      UpdateProfileRequest  true  "Profile fields"
+// @Success      200      {object}  ProfileResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me [patch]
+func (h *UserHandler) UpdateMe(c *gin.Context) {
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user, err := h.userService.UpdateProfile(c.Request.Context(), userID.(string), service.ProfileUpdate{
+		DisplayName: req.DisplayName,
+		AvatarURL:   req.AvatarURL,
+		Location:    req.Location,
+		Bio:         req.Bio,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newProfileResponse(user))
+}
+
+// Get godoc
+// @Summary      Get a user's public profile
+// @Description  Get the public profile of a user by ID. Email addresses are never included.
+// @Tags         Users
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  PublicProfileResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id} [get]
+func (h *UserHandler) Get(c *gin.Context) {
+	user, err := h.userService.GetUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newPublicProfileResponse(user))
+}
@@ -0,0 +1,87 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/saigenix/bidding-system/pkg/blobstore"
+)
+
+// MockBlobStore keeps blobs in memory. Its signed URLs are not verifiable;
+// they only name the key and expiry time.
+type MockBlobStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+	types map[string]string
+	err   error
+}
+
+func NewMockBlobStore() *MockBlobStore {
+	return &MockBlobStore{blobs: make(map[string][]byte), types: make(map[string]string)}
+}
+
+func (m *MockBlobStore) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockBlobStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blobs[key] = append([]byte(nil), data...)
+	m.types[key] = contentType
+	return nil
+}
+
+func (m *MockBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.blobs[key]
+	if !ok {
+		return nil, blobstore.ErrNotFound
+	}
+	return data, nil
+}
+
+func (m *MockBlobStore) Delete(ctx context.Context, key string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.blobs, key)
+	delete(m.types, key)
+	return nil
+}
+
+func (m *MockBlobStore) SignedURL(key string, ttl time.Duration) (string, error) {
+	return fmt.Sprintf("https://blobs.test/%s?expires=%d", url.PathEscape(key), time.Now().Add(ttl).Unix()), nil
+}
+
+// Keys returns the keys of all stored blobs, sorted
+func (m *MockBlobStore) Keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.blobs))
+	for key := range m.blobs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ContentType returns the content type a blob was stored with
+func (m *MockBlobStore) ContentType(key string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.types[key]
+}
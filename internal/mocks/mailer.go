@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/saigenix/bidding-system/pkg/mailer"
+)
+
+// MockMailer records sent messages instead of delivering them
+type MockMailer struct {
+	mu       sync.Mutex
+	messages []mailer.Message
+	err      error
+}
+
+func NewMockMailer() *MockMailer {
+	return &MockMailer{}
+}
+
+func (m *MockMailer) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockMailer) Send(ctx context.Context, msg mailer.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns all messages sent so far
+func (m *MockMailer) Messages() []mailer.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]mailer.Message(nil), m.messages...)
+}
+
+// LastMessage returns the most recently sent message, or nil if none were sent
+func (m *MockMailer) LastMessage() *mailer.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.messages) == 0 {
+		return nil
+	}
+	msg := m.messages[len(m.messages)-1]
+	return &msg
+}
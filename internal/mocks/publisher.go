@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"sync"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// MockEventPublisher records published auction events
+type MockEventPublisher struct {
+	mu      sync.Mutex
+	events  []*domain.AuctionEvent
+	watched []string
+}
+
+func NewMockEventPublisher() *MockEventPublisher {
+	return &MockEventPublisher{}
+}
+
+func (m *MockEventPublisher) Publish(event *domain.AuctionEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, event)
+}
+
+// Events returns all events published so far
+func (m *MockEventPublisher) Events() []*domain.AuctionEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*domain.AuctionEvent(nil), m.events...)
+}
+
+// Watch marks auctions as having live subscribers
+func (m *MockEventPublisher) Watch(auctionIDs ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watched = append(m.watched, auctionIDs...)
+}
+
+func (m *MockEventPublisher) WatchedAuctions() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.watched...)
+}
@@ -3,7 +3,11 @@ package mocks
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/saigenix/bidding-system/internal/domain"
 )
@@ -56,7 +60,7 @@ func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*dom
 			return u, nil
 		}
 	}
-	return nil, fmt.Errorf("user not found")
+	return nil, &domain.NotFoundError{Entity: "user", ID: email}
 }
 
 func (m *MockUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
@@ -69,194 +73,2007 @@ func (m *MockUserRepository) GetByID(ctx context.Context, id string) (*domain.Us
 	if u, ok := m.users[id]; ok {
 		return u, nil
 	}
-	return nil, fmt.Errorf("user not found")
+	return nil, &domain.NotFoundError{Entity: "user", ID: id}
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, user *domain.User) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.users[user.ID]; !ok {
+		return &domain.NotFoundError{Entity: "user", ID: user.ID}
+	}
+	m.users[user.ID] = user
+	return nil
+}
+
+func (m *MockUserRepository) UpdateProfile(ctx context.Context, userID string, profile domain.Profile) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.users[userID]
+	if !ok {
+		return &domain.NotFoundError{Entity: "user", ID: userID}
+	}
+	u.Profile = profile
+	return nil
+}
+
+func (m *MockUserRepository) IncrementBidRetractions(ctx context.Context, userID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.users[userID]
+	if !ok {
+		return &domain.NotFoundError{Entity: "user", ID: userID}
+	}
+	u.BidRetractions++
+	return nil
 }
 
 // ============================================================================
-// MockProductRepository
+// MockUserTokenRepository
 // ============================================================================
 
-type MockProductRepository struct {
-	mu       sync.RWMutex
-	products map[string]*domain.Product
-	err      error
+type MockUserTokenRepository struct {
+	mu     sync.RWMutex
+	tokens map[string]*domain.UserToken // keyed by ID
+	err    error
 }
 
-func NewMockProductRepository() *MockProductRepository {
-	return &MockProductRepository{products: make(map[string]*domain.Product)}
+func NewMockUserTokenRepository() *MockUserTokenRepository {
+	return &MockUserTokenRepository{tokens: make(map[string]*domain.UserToken)}
 }
 
-func (m *MockProductRepository) SetError(err error) {
+func (m *MockUserTokenRepository) SetError(err error) {
 	m.err = err
 }
 
-func (m *MockProductRepository) Create(ctx context.Context, product *domain.Product) error {
+func (m *MockUserTokenRepository) Create(ctx context.Context, token *domain.UserToken) error {
 	if m.err != nil {
 		return m.err
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.products[product.ID] = product
+	m.tokens[token.ID] = token
 	return nil
 }
 
-func (m *MockProductRepository) GetByID(ctx context.Context, id string) (*domain.Product, error) {
+func (m *MockUserTokenRepository) GetByHash(ctx context.Context, purpose domain.TokenPurpose, tokenHash string) (*domain.UserToken, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if p, ok := m.products[id]; ok {
-		return p, nil
+	for _, t := range m.tokens {
+		if t.Purpose == purpose && t.TokenHash == tokenHash {
+			return t, nil
+		}
+	}
+	return nil, &domain.NotFoundError{Entity: "user token"}
+}
+
+func (m *MockUserTokenRepository) MarkUsed(ctx context.Context, id string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.tokens[id]
+	if !ok {
+		return &domain.NotFoundError{Entity: "user token", ID: id}
+	}
+	if t.UsedAt != nil {
+		return fmt.Errorf("token already used")
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	return nil
+}
+
+func (m *MockUserTokenRepository) DeleteByUser(ctx context.Context, userID string, purpose domain.TokenPurpose) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, t := range m.tokens {
+		if t.UserID == userID && t.Purpose == purpose {
+			delete(m.tokens, id)
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// MockTwoFactorRepository
+// ============================================================================
+
+type MockTwoFactorRepository struct {
+	mu       sync.RWMutex
+	settings map[string]*domain.TwoFactor      // keyed by user ID
+	codes    map[string][]*domain.RecoveryCode // keyed by user ID
+	err      error
+}
+
+func NewMockTwoFactorRepository() *MockTwoFactorRepository {
+	return &MockTwoFactorRepository{
+		settings: make(map[string]*domain.TwoFactor),
+		codes:    make(map[string][]*domain.RecoveryCode),
 	}
-	return nil, fmt.Errorf("product not found")
 }
 
-func (m *MockProductRepository) List(ctx context.Context) ([]*domain.Product, error) {
+func (m *MockTwoFactorRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockTwoFactorRepository) GetByUserID(ctx context.Context, userID string) (*domain.TwoFactor, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	var result []*domain.Product
-	for _, p := range m.products {
-		result = append(result, p)
+	if tf, ok := m.settings[userID]; ok {
+		return tf, nil
 	}
-	return result, nil
+	return nil, nil
+}
+
+func (m *MockTwoFactorRepository) Save(ctx context.Context, tf *domain.TwoFactor) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.settings[tf.UserID] = tf
+	return nil
+}
+
+func (m *MockTwoFactorRepository) Delete(ctx context.Context, userID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.settings, userID)
+	delete(m.codes, userID)
+	return nil
+}
+
+func (m *MockTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codes []*domain.RecoveryCode) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.codes[userID] = codes
+	return nil
+}
+
+func (m *MockTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range m.codes[userID] {
+		if c.CodeHash == codeHash && c.UsedAt == nil {
+			now := time.Now()
+			c.UsedAt = &now
+			return nil
+		}
+	}
+	return &domain.NotFoundError{Entity: "recovery code"}
 }
 
 // ============================================================================
-// MockAuctionRepository
+// MockLoginAttemptRepository
 // ============================================================================
 
-type MockAuctionRepository struct {
+type MockLoginAttemptRepository struct {
 	mu       sync.RWMutex
-	auctions map[string]*domain.Auction
+	attempts map[string]*domain.LoginAttempt // keyed by throttle key
 	err      error
 }
 
-func NewMockAuctionRepository() *MockAuctionRepository {
-	return &MockAuctionRepository{auctions: make(map[string]*domain.Auction)}
+func NewMockLoginAttemptRepository() *MockLoginAttemptRepository {
+	return &MockLoginAttemptRepository{attempts: make(map[string]*domain.LoginAttempt)}
 }
 
-func (m *MockAuctionRepository) SetError(err error) {
+func (m *MockLoginAttemptRepository) SetError(err error) {
 	m.err = err
 }
 
-func (m *MockAuctionRepository) Create(ctx context.Context, auction *domain.Auction) error {
+func (m *MockLoginAttemptRepository) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if a, ok := m.attempts[key]; ok {
+		copied := *a
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *MockLoginAttemptRepository) RecordFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginAttempt, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	a, ok := m.attempts[key]
+	if !ok {
+		a = &domain.LoginAttempt{Key: key}
+		m.attempts[key] = a
+	}
+	if a.LastFailureAt.Before(now.Add(-window)) {
+		a.Failures = 0
+	}
+	a.Failures++
+	a.LastFailureAt = now
+
+	copied := *a
+	return &copied, nil
+}
+
+func (m *MockLoginAttemptRepository) Save(ctx context.Context, attempt *domain.LoginAttempt) error {
 	if m.err != nil {
 		return m.err
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.auctions[auction.ID] = auction
+
+	if a, ok := m.attempts[attempt.Key]; ok {
+		a.NextAttemptAt = attempt.NextAttemptAt
+		a.LockedUntil = attempt.LockedUntil
+	}
 	return nil
 }
 
-func (m *MockAuctionRepository) GetByID(ctx context.Context, id string) (*domain.Auction, error) {
+func (m *MockLoginAttemptRepository) Delete(ctx context.Context, key string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.attempts, key)
+	return nil
+}
+
+// Expire moves a key's delay and lockout into the past, simulating time passing
+func (m *MockLoginAttemptRepository) Expire(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if a, ok := m.attempts[key]; ok {
+		past := time.Now().Add(-time.Second)
+		if a.NextAttemptAt != nil {
+			a.NextAttemptAt = &past
+		}
+		if a.LockedUntil != nil {
+			a.LockedUntil = &past
+		}
+	}
+}
+
+// ============================================================================
+// MockSecurityEventRepository
+// ============================================================================
+
+type MockSecurityEventRepository struct {
+	mu     sync.RWMutex
+	events []*domain.SecurityEvent
+	err    error
+}
+
+func NewMockSecurityEventRepository() *MockSecurityEventRepository {
+	return &MockSecurityEventRepository{}
+}
+
+func (m *MockSecurityEventRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockSecurityEventRepository) Create(ctx context.Context, event *domain.SecurityEvent) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, event)
+	return nil
+}
+
+// Events returns all recorded events
+func (m *MockSecurityEventRepository) Events() []*domain.SecurityEvent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]*domain.SecurityEvent(nil), m.events...)
+}
+
+// ============================================================================
+// MockProductRepository
+// ============================================================================
+
+type MockProductRepository struct {
+	mu       sync.RWMutex
+	products map[string]*domain.Product
+	// versions tracks stored versions separately, since callers share the
+	// stored product pointers and may change them before updating
+	versions map[string]int
+	err      error
+}
+
+func NewMockProductRepository() *MockProductRepository {
+	return &MockProductRepository{
+		products: make(map[string]*domain.Product),
+		versions: make(map[string]int),
+	}
+}
+
+func (m *MockProductRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockProductRepository) Create(ctx context.Context, product *domain.Product) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.products[product.ID] = product
+	m.versions[product.ID] = product.Version
+	return nil
+}
+
+func (m *MockProductRepository) GetByID(ctx context.Context, id string) (*domain.Product, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if a, ok := m.auctions[id]; ok {
-		return a, nil
+	if p, ok := m.products[id]; ok {
+		return p, nil
 	}
-	return nil, fmt.Errorf("auction not found")
+	return nil, &domain.NotFoundError{Entity: "product", ID: id}
 }
 
-func (m *MockAuctionRepository) List(ctx context.Context) ([]*domain.Auction, error) {
+func (m *MockProductRepository) List(ctx context.Context, query domain.ProductQuery) ([]*domain.Product, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	var result []*domain.Auction
-	for _, a := range m.auctions {
-		result = append(result, a)
+	var result []*domain.Product
+	for _, p := range m.products {
+		switch {
+		case query.OwnerID != "" && p.OwnerID != query.OwnerID,
+			p.Status == domain.ProductStatusArchived && !query.IncludeArchived,
+			p.Status == domain.ProductStatusDeleted && !query.IncludeDeleted,
+			len(query.CategoryIDs) > 0 && !slices.Contains(query.CategoryIDs, p.CategoryID),
+			!hasAttributes(p, query.Attributes):
+			continue
+		}
+		result = append(result, p)
 	}
-	return result, nil
+	return page(result, func(p *domain.Product) *domain.Cursor {
+		return domain.NewestCursor(p.CreatedAt, p.ID)
+	}, query.After, query.Limit), nil
 }
 
-func (m *MockAuctionRepository) Update(ctx context.Context, auction *domain.Auction) error {
+func (m *MockProductRepository) Update(ctx context.Context, product *domain.Product) error {
 	if m.err != nil {
 		return m.err
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if _, ok := m.auctions[auction.ID]; !ok {
-		return fmt.Errorf("auction not found")
+	if _, ok := m.products[product.ID]; !ok {
+		return &domain.NotFoundError{Entity: "product", ID: product.ID}
 	}
-	m.auctions[auction.ID] = auction
+	if m.versions[product.ID] != product.Version {
+		return &domain.ConflictError{Entity: "product", ID: product.ID, Version: product.Version}
+	}
+	product.Version++
+	m.products[product.ID] = product
+	m.versions[product.ID] = product.Version
 	return nil
 }
 
+// hasAttributes checks if the product has all of the attribute values
+func hasAttributes(product *domain.Product, attributes map[string]string) bool {
+	for name, value := range attributes {
+		if product.Attributes[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
 // ============================================================================
-// MockBidRepository
+// MockCategoryRepository
 // ============================================================================
 
-type MockBidRepository struct {
-	mu   sync.RWMutex
-	bids []*domain.Bid
-	err  error
+type MockCategoryRepository struct {
+	mu         sync.RWMutex
+	categories map[string]*domain.Category
+	err        error
 }
 
-func NewMockBidRepository() *MockBidRepository {
-	return &MockBidRepository{}
+func NewMockCategoryRepository() *MockCategoryRepository {
+	return &MockCategoryRepository{categories: make(map[string]*domain.Category)}
 }
 
-func (m *MockBidRepository) SetError(err error) {
+func (m *MockCategoryRepository) SetError(err error) {
 	m.err = err
 }
 
-func (m *MockBidRepository) Create(ctx context.Context, bid *domain.Bid) error {
+func (m *MockCategoryRepository) Create(ctx context.Context, category *domain.Category) error {
 	if m.err != nil {
 		return m.err
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.bids = append(m.bids, bid)
+
+	if err := m.checkName(category); err != nil {
+		return err
+	}
+	m.categories[category.ID] = category
 	return nil
 }
 
-func (m *MockBidRepository) GetByAuctionID(ctx context.Context, auctionID string) ([]*domain.Bid, error) {
+func (m *MockCategoryRepository) GetByID(ctx context.Context, id string) (*domain.Category, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	var result []*domain.Bid
-	for _, b := range m.bids {
-		if b.AuctionID == auctionID {
-			result = append(result, b)
-		}
+	if c, ok := m.categories[id]; ok {
+		return c, nil
 	}
-	return result, nil
+	return nil, &domain.NotFoundError{Entity: "category", ID: id}
 }
 
-func (m *MockBidRepository) GetHighestBid(ctx context.Context, auctionID string) (*domain.Bid, error) {
+func (m *MockCategoryRepository) List(ctx context.Context) ([]*domain.Category, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	var highest *domain.Bid
-	for _, b := range m.bids {
+	result := make([]*domain.Category, 0, len(m.categories))
+	for _, c := range m.categories {
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := strings.ToLower(result[i].Name), strings.ToLower(result[j].Name)
+		if a != b {
+			return a < b
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+func (m *MockCategoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.categories[category.ID]; !ok {
+		return &domain.NotFoundError{Entity: "category", ID: category.ID}
+	}
+	if err := m.checkName(category); err != nil {
+		return err
+	}
+	m.categories[category.ID] = category
+	return nil
+}
+
+func (m *MockCategoryRepository) Delete(ctx context.Context, id string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.categories[id]; !ok {
+		return &domain.NotFoundError{Entity: "category", ID: id}
+	}
+	for _, c := range m.categories {
+		if c.ParentID == id {
+			return domain.ErrCategoryInUse
+		}
+	}
+	delete(m.categories, id)
+	return nil
+}
+
+// checkName enforces the unique index on sibling names
+func (m *MockCategoryRepository) checkName(category *domain.Category) error {
+	for _, c := range m.categories {
+		if c.ID != category.ID && c.ParentID == category.ParentID && strings.EqualFold(c.Name, category.Name) {
+			return fmt.Errorf("%w: a sibling category is already named %q", domain.ErrConflict, category.Name)
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// MockAttachmentRepository
+// ============================================================================
+
+type MockAttachmentRepository struct {
+	mu sync.RWMutex
+	// attachments holds copies, so callers must update them through the repo
+	attachments map[string]domain.Attachment
+	err         error
+}
+
+func NewMockAttachmentRepository() *MockAttachmentRepository {
+	return &MockAttachmentRepository{attachments: make(map[string]domain.Attachment)}
+}
+
+func (m *MockAttachmentRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockAttachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if attachment.Primary {
+		for _, a := range m.attachments {
+			if a.ProductID == attachment.ProductID && a.Primary {
+				return fmt.Errorf("%w: product %s already has a primary image", domain.ErrConflict, attachment.ProductID)
+			}
+		}
+	}
+	m.attachments[attachment.ID] = *attachment
+	return nil
+}
+
+func (m *MockAttachmentRepository) GetByID(ctx context.Context, id string) (*domain.Attachment, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if a, ok := m.attachments[id]; ok {
+		return &a, nil
+	}
+	return nil, &domain.NotFoundError{Entity: "attachment", ID: id}
+}
+
+func (m *MockAttachmentRepository) ListByProducts(ctx context.Context, productIDs []string) ([]*domain.Attachment, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*domain.Attachment
+	for _, a := range m.attachments {
+		if slices.Contains(productIDs, a.ProductID) {
+			result = append(result, &a)
+		}
+	}
+	// Images sort before documents, as "image" > "document"
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		switch {
+		case a.ProductID != b.ProductID:
+			return a.ProductID < b.ProductID
+		case a.Kind != b.Kind:
+			return a.Kind > b.Kind
+		case a.Position != b.Position:
+			return a.Position < b.Position
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return result, nil
+}
+
+func (m *MockAttachmentRepository) UpdateOrder(ctx context.Context, attachments []*domain.Attachment) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, attachment := range attachments {
+		stored, ok := m.attachments[attachment.ID]
+		if !ok {
+			continue
+		}
+		stored.Position = attachment.Position
+		stored.Primary = attachment.Primary
+		m.attachments[attachment.ID] = stored
+	}
+	return nil
+}
+
+func (m *MockAttachmentRepository) Delete(ctx context.Context, id string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.attachments[id]; !ok {
+		return &domain.NotFoundError{Entity: "attachment", ID: id}
+	}
+	delete(m.attachments, id)
+	return nil
+}
+
+// ============================================================================
+// MockAuctionRepository
+// ============================================================================
+
+type MockAuctionRepository struct {
+	mu       sync.RWMutex
+	auctions map[string]*domain.Auction
+	// versions tracks stored versions separately, since callers share the
+	// stored auction pointers and may change them before updating
+	versions map[string]int
+	// owners maps product IDs to their owners for the OwnerID filter
+	owners map[string]string
+	err    error
+}
+
+func NewMockAuctionRepository() *MockAuctionRepository {
+	return &MockAuctionRepository{
+		auctions: make(map[string]*domain.Auction),
+		versions: make(map[string]int),
+		owners:   make(map[string]string),
+	}
+}
+
+// SetProductOwner records the owner of a product for List's OwnerID filter
+func (m *MockAuctionRepository) SetProductOwner(productID, ownerID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.owners[productID] = ownerID
+}
+
+func (m *MockAuctionRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockAuctionRepository) Create(ctx context.Context, auction *domain.Auction) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auctions[auction.ID] = auction
+	m.versions[auction.ID] = auction.Version
+	return nil
+}
+
+func (m *MockAuctionRepository) GetByID(ctx context.Context, id string) (*domain.Auction, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if a, ok := m.auctions[id]; ok {
+		return a, nil
+	}
+	return nil, &domain.NotFoundError{Entity: "auction", ID: id}
+}
+
+func (m *MockAuctionRepository) List(ctx context.Context, query domain.AuctionQuery) ([]*domain.Auction, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*domain.Auction
+	for _, a := range m.auctions {
+		switch {
+		case query.Status != "" && a.Status != query.Status,
+			query.ProductID != "" && a.ProductID != query.ProductID,
+			query.OwnerID != "" && m.owners[a.ProductID] != query.OwnerID,
+			query.MinPrice != nil && a.CurrentPrice < *query.MinPrice,
+			query.MaxPrice != nil && a.CurrentPrice > *query.MaxPrice,
+			query.EndingAfter != nil && !a.EndTime.After(*query.EndingAfter),
+			query.EndingBefore != nil && !a.EndTime.Before(*query.EndingBefore):
+			continue
+		}
+		result = append(result, a)
+	}
+	order := query.Sort
+	if !order.Valid() {
+		order = domain.AuctionSortNewest
+	}
+	return page(result, order.Cursor, query.After, query.Limit), nil
+}
+
+func (m *MockAuctionRepository) ListExpired(ctx context.Context, now time.Time) ([]*domain.Auction, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*domain.Auction
+	for _, a := range m.auctions {
+		if a.Status == domain.AuctionStatusActive && !a.EndTime.After(now) && !a.Live {
+			result = append(result, a)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].EndTime.Before(result[j].EndTime) })
+	return result, nil
+}
+
+func (m *MockAuctionRepository) ListDueToStart(ctx context.Context, now time.Time) ([]*domain.Auction, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*domain.Auction
+	for _, a := range m.auctions {
+		if a.Status == domain.AuctionStatusScheduled && !a.StartTime.After(now) {
+			result = append(result, a)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StartTime.Before(result[j].StartTime) })
+	return result, nil
+}
+
+func (m *MockAuctionRepository) Update(ctx context.Context, auction *domain.Auction) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.auctions[auction.ID]; !ok {
+		return &domain.NotFoundError{Entity: "auction", ID: auction.ID}
+	}
+	if m.versions[auction.ID] != auction.Version {
+		return &domain.ConflictError{Entity: "auction", ID: auction.ID, Version: auction.Version}
+	}
+	auction.Version++
+	m.auctions[auction.ID] = auction
+	m.versions[auction.ID] = auction.Version
+	return nil
+}
+
+// ============================================================================
+// MockBidRepository
+// ============================================================================
+
+type MockBidRepository struct {
+	mu   sync.RWMutex
+	bids []*domain.Bid
+	err  error
+}
+
+func NewMockBidRepository() *MockBidRepository {
+	return &MockBidRepository{}
+}
+
+func (m *MockBidRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockBidRepository) Create(ctx context.Context, bid *domain.Bid) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bids = append(m.bids, bid)
+	return nil
+}
+
+func (m *MockBidRepository) GetByAuctionID(ctx context.Context, auctionID string, query domain.BidQuery) ([]*domain.Bid, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*domain.Bid
+	for _, b := range m.bids {
 		if b.AuctionID == auctionID {
+			result = append(result, b)
+		}
+	}
+	return page(result, func(b *domain.Bid) *domain.Cursor {
+		return domain.NewestCursor(b.CreatedAt, b.ID)
+	}, query.After, query.Limit), nil
+}
+
+func (m *MockBidRepository) GetHighestBid(ctx context.Context, auctionID string) (*domain.Bid, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var highest *domain.Bid
+	for _, b := range m.bids {
+		if b.AuctionID == auctionID && !b.IsRetracted() {
 			if highest == nil || b.Amount > highest.Amount {
 				highest = b
 			}
 		}
 	}
-	if highest == nil {
-		return nil, fmt.Errorf("no bids found")
-	}
 	return highest, nil
 }
+
+func (m *MockBidRepository) GetByID(ctx context.Context, id string) (*domain.Bid, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, b := range m.bids {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+	return nil, &domain.NotFoundError{Entity: "bid", ID: id}
+}
+
+func (m *MockBidRepository) Retract(ctx context.Context, id string, at time.Time) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, b := range m.bids {
+		if b.ID == id {
+			if b.RetractedAt == nil {
+				b.RetractedAt = &at
+			}
+			return nil
+		}
+	}
+	return &domain.NotFoundError{Entity: "bid", ID: id}
+}
+
+// ============================================================================
+// MockAuctionBidderRepository
+// ============================================================================
+
+type MockAuctionBidderRepository struct {
+	mu      sync.RWMutex
+	numbers map[string]map[string]int // auction ID -> user ID -> number
+	err     error
+}
+
+func NewMockAuctionBidderRepository() *MockAuctionBidderRepository {
+	return &MockAuctionBidderRepository{numbers: make(map[string]map[string]int)}
+}
+
+func (m *MockAuctionBidderRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockAuctionBidderRepository) Assign(ctx context.Context, auctionID, userID string) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bidders, ok := m.numbers[auctionID]
+	if !ok {
+		bidders = make(map[string]int)
+		m.numbers[auctionID] = bidders
+	}
+	if n, ok := bidders[userID]; ok {
+		return n, nil
+	}
+	bidders[userID] = len(bidders) + 1
+	return bidders[userID], nil
+}
+
+func (m *MockAuctionBidderRepository) ListByAuction(ctx context.Context, auctionID string) (map[string]int, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make(map[string]int)
+	for userID, n := range m.numbers[auctionID] {
+		result[userID] = n
+	}
+	return result, nil
+}
+
+// ============================================================================
+// MockProxyBidRepository
+// ============================================================================
+
+type MockProxyBidRepository struct {
+	mu      sync.RWMutex
+	proxies []*domain.ProxyBid
+	err     error
+}
+
+func NewMockProxyBidRepository() *MockProxyBidRepository {
+	return &MockProxyBidRepository{}
+}
+
+func (m *MockProxyBidRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockProxyBidRepository) Save(ctx context.Context, proxy *domain.ProxyBid) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, p := range m.proxies {
+		if p.AuctionID == proxy.AuctionID && p.UserID == proxy.UserID {
+			p.MaxAmount = proxy.MaxAmount
+			p.UpdatedAt = proxy.UpdatedAt
+			proxy.ID = p.ID
+			proxy.CreatedAt = p.CreatedAt
+			return nil
+		}
+	}
+	stored := *proxy
+	m.proxies = append(m.proxies, &stored)
+	return nil
+}
+
+func (m *MockProxyBidRepository) GetByAuctionID(ctx context.Context, auctionID string) ([]*domain.ProxyBid, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*domain.ProxyBid
+	for _, p := range m.proxies {
+		if p.AuctionID == auctionID {
+			copied := *p
+			result = append(result, &copied)
+		}
+	}
+	// Stable sort keeps insertion order, standing in for created_at, among equal maxima
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].MaxAmount > result[j].MaxAmount
+	})
+	return result, nil
+}
+
+func (m *MockProxyBidRepository) Delete(ctx context.Context, auctionID, userID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, p := range m.proxies {
+		if p.AuctionID == auctionID && p.UserID == userID {
+			m.proxies = append(m.proxies[:i], m.proxies[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// MockPreBidRepository
+// ============================================================================
+
+// MockPreBidRepository reads auction statuses from the auction mock it is
+// given, which ListDue needs
+type MockPreBidRepository struct {
+	mu       sync.RWMutex
+	preBids  []*domain.PreBid
+	auctions *MockAuctionRepository
+	err      error
+}
+
+func NewMockPreBidRepository(auctions *MockAuctionRepository) *MockPreBidRepository {
+	return &MockPreBidRepository{auctions: auctions}
+}
+
+func (m *MockPreBidRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockPreBidRepository) Save(ctx context.Context, preBid *domain.PreBid) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, p := range m.preBids {
+		if p.AuctionID == preBid.AuctionID && p.UserID == preBid.UserID {
+			if p.ExecutedAt != nil {
+				return domain.ErrPreBidsClosed
+			}
+			p.MaxAmount = preBid.MaxAmount
+			p.UpdatedAt = preBid.UpdatedAt
+			preBid.ID = p.ID
+			preBid.CreatedAt = p.CreatedAt
+			return nil
+		}
+	}
+	stored := *preBid
+	m.preBids = append(m.preBids, &stored)
+	return nil
+}
+
+func (m *MockPreBidRepository) GetByUser(ctx context.Context, auctionID, userID string) (*domain.PreBid, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, p := range m.preBids {
+		if p.AuctionID == auctionID && p.UserID == userID {
+			copied := *p
+			return &copied, nil
+		}
+	}
+	return nil, &domain.NotFoundError{Entity: "pre-bid", ID: auctionID}
+}
+
+func (m *MockPreBidRepository) Delete(ctx context.Context, auctionID, userID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, p := range m.preBids {
+		if p.AuctionID == auctionID && p.UserID == userID && p.ExecutedAt == nil {
+			m.preBids = append(m.preBids[:i], m.preBids[i+1:]...)
+			return nil
+		}
+	}
+	return &domain.NotFoundError{Entity: "pre-bid", ID: auctionID}
+}
+
+func (m *MockPreBidRepository) ListPending(ctx context.Context, auctionID string) ([]*domain.PreBid, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var pending []*domain.PreBid
+	for _, p := range m.preBids {
+		if p.AuctionID == auctionID && p.ExecutedAt == nil {
+			copied := *p
+			pending = append(pending, &copied)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		if pending[i].MaxAmount != pending[j].MaxAmount {
+			return pending[i].MaxAmount > pending[j].MaxAmount
+		}
+		return pending[i].UpdatedAt.Before(pending[j].UpdatedAt)
+	})
+	return pending, nil
+}
+
+func (m *MockPreBidRepository) MarkExecuted(ctx context.Context, auctionID string, ids []string, now time.Time) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, p := range m.preBids {
+		if p.AuctionID == auctionID && p.ExecutedAt == nil && slices.Contains(ids, p.ID) {
+			executedAt := now
+			p.ExecutedAt = &executedAt
+		}
+	}
+	return nil
+}
+
+func (m *MockPreBidRepository) ListDue(ctx context.Context) ([]string, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var auctionIDs []string
+	for _, p := range m.preBids {
+		if p.ExecutedAt != nil || seen[p.AuctionID] {
+			continue
+		}
+		auction, err := m.auctions.GetByID(ctx, p.AuctionID)
+		if err != nil || auction.Status != domain.AuctionStatusActive {
+			continue
+		}
+		seen[p.AuctionID] = true
+		auctionIDs = append(auctionIDs, p.AuctionID)
+	}
+	return auctionIDs, nil
+}
+
+// ============================================================================
+// MockLiveLotRepository
+// ============================================================================
+
+type MockLiveLotRepository struct {
+	mu   sync.RWMutex
+	lots map[string]*domain.LiveLot // auction ID -> lot
+	err  error
+}
+
+func NewMockLiveLotRepository() *MockLiveLotRepository {
+	return &MockLiveLotRepository{lots: make(map[string]*domain.LiveLot)}
+}
+
+func (m *MockLiveLotRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockLiveLotRepository) Create(ctx context.Context, lot *domain.LiveLot) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.lots[lot.AuctionID]; ok {
+		return fmt.Errorf("%w: auction %s has already been opened live", domain.ErrConflict, lot.AuctionID)
+	}
+	stored := *lot
+	m.lots[lot.AuctionID] = &stored
+	return nil
+}
+
+func (m *MockLiveLotRepository) GetByAuction(ctx context.Context, auctionID string) (*domain.LiveLot, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	lot, ok := m.lots[auctionID]
+	if !ok {
+		return nil, &domain.NotFoundError{Entity: "live lot", ID: auctionID}
+	}
+	result := *lot
+	return &result, nil
+}
+
+func (m *MockLiveLotRepository) Update(ctx context.Context, lot *domain.LiveLot) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.lots[lot.AuctionID]; !ok {
+		return &domain.NotFoundError{Entity: "live lot", ID: lot.AuctionID}
+	}
+	stored := *lot
+	m.lots[lot.AuctionID] = &stored
+	return nil
+}
+
+// ============================================================================
+// MockAuctionEventRepository
+// ============================================================================
+
+type MockAuctionEventRepository struct {
+	mu     sync.RWMutex
+	events map[string][]*domain.AuctionEvent // auction ID -> events in sequence order
+	err    error
+}
+
+func NewMockAuctionEventRepository() *MockAuctionEventRepository {
+	return &MockAuctionEventRepository{events: make(map[string][]*domain.AuctionEvent)}
+}
+
+func (m *MockAuctionEventRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockAuctionEventRepository) Append(ctx context.Context, event *domain.AuctionEvent) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	event.Sequence = int64(len(m.events[event.AuctionID]) + 1)
+	stored := *event
+	m.events[event.AuctionID] = append(m.events[event.AuctionID], &stored)
+	return nil
+}
+
+func (m *MockAuctionEventRepository) ListAfter(ctx context.Context, auctionID string, afterSeq int64, limit int) ([]*domain.AuctionEvent, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*domain.AuctionEvent
+	for _, event := range m.events[auctionID] {
+		if event.Sequence > afterSeq && len(result) < limit {
+			copied := *event
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+// ============================================================================
+// MockAuctionStatusChangeRepository
+// ============================================================================
+
+type MockAuctionStatusChangeRepository struct {
+	mu      sync.RWMutex
+	changes []*domain.AuctionStatusChange
+	err     error
+}
+
+func NewMockAuctionStatusChangeRepository() *MockAuctionStatusChangeRepository {
+	return &MockAuctionStatusChangeRepository{}
+}
+
+func (m *MockAuctionStatusChangeRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockAuctionStatusChangeRepository) Create(ctx context.Context, change *domain.AuctionStatusChange) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.changes = append(m.changes, change)
+	return nil
+}
+
+func (m *MockAuctionStatusChangeRepository) ListByAuction(ctx context.Context, auctionID string) ([]*domain.AuctionStatusChange, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*domain.AuctionStatusChange
+	for _, change := range m.changes {
+		if change.AuctionID == auctionID {
+			result = append(result, change)
+		}
+	}
+	return result, nil
+}
+
+// ============================================================================
+// MockAuctionChangeRepository
+// ============================================================================
+
+type MockAuctionChangeRepository struct {
+	mu      sync.RWMutex
+	changes []*domain.AuctionChange
+	err     error
+}
+
+func NewMockAuctionChangeRepository() *MockAuctionChangeRepository {
+	return &MockAuctionChangeRepository{}
+}
+
+func (m *MockAuctionChangeRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockAuctionChangeRepository) Create(ctx context.Context, change *domain.AuctionChange) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.changes = append(m.changes, change)
+	return nil
+}
+
+func (m *MockAuctionChangeRepository) ListByAuction(ctx context.Context, auctionID string) ([]*domain.AuctionChange, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*domain.AuctionChange
+	for _, change := range m.changes {
+		if change.AuctionID == auctionID {
+			result = append(result, change)
+		}
+	}
+	return result, nil
+}
+
+// ============================================================================
+// MockSearchRepository
+// ============================================================================
+
+// MockSearchRepository searches the products of a MockProductRepository,
+// joined with their latest auction in a MockAuctionRepository. A product that
+// is not archived or deleted matches if every term starts a word of its name
+// or description, and ranks
+// higher the more terms its name holds. Highlights are the plain name and
+// description.
+type MockSearchRepository struct {
+	products *MockProductRepository
+	auctions *MockAuctionRepository
+	err      error
+}
+
+func NewMockSearchRepository(products *MockProductRepository, auctions *MockAuctionRepository) *MockSearchRepository {
+	return &MockSearchRepository{products: products, auctions: auctions}
+}
+
+func (m *MockSearchRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockSearchRepository) Search(ctx context.Context, query domain.SearchQuery) (*domain.SearchResults, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	terms := domain.SearchTerms(query.Text)
+	results := &domain.SearchResults{Facets: domain.NewSearchFacets()}
+
+	m.products.mu.RLock()
+	m.auctions.mu.RLock()
+	var hits []*domain.SearchHit
+	for _, product := range m.products.products {
+		rank, ok := mockSearchRank(product, terms)
+		if !ok || product.Status == domain.ProductStatusArchived || product.Status == domain.ProductStatusDeleted {
+			continue
+		}
+		var latest *domain.Auction
+		for _, auction := range m.auctions.auctions {
+			if auction.ProductID == product.ID && (latest == nil || auction.CreatedAt.After(latest.CreatedAt)) {
+				latest = auction
+			}
+		}
+
+		if latest != nil {
+			results.Facets.Statuses[latest.Status]++
+			results.Facets.PriceBuckets[domain.PriceBucketIndex(latest.CurrentPrice)].Count++
+		}
+		if product.CategoryID != "" {
+			results.Facets.Categories[product.CategoryID]++
+		}
+		switch {
+		case query.Status != "" && (latest == nil || latest.Status != query.Status),
+			len(query.CategoryIDs) > 0 && !slices.Contains(query.CategoryIDs, product.CategoryID),
+			query.MinPrice != nil && (latest == nil || latest.CurrentPrice < *query.MinPrice),
+			query.MaxPrice != nil && (latest == nil || latest.CurrentPrice > *query.MaxPrice):
+			continue
+		}
+		hits = append(hits, &domain.SearchHit{
+			Product:       product,
+			Auction:       latest,
+			Rank:          rank,
+			NameHighlight: product.Name,
+			Snippet:       product.Description,
+		})
+	}
+	m.auctions.mu.RUnlock()
+	m.products.mu.RUnlock()
+
+	results.Total = len(hits)
+	results.Hits = page(hits, (*domain.SearchHit).Cursor, query.After, query.Limit)
+	return results, nil
+}
+
+// mockSearchRank reports whether every term starts a word of the product and
+// ranks it by the number of terms found in its name
+func mockSearchRank(product *domain.Product, terms []string) (float64, bool) {
+	if len(terms) == 0 {
+		return 0, false
+	}
+	name := domain.SearchTerms(product.Name)
+	words := append(name, domain.SearchTerms(product.Description)...)
+	rank := 0.0
+	for _, term := range terms {
+		found := false
+		for i, word := range words {
+			if strings.HasPrefix(word, term) {
+				found = true
+				if i < len(name) {
+					rank++
+					break
+				}
+			}
+		}
+		if !found {
+			return 0, false
+		}
+	}
+	return rank, true
+}
+
+// ============================================================================
+// Keyset pagination
+// ============================================================================
+
+// page sorts items in the order their cursors describe, like the ORDER BY of
+// the Postgres repositories, and returns up to limit of them after the item
+// at after. A zero limit returns all of them.
+func page[T any](items []T, cursor func(T) *domain.Cursor, after *domain.Cursor, limit int) []T {
+	sort.SliceStable(items, func(i, j int) bool {
+		return cursorBefore(cursor(items[i]), cursor(items[j]))
+	})
+	if after != nil {
+		start := len(items)
+		for i, item := range items {
+			if cursorBefore(after, cursor(item)) {
+				start = i
+				break
+			}
+		}
+		items = items[start:]
+	}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// cursorBefore reports whether a comes before b in their list's order: by
+// key, then by ID, descending except for the ascending ending-soonest order
+func cursorBefore(a, b *domain.Cursor) bool {
+	ascending := a.Sort == string(domain.AuctionSortEndingSoonest)
+	switch {
+	case !a.Time.Equal(b.Time):
+		return a.Time.Before(b.Time) == ascending
+	case a.Value != b.Value:
+		return (a.Value < b.Value) == ascending
+	case a.ID != b.ID:
+		return (a.ID < b.ID) == ascending
+	}
+	return false
+}
+
+// ============================================================================
+// MockImportRepository
+// ============================================================================
+
+// MockImportRepository stores the lots of an import in a
+// MockProductRepository and a MockAuctionRepository. Jobs are stored as
+// copies, since imports update them from a goroutine of their own.
+type MockImportRepository struct {
+	mu       sync.RWMutex
+	jobs     map[string]domain.ImportJob
+	products *MockProductRepository
+	auctions *MockAuctionRepository
+	err      error
+}
+
+func NewMockImportRepository(products *MockProductRepository, auctions *MockAuctionRepository) *MockImportRepository {
+	return &MockImportRepository{
+		jobs:     make(map[string]domain.ImportJob),
+		products: products,
+		auctions: auctions,
+	}
+}
+
+// SetError makes CreateLots fail with err
+func (m *MockImportRepository) SetError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+func (m *MockImportRepository) CreateJob(ctx context.Context, job *domain.ImportJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.ID] = copyImportJob(job)
+	return nil
+}
+
+func (m *MockImportRepository) GetJob(ctx context.Context, id string) (*domain.ImportJob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, &domain.NotFoundError{Entity: "import job", ID: id}
+	}
+	job = copyImportJob(&job)
+	return &job, nil
+}
+
+func (m *MockImportRepository) UpdateJob(ctx context.Context, job *domain.ImportJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.jobs[job.ID]; !ok {
+		return &domain.NotFoundError{Entity: "import job", ID: job.ID}
+	}
+	m.jobs[job.ID] = copyImportJob(job)
+	return nil
+}
+
+func (m *MockImportRepository) CreateLots(ctx context.Context, lots []*domain.ImportLot) error {
+	m.mu.RLock()
+	err := m.err
+	m.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	for _, lot := range lots {
+		m.products.Create(ctx, lot.Product)
+		if lot.Auction != nil {
+			m.auctions.Create(ctx, lot.Auction)
+		}
+	}
+	return nil
+}
+
+func copyImportJob(job *domain.ImportJob) domain.ImportJob {
+	c := *job
+	c.Errors = append([]domain.ImportRowError(nil), job.Errors...)
+	return c
+}
+
+// ============================================================================
+// MockCatalogRepository
+// ============================================================================
+
+// MockCatalogRepository joins lots with the auctions of a
+// MockAuctionRepository
+type MockCatalogRepository struct {
+	mu       sync.RWMutex
+	catalogs map[string]*domain.Catalog
+	// lots maps auction IDs to their lots, which hold no auction
+	lots     map[string]domain.CatalogLot
+	auctions *MockAuctionRepository
+	err      error
+}
+
+func NewMockCatalogRepository(auctions *MockAuctionRepository) *MockCatalogRepository {
+	return &MockCatalogRepository{
+		catalogs: make(map[string]*domain.Catalog),
+		lots:     make(map[string]domain.CatalogLot),
+		auctions: auctions,
+	}
+}
+
+func (m *MockCatalogRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockCatalogRepository) Create(ctx context.Context, catalog *domain.Catalog) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.catalogs[catalog.ID] = catalog
+	return nil
+}
+
+func (m *MockCatalogRepository) GetByID(ctx context.Context, id string) (*domain.Catalog, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	catalog, ok := m.catalogs[id]
+	if !ok {
+		return nil, &domain.NotFoundError{Entity: "catalog", ID: id}
+	}
+	catalog.LotCount = m.lotCount(id)
+	return catalog, nil
+}
+
+func (m *MockCatalogRepository) List(ctx context.Context, query domain.CatalogQuery) ([]*domain.Catalog, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var catalogs []*domain.Catalog
+	for _, catalog := range m.catalogs {
+		if query.OwnerID != "" && catalog.OwnerID != query.OwnerID {
+			continue
+		}
+		catalog.LotCount = m.lotCount(catalog.ID)
+		catalogs = append(catalogs, catalog)
+	}
+	return page(catalogs, func(c *domain.Catalog) *domain.Cursor {
+		return domain.NewestCursor(c.CreatedAt, c.ID)
+	}, query.After, query.Limit), nil
+}
+
+func (m *MockCatalogRepository) ListLive(ctx context.Context) ([]*domain.Catalog, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	m.auctions.mu.RLock()
+	defer m.auctions.mu.RUnlock()
+	live := map[string]bool{}
+	for auctionID, lot := range m.lots {
+		auction, ok := m.auctions.auctions[auctionID]
+		if ok && (auction.Status == domain.AuctionStatusActive || auction.Status == domain.AuctionStatusPaused) {
+			live[lot.CatalogID] = true
+		}
+	}
+	var catalogs []*domain.Catalog
+	for id := range live {
+		catalogs = append(catalogs, m.catalogs[id])
+	}
+	return catalogs, nil
+}
+
+func (m *MockCatalogRepository) AddLot(ctx context.Context, lot *domain.CatalogLot) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.lots[lot.AuctionID]; ok {
+		return fmt.Errorf("%w: auction %s is a lot already", domain.ErrConflict, lot.AuctionID)
+	}
+	for _, other := range m.lots {
+		if other.CatalogID == lot.CatalogID && other.Number == lot.Number {
+			return fmt.Errorf("%w: lot %d of catalog %s is taken", domain.ErrConflict, lot.Number, lot.CatalogID)
+		}
+	}
+	m.lots[lot.AuctionID] = domain.CatalogLot{CatalogID: lot.CatalogID, AuctionID: lot.AuctionID, Number: lot.Number}
+	return nil
+}
+
+func (m *MockCatalogRepository) RemoveLot(ctx context.Context, catalogID, auctionID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	removed, ok := m.lots[auctionID]
+	if !ok || removed.CatalogID != catalogID {
+		return &domain.NotFoundError{Entity: "lot", ID: auctionID}
+	}
+	delete(m.lots, auctionID)
+	for id, lot := range m.lots {
+		if lot.CatalogID == catalogID && lot.Number > removed.Number {
+			lot.Number--
+			m.lots[id] = lot
+		}
+	}
+	return nil
+}
+
+func (m *MockCatalogRepository) GetLotByAuction(ctx context.Context, auctionID string) (*domain.CatalogLot, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	lot, ok := m.lots[auctionID]
+	if !ok {
+		return nil, &domain.NotFoundError{Entity: "lot", ID: auctionID}
+	}
+	return &lot, nil
+}
+
+func (m *MockCatalogRepository) ListLots(ctx context.Context, catalogID string) ([]*domain.CatalogLot, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	m.auctions.mu.RLock()
+	defer m.auctions.mu.RUnlock()
+	var lots []*domain.CatalogLot
+	for _, lot := range m.lots {
+		if lot.CatalogID == catalogID {
+			lot.Auction = m.auctions.auctions[lot.AuctionID]
+			lots = append(lots, &lot)
+		}
+	}
+	sort.Slice(lots, func(i, j int) bool { return lots[i].Number < lots[j].Number })
+	return lots, nil
+}
+
+func (m *MockCatalogRepository) lotCount(catalogID string) int {
+	count := 0
+	for _, lot := range m.lots {
+		if lot.CatalogID == catalogID {
+			count++
+		}
+	}
+	return count
+}
+
+// ============================================================================
+// MockRetractionRepository
+// ============================================================================
+
+type MockRetractionRepository struct {
+	mu          sync.RWMutex
+	retractions []*domain.BidRetraction
+	err         error
+}
+
+func NewMockRetractionRepository() *MockRetractionRepository {
+	return &MockRetractionRepository{}
+}
+
+func (m *MockRetractionRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockRetractionRepository) Create(ctx context.Context, retraction *domain.BidRetraction) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, r := range m.retractions {
+		if r.BidID == retraction.BidID && r.Status == domain.RetractionStatusPending {
+			return fmt.Errorf("%w: bid %s already has a retraction awaiting review", domain.ErrConflict, retraction.BidID)
+		}
+	}
+	stored := *retraction
+	m.retractions = append(m.retractions, &stored)
+	return nil
+}
+
+func (m *MockRetractionRepository) GetByID(ctx context.Context, id string) (*domain.BidRetraction, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, r := range m.retractions {
+		if r.ID == id {
+			copied := *r
+			return &copied, nil
+		}
+	}
+	return nil, &domain.NotFoundError{Entity: "bid retraction", ID: id}
+}
+
+func (m *MockRetractionRepository) Update(ctx context.Context, retraction *domain.BidRetraction) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, r := range m.retractions {
+		if r.ID == retraction.ID && r.Status == domain.RetractionStatusPending {
+			stored := *retraction
+			m.retractions[i] = &stored
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: bid retraction %s is not awaiting review", domain.ErrConflict, retraction.ID)
+}
+
+func (m *MockRetractionRepository) ListByUser(ctx context.Context, userID string) ([]*domain.BidRetraction, error) {
+	return m.list(func(r *domain.BidRetraction) bool { return r.UserID == userID }, true)
+}
+
+func (m *MockRetractionRepository) ListPending(ctx context.Context) ([]*domain.BidRetraction, error) {
+	return m.list(func(r *domain.BidRetraction) bool { return r.Status == domain.RetractionStatusPending }, false)
+}
+
+// list returns copies of the matching retractions in the order they were
+// created, or the reverse if newestFirst
+func (m *MockRetractionRepository) list(match func(*domain.BidRetraction) bool, newestFirst bool) ([]*domain.BidRetraction, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*domain.BidRetraction
+	for _, r := range m.retractions {
+		if match(r) {
+			copied := *r
+			result = append(result, &copied)
+		}
+	}
+	if newestFirst {
+		slices.Reverse(result)
+	}
+	return result, nil
+}
+
+// ============================================================================
+// MockOrderRepository
+// ============================================================================
+
+type MockOrderRepository struct {
+	mu       sync.RWMutex
+	orders   map[string]*domain.Order
+	auctions *MockAuctionRepository
+	err      error
+}
+
+// NewMockOrderRepository returns an order repository that finds unsettled
+// auctions in auctions
+func NewMockOrderRepository(auctions *MockAuctionRepository) *MockOrderRepository {
+	return &MockOrderRepository{orders: make(map[string]*domain.Order), auctions: auctions}
+}
+
+func (m *MockOrderRepository) SetError(err error) {
+	m.err = err
+}
+
+func (m *MockOrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, o := range m.orders {
+		if o.AuctionID == order.AuctionID {
+			return fmt.Errorf("%w: auction %s already has an order", domain.ErrConflict, order.AuctionID)
+		}
+	}
+	stored := *order
+	m.orders[order.ID] = &stored
+	return nil
+}
+
+func (m *MockOrderRepository) GetByID(ctx context.Context, id string) (*domain.Order, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	order, ok := m.orders[id]
+	if !ok {
+		return nil, &domain.NotFoundError{Entity: "order", ID: id}
+	}
+	copied := *order
+	return &copied, nil
+}
+
+func (m *MockOrderRepository) GetByAuction(ctx context.Context, auctionID string) (*domain.Order, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, order := range m.orders {
+		if order.AuctionID == auctionID {
+			copied := *order
+			return &copied, nil
+		}
+	}
+	return nil, &domain.NotFoundError{Entity: "order", ID: auctionID}
+}
+
+func (m *MockOrderRepository) List(ctx context.Context, query domain.OrderQuery) ([]*domain.Order, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var orders []*domain.Order
+	for _, order := range m.orders {
+		party, ok := order.PartyOf(query.UserID)
+		if !ok || (query.Party != "" && party != query.Party) {
+			continue
+		}
+		if query.Status != "" && order.Status != query.Status {
+			continue
+		}
+		copied := *order
+		orders = append(orders, &copied)
+	}
+	return page(orders, func(o *domain.Order) *domain.Cursor {
+		return domain.NewestCursor(o.CreatedAt, o.ID)
+	}, query.After, query.Limit), nil
+}
+
+func (m *MockOrderRepository) Update(ctx context.Context, order *domain.Order) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.orders[order.ID]
+	if !ok {
+		return &domain.NotFoundError{Entity: "order", ID: order.ID}
+	}
+	stored.Status = order.Status
+	stored.UpdatedAt = order.UpdatedAt
+	return nil
+}
+
+func (m *MockOrderRepository) ListUnsettled(ctx context.Context) ([]string, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	m.auctions.mu.RLock()
+	defer m.auctions.mu.RUnlock()
+
+	settled := make(map[string]bool)
+	for _, order := range m.orders {
+		settled[order.AuctionID] = true
+	}
+	var auctionIDs []string
+	for id, auction := range m.auctions.auctions {
+		if auction.Status == domain.AuctionStatusEnded && auction.BidCount > 0 && !settled[id] {
+			auctionIDs = append(auctionIDs, id)
+		}
+	}
+	sort.Strings(auctionIDs)
+	return auctionIDs, nil
+}
+
+// ============================================================================
+// MockTransactor
+// ============================================================================
+
+// MockTransactor runs fn directly; the in-memory repositories have nothing to roll back
+type MockTransactor struct{}
+
+func NewMockTransactor() *MockTransactor {
+	return &MockTransactor{}
+}
+
+func (m *MockTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,232 @@
+// Package realtime fans auction events out to live connections. A Hub tracks
+// which subscribers watch which auctions; transports (WebSocket, SSE) register
+// a Subscriber and forward the events it receives.
+package realtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+var (
+	// ErrHubClosed is returned when registering with, or reported by subscribers
+	// of, a hub that is shutting down
+	ErrHubClosed = errors.New("server is shutting down")
+	// ErrSlowConsumer is reported by subscribers dropped for falling behind
+	ErrSlowConsumer = errors.New("subscriber too slow to keep up")
+)
+
+// DefaultBufferSize is the number of events queued per subscriber before it
+// is considered too slow and dropped
+const DefaultBufferSize = 64
+
+// Subscriber is one live connection registered with a Hub
+type Subscriber struct {
+	userID string
+	events chan *domain.AuctionEvent
+	done   chan struct{}
+
+	closeOnce sync.Once
+	err       error
+
+	// auctions is guarded by the hub's mutex
+	auctions map[string]struct{}
+}
+
+// UserID returns the authenticated user behind the connection
+func (s *Subscriber) UserID() string {
+	return s.userID
+}
+
+// Events delivers events of subscribed auctions
+func (s *Subscriber) Events() <-chan *domain.AuctionEvent {
+	return s.events
+}
+
+// Done is closed when the hub drops the subscriber; Err then tells why
+func (s *Subscriber) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns ErrSlowConsumer or ErrHubClosed once Done is closed
+func (s *Subscriber) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+func (s *Subscriber) close(err error) {
+	s.closeOnce.Do(func() {
+		s.err = err
+		close(s.done)
+	})
+}
+
+// Hub multiplexes auction events to subscribers
+type Hub struct {
+	bufferSize int
+
+	mu          sync.RWMutex
+	subscribers map[*Subscriber]struct{}
+	auctions    map[string]map[*Subscriber]struct{}
+	closed      bool
+	active      sync.WaitGroup
+}
+
+// NewHub creates a hub whose subscribers buffer up to bufferSize events
+func NewHub(bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Hub{
+		bufferSize:  bufferSize,
+		subscribers: make(map[*Subscriber]struct{}),
+		auctions:    make(map[string]map[*Subscriber]struct{}),
+	}
+}
+
+// Register adds a subscriber for userID. Every registered subscriber must be
+// passed to Unregister when its connection ends.
+func (h *Hub) Register(userID string) (*Subscriber, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+
+	sub := &Subscriber{
+		userID:   userID,
+		events:   make(chan *domain.AuctionEvent, h.bufferSize),
+		done:     make(chan struct{}),
+		auctions: make(map[string]struct{}),
+	}
+	h.subscribers[sub] = struct{}{}
+	h.active.Add(1)
+	return sub, nil
+}
+
+// Unregister removes a subscriber and all its subscriptions
+func (h *Hub) Unregister(sub *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+	h.remove(sub)
+	delete(h.subscribers, sub)
+	h.active.Done()
+}
+
+// remove drops all of a subscriber's subscriptions; h.mu must be held
+func (h *Hub) remove(sub *Subscriber) {
+	for auctionID := range sub.auctions {
+		h.unsubscribe(sub, auctionID)
+	}
+}
+
+// Subscribe starts delivering an auction's events to sub
+func (h *Hub) Subscribe(sub *Subscriber, auctionID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+
+	watchers, ok := h.auctions[auctionID]
+	if !ok {
+		watchers = make(map[*Subscriber]struct{})
+		h.auctions[auctionID] = watchers
+	}
+	watchers[sub] = struct{}{}
+	sub.auctions[auctionID] = struct{}{}
+}
+
+// Unsubscribe stops delivering an auction's events to sub
+func (h *Hub) Unsubscribe(sub *Subscriber, auctionID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.unsubscribe(sub, auctionID)
+}
+
+func (h *Hub) unsubscribe(sub *Subscriber, auctionID string) {
+	delete(sub.auctions, auctionID)
+	watchers := h.auctions[auctionID]
+	delete(watchers, sub)
+	if len(watchers) == 0 {
+		delete(h.auctions, auctionID)
+	}
+}
+
+// SubscriberCount returns the number of connections watching an auction
+func (h *Hub) SubscriberCount(auctionID string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.auctions[auctionID])
+}
+
+// WatchedAuctions lists the auctions with at least one subscriber
+func (h *Hub) WatchedAuctions() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	auctionIDs := make([]string, 0, len(h.auctions))
+	for auctionID := range h.auctions {
+		auctionIDs = append(auctionIDs, auctionID)
+	}
+	return auctionIDs
+}
+
+// Publish delivers an event to the auction's subscribers without blocking.
+// Subscribers whose buffer is full are dropped with ErrSlowConsumer.
+func (h *Hub) Publish(event *domain.AuctionEvent) {
+	var slow []*Subscriber
+
+	h.mu.RLock()
+	for sub := range h.auctions[event.AuctionID] {
+		select {
+		case sub.events <- event:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	if len(slow) == 0 {
+		return
+	}
+	h.mu.Lock()
+	for _, sub := range slow {
+		h.remove(sub)
+		sub.close(ErrSlowConsumer)
+	}
+	h.mu.Unlock()
+}
+
+// Shutdown stops accepting subscribers, asks every connection to close with
+// ErrHubClosed and waits until they have all unregistered or ctx is done
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	for sub := range h.subscribers {
+		sub.close(ErrHubClosed)
+	}
+	h.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		h.active.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
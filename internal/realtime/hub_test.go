@@ -0,0 +1,134 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+func bidEvent(auctionID string) *domain.AuctionEvent {
+	return &domain.AuctionEvent{
+		AuctionID: auctionID,
+		Type:      domain.AuctionEventBid,
+		Bid:       &domain.Bid{ID: "bid-1", AuctionID: auctionID, UserID: "user-1", Amount: 150.00},
+		Bidder:    "Bidder 1",
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestHub_PublishToSubscribers(t *testing.T) {
+	hub := NewHub(DefaultBufferSize)
+	watcher, _ := hub.Register("user-1")
+	other, _ := hub.Register("user-2")
+	hub.Subscribe(watcher, "auction-1")
+	hub.Subscribe(watcher, "auction-2")
+	hub.Subscribe(other, "auction-2")
+
+	hub.Publish(bidEvent("auction-1"))
+
+	select {
+	case event := <-watcher.Events():
+		if event.AuctionID != "auction-1" {
+			t.Errorf("event auction = %q, want %q", event.AuctionID, "auction-1")
+		}
+	default:
+		t.Fatal("subscriber did not receive event")
+	}
+	select {
+	case <-other.Events():
+		t.Error("event delivered to subscriber of a different auction")
+	default:
+	}
+}
+
+func TestHub_SubscriberCount(t *testing.T) {
+	hub := NewHub(DefaultBufferSize)
+	first, _ := hub.Register("user-1")
+	second, _ := hub.Register("user-2")
+	hub.Subscribe(first, "auction-1")
+	hub.Subscribe(second, "auction-1")
+
+	if got := hub.SubscriberCount("auction-1"); got != 2 {
+		t.Errorf("SubscriberCount() = %d, want 2", got)
+	}
+
+	hub.Unsubscribe(first, "auction-1")
+	hub.Unregister(second)
+	if got := hub.SubscriberCount("auction-1"); got != 0 {
+		t.Errorf("SubscriberCount() after leaving = %d, want 0", got)
+	}
+}
+
+func TestHub_DropsSlowConsumer(t *testing.T) {
+	hub := NewHub(2)
+	slow, _ := hub.Register("user-1")
+	hub.Subscribe(slow, "auction-1")
+
+	for i := 0; i < 3; i++ {
+		hub.Publish(bidEvent("auction-1"))
+	}
+
+	select {
+	case <-slow.Done():
+	default:
+		t.Fatal("slow subscriber was not dropped")
+	}
+	if !errors.Is(slow.Err(), ErrSlowConsumer) {
+		t.Errorf("Err() = %v, want %v", slow.Err(), ErrSlowConsumer)
+	}
+	if got := hub.SubscriberCount("auction-1"); got != 0 {
+		t.Errorf("SubscriberCount() after drop = %d, want 0", got)
+	}
+}
+
+func TestHub_Shutdown(t *testing.T) {
+	hub := NewHub(DefaultBufferSize)
+	sub, _ := hub.Register("user-1")
+
+	// The connection unregisters once it sees the hub closing
+	go func() {
+		<-sub.Done()
+		hub.Unregister(sub)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := hub.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() unexpected error: %v", err)
+	}
+	if !errors.Is(sub.Err(), ErrHubClosed) {
+		t.Errorf("Err() = %v, want %v", sub.Err(), ErrHubClosed)
+	}
+	if _, err := hub.Register("user-2"); !errors.Is(err, ErrHubClosed) {
+		t.Errorf("Register() after shutdown error = %v, want %v", err, ErrHubClosed)
+	}
+}
+
+func TestHub_ShutdownTimeout(t *testing.T) {
+	hub := NewHub(DefaultBufferSize)
+	hub.Register("user-1") // never unregisters
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := hub.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestHub_WatchedAuctions(t *testing.T) {
+	hub := NewHub(DefaultBufferSize)
+	sub, _ := hub.Register("user-1")
+	hub.Subscribe(sub, "auction-1")
+
+	if got := hub.WatchedAuctions(); len(got) != 1 || got[0] != "auction-1" {
+		t.Errorf("WatchedAuctions() = %v, want [auction-1]", got)
+	}
+
+	hub.Unsubscribe(sub, "auction-1")
+	if got := hub.WatchedAuctions(); len(got) != 0 {
+		t.Errorf("WatchedAuctions() after unsubscribing = %v, want none", got)
+	}
+}
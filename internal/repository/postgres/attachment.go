@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+const attachmentColumns = "id, product_id, kind, filename, content_type, size, blob_key, thumbnail_key, width, height, position, is_primary, created_at"
+
+type AttachmentRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAttachmentRepository(pool *pgxpool.Pool) *AttachmentRepository {
+	return &AttachmentRepository{pool: pool}
+}
+
+func (r *AttachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	query := `
+		INSERT INTO product_attachments (` + attachmentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		attachment.ID, attachment.ProductID, attachment.Kind, attachment.Filename, attachment.ContentType,
+		attachment.Size, attachment.BlobKey, attachment.ThumbnailKey, attachment.Width, attachment.Height,
+		attachment.Position, attachment.Primary, attachment.CreatedAt,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		// Another upload became the product's first, primary image meanwhile
+		return fmt.Errorf("%w: product %s already has a primary image", domain.ErrConflict, attachment.ProductID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return nil
+}
+
+func (r *AttachmentRepository) GetByID(ctx context.Context, id string) (*domain.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM product_attachments WHERE id = $1`
+	attachment, err := scanAttachment(r.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "attachment", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+func (r *AttachmentRepository) ListByProducts(ctx context.Context, productIDs []string) ([]*domain.Attachment, error) {
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+	query := `
+		SELECT ` + attachmentColumns + ` FROM product_attachments
+		WHERE product_id = ANY($1::uuid[])
+		ORDER BY product_id, kind DESC, position, created_at
+	`
+	rows, err := r.pool.Query(ctx, query, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []*domain.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) UpdateOrder(ctx context.Context, attachments []*domain.Attachment) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Clear the primary image first; the unique index is checked per row
+	_, err = tx.Exec(ctx, `UPDATE product_attachments SET is_primary = FALSE WHERE product_id = $1 AND is_primary`,
+		attachments[0].ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to update attachment order: %w", err)
+	}
+	query := `UPDATE product_attachments SET position = $2, is_primary = $3 WHERE id = $1`
+	for _, attachment := range attachments {
+		if _, err := tx.Exec(ctx, query, attachment.ID, attachment.Position, attachment.Primary); err != nil {
+			return fmt.Errorf("failed to update attachment order: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to update attachment order: %w", err)
+	}
+	return nil
+}
+
+func (r *AttachmentRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM product_attachments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return &domain.NotFoundError{Entity: "attachment", ID: id}
+	}
+	return nil
+}
+
+func scanAttachment(row pgx.Row) (*domain.Attachment, error) {
+	var a domain.Attachment
+	if err := row.Scan(
+		&a.ID, &a.ProductID, &a.Kind, &a.Filename, &a.ContentType, &a.Size, &a.BlobKey,
+		&a.ThumbnailKey, &a.Width, &a.Height, &a.Position, &a.Primary, &a.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -2,12 +2,19 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/saigenix/bidding-system/internal/domain"
 )
 
+const auctionColumns = `id, product_id, start_time, end_time, starting_price, current_price, status,
+	two_factor_threshold, paused_at, bid_count, increment_table, soft_close_seconds, live, version, created_at`
+
 type AuctionRepository struct {
 	pool *pgxpool.Pool
 }
@@ -16,15 +23,22 @@ func NewAuctionRepository(pool *pgxpool.Pool) *AuctionRepository {
 	return &AuctionRepository{pool: pool}
 }
 
-func (r *AuctionRepository) Create(ctx context.Context, auction *domain.Auction) error {
-	query := `
-		INSERT INTO auctions (id, product_id, start_time, end_time, starting_price, current_price, status, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`
-	_, err := r.pool.Exec(ctx, query,
+// createAuctionQuery inserts the auction given by createAuctionArgs
+const createAuctionQuery = `
+	INSERT INTO auctions (` + auctionColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+`
+
+func createAuctionArgs(auction *domain.Auction) []any {
+	return []any{
 		auction.ID, auction.ProductID, auction.StartTime, auction.EndTime,
-		auction.StartingPrice, auction.CurrentPrice, auction.Status, auction.CreatedAt,
-	)
+		auction.StartingPrice, auction.CurrentPrice, auction.Status, auction.TwoFactorThreshold, auction.PausedAt, auction.BidCount,
+		toIncrementTableJSON(auction.IncrementTable), auction.SoftCloseSeconds, auction.Live, auction.Version, auction.CreatedAt,
+	}
+}
+
+func (r *AuctionRepository) Create(ctx context.Context, auction *domain.Auction) error {
+	_, err := conn(ctx, r.pool).Exec(ctx, createAuctionQuery, createAuctionArgs(auction)...)
 	if err != nil {
 		return fmt.Errorf("failed to create auction: %w", err)
 	}
@@ -33,28 +47,133 @@ func (r *AuctionRepository) Create(ctx context.Context, auction *domain.Auction)
 
 func (r *AuctionRepository) GetByID(ctx context.Context, id string) (*domain.Auction, error) {
 	query := `
-		SELECT id, product_id, start_time, end_time, starting_price, current_price, status, created_at
+		SELECT ` + auctionColumns + `
 		FROM auctions
 		WHERE id = $1
 	`
-	var auction domain.Auction
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&auction.ID, &auction.ProductID, &auction.StartTime, &auction.EndTime,
-		&auction.StartingPrice, &auction.CurrentPrice, &auction.Status, &auction.CreatedAt,
-	)
+	auction, err := scanAuction(conn(ctx, r.pool).QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "auction", ID: id}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get auction: %w", err)
 	}
-	return &auction, nil
+	return auction, nil
+}
+
+// auctionOrder is how the auction list is sorted for one AuctionSort
+type auctionOrder struct {
+	orderBy string
+	// after is the keyset condition that continues the list after a cursor,
+	// with placeholders for the cursor's key and ID
+	after string
+	// key returns the cursor's key as the sort column's type
+	key func(cursor *domain.Cursor) any
 }
 
-func (r *AuctionRepository) List(ctx context.Context) ([]*domain.Auction, error) {
+var auctionOrders = map[domain.AuctionSort]auctionOrder{
+	domain.AuctionSortNewest: {
+		orderBy: "created_at DESC, id DESC",
+		after:   "(created_at, id) < ($%d, $%d)",
+		key:     func(cursor *domain.Cursor) any { return cursor.Time },
+	},
+	domain.AuctionSortEndingSoonest: {
+		orderBy: "end_time ASC, id ASC",
+		after:   "(end_time, id) > ($%d, $%d)",
+		key:     func(cursor *domain.Cursor) any { return cursor.Time },
+	},
+	domain.AuctionSortHighestPrice: {
+		orderBy: "current_price DESC, id DESC",
+		after:   "(current_price, id) < ($%d, $%d)",
+		key:     func(cursor *domain.Cursor) any { return cursor.Value },
+	},
+	domain.AuctionSortMostBids: {
+		orderBy: "bid_count DESC, id DESC",
+		after:   "(bid_count, id) < ($%d, $%d)",
+		key:     func(cursor *domain.Cursor) any { return int64(cursor.Value) },
+	},
+}
+
+func (r *AuctionRepository) List(ctx context.Context, q domain.AuctionQuery) ([]*domain.Auction, error) {
+	order, ok := auctionOrders[q.Sort]
+	if !ok {
+		order = auctionOrders[domain.AuctionSortNewest]
+	}
+
+	// where adds a condition, numbering its placeholders after the arguments so far
+	var conditions []string
+	var args []any
+	where := func(condition string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	if q.Status != "" {
+		where("status = $%d", q.Status)
+	}
+	if q.ProductID != "" {
+		where("product_id = $%d", q.ProductID)
+	}
+	if q.OwnerID != "" {
+		where("product_id IN (SELECT id FROM products WHERE owner_id = $%d)", q.OwnerID)
+	}
+	if q.MinPrice != nil {
+		where("current_price >= $%d", *q.MinPrice)
+	}
+	if q.MaxPrice != nil {
+		where("current_price <= $%d", *q.MaxPrice)
+	}
+	if q.EndingAfter != nil {
+		where("end_time > $%d", *q.EndingAfter)
+	}
+	if q.EndingBefore != nil {
+		where("end_time < $%d", *q.EndingBefore)
+	}
+	if q.After != nil {
+		where(order.after, order.key(q.After), q.After.ID)
+	}
+
+	query := `
+		SELECT ` + auctionColumns + `
+		FROM auctions
+	`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY " + order.orderBy
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+	return r.query(ctx, query, args...)
+}
+
+func (r *AuctionRepository) ListExpired(ctx context.Context, now time.Time) ([]*domain.Auction, error) {
 	query := `
-		SELECT id, product_id, start_time, end_time, starting_price, current_price, status, created_at
+		SELECT ` + auctionColumns + `
 		FROM auctions
-		ORDER BY created_at DESC
+		WHERE status = $1 AND end_time <= $2 AND NOT live
+		ORDER BY end_time
 	`
-	rows, err := r.pool.Query(ctx, query)
+	return r.query(ctx, query, domain.AuctionStatusActive, now)
+}
+
+func (r *AuctionRepository) ListDueToStart(ctx context.Context, now time.Time) ([]*domain.Auction, error) {
+	query := `
+		SELECT ` + auctionColumns + `
+		FROM auctions
+		WHERE status = $1 AND start_time <= $2
+		ORDER BY start_time
+	`
+	return r.query(ctx, query, domain.AuctionStatusScheduled, now)
+}
+
+// query runs a SELECT of full auction rows
+func (r *AuctionRepository) query(ctx context.Context, query string, args ...any) ([]*domain.Auction, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list auctions: %w", err)
 	}
@@ -62,14 +181,11 @@ func (r *AuctionRepository) List(ctx context.Context) ([]*domain.Auction, error)
 
 	var auctions []*domain.Auction
 	for rows.Next() {
-		var auction domain.Auction
-		if err := rows.Scan(
-			&auction.ID, &auction.ProductID, &auction.StartTime, &auction.EndTime,
-			&auction.StartingPrice, &auction.CurrentPrice, &auction.Status, &auction.CreatedAt,
-		); err != nil {
+		auction, err := scanAuction(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan auction: %w", err)
 		}
-		auctions = append(auctions, &auction)
+		auctions = append(auctions, auction)
 	}
 
 	return auctions, nil
@@ -79,15 +195,35 @@ func (r *AuctionRepository) Update(ctx context.Context, auction *domain.Auction)
 	query := `
 		UPDATE auctions
 		SET product_id = $2, start_time = $3, end_time = $4,
-		    starting_price = $5, current_price = $6, status = $7
-		WHERE id = $1
+		    starting_price = $5, current_price = $6, status = $7, two_factor_threshold = $8, paused_at = $9,
+		    bid_count = $10, increment_table = $11, soft_close_seconds = $12, live = $13, version = version + 1
+		WHERE id = $1 AND version = $14
 	`
-	_, err := r.pool.Exec(ctx, query,
+	tag, err := conn(ctx, r.pool).Exec(ctx, query,
 		auction.ID, auction.ProductID, auction.StartTime, auction.EndTime,
-		auction.StartingPrice, auction.CurrentPrice, auction.Status,
+		auction.StartingPrice, auction.CurrentPrice, auction.Status, auction.TwoFactorThreshold, auction.PausedAt,
+		auction.BidCount, toIncrementTableJSON(auction.IncrementTable), auction.SoftCloseSeconds, auction.Live, auction.Version,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update auction: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return &domain.ConflictError{Entity: "auction", ID: auction.ID, Version: auction.Version}
+	}
+	auction.Version++
 	return nil
 }
+
+func scanAuction(row pgx.Row) (*domain.Auction, error) {
+	var auction domain.Auction
+	var increments []incrementStepJSON
+	if err := row.Scan(
+		&auction.ID, &auction.ProductID, &auction.StartTime, &auction.EndTime,
+		&auction.StartingPrice, &auction.CurrentPrice, &auction.Status, &auction.TwoFactorThreshold, &auction.PausedAt, &auction.BidCount,
+		&increments, &auction.SoftCloseSeconds, &auction.Live, &auction.Version, &auction.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	auction.IncrementTable = fromIncrementTableJSON(increments)
+	return &auction, nil
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuctionBidderRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuctionBidderRepository(pool *pgxpool.Pool) *AuctionBidderRepository {
+	return &AuctionBidderRepository{pool: pool}
+}
+
+// assignRetries bounds retries when concurrent first bids race for the same number
+const assignRetries = 5
+
+func (r *AuctionBidderRepository) Assign(ctx context.Context, auctionID, userID string) (int, error) {
+	insert := `
+		INSERT INTO auction_bidders (auction_id, user_id, number)
+		SELECT $1, $2, COALESCE(MAX(number), 0) + 1
+		FROM auction_bidders
+		WHERE auction_id = $1
+		ON CONFLICT (auction_id, user_id) DO NOTHING
+	`
+	for i := 0; i < assignRetries; i++ {
+		_, err := r.pool.Exec(ctx, insert, auctionID, userID)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			continue // another bidder took the number first
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to assign bidder number: %w", err)
+		}
+		break
+	}
+
+	var number int
+	query := `SELECT number FROM auction_bidders WHERE auction_id = $1 AND user_id = $2`
+	if err := r.pool.QueryRow(ctx, query, auctionID, userID).Scan(&number); err != nil {
+		return 0, fmt.Errorf("failed to get bidder number: %w", err)
+	}
+	return number, nil
+}
+
+func (r *AuctionBidderRepository) ListByAuction(ctx context.Context, auctionID string) (map[string]int, error) {
+	query := `
+		SELECT user_id, number
+		FROM auction_bidders
+		WHERE auction_id = $1
+	`
+	rows, err := r.pool.Query(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bidders: %w", err)
+	}
+	defer rows.Close()
+
+	numbers := make(map[string]int)
+	for rows.Next() {
+		var userID string
+		var number int
+		if err := rows.Scan(&userID, &number); err != nil {
+			return nil, fmt.Errorf("failed to scan bidder: %w", err)
+		}
+		numbers[userID] = number
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list bidders: %w", err)
+	}
+	return numbers, nil
+}
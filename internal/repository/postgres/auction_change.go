@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+type AuctionChangeRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuctionChangeRepository(pool *pgxpool.Pool) *AuctionChangeRepository {
+	return &AuctionChangeRepository{pool: pool}
+}
+
+func (r *AuctionChangeRepository) Create(ctx context.Context, change *domain.AuctionChange) error {
+	query := `
+		INSERT INTO auction_changes (id, auction_id, version, field, old_value, new_value, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::uuid, $8)
+	`
+	_, err := conn(ctx, r.pool).Exec(ctx, query,
+		change.ID, change.AuctionID, change.Version, change.Field, change.OldValue, change.NewValue, change.ActorID, change.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create auction change: %w", err)
+	}
+	return nil
+}
+
+func (r *AuctionChangeRepository) ListByAuction(ctx context.Context, auctionID string) ([]*domain.AuctionChange, error) {
+	query := `
+		SELECT id, auction_id, version, field, old_value, new_value, COALESCE(actor_id::text, ''), created_at
+		FROM auction_changes
+		WHERE auction_id = $1
+		ORDER BY version, field
+	`
+	rows, err := conn(ctx, r.pool).Query(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auction changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*domain.AuctionChange
+	for rows.Next() {
+		var change domain.AuctionChange
+		if err := rows.Scan(
+			&change.ID, &change.AuctionID, &change.Version, &change.Field, &change.OldValue, &change.NewValue, &change.ActorID, &change.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan auction change: %w", err)
+		}
+		changes = append(changes, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list auction changes: %w", err)
+	}
+	return changes, nil
+}
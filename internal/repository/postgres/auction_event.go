@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+type AuctionEventRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuctionEventRepository(pool *pgxpool.Pool) *AuctionEventRepository {
+	return &AuctionEventRepository{pool: pool}
+}
+
+// auctionEventData is the JSONB payload of an event row
+type auctionEventData struct {
+	BidID        string           `json:"bid_id,omitempty"`
+	UserID       string           `json:"user_id,omitempty"`
+	Bidder       string           `json:"bidder,omitempty"`
+	Amount       float64          `json:"amount,omitempty"`
+	Source       domain.BidSource `json:"source,omitempty"`
+	Paddle       string           `json:"paddle,omitempty"`
+	BidCreatedAt time.Time        `json:"bid_created_at"`
+
+	// Auction state carried by clock events
+	Status       domain.AuctionStatus `json:"status,omitempty"`
+	EndTime      *time.Time           `json:"end_time,omitempty"`
+	CurrentPrice float64              `json:"current_price,omitempty"`
+
+	// Live lot state carried by live events
+	LiveState   domain.LiveLotState `json:"live_state,omitempty"`
+	LiveAction  domain.LiveAction   `json:"live_action,omitempty"`
+	AskingPrice float64             `json:"asking_price,omitempty"`
+}
+
+func (r *AuctionEventRepository) Append(ctx context.Context, event *domain.AuctionEvent) error {
+	data := auctionEventData{Bidder: event.Bidder}
+	if event.Bid != nil {
+		data.BidID = event.Bid.ID
+		data.UserID = event.Bid.UserID
+		data.Amount = event.Bid.Amount
+		data.Source = event.Bid.Source
+		data.Paddle = event.Bid.Paddle
+		data.BidCreatedAt = event.Bid.CreatedAt
+	}
+	if event.Auction != nil {
+		data.Status = event.Auction.Status
+		data.EndTime = &event.Auction.EndTime
+		data.CurrentPrice = event.Auction.CurrentPrice
+	}
+	if event.Live != nil {
+		data.LiveState = event.Live.State
+		data.LiveAction = event.Live.LastAction
+		data.AskingPrice = event.Live.AskingPrice
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode auction event: %w", err)
+	}
+
+	// Bumping the auction's counter serializes appends per auction
+	query := `
+		WITH next AS (
+			UPDATE auctions SET last_event_seq = last_event_seq + 1
+			WHERE id = $1
+			RETURNING last_event_seq
+		)
+		INSERT INTO auction_events (auction_id, seq, type, data, created_at)
+		SELECT $1, last_event_seq, $2, $3, $4 FROM next
+		RETURNING seq
+	`
+	err = r.pool.QueryRow(ctx, query, event.AuctionID, event.Type, raw, event.CreatedAt).Scan(&event.Sequence)
+	if err != nil {
+		return fmt.Errorf("failed to append auction event: %w", err)
+	}
+	return nil
+}
+
+func (r *AuctionEventRepository) ListAfter(ctx context.Context, auctionID string, afterSeq int64, limit int) ([]*domain.AuctionEvent, error) {
+	query := `
+		SELECT auction_id, seq, type, data, created_at
+		FROM auction_events
+		WHERE auction_id = $1 AND seq > $2
+		ORDER BY seq
+		LIMIT $3
+	`
+	rows, err := r.pool.Query(ctx, query, auctionID, afterSeq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auction events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.AuctionEvent
+	for rows.Next() {
+		var event domain.AuctionEvent
+		var raw []byte
+		if err := rows.Scan(&event.AuctionID, &event.Sequence, &event.Type, &raw, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan auction event: %w", err)
+		}
+
+		var data auctionEventData
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode auction event: %w", err)
+		}
+		event.Bidder = data.Bidder
+		if data.BidID != "" {
+			event.Bid = &domain.Bid{
+				ID:        data.BidID,
+				AuctionID: event.AuctionID,
+				UserID:    data.UserID,
+				Amount:    data.Amount,
+				Source:    data.Source,
+				Paddle:    data.Paddle,
+				CreatedAt: data.BidCreatedAt,
+			}
+		}
+		if data.EndTime != nil {
+			event.Auction = &domain.Auction{
+				ID:           event.AuctionID,
+				EndTime:      *data.EndTime,
+				CurrentPrice: data.CurrentPrice,
+				Status:       data.Status,
+			}
+		}
+		if data.LiveState != "" {
+			event.Live = &domain.LiveLot{
+				AuctionID:   event.AuctionID,
+				State:       data.LiveState,
+				LastAction:  data.LiveAction,
+				AskingPrice: data.AskingPrice,
+				UpdatedAt:   event.CreatedAt,
+			}
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list auction events: %w", err)
+	}
+	return events, nil
+}
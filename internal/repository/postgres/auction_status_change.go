@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+type AuctionStatusChangeRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuctionStatusChangeRepository(pool *pgxpool.Pool) *AuctionStatusChangeRepository {
+	return &AuctionStatusChangeRepository{pool: pool}
+}
+
+func (r *AuctionStatusChangeRepository) Create(ctx context.Context, change *domain.AuctionStatusChange) error {
+	query := `
+		INSERT INTO auction_status_changes (id, auction_id, from_status, to_status, reason, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::uuid, $7)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		change.ID, change.AuctionID, change.From, change.To, change.Reason, change.ActorID, change.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create auction status change: %w", err)
+	}
+	return nil
+}
+
+func (r *AuctionStatusChangeRepository) ListByAuction(ctx context.Context, auctionID string) ([]*domain.AuctionStatusChange, error) {
+	query := `
+		SELECT id, auction_id, from_status, to_status, reason, COALESCE(actor_id::text, ''), created_at
+		FROM auction_status_changes
+		WHERE auction_id = $1
+		ORDER BY created_at
+	`
+	rows, err := r.pool.Query(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auction status changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*domain.AuctionStatusChange
+	for rows.Next() {
+		var change domain.AuctionStatusChange
+		if err := rows.Scan(
+			&change.ID, &change.AuctionID, &change.From, &change.To, &change.Reason, &change.ActorID, &change.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan auction status change: %w", err)
+		}
+		changes = append(changes, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list auction status changes: %w", err)
+	}
+	return changes, nil
+}
@@ -2,13 +2,20 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/saigenix/bidding-system/internal/domain"
 )
 
+// bidColumns selects a full bid row for scanBid; floor bids have no user,
+// online bids no paddle or clerk
+const bidColumns = `id, auction_id, coalesce(user_id::text, ''), amount, source,
+	coalesce(paddle, ''), coalesce(clerk_id::text, ''), created_at, retracted_at`
+
 type BidRepository struct {
 	pool *pgxpool.Pool
 }
@@ -19,24 +26,47 @@ func NewBidRepository(pool *pgxpool.Pool) *BidRepository {
 
 func (r *BidRepository) Create(ctx context.Context, bid *domain.Bid) error {
 	query := `
-		INSERT INTO bids (id, auction_id, user_id, amount, created_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO bids (id, auction_id, user_id, amount, source, paddle, clerk_id, created_at)
+		VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5, NULLIF($6, ''), NULLIF($7, '')::uuid, $8)
 	`
-	_, err := r.pool.Exec(ctx, query, bid.ID, bid.AuctionID, bid.UserID, bid.Amount, bid.CreatedAt)
+	_, err := conn(ctx, r.pool).Exec(ctx, query,
+		bid.ID, bid.AuctionID, bid.UserID, bid.Amount, bid.Source, bid.Paddle, bid.ClerkID, bid.CreatedAt,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to create bid: %w", err)
 	}
 	return nil
 }
 
-func (r *BidRepository) GetByAuctionID(ctx context.Context, auctionID string) ([]*domain.Bid, error) {
+func (r *BidRepository) GetByID(ctx context.Context, id string) (*domain.Bid, error) {
+	query := `SELECT ` + bidColumns + ` FROM bids WHERE id = $1`
+	bid, err := scanBid(conn(ctx, r.pool).QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "bid", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bid: %w", err)
+	}
+	return bid, nil
+}
+
+func (r *BidRepository) GetByAuctionID(ctx context.Context, auctionID string, q domain.BidQuery) ([]*domain.Bid, error) {
 	query := `
-		SELECT id, auction_id, user_id, amount, created_at
+		SELECT ` + bidColumns + `
 		FROM bids
 		WHERE auction_id = $1
-		ORDER BY created_at DESC
+		  AND ($2::timestamptz IS NULL OR (created_at, id) < ($2, NULLIF($3, '')::uuid))
+		ORDER BY created_at DESC, id DESC
 	`
-	rows, err := r.pool.Query(ctx, query, auctionID)
+	var afterTime *time.Time
+	afterID := ""
+	if q.After != nil {
+		afterTime, afterID = &q.After.Time, q.After.ID
+	}
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+	rows, err := conn(ctx, r.pool).Query(ctx, query, auctionID, afterTime, afterID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bids: %w", err)
 	}
@@ -44,33 +74,54 @@ func (r *BidRepository) GetByAuctionID(ctx context.Context, auctionID string) ([
 
 	var bids []*domain.Bid
 	for rows.Next() {
-		var bid domain.Bid
-		if err := rows.Scan(&bid.ID, &bid.AuctionID, &bid.UserID, &bid.Amount, &bid.CreatedAt); err != nil {
+		bid, err := scanBid(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan bid: %w", err)
 		}
-		bids = append(bids, &bid)
+		bids = append(bids, bid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get bids: %w", err)
 	}
-
 	return bids, nil
 }
 
 func (r *BidRepository) GetHighestBid(ctx context.Context, auctionID string) (*domain.Bid, error) {
 	query := `
-		SELECT id, auction_id, user_id, amount, created_at
+		SELECT ` + bidColumns + `
 		FROM bids
-		WHERE auction_id = $1
+		WHERE auction_id = $1 AND retracted_at IS NULL
 		ORDER BY amount DESC, created_at ASC
 		LIMIT 1
 	`
-	var bid domain.Bid
-	err := r.pool.QueryRow(ctx, query, auctionID).Scan(
-		&bid.ID, &bid.AuctionID, &bid.UserID, &bid.Amount, &bid.CreatedAt,
-	)
+	bid, err := scanBid(conn(ctx, r.pool).QueryRow(ctx, query, auctionID))
 	if err == pgx.ErrNoRows {
 		return nil, nil // No bids yet
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get highest bid: %w", err)
 	}
+	return bid, nil
+}
+
+func (r *BidRepository) Retract(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE bids SET retracted_at = coalesce(retracted_at, $2) WHERE id = $1`
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, id, at)
+	if err != nil {
+		return fmt.Errorf("failed to retract bid: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return &domain.NotFoundError{Entity: "bid", ID: id}
+	}
+	return nil
+}
+
+func scanBid(row pgx.Row) (*domain.Bid, error) {
+	var bid domain.Bid
+	if err := row.Scan(
+		&bid.ID, &bid.AuctionID, &bid.UserID, &bid.Amount, &bid.Source, &bid.Paddle, &bid.ClerkID, &bid.CreatedAt, &bid.RetractedAt,
+	); err != nil {
+		return nil, err
+	}
 	return &bid, nil
 }
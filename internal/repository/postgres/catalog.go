@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// catalogColumns selects a catalog with its number of lots
+const catalogColumns = `id, owner_id, title, description, terms, start_time, first_lot_end_time, lot_interval_seconds,
+	(SELECT count(*) FROM catalog_lots l WHERE l.catalog_id = catalogs.id), created_at`
+
+type CatalogRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewCatalogRepository(pool *pgxpool.Pool) *CatalogRepository {
+	return &CatalogRepository{pool: pool}
+}
+
+func (r *CatalogRepository) Create(ctx context.Context, catalog *domain.Catalog) error {
+	query := `
+		INSERT INTO catalogs (id, owner_id, title, description, terms, start_time, first_lot_end_time,
+		                      lot_interval_seconds, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		catalog.ID, catalog.OwnerID, catalog.Title, catalog.Description, catalog.Terms,
+		catalog.StartTime, catalog.FirstLotEndTime, catalog.LotIntervalSeconds, catalog.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create catalog: %w", err)
+	}
+	return nil
+}
+
+func (r *CatalogRepository) GetByID(ctx context.Context, id string) (*domain.Catalog, error) {
+	query := `SELECT ` + catalogColumns + ` FROM catalogs WHERE id = $1`
+	catalog, err := scanCatalog(r.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "catalog", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog: %w", err)
+	}
+	return catalog, nil
+}
+
+func (r *CatalogRepository) List(ctx context.Context, q domain.CatalogQuery) ([]*domain.Catalog, error) {
+	// where adds a condition, numbering its placeholders after the arguments so far
+	var conditions []string
+	var args []any
+	where := func(condition string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	if q.OwnerID != "" {
+		where("owner_id = $%d", q.OwnerID)
+	}
+	if q.After != nil {
+		where("(created_at, id) < ($%d, $%d)", q.After.Time, q.After.ID)
+	}
+
+	query := `SELECT ` + catalogColumns + ` FROM catalogs` + whereClause(conditions) + ` ORDER BY created_at DESC, id DESC`
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+	return r.query(ctx, query, args...)
+}
+
+func (r *CatalogRepository) ListLive(ctx context.Context) ([]*domain.Catalog, error) {
+	query := `
+		SELECT ` + catalogColumns + `
+		FROM catalogs
+		WHERE EXISTS (
+			SELECT 1 FROM catalog_lots l JOIN auctions a ON a.id = l.auction_id
+			WHERE l.catalog_id = catalogs.id AND a.status = ANY($1)
+		)
+	`
+	return r.query(ctx, query, []domain.AuctionStatus{domain.AuctionStatusActive, domain.AuctionStatusPaused})
+}
+
+// query runs a SELECT of full catalog rows
+func (r *CatalogRepository) query(ctx context.Context, query string, args ...any) ([]*domain.Catalog, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list catalogs: %w", err)
+	}
+	defer rows.Close()
+
+	var catalogs []*domain.Catalog
+	for rows.Next() {
+		catalog, err := scanCatalog(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan catalog: %w", err)
+		}
+		catalogs = append(catalogs, catalog)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list catalogs: %w", err)
+	}
+	return catalogs, nil
+}
+
+func (r *CatalogRepository) AddLot(ctx context.Context, lot *domain.CatalogLot) error {
+	query := `INSERT INTO catalog_lots (auction_id, catalog_id, number) VALUES ($1, $2, $3)`
+	_, err := r.pool.Exec(ctx, query, lot.AuctionID, lot.CatalogID, lot.Number)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return fmt.Errorf("%w: lot %d of catalog %s or auction %s is taken", domain.ErrConflict, lot.Number, lot.CatalogID, lot.AuctionID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add lot: %w", err)
+	}
+	return nil
+}
+
+func (r *CatalogRepository) RemoveLot(ctx context.Context, catalogID, auctionID string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var number int
+	err = tx.QueryRow(ctx,
+		`DELETE FROM catalog_lots WHERE catalog_id = $1 AND auction_id = $2 RETURNING number`,
+		catalogID, auctionID,
+	).Scan(&number)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &domain.NotFoundError{Entity: "lot", ID: auctionID}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove lot: %w", err)
+	}
+
+	// The unique lot number constraint is deferred, so the lots may pass
+	// through each other's numbers here
+	_, err = tx.Exec(ctx,
+		`UPDATE catalog_lots SET number = number - 1 WHERE catalog_id = $1 AND number > $2`,
+		catalogID, number,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to renumber lots: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to remove lot: %w", err)
+	}
+	return nil
+}
+
+func (r *CatalogRepository) GetLotByAuction(ctx context.Context, auctionID string) (*domain.CatalogLot, error) {
+	lot := &domain.CatalogLot{AuctionID: auctionID}
+	err := r.pool.QueryRow(ctx,
+		`SELECT catalog_id, number FROM catalog_lots WHERE auction_id = $1`, auctionID,
+	).Scan(&lot.CatalogID, &lot.Number)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "lot", ID: auctionID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lot: %w", err)
+	}
+	return lot, nil
+}
+
+func (r *CatalogRepository) ListLots(ctx context.Context, catalogID string) ([]*domain.CatalogLot, error) {
+	query := `
+		SELECT l.number, ` + auctionColumns + `
+		FROM catalog_lots l
+		JOIN auctions ON auctions.id = l.auction_id
+		WHERE l.catalog_id = $1
+		ORDER BY l.number
+	`
+	rows, err := r.pool.Query(ctx, query, catalogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lots: %w", err)
+	}
+	defer rows.Close()
+
+	var lots []*domain.CatalogLot
+	for rows.Next() {
+		var number int
+		var auction domain.Auction
+		var increments []incrementStepJSON
+		if err := rows.Scan(
+			&number, &auction.ID, &auction.ProductID, &auction.StartTime, &auction.EndTime,
+			&auction.StartingPrice, &auction.CurrentPrice, &auction.Status, &auction.TwoFactorThreshold, &auction.PausedAt, &auction.BidCount,
+			&increments, &auction.SoftCloseSeconds, &auction.Version, &auction.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan lot: %w", err)
+		}
+		auction.IncrementTable = fromIncrementTableJSON(increments)
+		lots = append(lots, &domain.CatalogLot{CatalogID: catalogID, AuctionID: auction.ID, Number: number, Auction: &auction})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list lots: %w", err)
+	}
+	return lots, nil
+}
+
+func scanCatalog(row pgx.Row) (*domain.Catalog, error) {
+	var catalog domain.Catalog
+	if err := row.Scan(
+		&catalog.ID, &catalog.OwnerID, &catalog.Title, &catalog.Description, &catalog.Terms,
+		&catalog.StartTime, &catalog.FirstLotEndTime, &catalog.LotIntervalSeconds, &catalog.LotCount, &catalog.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &catalog, nil
+}
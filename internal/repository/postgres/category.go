@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// attributeJSON is an attribute definition as stored in categories.attributes
+type attributeJSON struct {
+	Name     string               `json:"name"`
+	Type     domain.AttributeType `json:"type"`
+	Required bool                 `json:"required,omitempty"`
+	Options  []string             `json:"options,omitempty"`
+	Min      *float64             `json:"min,omitempty"`
+	Max      *float64             `json:"max,omitempty"`
+}
+
+// incrementStepJSON is a step of an increment table as stored in the
+// increment_table columns
+type incrementStepJSON struct {
+	From      float64 `json:"from"`
+	Increment float64 `json:"increment"`
+}
+
+func toAttributesJSON(definitions []domain.AttributeDefinition) []attributeJSON {
+	stored := make([]attributeJSON, 0, len(definitions))
+	for _, d := range definitions {
+		stored = append(stored, attributeJSON(d))
+	}
+	return stored
+}
+
+func fromAttributesJSON(stored []attributeJSON) []domain.AttributeDefinition {
+	definitions := make([]domain.AttributeDefinition, 0, len(stored))
+	for _, a := range stored {
+		definitions = append(definitions, domain.AttributeDefinition(a))
+	}
+	return definitions
+}
+
+func toIncrementTableJSON(table []domain.IncrementStep) []incrementStepJSON {
+	stored := make([]incrementStepJSON, 0, len(table))
+	for _, step := range table {
+		stored = append(stored, incrementStepJSON(step))
+	}
+	return stored
+}
+
+// inheritableIncrementTable converts a category's increment table for
+// storage, keeping a nil table as NULL so that it is inherited
+func inheritableIncrementTable(table []domain.IncrementStep) any {
+	if table == nil {
+		return nil
+	}
+	return toIncrementTableJSON(table)
+}
+
+func fromIncrementTableJSON(stored []incrementStepJSON) []domain.IncrementStep {
+	if stored == nil {
+		return nil
+	}
+	table := make([]domain.IncrementStep, 0, len(stored))
+	for _, step := range stored {
+		table = append(table, domain.IncrementStep(step))
+	}
+	return table
+}
+
+const categoryColumns = "id, parent_id, name, attributes, increment_table, soft_close_seconds, buyer_premium, seller_commission, created_at"
+
+type CategoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewCategoryRepository(pool *pgxpool.Pool) *CategoryRepository {
+	return &CategoryRepository{pool: pool}
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	query := `
+		INSERT INTO categories (` + categoryColumns + `)
+		VALUES ($1, NULLIF($2, '')::uuid, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		category.ID, category.ParentID, category.Name, toAttributesJSON(category.Attributes),
+		inheritableIncrementTable(category.Defaults.IncrementTable), category.Defaults.SoftCloseSeconds,
+		inheritableFeeSchedule(category.Fees.BuyerPremium), inheritableFeeSchedule(category.Fees.SellerCommission),
+		category.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create category: %w", categoryError(err, category.Name))
+	}
+	return nil
+}
+
+func (r *CategoryRepository) GetByID(ctx context.Context, id string) (*domain.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE id = $1`
+	category, err := scanCategory(r.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "category", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	return category, nil
+}
+
+func (r *CategoryRepository) List(ctx context.Context) ([]*domain.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories ORDER BY lower(name), id`
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*domain.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	return categories, nil
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	query := `
+		UPDATE categories
+		SET parent_id = NULLIF($2, '')::uuid, name = $3, attributes = $4, increment_table = $5, soft_close_seconds = $6,
+		    buyer_premium = $7, seller_commission = $8
+		WHERE id = $1
+	`
+	tag, err := r.pool.Exec(ctx, query,
+		category.ID, category.ParentID, category.Name, toAttributesJSON(category.Attributes),
+		inheritableIncrementTable(category.Defaults.IncrementTable), category.Defaults.SoftCloseSeconds,
+		inheritableFeeSchedule(category.Fees.BuyerPremium), inheritableFeeSchedule(category.Fees.SellerCommission),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update category: %w", categoryError(err, category.Name))
+	}
+	if tag.RowsAffected() == 0 {
+		return &domain.NotFoundError{Entity: "category", ID: category.ID}
+	}
+	return nil
+}
+
+func (r *CategoryRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return domain.ErrCategoryInUse
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return &domain.NotFoundError{Entity: "category", ID: id}
+	}
+	return nil
+}
+
+// categoryError reports a duplicate sibling name as a conflict
+func categoryError(err error, name string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return fmt.Errorf("%w: a sibling category is already named %q", domain.ErrConflict, name)
+	}
+	return err
+}
+
+func scanCategory(row pgx.Row) (*domain.Category, error) {
+	var category domain.Category
+	var parentID *string
+	var attributes []attributeJSON
+	var increments []incrementStepJSON
+	var buyerPremium, sellerCommission *feeScheduleJSON
+	if err := row.Scan(
+		&category.ID, &parentID, &category.Name, &attributes,
+		&increments, &category.Defaults.SoftCloseSeconds, &buyerPremium, &sellerCommission, &category.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if parentID != nil {
+		category.ParentID = *parentID
+	}
+	category.Attributes = fromAttributesJSON(attributes)
+	category.Defaults.IncrementTable = fromIncrementTableJSON(increments)
+	category.Fees.BuyerPremium = fromFeeScheduleJSON(buyerPremium)
+	category.Fees.SellerCommission = fromFeeScheduleJSON(sellerCommission)
+	return &category, nil
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import "github.com/saigenix/bidding-system/internal/domain"
+
+// feeTierJSON is a tier of a tiered fee schedule as stored in feeScheduleJSON
+type feeTierJSON struct {
+	From    float64 `json:"from"`
+	Percent float64 `json:"percent"`
+}
+
+// feeScheduleJSON is a fee schedule as stored in the buyer_premium and
+// seller_commission columns of categories
+type feeScheduleJSON struct {
+	Type    domain.FeeType `json:"type"`
+	Amount  float64        `json:"amount,omitempty"`
+	Tiers   []feeTierJSON  `json:"tiers,omitempty"`
+	Minimum float64        `json:"minimum,omitempty"`
+	Maximum float64        `json:"maximum,omitempty"`
+}
+
+// feeItemJSON is a line of a fee breakdown as stored in orders.fees
+type feeItemJSON struct {
+	Kind        domain.FeeKind `json:"kind"`
+	Description string         `json:"description"`
+	Amount      float64        `json:"amount"`
+}
+
+// inheritableFeeSchedule converts a category's fee schedule for storage,
+// keeping a nil schedule as NULL so that it is inherited
+func inheritableFeeSchedule(schedule *domain.FeeSchedule) any {
+	if schedule == nil {
+		return nil
+	}
+	stored := feeScheduleJSON{
+		Type:    schedule.Type,
+		Amount:  schedule.Amount,
+		Minimum: schedule.Minimum,
+		Maximum: schedule.Maximum,
+	}
+	for _, tier := range schedule.Tiers {
+		stored.Tiers = append(stored.Tiers, feeTierJSON(tier))
+	}
+	return stored
+}
+
+func fromFeeScheduleJSON(stored *feeScheduleJSON) *domain.FeeSchedule {
+	if stored == nil {
+		return nil
+	}
+	schedule := &domain.FeeSchedule{
+		Type:    stored.Type,
+		Amount:  stored.Amount,
+		Minimum: stored.Minimum,
+		Maximum: stored.Maximum,
+	}
+	for _, tier := range stored.Tiers {
+		schedule.Tiers = append(schedule.Tiers, domain.FeeTier(tier))
+	}
+	return schedule
+}
+
+func toFeeItemsJSON(items []domain.FeeItem) []feeItemJSON {
+	stored := make([]feeItemJSON, 0, len(items))
+	for _, item := range items {
+		stored = append(stored, feeItemJSON(item))
+	}
+	return stored
+}
+
+func fromFeeItemsJSON(stored []feeItemJSON) []domain.FeeItem {
+	items := make([]domain.FeeItem, 0, len(stored))
+	for _, item := range stored {
+		items = append(items, domain.FeeItem(item))
+	}
+	return items
+}
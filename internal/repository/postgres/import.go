@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+const importJobColumns = `id, user_id, format, dry_run, status, total_rows, processed_rows, product_count, auction_count,
+	errors, error_count, error, created_at, started_at, finished_at`
+
+// importRowErrorJSON is a row error as stored in import_jobs.errors
+type importRowErrorJSON struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ImportRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewImportRepository(pool *pgxpool.Pool) *ImportRepository {
+	return &ImportRepository{pool: pool}
+}
+
+func (r *ImportRepository) CreateJob(ctx context.Context, job *domain.ImportJob) error {
+	query := `
+		INSERT INTO import_jobs (` + importJobColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		job.ID, job.UserID, job.Format, job.DryRun, job.Status, job.TotalRows, job.ProcessedRows,
+		job.ProductCount, job.AuctionCount, toImportRowErrorsJSON(job.Errors), job.ErrorCount, job.Error,
+		job.CreatedAt, job.StartedAt, job.FinishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create import job: %w", err)
+	}
+	return nil
+}
+
+func (r *ImportRepository) GetJob(ctx context.Context, id string) (*domain.ImportJob, error) {
+	query := `SELECT ` + importJobColumns + ` FROM import_jobs WHERE id = $1`
+	var job domain.ImportJob
+	var rowErrors []importRowErrorJSON
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&job.ID, &job.UserID, &job.Format, &job.DryRun, &job.Status, &job.TotalRows, &job.ProcessedRows,
+		&job.ProductCount, &job.AuctionCount, &rowErrors, &job.ErrorCount, &job.Error,
+		&job.CreatedAt, &job.StartedAt, &job.FinishedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "import job", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import job: %w", err)
+	}
+	for _, e := range rowErrors {
+		job.Errors = append(job.Errors, domain.ImportRowError(e))
+	}
+	return &job, nil
+}
+
+func (r *ImportRepository) UpdateJob(ctx context.Context, job *domain.ImportJob) error {
+	query := `
+		UPDATE import_jobs
+		SET status = $2, processed_rows = $3, product_count = $4, auction_count = $5, errors = $6,
+		    error_count = $7, error = $8, started_at = $9, finished_at = $10
+		WHERE id = $1
+	`
+	tag, err := r.pool.Exec(ctx, query,
+		job.ID, job.Status, job.ProcessedRows, job.ProductCount, job.AuctionCount,
+		toImportRowErrorsJSON(job.Errors), job.ErrorCount, job.Error, job.StartedAt, job.FinishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update import job: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return &domain.NotFoundError{Entity: "import job", ID: job.ID}
+	}
+	return nil
+}
+
+func (r *ImportRepository) CreateLots(ctx context.Context, lots []*domain.ImportLot) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, lot := range lots {
+		if err := insertProduct(ctx, tx, lot.Product); err != nil {
+			return err
+		}
+		if lot.Auction == nil {
+			continue
+		}
+		if _, err := tx.Exec(ctx, createAuctionQuery, createAuctionArgs(lot.Auction)...); err != nil {
+			return fmt.Errorf("failed to create auction: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to create lots: %w", err)
+	}
+	return nil
+}
+
+func toImportRowErrorsJSON(rowErrors []domain.ImportRowError) []importRowErrorJSON {
+	stored := make([]importRowErrorJSON, 0, len(rowErrors))
+	for _, e := range rowErrors {
+		stored = append(stored, importRowErrorJSON(e))
+	}
+	return stored
+}
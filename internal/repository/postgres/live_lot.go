@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+type LiveLotRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLiveLotRepository(pool *pgxpool.Pool) *LiveLotRepository {
+	return &LiveLotRepository{pool: pool}
+}
+
+func (r *LiveLotRepository) Create(ctx context.Context, lot *domain.LiveLot) error {
+	query := `
+		INSERT INTO live_lots (auction_id, auctioneer_id, state, asking_price, last_action, opened_at, updated_at)
+		VALUES ($1, NULLIF($2, '')::uuid, $3, $4, $5, $6, $7)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		lot.AuctionID, lot.AuctioneerID, lot.State, lot.AskingPrice, lot.LastAction, lot.OpenedAt, lot.UpdatedAt,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return fmt.Errorf("%w: auction %s has already been opened live", domain.ErrConflict, lot.AuctionID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create live lot: %w", err)
+	}
+	return nil
+}
+
+func (r *LiveLotRepository) GetByAuction(ctx context.Context, auctionID string) (*domain.LiveLot, error) {
+	query := `
+		SELECT auction_id, coalesce(auctioneer_id::text, ''), state, asking_price, last_action, opened_at, updated_at
+		FROM live_lots
+		WHERE auction_id = $1
+	`
+	var lot domain.LiveLot
+	err := r.pool.QueryRow(ctx, query, auctionID).Scan(
+		&lot.AuctionID, &lot.AuctioneerID, &lot.State, &lot.AskingPrice, &lot.LastAction, &lot.OpenedAt, &lot.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "live lot", ID: auctionID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get live lot: %w", err)
+	}
+	return &lot, nil
+}
+
+func (r *LiveLotRepository) Update(ctx context.Context, lot *domain.LiveLot) error {
+	query := `
+		UPDATE live_lots
+		SET state = $2, asking_price = $3, last_action = $4, updated_at = $5
+		WHERE auction_id = $1
+	`
+	tag, err := r.pool.Exec(ctx, query, lot.AuctionID, lot.State, lot.AskingPrice, lot.LastAction, lot.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update live lot: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return &domain.NotFoundError{Entity: "live lot", ID: lot.AuctionID}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+type LoginAttemptRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLoginAttemptRepository(pool *pgxpool.Pool) *LoginAttemptRepository {
+	return &LoginAttemptRepository{pool: pool}
+}
+
+func (r *LoginAttemptRepository) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	query := `
+		SELECT key, failures, last_failure_at, next_attempt_at, locked_until
+		FROM login_attempts
+		WHERE key = $1
+	`
+	var attempt domain.LoginAttempt
+	err := r.pool.QueryRow(ctx, query, key).Scan(
+		&attempt.Key, &attempt.Failures, &attempt.LastFailureAt, &attempt.NextAttemptAt, &attempt.LockedUntil,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil // No failures recorded
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login attempt: %w", err)
+	}
+	return &attempt, nil
+}
+
+func (r *LoginAttemptRepository) RecordFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginAttempt, error) {
+	query := `
+		INSERT INTO login_attempts (key, failures, last_failure_at)
+		VALUES ($1, 1, NOW())
+		ON CONFLICT (key) DO UPDATE
+		SET failures = CASE
+		        WHEN login_attempts.last_failure_at < NOW() - make_interval(secs => $2) THEN 1
+		        ELSE login_attempts.failures + 1
+		    END,
+		    last_failure_at = NOW()
+		RETURNING key, failures, last_failure_at, next_attempt_at, locked_until
+	`
+	var attempt domain.LoginAttempt
+	err := r.pool.QueryRow(ctx, query, key, window.Seconds()).Scan(
+		&attempt.Key, &attempt.Failures, &attempt.LastFailureAt, &attempt.NextAttemptAt, &attempt.LockedUntil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record login failure: %w", err)
+	}
+	return &attempt, nil
+}
+
+func (r *LoginAttemptRepository) Save(ctx context.Context, attempt *domain.LoginAttempt) error {
+	query := `
+		UPDATE login_attempts
+		SET next_attempt_at = $2, locked_until = $3
+		WHERE key = $1
+	`
+	_, err := r.pool.Exec(ctx, query, attempt.Key, attempt.NextAttemptAt, attempt.LockedUntil)
+	if err != nil {
+		return fmt.Errorf("failed to save login attempt: %w", err)
+	}
+	return nil
+}
+
+func (r *LoginAttemptRepository) Delete(ctx context.Context, key string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM login_attempts WHERE key = $1`, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete login attempt: %w", err)
+	}
+	return nil
+}
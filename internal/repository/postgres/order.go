@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+const orderColumns = `id, auction_id, product_id, bid_id, buyer_id, seller_id, hammer_price, buyer_premium,
+	seller_commission, tax, total, fees, status, due_at, created_at, updated_at`
+
+type OrderRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewOrderRepository(pool *pgxpool.Pool) *OrderRepository {
+	return &OrderRepository{pool: pool}
+}
+
+func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	query := `
+		INSERT INTO orders (id, auction_id, product_id, bid_id, buyer_id, seller_id, hammer_price, buyer_premium,
+		                    seller_commission, tax, total, fees, status, due_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		order.ID, order.AuctionID, order.ProductID, order.BidID, order.BuyerID, order.SellerID,
+		order.HammerPrice, order.BuyerPremium, order.SellerCommission, order.Tax, order.Total,
+		toFeeItemsJSON(order.Fees), order.Status, order.DueAt, order.CreatedAt, order.UpdatedAt,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return fmt.Errorf("%w: auction %s already has an order", domain.ErrConflict, order.AuctionID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+	return nil
+}
+
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (*domain.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE id = $1`
+	order, err := scanOrder(r.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "order", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return order, nil
+}
+
+func (r *OrderRepository) GetByAuction(ctx context.Context, auctionID string) (*domain.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE auction_id = $1`
+	order, err := scanOrder(r.pool.QueryRow(ctx, query, auctionID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "order", ID: auctionID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return order, nil
+}
+
+func (r *OrderRepository) List(ctx context.Context, q domain.OrderQuery) ([]*domain.Order, error) {
+	// where adds a condition, numbering its placeholders after the arguments so far
+	var conditions []string
+	var args []any
+	where := func(condition string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	switch q.Party {
+	case domain.OrderPartyBuyer:
+		where("buyer_id = $%d", q.UserID)
+	case domain.OrderPartySeller:
+		where("seller_id = $%d", q.UserID)
+	default:
+		where("(buyer_id = $%d OR seller_id = $%d)", q.UserID, q.UserID)
+	}
+	if q.Status != "" {
+		where("status = $%d", q.Status)
+	}
+	if q.After != nil {
+		where("(created_at, id) < ($%d, $%d)", q.After.Time, q.After.ID)
+	}
+
+	query := `SELECT ` + orderColumns + ` FROM orders` + whereClause(conditions) + ` ORDER BY created_at DESC, id DESC`
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []*domain.Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	return orders, nil
+}
+
+func (r *OrderRepository) Update(ctx context.Context, order *domain.Order) error {
+	query := `UPDATE orders SET status = $2, updated_at = $3 WHERE id = $1`
+	tag, err := r.pool.Exec(ctx, query, order.ID, order.Status, order.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return &domain.NotFoundError{Entity: "order", ID: order.ID}
+	}
+	return nil
+}
+
+func (r *OrderRepository) ListUnsettled(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT a.id
+		FROM auctions a
+		WHERE a.status = 'ended'
+		  AND NOT EXISTS (SELECT 1 FROM orders o WHERE o.auction_id = a.id)
+		  AND (
+		      SELECT b.user_id FROM bids b
+		      WHERE b.auction_id = a.id AND b.retracted_at IS NULL
+		      ORDER BY b.amount DESC, b.created_at ASC
+		      LIMIT 1
+		  ) IS NOT NULL
+		ORDER BY a.end_time
+	`
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unsettled auctions: %w", err)
+	}
+	defer rows.Close()
+
+	var auctionIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan auction id: %w", err)
+		}
+		auctionIDs = append(auctionIDs, id)
+	}
+	return auctionIDs, rows.Err()
+}
+
+func scanOrder(row pgx.Row) (*domain.Order, error) {
+	var order domain.Order
+	var fees []feeItemJSON
+	if err := row.Scan(
+		&order.ID, &order.AuctionID, &order.ProductID, &order.BidID, &order.BuyerID, &order.SellerID,
+		&order.HammerPrice, &order.BuyerPremium, &order.SellerCommission, &order.Tax, &order.Total,
+		&fees, &order.Status, &order.DueAt, &order.CreatedAt, &order.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	order.Fees = fromFeeItemsJSON(fees)
+	return &order, nil
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+const preBidColumns = `id, auction_id, user_id, max_amount, executed_at, created_at, updated_at`
+
+type PreBidRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPreBidRepository(pool *pgxpool.Pool) *PreBidRepository {
+	return &PreBidRepository{pool: pool}
+}
+
+func (r *PreBidRepository) Save(ctx context.Context, preBid *domain.PreBid) error {
+	query := `
+		INSERT INTO pre_bids (id, auction_id, user_id, max_amount, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (auction_id, user_id) DO UPDATE
+		SET max_amount = EXCLUDED.max_amount, updated_at = EXCLUDED.updated_at
+		WHERE pre_bids.executed_at IS NULL
+		RETURNING id, created_at
+	`
+	err := r.pool.QueryRow(ctx, query,
+		preBid.ID, preBid.AuctionID, preBid.UserID, preBid.MaxAmount, preBid.CreatedAt, preBid.UpdatedAt,
+	).Scan(&preBid.ID, &preBid.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrPreBidsClosed
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save pre-bid: %w", err)
+	}
+	return nil
+}
+
+func (r *PreBidRepository) GetByUser(ctx context.Context, auctionID, userID string) (*domain.PreBid, error) {
+	query := `SELECT ` + preBidColumns + ` FROM pre_bids WHERE auction_id = $1 AND user_id = $2`
+	preBid, err := scanPreBid(r.pool.QueryRow(ctx, query, auctionID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "pre-bid", ID: auctionID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pre-bid: %w", err)
+	}
+	return preBid, nil
+}
+
+func (r *PreBidRepository) Delete(ctx context.Context, auctionID, userID string) error {
+	query := `DELETE FROM pre_bids WHERE auction_id = $1 AND user_id = $2 AND executed_at IS NULL`
+	tag, err := r.pool.Exec(ctx, query, auctionID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete pre-bid: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return &domain.NotFoundError{Entity: "pre-bid", ID: auctionID}
+	}
+	return nil
+}
+
+func (r *PreBidRepository) ListPending(ctx context.Context, auctionID string) ([]*domain.PreBid, error) {
+	query := `
+		SELECT ` + preBidColumns + `
+		FROM pre_bids
+		WHERE auction_id = $1 AND executed_at IS NULL
+		ORDER BY max_amount DESC, updated_at ASC
+		FOR UPDATE
+	`
+	rows, err := conn(ctx, r.pool).Query(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pre-bids: %w", err)
+	}
+	defer rows.Close()
+
+	var preBids []*domain.PreBid
+	for rows.Next() {
+		preBid, err := scanPreBid(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pre-bid: %w", err)
+		}
+		preBids = append(preBids, preBid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list pre-bids: %w", err)
+	}
+	return preBids, nil
+}
+
+func (r *PreBidRepository) MarkExecuted(ctx context.Context, auctionID string, ids []string, now time.Time) error {
+	query := `
+		UPDATE pre_bids SET executed_at = $3
+		WHERE auction_id = $1 AND id = ANY($2::uuid[]) AND executed_at IS NULL
+	`
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, auctionID, ids, now); err != nil {
+		return fmt.Errorf("failed to mark pre-bids executed: %w", err)
+	}
+	return nil
+}
+
+func (r *PreBidRepository) ListDue(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT DISTINCT p.auction_id
+		FROM pre_bids p
+		JOIN auctions a ON a.id = p.auction_id
+		WHERE p.executed_at IS NULL AND a.status = 'active'
+	`
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auctions with pending pre-bids: %w", err)
+	}
+	defer rows.Close()
+
+	var auctionIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan auction id: %w", err)
+		}
+		auctionIDs = append(auctionIDs, id)
+	}
+	return auctionIDs, rows.Err()
+}
+
+func scanPreBid(row pgx.Row) (*domain.PreBid, error) {
+	var preBid domain.PreBid
+	err := row.Scan(&preBid.ID, &preBid.AuctionID, &preBid.UserID, &preBid.MaxAmount, &preBid.ExecutedAt, &preBid.CreatedAt, &preBid.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &preBid, nil
+}
@@ -2,12 +2,18 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/saigenix/bidding-system/internal/domain"
 )
 
+const productColumns = "id, name, description, owner_id, category_id, condition, status, version, created_at"
+
 type ProductRepository struct {
 	pool *pgxpool.Pool
 }
@@ -17,40 +23,81 @@ func NewProductRepository(pool *pgxpool.Pool) *ProductRepository {
 }
 
 func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) error {
-	query := `
-		INSERT INTO products (id, name, description, owner_id, created_at)
-		VALUES ($1, $2, $3, $4, $5)
-	`
-	_, err := r.pool.Exec(ctx, query, product.ID, product.Name, product.Description, product.OwnerID, product.CreatedAt)
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create product: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	return nil
+	defer tx.Rollback(ctx)
+
+	if err := insertProduct(ctx, tx, product); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (r *ProductRepository) GetByID(ctx context.Context, id string) (*domain.Product, error) {
-	query := `
-		SELECT id, name, description, owner_id, created_at
-		FROM products
-		WHERE id = $1
-	`
-	var product domain.Product
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&product.ID, &product.Name, &product.Description, &product.OwnerID, &product.CreatedAt,
-	)
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = $1`
+	product, err := scanProduct(r.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "product", ID: id}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
-	return &product, nil
+	if err := loadAttributes(ctx, r.pool, []*domain.Product{product}); err != nil {
+		return nil, err
+	}
+	return product, nil
 }
 
-func (r *ProductRepository) List(ctx context.Context) ([]*domain.Product, error) {
-	query := `
-		SELECT id, name, description, owner_id, created_at
-		FROM products
-		ORDER BY created_at DESC
-	`
-	rows, err := r.pool.Query(ctx, query)
+func (r *ProductRepository) List(ctx context.Context, q domain.ProductQuery) ([]*domain.Product, error) {
+	// where adds a condition, numbering its placeholders after the arguments so far
+	var conditions []string
+	var args []any
+	where := func(condition string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	if q.OwnerID != "" {
+		where("owner_id = $%d", q.OwnerID)
+	}
+	statuses := []domain.ProductStatus{domain.ProductStatusActive}
+	if q.IncludeArchived {
+		statuses = append(statuses, domain.ProductStatusArchived)
+	}
+	if q.IncludeDeleted {
+		statuses = append(statuses, domain.ProductStatusDeleted)
+	}
+	where("status = ANY($%d)", statuses)
+	if len(q.CategoryIDs) > 0 {
+		where("category_id = ANY($%d::uuid[])", q.CategoryIDs)
+	}
+	names := make([]string, 0, len(q.Attributes))
+	for name := range q.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		where(`EXISTS (
+			SELECT 1 FROM product_attributes pa
+			WHERE pa.product_id = products.id AND pa.name = $%d AND pa.value = $%d
+		)`, name, q.Attributes[name])
+	}
+	if q.After != nil {
+		where("(created_at, id) < ($%d, $%d)", q.After.Time, q.After.ID)
+	}
+
+	query := `SELECT ` + productColumns + ` FROM products` + whereClause(conditions) + ` ORDER BY created_at DESC, id DESC`
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list products: %w", err)
 	}
@@ -58,12 +105,136 @@ func (r *ProductRepository) List(ctx context.Context) ([]*domain.Product, error)
 
 	var products []*domain.Product
 	for rows.Next() {
-		var product domain.Product
-		if err := rows.Scan(&product.ID, &product.Name, &product.Description, &product.OwnerID, &product.CreatedAt); err != nil {
+		product, err := scanProduct(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan product: %w", err)
 		}
-		products = append(products, &product)
+		products = append(products, product)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list products: %w", err)
 	}
 
+	if err := loadAttributes(ctx, r.pool, products); err != nil {
+		return nil, err
+	}
 	return products, nil
 }
+
+func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE products
+		SET name = $2, description = $3, owner_id = $4, category_id = NULLIF($5, '')::uuid, condition = $6,
+		    status = $7, version = version + 1
+		WHERE id = $1 AND version = $8
+	`
+	tag, err := tx.Exec(ctx, query,
+		product.ID, product.Name, product.Description, product.OwnerID,
+		product.CategoryID, product.Condition, product.Status, product.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update product: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return &domain.ConflictError{Entity: "product", ID: product.ID, Version: product.Version}
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM product_attributes WHERE product_id = $1`, product.ID); err != nil {
+		return fmt.Errorf("failed to delete product attributes: %w", err)
+	}
+	if err := insertAttributes(ctx, tx, product); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to update product: %w", err)
+	}
+	product.Version++
+	return nil
+}
+
+// insertProduct stores a product with its attribute values
+func insertProduct(ctx context.Context, tx pgx.Tx, product *domain.Product) error {
+	query := `
+		INSERT INTO products (` + productColumns + `)
+		VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, $6, $7, $8, $9)
+	`
+	_, err := tx.Exec(ctx, query,
+		product.ID, product.Name, product.Description, product.OwnerID,
+		product.CategoryID, product.Condition, product.Status, product.Version, product.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create product: %w", err)
+	}
+	return insertAttributes(ctx, tx, product)
+}
+
+// insertAttributes stores the attribute values of a product
+func insertAttributes(ctx context.Context, tx pgx.Tx, product *domain.Product) error {
+	query := `INSERT INTO product_attributes (product_id, name, value) VALUES ($1, $2, $3)`
+	for name, value := range product.Attributes {
+		if _, err := tx.Exec(ctx, query, product.ID, name, value); err != nil {
+			return fmt.Errorf("failed to create product attribute: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadAttributes fills in the attribute values of products with one query
+func loadAttributes(ctx context.Context, pool *pgxpool.Pool, products []*domain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	byID := make(map[string]*domain.Product, len(products))
+	ids := make([]string, 0, len(products))
+	for _, product := range products {
+		product.Attributes = map[string]string{}
+		byID[product.ID] = product
+		ids = append(ids, product.ID)
+	}
+
+	query := `SELECT product_id, name, value FROM product_attributes WHERE product_id = ANY($1::uuid[])`
+	rows, err := pool.Query(ctx, query, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get product attributes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID, name, value string
+		if err := rows.Scan(&productID, &name, &value); err != nil {
+			return fmt.Errorf("failed to scan product attribute: %w", err)
+		}
+		byID[productID].Attributes[name] = value
+	}
+	return rows.Err()
+}
+
+func scanProduct(row pgx.Row) (*domain.Product, error) {
+	var product domain.Product
+	var categoryID *string
+	if err := row.Scan(
+		&product.ID, &product.Name, &product.Description, &product.OwnerID,
+		&categoryID, &product.Condition, &product.Status, &product.Version, &product.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if categoryID != nil {
+		product.CategoryID = *categoryID
+	}
+	return &product, nil
+}
+
+// whereClause joins conditions into a WHERE clause, or nothing without any
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+type ProxyBidRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewProxyBidRepository(pool *pgxpool.Pool) *ProxyBidRepository {
+	return &ProxyBidRepository{pool: pool}
+}
+
+func (r *ProxyBidRepository) Save(ctx context.Context, proxy *domain.ProxyBid) error {
+	query := `
+		INSERT INTO proxy_bids (id, auction_id, user_id, max_amount, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (auction_id, user_id) DO UPDATE
+		SET max_amount = EXCLUDED.max_amount, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+	err := conn(ctx, r.pool).QueryRow(ctx, query,
+		proxy.ID, proxy.AuctionID, proxy.UserID, proxy.MaxAmount, proxy.CreatedAt, proxy.UpdatedAt,
+	).Scan(&proxy.ID, &proxy.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save proxy bid: %w", err)
+	}
+	return nil
+}
+
+func (r *ProxyBidRepository) GetByAuctionID(ctx context.Context, auctionID string) ([]*domain.ProxyBid, error) {
+	query := `
+		SELECT id, auction_id, user_id, max_amount, created_at, updated_at
+		FROM proxy_bids
+		WHERE auction_id = $1
+		ORDER BY max_amount DESC, created_at ASC
+	`
+	rows, err := conn(ctx, r.pool).Query(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proxy bids: %w", err)
+	}
+	defer rows.Close()
+
+	var proxies []*domain.ProxyBid
+	for rows.Next() {
+		var proxy domain.ProxyBid
+		if err := rows.Scan(&proxy.ID, &proxy.AuctionID, &proxy.UserID, &proxy.MaxAmount, &proxy.CreatedAt, &proxy.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan proxy bid: %w", err)
+		}
+		proxies = append(proxies, &proxy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get proxy bids: %w", err)
+	}
+	return proxies, nil
+}
+
+func (r *ProxyBidRepository) Delete(ctx context.Context, auctionID, userID string) error {
+	query := `DELETE FROM proxy_bids WHERE auction_id = $1 AND user_id = $2`
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, auctionID, userID); err != nil {
+		return fmt.Errorf("failed to delete proxy bid: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+const retractionColumns = `id, bid_id, auction_id, user_id, reason, note, status,
+	coalesce(reviewer_id::text, ''), review_note, created_at, reviewed_at`
+
+type RetractionRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRetractionRepository(pool *pgxpool.Pool) *RetractionRepository {
+	return &RetractionRepository{pool: pool}
+}
+
+func (r *RetractionRepository) Create(ctx context.Context, retraction *domain.BidRetraction) error {
+	query := `
+		INSERT INTO bid_retractions (id, bid_id, auction_id, user_id, reason, note, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := conn(ctx, r.pool).Exec(ctx, query,
+		retraction.ID, retraction.BidID, retraction.AuctionID, retraction.UserID,
+		retraction.Reason, retraction.Note, retraction.Status, retraction.CreatedAt,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return fmt.Errorf("%w: bid %s already has a retraction awaiting review", domain.ErrConflict, retraction.BidID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create bid retraction: %w", err)
+	}
+	return nil
+}
+
+func (r *RetractionRepository) GetByID(ctx context.Context, id string) (*domain.BidRetraction, error) {
+	query := `SELECT ` + retractionColumns + ` FROM bid_retractions WHERE id = $1`
+	retraction, err := scanRetraction(conn(ctx, r.pool).QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "bid retraction", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bid retraction: %w", err)
+	}
+	return retraction, nil
+}
+
+func (r *RetractionRepository) Update(ctx context.Context, retraction *domain.BidRetraction) error {
+	query := `
+		UPDATE bid_retractions
+		SET status = $2, reviewer_id = NULLIF($3, '')::uuid, review_note = $4, reviewed_at = $5
+		WHERE id = $1 AND status = 'pending'
+	`
+	tag, err := conn(ctx, r.pool).Exec(ctx, query,
+		retraction.ID, retraction.Status, retraction.ReviewerID, retraction.ReviewNote, retraction.ReviewedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update bid retraction: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: bid retraction %s is not awaiting review", domain.ErrConflict, retraction.ID)
+	}
+	return nil
+}
+
+func (r *RetractionRepository) ListByUser(ctx context.Context, userID string) ([]*domain.BidRetraction, error) {
+	query := `
+		SELECT ` + retractionColumns + `
+		FROM bid_retractions
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	return r.list(ctx, query, userID)
+}
+
+func (r *RetractionRepository) ListPending(ctx context.Context) ([]*domain.BidRetraction, error) {
+	query := `
+		SELECT ` + retractionColumns + `
+		FROM bid_retractions
+		WHERE status = 'pending'
+		ORDER BY created_at ASC
+	`
+	return r.list(ctx, query)
+}
+
+func (r *RetractionRepository) list(ctx context.Context, query string, args ...any) ([]*domain.BidRetraction, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bid retractions: %w", err)
+	}
+	defer rows.Close()
+
+	var retractions []*domain.BidRetraction
+	for rows.Next() {
+		retraction, err := scanRetraction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bid retraction: %w", err)
+		}
+		retractions = append(retractions, retraction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list bid retractions: %w", err)
+	}
+	return retractions, nil
+}
+
+func scanRetraction(row pgx.Row) (*domain.BidRetraction, error) {
+	var retraction domain.BidRetraction
+	if err := row.Scan(
+		&retraction.ID, &retraction.BidID, &retraction.AuctionID, &retraction.UserID, &retraction.Reason,
+		&retraction.Note, &retraction.Status, &retraction.ReviewerID, &retraction.ReviewNote,
+		&retraction.CreatedAt, &retraction.ReviewedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &retraction, nil
+}
@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// Markers ts_headline puts around matched words. Control characters survive
+// HTML escaping, so they can be turned into tags afterwards.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+// ts_headline options for the whole name and for fragments of the description
+const (
+	nameHighlightOptions = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", HighlightAll=true`
+	snippetOptions       = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", MaxFragments=2, MaxWords=20, MinWords=8, FragmentDelimiter=" … "`
+)
+
+// searchMatches selects every active product matching the tsquery in $1, with
+// its rank and its latest auction, if any
+const searchMatches = `
+	WITH matches AS (
+		SELECT p.id, p.name, coalesce(p.description, '') AS description, p.owner_id, p.category_id, p.condition,
+		       p.version, p.created_at, ts_rank(p.search_vector, q.tsq)::float8 AS rank, q.tsq,
+		       a.id AS auction_id, a.start_time, a.end_time, a.starting_price, a.current_price, a.status,
+		       a.two_factor_threshold, a.paused_at, a.bid_count, a.increment_table, a.soft_close_seconds,
+		       a.live, a.version AS auction_version, a.created_at AS auction_created_at
+		FROM products p
+		CROSS JOIN (SELECT to_tsquery('english', $1) AS tsq) q
+		LEFT JOIN LATERAL (
+			SELECT * FROM auctions WHERE product_id = p.id ORDER BY created_at DESC LIMIT 1
+		) a ON true
+		WHERE p.search_vector @@ q.tsq AND p.status = 'active'
+	)
+`
+
+type SearchRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSearchRepository(pool *pgxpool.Pool) *SearchRepository {
+	return &SearchRepository{pool: pool}
+}
+
+func (r *SearchRepository) Search(ctx context.Context, q domain.SearchQuery) (*domain.SearchResults, error) {
+	results := &domain.SearchResults{Facets: domain.NewSearchFacets()}
+	tsquery := prefixTSQuery(domain.SearchTerms(q.Text))
+	if tsquery == "" {
+		return results, nil
+	}
+
+	// where adds a condition, numbering its placeholders after the arguments so far
+	args := []any{tsquery}
+	var conditions []string
+	where := func(condition string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+	if q.Status != "" {
+		where("status = $%d", q.Status)
+	}
+	if len(q.CategoryIDs) > 0 {
+		where("category_id = ANY($%d::uuid[])", q.CategoryIDs)
+	}
+	if q.MinPrice != nil {
+		where("current_price >= $%d", *q.MinPrice)
+	}
+	if q.MaxPrice != nil {
+		where("current_price <= $%d", *q.MaxPrice)
+	}
+
+	// The total counts every page, so it leaves out the cursor's condition
+	countQuery := searchMatches + "SELECT count(*) FROM matches" + whereClause(conditions)
+	if err := r.pool.QueryRow(ctx, countQuery, args...).Scan(&results.Total); err != nil {
+		return nil, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	if q.After != nil {
+		where("(rank, id) < ($%d, $%d)", q.After.Value, q.After.ID)
+	}
+	hits, err := r.hits(ctx, conditions, args, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+	results.Hits = hits
+
+	if results.Facets, err = r.facets(ctx, tsquery); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// hits returns up to limit matches meeting conditions, best first, with
+// their highlights
+func (r *SearchRepository) hits(ctx context.Context, conditions []string, args []any, limit int) ([]*domain.SearchHit, error) {
+	options := len(args) + 1
+	query := searchMatches + fmt.Sprintf(`
+		SELECT id, name, description, owner_id, category_id, condition, version, created_at, rank,
+		       ts_headline('english', name, tsq, $%d), ts_headline('english', description, tsq, $%d),
+		       auction_id, start_time, end_time, starting_price, current_price, status,
+		       two_factor_threshold, paused_at, bid_count, increment_table, soft_close_seconds,
+		       live, auction_version, auction_created_at
+		FROM matches`, options, options+1) + whereClause(conditions) + " ORDER BY rank DESC, id DESC"
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := r.pool.Query(ctx, query, append(args, nameHighlightOptions, snippetOptions)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []*domain.SearchHit
+	for rows.Next() {
+		product := domain.Product{Status: domain.ProductStatusActive}
+		var categoryID *string
+		var auction nullableAuction
+		hit := &domain.SearchHit{Product: &product}
+		if err := rows.Scan(
+			&product.ID, &product.Name, &product.Description, &product.OwnerID, &categoryID, &product.Condition,
+			&product.Version, &product.CreatedAt, &hit.Rank, &hit.NameHighlight, &hit.Snippet,
+			&auction.ID, &auction.StartTime, &auction.EndTime, &auction.StartingPrice, &auction.CurrentPrice, &auction.Status,
+			&auction.TwoFactorThreshold, &auction.PausedAt, &auction.BidCount, &auction.IncrementTable, &auction.SoftCloseSeconds,
+			&auction.Live, &auction.Version, &auction.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		if categoryID != nil {
+			product.CategoryID = *categoryID
+		}
+		hit.NameHighlight = highlightHTML(hit.NameHighlight)
+		hit.Snippet = highlightHTML(hit.Snippet)
+		hit.Auction = auction.auction(product.ID)
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+
+	products := make([]*domain.Product, 0, len(hits))
+	for _, hit := range hits {
+		products = append(products, hit.Product)
+	}
+	if err := loadAttributes(ctx, r.pool, products); err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// GROUPING(status, bucket, category_id) of each grouping set of the facets
+// query: a bit is set for every column the set does not group by
+const (
+	groupedByStatus   = 0b011
+	groupedByBucket   = 0b101
+	groupedByCategory = 0b110
+)
+
+// facets counts the products matching tsquery by auction status, price and
+// category
+func (r *SearchRepository) facets(ctx context.Context, tsquery string) (domain.SearchFacets, error) {
+	facets := domain.NewSearchFacets()
+	query := searchMatches + `
+		SELECT GROUPING(status, bucket, category_id), status, bucket, category_id, count(*)
+		FROM (
+			SELECT status, category_id,
+			       CASE WHEN auction_id IS NOT NULL
+			            THEN width_bucket(current_price::float8, $2::float8[]) END AS bucket
+			FROM matches
+		) m
+		GROUP BY GROUPING SETS ((status), (bucket), (category_id))
+	`
+	rows, err := r.pool.Query(ctx, query, tsquery, domain.PriceBucketBounds)
+	if err != nil {
+		return facets, fmt.Errorf("failed to count search facets: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var grouping int
+		var status, categoryID *string
+		var bucket *int
+		var count int
+		if err := rows.Scan(&grouping, &status, &bucket, &categoryID, &count); err != nil {
+			return facets, fmt.Errorf("failed to scan search facet: %w", err)
+		}
+		// Products without an auction or a category form a NULL group
+		switch {
+		case grouping == groupedByStatus && status != nil:
+			facets.Statuses[domain.AuctionStatus(*status)] = count
+		case grouping == groupedByBucket && bucket != nil && *bucket < len(facets.PriceBuckets):
+			facets.PriceBuckets[*bucket].Count = count
+		case grouping == groupedByCategory && categoryID != nil:
+			facets.Categories[*categoryID] = count
+		}
+	}
+	return facets, rows.Err()
+}
+
+// prefixTSQuery builds a tsquery matching all terms, the last one as a prefix.
+// Terms only hold letters and digits, so they need no quoting.
+func prefixTSQuery(terms []string) string {
+	if len(terms) == 0 {
+		return ""
+	}
+	return strings.Join(terms, " & ") + ":*"
+}
+
+// highlightHTML escapes a ts_headline result and turns its markers into
+// <mark> tags
+func highlightHTML(text string) string {
+	text = html.EscapeString(text)
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(text)
+}
+
+// nullableAuction scans the auction columns of a LEFT JOIN, which are all NULL
+// for products that were never auctioned
+type nullableAuction struct {
+	ID                 *string
+	StartTime          *time.Time
+	EndTime            *time.Time
+	StartingPrice      *float64
+	CurrentPrice       *float64
+	Status             *domain.AuctionStatus
+	TwoFactorThreshold *float64
+	PausedAt           *time.Time
+	BidCount           *int
+	IncrementTable     []incrementStepJSON
+	SoftCloseSeconds   *int
+	Live               *bool
+	Version            *int
+	CreatedAt          *time.Time
+}
+
+func (a *nullableAuction) auction(productID string) *domain.Auction {
+	if a.ID == nil {
+		return nil
+	}
+	return &domain.Auction{
+		ID:                 *a.ID,
+		ProductID:          productID,
+		StartTime:          *a.StartTime,
+		EndTime:            *a.EndTime,
+		StartingPrice:      *a.StartingPrice,
+		CurrentPrice:       *a.CurrentPrice,
+		Status:             *a.Status,
+		TwoFactorThreshold: a.TwoFactorThreshold,
+		PausedAt:           a.PausedAt,
+		BidCount:           *a.BidCount,
+		IncrementTable:     fromIncrementTableJSON(a.IncrementTable),
+		SoftCloseSeconds:   *a.SoftCloseSeconds,
+		Live:               *a.Live,
+		Version:            *a.Version,
+		CreatedAt:          *a.CreatedAt,
+	}
+}
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+type SecurityEventRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSecurityEventRepository(pool *pgxpool.Pool) *SecurityEventRepository {
+	return &SecurityEventRepository{pool: pool}
+}
+
+func (r *SecurityEventRepository) Create(ctx context.Context, event *domain.SecurityEvent) error {
+	query := `
+		INSERT INTO security_events (id, type, user_id, ip_address, details, created_at)
+		VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5, $6)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		event.ID, event.Type, event.UserID, event.IPAddress, event.Details, event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create security event: %w", err)
+	}
+	return nil
+}
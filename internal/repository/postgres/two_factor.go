@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+type TwoFactorRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTwoFactorRepository(pool *pgxpool.Pool) *TwoFactorRepository {
+	return &TwoFactorRepository{pool: pool}
+}
+
+func (r *TwoFactorRepository) GetByUserID(ctx context.Context, userID string) (*domain.TwoFactor, error) {
+	query := `
+		SELECT user_id, secret, confirmed_at, last_used_step, created_at
+		FROM user_two_factor
+		WHERE user_id = $1
+	`
+	var tf domain.TwoFactor
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&tf.UserID, &tf.Secret, &tf.ConfirmedAt, &tf.LastUsedAt, &tf.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil // Not enrolled
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	return &tf, nil
+}
+
+func (r *TwoFactorRepository) Save(ctx context.Context, tf *domain.TwoFactor) error {
+	query := `
+		INSERT INTO user_two_factor (user_id, secret, confirmed_at, last_used_step, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, confirmed_at = EXCLUDED.confirmed_at, last_used_step = EXCLUDED.last_used_step
+	`
+	_, err := r.pool.Exec(ctx, query, tf.UserID, tf.Secret, tf.ConfirmedAt, tf.LastUsedAt, tf.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save two-factor settings: %w", err)
+	}
+	return nil
+}
+
+func (r *TwoFactorRepository) Delete(ctx context.Context, userID string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM user_two_factor WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete two-factor settings: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codes []*domain.RecoveryCode) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	query := `
+		INSERT INTO user_recovery_codes (id, user_id, code_hash, used_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	for _, code := range codes {
+		if _, err := tx.Exec(ctx, query, code.ID, code.UserID, code.CodeHash, code.UsedAt, code.CreatedAt); err != nil {
+			return fmt.Errorf("failed to create recovery code: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	query := `
+		UPDATE user_recovery_codes
+		SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+	tag, err := r.pool.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return &domain.NotFoundError{Entity: "recovery code"}
+	}
+	return nil
+}
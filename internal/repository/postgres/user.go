@@ -2,8 +2,10 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/saigenix/bidding-system/internal/domain"
 )
@@ -18,10 +20,13 @@ func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
 
 func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, email, password_hash, created_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO users (id, email, password_hash, email_verified_at, role, display_name, avatar_url, location, bio, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
-	_, err := r.pool.Exec(ctx, query, user.ID, user.Email, user.PasswordHash, user.CreatedAt)
+	_, err := conn(ctx, r.pool).Exec(ctx, query,
+		user.ID, user.Email, user.PasswordHash, user.EmailVerifiedAt, user.Role,
+		user.Profile.DisplayName, user.Profile.AvatarURL, user.Profile.Location, user.Profile.Bio, user.CreatedAt,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
@@ -30,14 +35,18 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, created_at
+		SELECT id, email, password_hash, email_verified_at, role, display_name, avatar_url, location, bio, bid_retractions, created_at
 		FROM users
 		WHERE email = $1
 	`
 	var user domain.User
-	err := r.pool.QueryRow(ctx, query, email).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt,
+	err := conn(ctx, r.pool).QueryRow(ctx, query, email).Scan(
+		&user.ID, &user.Email, &user.PasswordHash, &user.EmailVerifiedAt, &user.Role,
+		&user.Profile.DisplayName, &user.Profile.AvatarURL, &user.Profile.Location, &user.Profile.Bio, &user.BidRetractions, &user.CreatedAt,
 	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "user", ID: email}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
@@ -46,16 +55,65 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.
 
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, created_at
+		SELECT id, email, password_hash, email_verified_at, role, display_name, avatar_url, location, bio, bid_retractions, created_at
 		FROM users
 		WHERE id = $1
 	`
 	var user domain.User
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt,
+	err := conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(
+		&user.ID, &user.Email, &user.PasswordHash, &user.EmailVerifiedAt, &user.Role,
+		&user.Profile.DisplayName, &user.Profile.AvatarURL, &user.Profile.Location, &user.Profile.Bio, &user.BidRetractions, &user.CreatedAt,
 	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "user", ID: id}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by ID: %w", err)
 	}
 	return &user, nil
 }
+
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	query := `
+		UPDATE users
+		SET email = $2, password_hash = $3, email_verified_at = $4,
+		    display_name = $5, avatar_url = $6, location = $7, bio = $8
+		WHERE id = $1
+	`
+	_, err := conn(ctx, r.pool).Exec(ctx, query,
+		user.ID, user.Email, user.PasswordHash, user.EmailVerifiedAt,
+		user.Profile.DisplayName, user.Profile.AvatarURL, user.Profile.Location, user.Profile.Bio,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdateProfile(ctx context.Context, userID string, profile domain.Profile) error {
+	query := `
+		UPDATE users
+		SET display_name = $2, avatar_url = $3, location = $4, bio = $5
+		WHERE id = $1
+	`
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, userID, profile.DisplayName, profile.AvatarURL, profile.Location, profile.Bio)
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return &domain.NotFoundError{Entity: "user", ID: userID}
+	}
+	return nil
+}
+
+func (r *UserRepository) IncrementBidRetractions(ctx context.Context, userID string) error {
+	query := `UPDATE users SET bid_retractions = bid_retractions + 1 WHERE id = $1`
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to count bid retraction: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return &domain.NotFoundError{Entity: "user", ID: userID}
+	}
+	return nil
+}
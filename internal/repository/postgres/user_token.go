@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+type UserTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewUserTokenRepository(pool *pgxpool.Pool) *UserTokenRepository {
+	return &UserTokenRepository{pool: pool}
+}
+
+func (r *UserTokenRepository) Create(ctx context.Context, token *domain.UserToken) error {
+	query := `
+		INSERT INTO user_tokens (id, user_id, purpose, token_hash, expires_at, used_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		token.ID, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt, token.UsedAt, token.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create user token: %w", err)
+	}
+	return nil
+}
+
+func (r *UserTokenRepository) GetByHash(ctx context.Context, purpose domain.TokenPurpose, tokenHash string) (*domain.UserToken, error) {
+	query := `
+		SELECT id, user_id, purpose, token_hash, expires_at, used_at, created_at
+		FROM user_tokens
+		WHERE purpose = $1 AND token_hash = $2
+	`
+	var token domain.UserToken
+	err := r.pool.QueryRow(ctx, query, purpose, tokenHash).Scan(
+		&token.ID, &token.UserID, &token.Purpose, &token.TokenHash, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &domain.NotFoundError{Entity: "user token"}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user token: %w", err)
+	}
+	return &token, nil
+}
+
+func (r *UserTokenRepository) MarkUsed(ctx context.Context, id string) error {
+	query := `
+		UPDATE user_tokens
+		SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL
+	`
+	tag, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark user token used: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("user token already used")
+	}
+	return nil
+}
+
+func (r *UserTokenRepository) DeleteByUser(ctx context.Context, userID string, purpose domain.TokenPurpose) error {
+	query := `
+		DELETE FROM user_tokens
+		WHERE user_id = $1 AND purpose = $2
+	`
+	_, err := r.pool.Exec(ctx, query, userID, purpose)
+	if err != nil {
+		return fmt.Errorf("failed to delete user tokens: %w", err)
+	}
+	return nil
+}
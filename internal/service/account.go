@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/pkg/mailer"
+)
+
+// AccountService handles email verification and password recovery
+type AccountService struct {
+	userRepo         domain.UserRepository
+	tokenRepo        domain.UserTokenRepository
+	mailer           mailer.Mailer
+	publicURL        string
+	verificationTTL  time.Duration
+	passwordResetTTL time.Duration
+}
+
+func NewAccountService(
+	userRepo domain.UserRepository,
+	tokenRepo domain.UserTokenRepository,
+	mailer mailer.Mailer,
+	publicURL string,
+	verificationTTL, passwordResetTTL time.Duration,
+) *AccountService {
+	return &AccountService{
+		userRepo:         userRepo,
+		tokenRepo:        tokenRepo,
+		mailer:           mailer,
+		publicURL:        publicURL,
+		verificationTTL:  verificationTTL,
+		passwordResetTTL: passwordResetTTL,
+	}
+}
+
+// SendVerificationEmail issues a new verification token and emails it to the user
+func (s *AccountService) SendVerificationEmail(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	token, err := issueToken(ctx, s.tokenRepo, user.ID, domain.TokenPurposeEmailVerification, s.verificationTTL)
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Welcome to the bidding system!\n\nConfirm your email address to start bidding:\n%s/verify-email?token=%s\n\nThis link expires in %s.\n",
+			s.publicURL, token, s.verificationTTL,
+		),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	return nil
+}
+
+// ResendVerificationEmail sends a fresh verification email by address.
+// Unknown or already verified addresses are ignored so callers cannot probe for accounts.
+func (s *AccountService) ResendVerificationEmail(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+	return s.SendVerificationEmail(ctx, user.ID)
+}
+
+// VerifyEmail redeems a verification token and marks the user's email as verified
+func (s *AccountService) VerifyEmail(ctx context.Context, rawToken string) error {
+	token, err := redeemToken(ctx, s.tokenRepo, domain.TokenPurposeEmailVerification, rawToken)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+	}
+
+	if err := s.tokenRepo.DeleteByUser(ctx, user.ID, domain.TokenPurposeEmailVerification); err != nil {
+		return fmt.Errorf("failed to delete verification tokens: %w", err)
+	}
+
+	return nil
+}
+
+// RequestPasswordReset emails a password reset link.
+// Unknown addresses are ignored so callers cannot probe for accounts.
+func (s *AccountService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+
+	token, err := issueToken(ctx, s.tokenRepo, user.ID, domain.TokenPurposePasswordReset, s.passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"A password reset was requested for your account.\n\nChoose a new password here:\n%s/reset-password?token=%s\n\nThis link expires in %s. If you did not request a reset, you can ignore this email.\n",
+			s.publicURL, token, s.passwordResetTTL,
+		),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+
+	return nil
+}
+
+// ResetPassword redeems a reset token and replaces the user's password
+func (s *AccountService) ResetPassword(ctx context.Context, rawToken, newPassword string) error {
+	token, err := redeemToken(ctx, s.tokenRepo, domain.TokenPurposePasswordReset, rawToken)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.PasswordHash = string(hashedPassword)
+
+	// Receiving the reset link proves ownership of the address as well
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// Invalidate any other outstanding reset links
+	if err := s.tokenRepo.DeleteByUser(ctx, user.ID, domain.TokenPurposePasswordReset); err != nil {
+		return fmt.Errorf("failed to delete reset tokens: %w", err)
+	}
+
+	return nil
+}
+
+// issueToken creates and stores a new token, returning the raw value to send to the user
+func issueToken(ctx context.Context, tokenRepo domain.UserTokenRepository, userID string, purpose domain.TokenPurpose, ttl time.Duration) (string, error) {
+	raw, err := generateRandomToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	now := time.Now()
+	token := &domain.UserToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(raw),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+
+	if err := tokenRepo.Create(ctx, token); err != nil {
+		return "", fmt.Errorf("failed to store token: %w", err)
+	}
+
+	return raw, nil
+}
+
+// redeemToken looks up a raw token and marks it used if it is still valid
+func redeemToken(ctx context.Context, tokenRepo domain.UserTokenRepository, purpose domain.TokenPurpose, raw string) (*domain.UserToken, error) {
+	token, err := tokenRepo.GetByHash(ctx, purpose, hashToken(raw))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	if token.IsUsed() || token.IsExpired() {
+		return nil, domain.ErrInvalidToken
+	}
+
+	// MarkUsed is conditional, so concurrent redemptions of the same token cannot both succeed
+	if err := tokenRepo.MarkUsed(ctx, token.ID); err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return token, nil
+}
+
+// generateRandomToken returns a URL-safe random token with 256 bits of entropy
+func generateRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the hex-encoded SHA-256 of a raw token
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+func newTestAccountService() (*AccountService, *AuthService, *mocks.MockUserTokenRepository, *mocks.MockMailer) {
+	userRepo := mocks.NewMockUserRepository()
+	tokenRepo := mocks.NewMockUserTokenRepository()
+	mailer := mocks.NewMockMailer()
+	twoFactor := NewTwoFactorService(mocks.NewMockTwoFactorRepository(), userRepo, "Test")
+	authSvc := NewAuthService(userRepo, twoFactor, newTestLoginThrottle(), "test-secret-key-for-testing", 24)
+	svc := NewAccountService(userRepo, tokenRepo, mailer, "http://localhost:8080", time.Hour, 30*time.Minute)
+	return svc, authSvc, tokenRepo, mailer
+}
+
+var tokenPattern = regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+
+// tokenFromLastEmail extracts the raw token from the link in the last sent email
+func tokenFromLastEmail(t *testing.T, mailer *mocks.MockMailer) string {
+	t.Helper()
+	msg := mailer.LastMessage()
+	if msg == nil {
+		t.Fatal("no email was sent")
+	}
+	m := tokenPattern.FindStringSubmatch(msg.Body)
+	if m == nil {
+		t.Fatalf("no token found in email body: %q", msg.Body)
+	}
+	return m[1]
+}
+
+// ============================================================================
+// VerifyEmail
+// ============================================================================
+
+func TestAccountService_VerifyEmail_Success(t *testing.T) {
+	svc, authSvc, _, mailer := newTestAccountService()
+	ctx := context.Background()
+
+	user, _ := authSvc.Register(ctx, "test@example.com", "password123")
+	if user.IsEmailVerified() {
+		t.Fatal("Register() user should start unverified")
+	}
+
+	if err := svc.SendVerificationEmail(ctx, user.ID); err != nil {
+		t.Fatalf("SendVerificationEmail() unexpected error: %v", err)
+	}
+	if to := mailer.LastMessage().To; to != "test@example.com" {
+		t.Errorf("SendVerificationEmail() sent to %q, want %q", to, "test@example.com")
+	}
+
+	if err := svc.VerifyEmail(ctx, tokenFromLastEmail(t, mailer)); err != nil {
+		t.Fatalf("VerifyEmail() unexpected error: %v", err)
+	}
+	if !user.IsEmailVerified() {
+		t.Error("VerifyEmail() did not mark the user verified")
+	}
+}
+
+func TestAccountService_VerifyEmail_SingleUse(t *testing.T) {
+	svc, authSvc, _, mailer := newTestAccountService()
+	ctx := context.Background()
+
+	user, _ := authSvc.Register(ctx, "test@example.com", "password123")
+	svc.SendVerificationEmail(ctx, user.ID)
+	token := tokenFromLastEmail(t, mailer)
+
+	if err := svc.VerifyEmail(ctx, token); err != nil {
+		t.Fatalf("First VerifyEmail() unexpected error: %v", err)
+	}
+	if err := svc.VerifyEmail(ctx, token); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Second VerifyEmail() error = %v, want %v", err, domain.ErrInvalidToken)
+	}
+}
+
+func TestAccountService_VerifyEmail_Expired(t *testing.T) {
+	svc, authSvc, tokenRepo, mailer := newTestAccountService()
+	ctx := context.Background()
+
+	user, _ := authSvc.Register(ctx, "test@example.com", "password123")
+	svc.SendVerificationEmail(ctx, user.ID)
+	raw := tokenFromLastEmail(t, mailer)
+
+	token, _ := tokenRepo.GetByHash(ctx, domain.TokenPurposeEmailVerification, hashToken(raw))
+	token.ExpiresAt = time.Now().Add(-time.Minute)
+
+	if err := svc.VerifyEmail(ctx, raw); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("VerifyEmail() error = %v, want %v", err, domain.ErrInvalidToken)
+	}
+	if user.IsEmailVerified() {
+		t.Error("VerifyEmail() verified the user with an expired token")
+	}
+}
+
+func TestAccountService_VerifyEmail_UnknownToken(t *testing.T) {
+	svc, _, _, _ := newTestAccountService()
+
+	if err := svc.VerifyEmail(context.Background(), "not-a-real-token"); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("VerifyEmail() error = %v, want %v", err, domain.ErrInvalidToken)
+	}
+}
+
+func TestAccountService_StoresOnlyTokenHash(t *testing.T) {
+	svc, authSvc, tokenRepo, mailer := newTestAccountService()
+	ctx := context.Background()
+
+	user, _ := authSvc.Register(ctx, "test@example.com", "password123")
+	svc.SendVerificationEmail(ctx, user.ID)
+	raw := tokenFromLastEmail(t, mailer)
+
+	if _, err := tokenRepo.GetByHash(ctx, domain.TokenPurposeEmailVerification, raw); err == nil {
+		t.Error("raw token was stored instead of its hash")
+	}
+}
+
+// ============================================================================
+// Password reset
+// ============================================================================
+
+func TestAccountService_ResetPassword_Success(t *testing.T) {
+	svc, authSvc, _, mailer := newTestAccountService()
+	ctx := context.Background()
+
+	authSvc.Register(ctx, "test@example.com", "password123")
+
+	if err := svc.RequestPasswordReset(ctx, "test@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset() unexpected error: %v", err)
+	}
+	if err := svc.ResetPassword(ctx, tokenFromLastEmail(t, mailer), "newpassword456"); err != nil {
+		t.Fatalf("ResetPassword() unexpected error: %v", err)
+	}
+
+	if _, err := authSvc.Login(ctx, "test@example.com", "password123", testClientIP); err == nil {
+		t.Error("Login() with old password should fail after reset")
+	}
+	if _, err := authSvc.Login(ctx, "test@example.com", "newpassword456", testClientIP); err != nil {
+		t.Errorf("Login() with new password unexpected error: %v", err)
+	}
+}
+
+func TestAccountService_ResetPassword_InvalidatesOtherTokens(t *testing.T) {
+	svc, authSvc, _, mailer := newTestAccountService()
+	ctx := context.Background()
+
+	user, _ := authSvc.Register(ctx, "test@example.com", "password123")
+
+	svc.RequestPasswordReset(ctx, "test@example.com")
+	first := tokenFromLastEmail(t, mailer)
+	svc.RequestPasswordReset(ctx, "test@example.com")
+	second := tokenFromLastEmail(t, mailer)
+
+	if err := svc.ResetPassword(ctx, second, "newpassword456"); err != nil {
+		t.Fatalf("ResetPassword() unexpected error: %v", err)
+	}
+	if err := svc.ResetPassword(ctx, first, "otherpassword789"); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("ResetPassword() with older token error = %v, want %v", err, domain.ErrInvalidToken)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("newpassword456")) != nil {
+		t.Error("password was changed by an invalidated token")
+	}
+}
+
+func TestAccountService_ResetPassword_WrongPurpose(t *testing.T) {
+	svc, authSvc, _, mailer := newTestAccountService()
+	ctx := context.Background()
+
+	user, _ := authSvc.Register(ctx, "test@example.com", "password123")
+	svc.SendVerificationEmail(ctx, user.ID)
+
+	err := svc.ResetPassword(ctx, tokenFromLastEmail(t, mailer), "newpassword456")
+	if !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("ResetPassword() with verification token error = %v, want %v", err, domain.ErrInvalidToken)
+	}
+}
+
+func TestAccountService_RequestPasswordReset_UnknownEmail(t *testing.T) {
+	svc, _, _, mailer := newTestAccountService()
+
+	if err := svc.RequestPasswordReset(context.Background(), "nobody@example.com"); err != nil {
+		t.Errorf("RequestPasswordReset() unexpected error: %v", err)
+	}
+	if len(mailer.Messages()) != 0 {
+		t.Error("RequestPasswordReset() sent an email for an unknown address")
+	}
+}
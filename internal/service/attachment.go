@@ -0,0 +1,407 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif" // registers GIF decoding
+	_ "image/jpeg"
+	_ "image/png"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/pkg/blobstore"
+	"github.com/saigenix/bidding-system/pkg/thumbnail"
+)
+
+const (
+	maxImageSize    = 10 << 20
+	maxDocumentSize = 20 << 20
+	// maxImagePixels keeps decoded images, four bytes per pixel, in bounds
+	maxImagePixels         = 25_000_000
+	maxImagesPerProduct    = 20
+	maxDocumentsPerProduct = 10
+	// thumbnailSize is the longest side of image thumbnails, in pixels
+	thumbnailSize     = 320
+	maxFilenameLength = 255
+)
+
+// MaxUploadSize bounds the request body of an upload: the largest file of
+// any kind, plus room for the multipart encoding around it
+const MaxUploadSize = maxDocumentSize + 1<<20
+
+// attachmentTypes maps the content types accepted for each kind of
+// attachment to the file extension they are stored with
+var attachmentTypes = map[domain.AttachmentKind]map[string]string{
+	domain.AttachmentImage: {
+		"image/jpeg": ".jpg",
+		"image/png":  ".png",
+		"image/gif":  ".gif",
+	},
+	domain.AttachmentDocument: {
+		"application/pdf": ".pdf",
+	},
+}
+
+// AttachmentLink is an attachment with signed URLs of its file and, for
+// images, its thumbnail. The URLs stop working at ExpiresAt.
+type AttachmentLink struct {
+	*domain.Attachment
+	URL          string
+	ThumbnailURL string
+	ExpiresAt    time.Time
+}
+
+type AttachmentService struct {
+	attachmentRepo domain.AttachmentRepository
+	productRepo    domain.ProductRepository
+	blobs          blobstore.BlobStore
+	// urlTTL is how long signed URLs stay valid
+	urlTTL time.Duration
+}
+
+func NewAttachmentService(
+	attachmentRepo domain.AttachmentRepository,
+	productRepo domain.ProductRepository,
+	blobs blobstore.BlobStore,
+	urlTTL time.Duration,
+) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		productRepo:    productRepo,
+		blobs:          blobs,
+		urlTTL:         urlTTL,
+	}
+}
+
+// Upload stores a file as an image or document of a product owned by userID.
+// The file's type is sniffed from its contents. Images get a JPEG thumbnail,
+// and the first image of a product becomes its primary image.
+func (s *AttachmentService) Upload(ctx context.Context, productID, userID string, kind domain.AttachmentKind, filename string, data []byte) (*AttachmentLink, error) {
+	if _, err := s.ownedProduct(ctx, productID, userID); err != nil {
+		return nil, err
+	}
+	contentType, ext, err := checkUpload(kind, data)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.attachmentsOf(ctx, productID, kind)
+	if err != nil {
+		return nil, err
+	}
+	limit := maxImagesPerProduct
+	if kind == domain.AttachmentDocument {
+		limit = maxDocumentsPerProduct
+	}
+	if len(existing) >= limit {
+		return nil, fmt.Errorf("%w: a product may have at most %d %ss", domain.ErrValidation, limit, kind)
+	}
+
+	id := uuid.New().String()
+	attachment := &domain.Attachment{
+		ID:          id,
+		ProductID:   productID,
+		Kind:        kind,
+		Filename:    cleanFilename(filename, string(kind)+ext),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		BlobKey:     "products/" + productID + "/" + id + ext,
+		Position:    len(existing),
+		Primary:     kind == domain.AttachmentImage && len(existing) == 0,
+		CreatedAt:   time.Now(),
+	}
+	if len(existing) > 0 {
+		attachment.Position = existing[len(existing)-1].Position + 1
+	}
+
+	var thumb []byte
+	if kind == domain.AttachmentImage {
+		img, err := decodeImage(data)
+		if err != nil {
+			return nil, err
+		}
+		if thumb, err = thumbnail.JPEG(img, thumbnailSize); err != nil {
+			return nil, err
+		}
+		attachment.Width, attachment.Height = img.Bounds().Dx(), img.Bounds().Dy()
+		attachment.ThumbnailKey = "products/" + productID + "/" + id + "_thumb.jpg"
+	}
+
+	if err := s.blobs.Put(ctx, attachment.BlobKey, data, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store file: %w", err)
+	}
+	if thumb != nil {
+		if err := s.blobs.Put(ctx, attachment.ThumbnailKey, thumb, "image/jpeg"); err != nil {
+			s.deleteFiles(ctx, attachment)
+			return nil, fmt.Errorf("failed to store thumbnail: %w", err)
+		}
+	}
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		s.deleteFiles(ctx, attachment)
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	return s.link(attachment)
+}
+
+// Links returns the attachments of products with signed URLs, by product ID,
+// images before documents, each in order
+func (s *AttachmentService) Links(ctx context.Context, productIDs ...string) (map[string][]*AttachmentLink, error) {
+	attachments, err := s.attachmentRepo.ListByProducts(ctx, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	links := make(map[string][]*AttachmentLink, len(productIDs))
+	for _, attachment := range attachments {
+		link, err := s.link(attachment)
+		if err != nil {
+			return nil, err
+		}
+		links[attachment.ProductID] = append(links[attachment.ProductID], link)
+	}
+	return links, nil
+}
+
+// ReorderImages puts the images of a product in the order of ids, which must
+// list each of its images once
+func (s *AttachmentService) ReorderImages(ctx context.Context, productID, userID string, ids []string) ([]*AttachmentLink, error) {
+	if _, err := s.ownedProduct(ctx, productID, userID); err != nil {
+		return nil, err
+	}
+	images, err := s.attachmentsOf(ctx, productID, domain.AttachmentImage)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*domain.Attachment, len(images))
+	for _, stored := range images {
+		byID[stored.ID] = stored
+	}
+	if len(ids) != len(images) {
+		return nil, fmt.Errorf("%w: the order must list all %d images of the product", domain.ErrValidation, len(images))
+	}
+	ordered := make([]*domain.Attachment, 0, len(ids))
+	for position, id := range ids {
+		stored, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not an image of the product or is listed twice", domain.ErrValidation, id)
+		}
+		delete(byID, id)
+		stored.Position = position
+		ordered = append(ordered, stored)
+	}
+
+	if err := s.attachmentRepo.UpdateOrder(ctx, ordered); err != nil {
+		return nil, fmt.Errorf("failed to reorder images: %w", err)
+	}
+	return s.links(ordered)
+}
+
+// SetPrimaryImage makes an image the one listings show first
+func (s *AttachmentService) SetPrimaryImage(ctx context.Context, productID, userID, attachmentID string) ([]*AttachmentLink, error) {
+	if _, err := s.ownedProduct(ctx, productID, userID); err != nil {
+		return nil, err
+	}
+	attachment, err := s.attachmentOf(ctx, productID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.Kind != domain.AttachmentImage {
+		return nil, fmt.Errorf("%w: only images can be primary", domain.ErrValidation)
+	}
+	images, err := s.attachmentsOf(ctx, productID, domain.AttachmentImage)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, other := range images {
+		other.Primary = other.ID == attachmentID
+	}
+	if err := s.attachmentRepo.UpdateOrder(ctx, images); err != nil {
+		return nil, fmt.Errorf("failed to set primary image: %w", err)
+	}
+	return s.links(images)
+}
+
+// Delete removes an attachment and its files. The remaining attachments of
+// its kind close the gap, and deleting the primary image makes the next
+// image primary.
+func (s *AttachmentService) Delete(ctx context.Context, productID, userID, attachmentID string) error {
+	if _, err := s.ownedProduct(ctx, productID, userID); err != nil {
+		return err
+	}
+	attachment, err := s.attachmentOf(ctx, productID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.attachmentRepo.Delete(ctx, attachmentID); err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	s.deleteFiles(ctx, attachment)
+
+	remaining, err := s.attachmentsOf(ctx, productID, attachment.Kind)
+	if err != nil {
+		return err
+	}
+	for position, other := range remaining {
+		other.Position = position
+		if attachment.Primary && position == 0 {
+			other.Primary = true
+		}
+	}
+	if err := s.attachmentRepo.UpdateOrder(ctx, remaining); err != nil {
+		return fmt.Errorf("failed to reorder attachments: %w", err)
+	}
+	return nil
+}
+
+// ownedProduct returns the product, if userID owns it and it is not deleted
+func (s *AttachmentService) ownedProduct(ctx context.Context, productID, userID string) (*domain.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product.OwnerID != userID {
+		return nil, domain.ErrNotProductOwner
+	}
+	if product.Status == domain.ProductStatusDeleted {
+		return nil, fmt.Errorf("%w: it is deleted", domain.ErrProductNotEditable)
+	}
+	return product, nil
+}
+
+// attachmentOf returns an attachment of the product, treating attachments of
+// other products as missing
+func (s *AttachmentService) attachmentOf(ctx context.Context, productID, attachmentID string) (*domain.Attachment, error) {
+	attachment, err := s.attachmentRepo.GetByID(ctx, attachmentID)
+	if err == nil && attachment.ProductID != productID {
+		err = &domain.NotFoundError{Entity: "attachment", ID: attachmentID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// attachmentsOf returns the product's attachments of one kind, in order
+func (s *AttachmentService) attachmentsOf(ctx context.Context, productID string, kind domain.AttachmentKind) ([]*domain.Attachment, error) {
+	attachments, err := s.attachmentRepo.ListByProducts(ctx, []string{productID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	var matching []*domain.Attachment
+	for _, attachment := range attachments {
+		if attachment.Kind == kind {
+			matching = append(matching, attachment)
+		}
+	}
+	return matching, nil
+}
+
+// deleteFiles removes the files of an attachment. Failures are ignored: an
+// orphaned file is unreachable without a signed URL, and the attachment
+// record is what clients see.
+func (s *AttachmentService) deleteFiles(ctx context.Context, attachment *domain.Attachment) {
+	_ = s.blobs.Delete(ctx, attachment.BlobKey)
+	if attachment.ThumbnailKey != "" {
+		_ = s.blobs.Delete(ctx, attachment.ThumbnailKey)
+	}
+}
+
+func (s *AttachmentService) link(attachment *domain.Attachment) (*AttachmentLink, error) {
+	link := &AttachmentLink{Attachment: attachment, ExpiresAt: time.Now().Add(s.urlTTL)}
+	var err error
+	if link.URL, err = s.blobs.SignedURL(attachment.BlobKey, s.urlTTL); err != nil {
+		return nil, fmt.Errorf("failed to sign attachment URL: %w", err)
+	}
+	if attachment.ThumbnailKey != "" {
+		if link.ThumbnailURL, err = s.blobs.SignedURL(attachment.ThumbnailKey, s.urlTTL); err != nil {
+			return nil, fmt.Errorf("failed to sign thumbnail URL: %w", err)
+		}
+	}
+	return link, nil
+}
+
+func (s *AttachmentService) links(attachments []*domain.Attachment) ([]*AttachmentLink, error) {
+	links := make([]*AttachmentLink, 0, len(attachments))
+	for _, attachment := range attachments {
+		link, err := s.link(attachment)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// checkUpload checks the size of an uploaded file and sniffs its content
+// type, returning it with the extension the file is stored with
+func checkUpload(kind domain.AttachmentKind, data []byte) (string, string, error) {
+	types, ok := attachmentTypes[kind]
+	if !ok {
+		return "", "", fmt.Errorf("%w: unknown attachment kind %q", domain.ErrValidation, kind)
+	}
+	limit := maxImageSize
+	if kind == domain.AttachmentDocument {
+		limit = maxDocumentSize
+	}
+	switch {
+	case len(data) == 0:
+		return "", "", fmt.Errorf("%w: the file is empty", domain.ErrValidation)
+	case len(data) > limit:
+		return "", "", fmt.Errorf("%w: %ss may be at most %d MB", domain.ErrFileTooLarge, kind, limit>>20)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	ext, ok := types[contentType]
+	if !ok {
+		accepted := make([]string, 0, len(types))
+		for t := range types {
+			accepted = append(accepted, t)
+		}
+		slices.Sort(accepted)
+		return "", "", fmt.Errorf("%w: %s is not accepted for %ss, only %s",
+			domain.ErrUnsupportedMediaType, contentType, kind, strings.Join(accepted, ", "))
+	}
+	return contentType, ext, nil
+}
+
+// decodeImage decodes an uploaded image, refusing images too large to decode
+// safely before decoding them
+func decodeImage(data []byte) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: the image cannot be decoded", domain.ErrValidation)
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, fmt.Errorf("%w: images may have at most %d megapixels", domain.ErrFileTooLarge, maxImagePixels/1_000_000)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: the image cannot be decoded", domain.ErrValidation)
+	}
+	return img, nil
+}
+
+// cleanFilename keeps the base name of an uploaded file, as browsers on some
+// systems send full paths, falling back to fallback for unnamed files
+func cleanFilename(filename, fallback string) string {
+	name := path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return fallback
+	}
+	if runes := []rune(name); len(runes) > maxFilenameLength {
+		name = string(runes[:maxFilenameLength])
+	}
+	return name
+}
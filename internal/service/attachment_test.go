@@ -0,0 +1,211 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+// newTestAttachmentService stores product-1, owned by owner-1
+func newTestAttachmentService() (*AttachmentService, *mocks.MockBlobStore) {
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Create(context.Background(), &domain.Product{ID: "product-1", OwnerID: "owner-1", Version: 1})
+	blobs := mocks.NewMockBlobStore()
+	return NewAttachmentService(mocks.NewMockAttachmentRepository(), productRepo, blobs, 15*time.Minute), blobs
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+var testPDF = []byte("%PDF-1.4\n1 0 obj << >> endobj\ntrailer << >>\n%%EOF\n")
+
+func uploadTestImages(t *testing.T, svc *AttachmentService, n int) []*AttachmentLink {
+	t.Helper()
+	links := make([]*AttachmentLink, 0, n)
+	for i := 0; i < n; i++ {
+		link, err := svc.Upload(context.Background(), "product-1", "owner-1", domain.AttachmentImage, "photo.png", testPNG(t, 800, 600))
+		if err != nil {
+			t.Fatalf("Upload() unexpected error: %v", err)
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+func TestAttachmentService_Upload_Image(t *testing.T) {
+	svc, blobs := newTestAttachmentService()
+
+	link, err := svc.Upload(context.Background(), "product-1", "owner-1", domain.AttachmentImage, `C:\Users\me\front.png`, testPNG(t, 800, 600))
+	if err != nil {
+		t.Fatalf("Upload() unexpected error: %v", err)
+	}
+	if link.ContentType != "image/png" || link.Filename != "front.png" {
+		t.Errorf("Upload() = %s %q, want image/png named front.png", link.ContentType, link.Filename)
+	}
+	if link.Width != 800 || link.Height != 600 || !link.Primary || link.Position != 0 {
+		t.Errorf("Upload() = %dx%d primary %v position %d, want the first, primary 800x600 image",
+			link.Width, link.Height, link.Primary, link.Position)
+	}
+	if link.URL == "" || link.ThumbnailURL == "" || !link.ExpiresAt.After(time.Now()) {
+		t.Errorf("Upload() URLs = %q, %q until %v; want signed URLs", link.URL, link.ThumbnailURL, link.ExpiresAt)
+	}
+	if keys := blobs.Keys(); len(keys) != 2 {
+		t.Fatalf("stored blobs = %v, want the image and its thumbnail", keys)
+	}
+	if blobs.ContentType(link.ThumbnailKey) != "image/jpeg" {
+		t.Errorf("thumbnail content type = %q, want image/jpeg", blobs.ContentType(link.ThumbnailKey))
+	}
+
+	second := uploadTestImages(t, svc, 1)[0]
+	if second.Primary || second.Position != 1 {
+		t.Errorf("second Upload() primary %v position %d, want a non-primary image at 1", second.Primary, second.Position)
+	}
+}
+
+func TestAttachmentService_Upload_Document(t *testing.T) {
+	svc, _ := newTestAttachmentService()
+
+	link, err := svc.Upload(context.Background(), "product-1", "owner-1", domain.AttachmentDocument, "manual.pdf", testPDF)
+	if err != nil {
+		t.Fatalf("Upload() unexpected error: %v", err)
+	}
+	if link.ContentType != "application/pdf" || link.ThumbnailURL != "" || link.Primary {
+		t.Errorf("Upload() = %+v, want a PDF without thumbnail", link.Attachment)
+	}
+}
+
+func TestAttachmentService_Upload_Rejected(t *testing.T) {
+	svc, blobs := newTestAttachmentService()
+	image := testPNG(t, 10, 10)
+
+	tests := []struct {
+		name    string
+		userID  string
+		kind    domain.AttachmentKind
+		data    []byte
+		wantErr error
+	}{
+		{"not the owner", "someone-else", domain.AttachmentImage, image, domain.ErrNotProductOwner},
+		{"empty", "owner-1", domain.AttachmentImage, nil, domain.ErrValidation},
+		{"document as image", "owner-1", domain.AttachmentImage, testPDF, domain.ErrUnsupportedMediaType},
+		{"image as document", "owner-1", domain.AttachmentDocument, image, domain.ErrUnsupportedMediaType},
+		{"text as document", "owner-1", domain.AttachmentDocument, []byte("just some text"), domain.ErrUnsupportedMediaType},
+		{"too large", "owner-1", domain.AttachmentImage, append(image, make([]byte, maxImageSize)...), domain.ErrFileTooLarge},
+		{"corrupt image", "owner-1", domain.AttachmentImage, image[:40], domain.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Upload(context.Background(), "product-1", tt.userID, tt.kind, "file", tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Upload() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if keys := blobs.Keys(); len(keys) != 0 {
+		t.Errorf("stored blobs = %v, want none", keys)
+	}
+}
+
+func TestAttachmentService_Upload_TooManyPixels(t *testing.T) {
+	svc, _ := newTestAttachmentService()
+
+	// A PNG header claiming 10000x10000 pixels; the check must come before decoding
+	header := testPNG(t, 1, 1)[:33]
+	binary.BigEndian.PutUint32(header[16:], 10000)
+	binary.BigEndian.PutUint32(header[20:], 10000)
+	binary.BigEndian.PutUint32(header[29:], crc32.ChecksumIEEE(header[12:29]))
+
+	_, err := svc.Upload(context.Background(), "product-1", "owner-1", domain.AttachmentImage, "huge.png", header)
+	if !errors.Is(err, domain.ErrFileTooLarge) || !strings.Contains(err.Error(), "megapixels") {
+		t.Errorf("Upload() error = %v, want a megapixel limit error", err)
+	}
+}
+
+func TestAttachmentService_ReorderImages(t *testing.T) {
+	svc, _ := newTestAttachmentService()
+	images := uploadTestImages(t, svc, 3)
+	ctx := context.Background()
+
+	if _, err := svc.ReorderImages(ctx, "product-1", "owner-1", []string{images[0].ID, images[1].ID}); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("ReorderImages() with a missing image error = %v, want %v", err, domain.ErrValidation)
+	}
+	if _, err := svc.ReorderImages(ctx, "product-1", "owner-1", []string{images[0].ID, images[0].ID, images[1].ID}); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("ReorderImages() with a duplicate error = %v, want %v", err, domain.ErrValidation)
+	}
+
+	if _, err := svc.ReorderImages(ctx, "product-1", "owner-1", []string{images[2].ID, images[0].ID, images[1].ID}); err != nil {
+		t.Fatalf("ReorderImages() unexpected error: %v", err)
+	}
+	links, _ := svc.Links(ctx, "product-1")
+	got := []string{links["product-1"][0].ID, links["product-1"][1].ID, links["product-1"][2].ID}
+	if got[0] != images[2].ID || got[1] != images[0].ID || got[2] != images[1].ID {
+		t.Errorf("Links() order = %v, want the new order", got)
+	}
+	if !links["product-1"][1].Primary {
+		t.Error("ReorderImages() changed the primary image")
+	}
+}
+
+func TestAttachmentService_SetPrimaryImage(t *testing.T) {
+	svc, _ := newTestAttachmentService()
+	images := uploadTestImages(t, svc, 2)
+	ctx := context.Background()
+
+	links, err := svc.SetPrimaryImage(ctx, "product-1", "owner-1", images[1].ID)
+	if err != nil {
+		t.Fatalf("SetPrimaryImage() unexpected error: %v", err)
+	}
+	if links[0].Primary || !links[1].Primary {
+		t.Errorf("SetPrimaryImage() primary flags = %v, %v; want only the second", links[0].Primary, links[1].Primary)
+	}
+
+	document, _ := svc.Upload(ctx, "product-1", "owner-1", domain.AttachmentDocument, "manual.pdf", testPDF)
+	if _, err := svc.SetPrimaryImage(ctx, "product-1", "owner-1", document.ID); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("SetPrimaryImage() of a document error = %v, want %v", err, domain.ErrValidation)
+	}
+	if _, err := svc.SetPrimaryImage(ctx, "product-1", "someone-else", images[0].ID); !errors.Is(err, domain.ErrNotProductOwner) {
+		t.Errorf("SetPrimaryImage() by another user error = %v, want %v", err, domain.ErrNotProductOwner)
+	}
+}
+
+func TestAttachmentService_Delete_PromotesNextImage(t *testing.T) {
+	svc, blobs := newTestAttachmentService()
+	images := uploadTestImages(t, svc, 3)
+	ctx := context.Background()
+
+	if err := svc.Delete(ctx, "product-1", "owner-1", images[0].ID); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+
+	links, _ := svc.Links(ctx, "product-1")
+	remaining := links["product-1"]
+	if len(remaining) != 2 || remaining[0].ID != images[1].ID || remaining[0].Position != 0 || remaining[1].Position != 1 {
+		t.Fatalf("Links() after delete = %d images, want the other two at positions 0 and 1", len(remaining))
+	}
+	if !remaining[0].Primary {
+		t.Error("Delete() of the primary image did not make the next one primary")
+	}
+	if keys := blobs.Keys(); len(keys) != 4 {
+		t.Errorf("stored blobs = %v, want the files of the two remaining images", keys)
+	}
+
+	if err := svc.Delete(ctx, "product-1", "owner-1", images[0].ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Delete() twice error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,37 +12,128 @@ import (
 )
 
 type AuctionService struct {
-	auctionRepo domain.AuctionRepository
+	auctionRepo  domain.AuctionRepository
+	statusRepo   domain.AuctionStatusChangeRepository
+	changeRepo   domain.AuctionChangeRepository
+	productRepo  domain.ProductRepository
+	categoryRepo domain.CategoryRepository
+	bidRepo      domain.BidRepository
+	transactor   domain.Transactor
+	events       *EventService
 }
 
-func NewAuctionService(auctionRepo domain.AuctionRepository) *AuctionService {
-	return &AuctionService{auctionRepo: auctionRepo}
+func NewAuctionService(
+	auctionRepo domain.AuctionRepository,
+	statusRepo domain.AuctionStatusChangeRepository,
+	changeRepo domain.AuctionChangeRepository,
+	productRepo domain.ProductRepository,
+	categoryRepo domain.CategoryRepository,
+	bidRepo domain.BidRepository,
+	transactor domain.Transactor,
+	events *EventService,
+) *AuctionService {
+	return &AuctionService{
+		auctionRepo:  auctionRepo,
+		statusRepo:   statusRepo,
+		changeRepo:   changeRepo,
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		bidRepo:      bidRepo,
+		transactor:   transactor,
+		events:       events,
+	}
+}
+
+// AnyVersion skips the version check of an auction change
+const AnyVersion = 0
+
+// AuctionUpdate holds the auction settings to change; nil fields are left untouched
+type AuctionUpdate struct {
+	StartTime          *time.Time
+	EndTime            *time.Time
+	StartingPrice      *float64
+	TwoFactorThreshold *float64
+}
+
+// AuctionOption configures optional auction settings at creation
+type AuctionOption func(*domain.Auction)
+
+// WithTwoFactorThreshold requires two-factor enabled accounts for bids above amount
+func WithTwoFactorThreshold(amount float64) AuctionOption {
+	return func(a *domain.Auction) {
+		a.TwoFactorThreshold = &amount
+	}
+}
+
+func (s *AuctionService) CreateAuction(ctx context.Context, productID string, startTime, endTime time.Time, startingPrice float64, opts ...AuctionOption) (*domain.Auction, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	auction, err := s.newAuction(ctx, product, startTime, endTime, startingPrice, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auctionRepo.Create(ctx, auction); err != nil {
+		return nil, fmt.Errorf("failed to create auction: %w", err)
+	}
+
+	return auction, nil
 }
 
-func (s *AuctionService) CreateAuction(ctx context.Context, productID string, startTime, endTime time.Time, startingPrice float64) (*domain.Auction, error) {
+// newAuction validates a new auction of product without storing it
+func (s *AuctionService) newAuction(ctx context.Context, product *domain.Product, startTime, endTime time.Time, startingPrice float64, opts ...AuctionOption) (*domain.Auction, error) {
+	if product.Status == domain.ProductStatusArchived || product.Status == domain.ProductStatusDeleted {
+		return nil, fmt.Errorf("%w: it is %s and cannot be auctioned", domain.ErrProductNotEditable, product.Status)
+	}
 	if endTime.Before(startTime) {
-		return nil, fmt.Errorf("end time must be after start time")
+		return nil, fmt.Errorf("%w: end time must be after start time", domain.ErrValidation)
 	}
 	if startingPrice < 0 {
-		return nil, fmt.Errorf("starting price must be non-negative")
+		return nil, fmt.Errorf("%w: starting price must be non-negative", domain.ErrValidation)
 	}
 
 	auction := &domain.Auction{
 		ID:            uuid.New().String(),
-		ProductID:     productID,
+		ProductID:     product.ID,
 		StartTime:     startTime,
 		EndTime:       endTime,
 		StartingPrice: startingPrice,
 		CurrentPrice:  startingPrice,
 		Status:        domain.AuctionStatusPending,
+		Version:       1,
 		CreatedAt:     time.Now(),
 	}
+	if err := s.applyCategoryDefaults(ctx, auction, product); err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(auction)
+	}
+	if auction.TwoFactorThreshold != nil && *auction.TwoFactorThreshold < 0 {
+		return nil, fmt.Errorf("%w: two-factor threshold must be non-negative", domain.ErrValidation)
+	}
+	return auction, nil
+}
 
-	if err := s.auctionRepo.Create(ctx, auction); err != nil {
-		return nil, fmt.Errorf("failed to create auction: %w", err)
+// applyCategoryDefaults sets the increment table and soft close window of a
+// new auction from its product's category
+func (s *AuctionService) applyCategoryDefaults(ctx context.Context, auction *domain.Auction, product *domain.Product) error {
+	if product.CategoryID == "" {
+		return nil
+	}
+	path, err := categoryPath(ctx, s.categoryRepo, product.CategoryID)
+	if err != nil {
+		return err
 	}
 
-	return auction, nil
+	defaults := domain.CategoryDefaults(path)
+	auction.IncrementTable = defaults.IncrementTable
+	if defaults.SoftCloseSeconds != nil {
+		auction.SoftCloseSeconds = *defaults.SoftCloseSeconds
+	}
+	return nil
 }
 
 func (s *AuctionService) GetAuction(ctx context.Context, id string) (*domain.Auction, error) {
@@ -51,46 +144,404 @@ func (s *AuctionService) GetAuction(ctx context.Context, id string) (*domain.Auc
 	return auction, nil
 }
 
-func (s *AuctionService) ListAuctions(ctx context.Context) ([]*domain.Auction, error) {
-	auctions, err := s.auctionRepo.List(ctx)
+// ListAuctions returns a page of the auctions matching query, continuing
+// after cursor if it is set. query.Limit is the page size, or zero for the
+// default; query.After is replaced by the decoded cursor.
+func (s *AuctionService) ListAuctions(ctx context.Context, query domain.AuctionQuery, cursor string) (*Page[*domain.Auction], error) {
+	if query.Sort == "" {
+		query.Sort = domain.AuctionSortNewest
+	}
+	if !query.Sort.Valid() {
+		return nil, fmt.Errorf("%w: unknown sort %q", domain.ErrValidation, query.Sort)
+	}
+	if err := validateID("product_id", query.ProductID); err != nil {
+		return nil, err
+	}
+	if err := validateID("owner_id", query.OwnerID); err != nil {
+		return nil, err
+	}
+	if query.MinPrice != nil && query.MaxPrice != nil && *query.MinPrice > *query.MaxPrice {
+		return nil, fmt.Errorf("%w: min_price must not exceed max_price", domain.ErrValidation)
+	}
+	limit, err := pageLimit(query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if query.After, err = domain.DecodeCursor(cursor, string(query.Sort)); err != nil {
+		return nil, err
+	}
+
+	query.Limit = limit + 1
+	auctions, err := s.auctionRepo.List(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list auctions: %w", err)
 	}
-	return auctions, nil
+	return newPage(auctions, limit, query.Sort.Cursor), nil
 }
 
-func (s *AuctionService) StartAuction(ctx context.Context, id string) error {
-	auction, err := s.auctionRepo.GetByID(ctx, id)
+// GetStatusHistory returns an auction's status changes, oldest first
+func (s *AuctionService) GetStatusHistory(ctx context.Context, id string) ([]*domain.AuctionStatusChange, error) {
+	if _, err := s.auctionRepo.GetByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	changes, err := s.statusRepo.ListByAuction(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status history: %w", err)
+	}
+	return changes, nil
+}
+
+// GetChangeLog returns the edits of an auction's settings, oldest first
+func (s *AuctionService) GetChangeLog(ctx context.Context, id string) ([]*domain.AuctionChange, error) {
+	if _, err := s.auctionRepo.GetByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	changes, err := s.changeRepo.ListByAuction(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to get auction: %w", err)
+		return nil, fmt.Errorf("failed to get change log: %w", err)
 	}
+	return changes, nil
+}
 
-	if auction.Status != domain.AuctionStatusPending {
-		return fmt.Errorf("can only start pending auctions")
+// UpdateAuction applies the seller's edit to an auction. Any setting may
+// change while the auction is pending or scheduled. Once it is active, and only
+// until the first bid, the end time may only be extended and the starting price
+// only lowered. Auctions have no reserve price, so there is none to edit. The
+// auction is saved together with the changed settings in its change log, which
+// are then announced to live subscribers.
+func (s *AuctionService) UpdateAuction(ctx context.Context, id string, version int, actorID string, update AuctionUpdate) (*domain.Auction, error) {
+	auction, err := s.getAuctionAt(ctx, id, version)
+	if err != nil {
+		return nil, err
 	}
 
-	auction.Status = domain.AuctionStatusActive
-	if err := s.auctionRepo.Update(ctx, auction); err != nil {
-		return fmt.Errorf("failed to update auction: %w", err)
+	if err := s.checkSeller(ctx, auction, actorID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkEditable(ctx, auction, update); err != nil {
+		return nil, err
+	}
+
+	edited := *auction
+	if update.StartTime != nil {
+		edited.StartTime = *update.StartTime
+	}
+	if update.EndTime != nil {
+		edited.EndTime = *update.EndTime
+	}
+	if update.StartingPrice != nil {
+		edited.StartingPrice = *update.StartingPrice
+		// No bids have been placed, so the price still starts from the beginning
+		edited.CurrentPrice = *update.StartingPrice
+	}
+	if update.TwoFactorThreshold != nil {
+		edited.TwoFactorThreshold = update.TwoFactorThreshold
+	}
+
+	if !edited.EndTime.After(edited.StartTime) {
+		return nil, fmt.Errorf("%w: end time must be after start time", domain.ErrValidation)
+	}
+	if update.EndTime != nil && !edited.EndTime.After(time.Now()) {
+		return nil, fmt.Errorf("%w: end time must be in the future", domain.ErrValidation)
+	}
+	if edited.StartingPrice < 0 {
+		return nil, fmt.Errorf("%w: starting price must be non-negative", domain.ErrValidation)
+	}
+	if edited.TwoFactorThreshold != nil && *edited.TwoFactorThreshold < 0 {
+		return nil, fmt.Errorf("%w: two-factor threshold must be non-negative", domain.ErrValidation)
+	}
+
+	changes := diffAuctions(auction, &edited)
+	if len(changes) == 0 {
+		return auction, nil
+	}
+
+	now := time.Now()
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.auctionRepo.Update(ctx, &edited); err != nil {
+			return fmt.Errorf("failed to update auction: %w", err)
+		}
+		for _, change := range changes {
+			change.ID = uuid.New().String()
+			change.AuctionID = edited.ID
+			change.Version = edited.Version
+			change.ActorID = actorID
+			change.CreatedAt = now
+			if err := s.changeRepo.Create(ctx, change); err != nil {
+				return fmt.Errorf("failed to record auction change: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	*auction = edited
+
+	if err := s.recordStatusEvent(ctx, domain.AuctionEventUpdated, auction); err != nil {
+		return nil, err
+	}
+	return auction, nil
+}
+
+// checkEditable rejects an update that the auction's status or bids no
+// longer allow
+func (s *AuctionService) checkEditable(ctx context.Context, auction *domain.Auction, update AuctionUpdate) error {
+	switch auction.Status {
+	case domain.AuctionStatusPending, domain.AuctionStatusScheduled:
+		return nil
+	case domain.AuctionStatusActive:
+	default:
+		return fmt.Errorf("%w: it is %s", domain.ErrAuctionNotEditable, auction.Status)
 	}
 
+	bids, err := s.bidRepo.GetByAuctionID(ctx, auction.ID, domain.BidQuery{Limit: 1})
+	if err != nil {
+		return fmt.Errorf("failed to get bids: %w", err)
+	}
+	if len(bids) > 0 {
+		return fmt.Errorf("%w: it already has bids", domain.ErrAuctionNotEditable)
+	}
+
+	switch {
+	case update.StartTime != nil && !update.StartTime.Equal(auction.StartTime):
+		return fmt.Errorf("%w: the start time of a running auction cannot change", domain.ErrAuctionNotEditable)
+	case update.EndTime != nil && update.EndTime.Before(auction.EndTime):
+		return fmt.Errorf("%w: the end time of a running auction can only be extended", domain.ErrAuctionNotEditable)
+	case update.StartingPrice != nil && *update.StartingPrice > auction.StartingPrice:
+		return fmt.Errorf("%w: the starting price of a running auction can only be lowered", domain.ErrAuctionNotEditable)
+	case update.TwoFactorThreshold != nil && formatOptionalPrice(update.TwoFactorThreshold) != formatOptionalPrice(auction.TwoFactorThreshold):
+		return fmt.Errorf("%w: the two-factor threshold of a running auction cannot change", domain.ErrAuctionNotEditable)
+	}
+	return nil
+}
+
+// diffAuctions lists the settings that differ between before and after
+func diffAuctions(before, after *domain.Auction) []*domain.AuctionChange {
+	var changes []*domain.AuctionChange
+	add := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, &domain.AuctionChange{Field: field, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	add(domain.AuctionFieldStartTime, formatChangeTime(before.StartTime), formatChangeTime(after.StartTime))
+	add(domain.AuctionFieldEndTime, formatChangeTime(before.EndTime), formatChangeTime(after.EndTime))
+	add(domain.AuctionFieldStartingPrice, formatPrice(before.StartingPrice), formatPrice(after.StartingPrice))
+	add(domain.AuctionFieldTwoFactorThreshold, formatOptionalPrice(before.TwoFactorThreshold), formatOptionalPrice(after.TwoFactorThreshold))
+	return changes
+}
+
+func formatChangeTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatPrice(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+func formatOptionalPrice(amount *float64) string {
+	if amount == nil {
+		return ""
+	}
+	return formatPrice(*amount)
+}
+
+// The methods below that change an auction take the version the caller last
+// saw and fail with a *domain.ConflictError if the auction has changed since;
+// AnyVersion skips the check.
+
+// StartAuction opens a pending or scheduled auction for bidding
+func (s *AuctionService) StartAuction(ctx context.Context, id string, version int, actorID string) error {
+	_, err := s.changeStatus(ctx, id, version, domain.AuctionStatusActive, "", actorID)
+	return err
+}
+
+// ScheduleAuction sets a pending auction to start automatically at its start
+// time. Only the seller may schedule an auction.
+func (s *AuctionService) ScheduleAuction(ctx context.Context, id string, version int, actorID string) (*domain.Auction, error) {
+	return s.changeSellerStatus(ctx, id, version, domain.AuctionStatusScheduled, "", actorID)
+}
+
+// PauseAuction stops bidding on an active auction and freezes its remaining
+// time. Only the seller may pause an auction.
+func (s *AuctionService) PauseAuction(ctx context.Context, id string, version int, actorID, reason string) (*domain.Auction, error) {
+	return s.changeSellerStatus(ctx, id, version, domain.AuctionStatusPaused, reason, actorID)
+}
+
+// ResumeAuction reopens a paused auction, extending its end time by the time
+// it spent paused. Only the seller may resume an auction.
+func (s *AuctionService) ResumeAuction(ctx context.Context, id string, version int, actorID string) (*domain.Auction, error) {
+	return s.changeSellerStatus(ctx, id, version, domain.AuctionStatusActive, "", actorID)
+}
+
+// CancelAuction stops an auction that has not ended without a result. Only
+// the seller may cancel an auction.
+func (s *AuctionService) CancelAuction(ctx context.Context, id string, version int, actorID, reason string) (*domain.Auction, error) {
+	return s.changeSellerStatus(ctx, id, version, domain.AuctionStatusCancelled, reason, actorID)
+}
+
+// EndAuction closes an active auction before its end time
+func (s *AuctionService) EndAuction(ctx context.Context, id string, version int, actorID string) error {
+	auction, err := s.getAuctionAt(ctx, id, version)
+	if err != nil {
+		return err
+	}
+
+	return s.closeAuction(ctx, auction, "ended manually", actorID)
+}
+
+// StartDue starts every scheduled auction whose start time has passed by now
+// and returns how many were started. An auction that fails to start, such as
+// one changed meanwhile, is skipped until the next call; the failures are
+// returned together.
+func (s *AuctionService) StartDue(ctx context.Context, now time.Time) (int, error) {
+	auctions, err := s.auctionRepo.ListDueToStart(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list auctions due to start: %w", err)
+	}
+
+	started := 0
+	var errs []error
+	for _, auction := range auctions {
+		err := s.transition(ctx, auction, domain.AuctionStatusActive, "start time reached", "")
+		if err == nil {
+			started++
+			err = s.recordStatusEvent(ctx, domain.AuctionEventStatus, auction)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to start auction %s: %w", auction.ID, err))
+		}
+	}
+	return started, errors.Join(errs...)
+}
+
+// CloseExpired ends every active auction whose end time has passed by now and
+// returns how many were closed. An auction that fails to close, such as one
+// that took a late bid meanwhile, is skipped until the next call; the
+// failures are returned together.
+func (s *AuctionService) CloseExpired(ctx context.Context, now time.Time) (int, error) {
+	auctions, err := s.auctionRepo.ListExpired(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired auctions: %w", err)
+	}
+
+	closed := 0
+	var errs []error
+	for _, auction := range auctions {
+		if err := s.closeAuction(ctx, auction, "end time reached", ""); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close auction %s: %w", auction.ID, err))
+			continue
+		}
+		closed++
+	}
+	return closed, errors.Join(errs...)
+}
+
+// changeStatus moves an auction to status to and announces the change to
+// live subscribers
+func (s *AuctionService) changeStatus(ctx context.Context, id string, version int, to domain.AuctionStatus, reason, actorID string) (*domain.Auction, error) {
+	auction, err := s.getAuctionAt(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.applyStatus(ctx, auction, to, reason, actorID)
+}
+
+// changeSellerStatus is changeStatus on behalf of the auction's seller,
+// refusing everyone else with domain.ErrNotAuctionOwner
+func (s *AuctionService) changeSellerStatus(ctx context.Context, id string, version int, to domain.AuctionStatus, reason, actorID string) (*domain.Auction, error) {
+	auction, err := s.getAuctionAt(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSeller(ctx, auction, actorID); err != nil {
+		return nil, err
+	}
+
+	return s.applyStatus(ctx, auction, to, reason, actorID)
+}
+
+// applyStatus moves a loaded auction to status to and announces the change
+func (s *AuctionService) applyStatus(ctx context.Context, auction *domain.Auction, to domain.AuctionStatus, reason, actorID string) (*domain.Auction, error) {
+	if err := s.transition(ctx, auction, to, reason, actorID); err != nil {
+		return nil, err
+	}
+	if err := s.recordStatusEvent(ctx, domain.AuctionEventStatus, auction); err != nil {
+		return nil, err
+	}
+	return auction, nil
+}
+
+// checkSeller fails with domain.ErrNotAuctionOwner unless userID owns the
+// auction's product
+func (s *AuctionService) checkSeller(ctx context.Context, auction *domain.Auction, userID string) error {
+	product, err := s.productRepo.GetByID(ctx, auction.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+	if product.OwnerID != userID {
+		return domain.ErrNotAuctionOwner
+	}
 	return nil
 }
 
-func (s *AuctionService) EndAuction(ctx context.Context, id string) error {
+// getAuctionAt returns the auction, failing with a *domain.ConflictError if
+// version is set and the auction is no longer at that version. Since updates
+// only apply to the version they read, checking it here is enough to keep a
+// change from overwriting one the caller has not seen.
+func (s *AuctionService) getAuctionAt(ctx context.Context, id string, version int) (*domain.Auction, error) {
 	auction, err := s.auctionRepo.GetByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to get auction: %w", err)
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	if version != AnyVersion && auction.Version != version {
+		return nil, &domain.ConflictError{Entity: "auction", ID: id, Version: version}
 	}
+	return auction, nil
+}
 
-	if auction.Status == domain.AuctionStatusEnded {
-		return fmt.Errorf("auction already ended")
+// closeAuction ends an auction, announcing it to live subscribers with a
+// closing event showing the auction as it closed and a closed event showing
+// it ended. Neither is sent unless the status change is saved, so a close
+// that loses to a late bid announces nothing.
+func (s *AuctionService) closeAuction(ctx context.Context, auction *domain.Auction, reason, actorID string) error {
+	closing := *auction
+	if err := s.transition(ctx, auction, domain.AuctionStatusEnded, reason, actorID); err != nil {
+		return err
 	}
+	if err := s.recordStatusEvent(ctx, domain.AuctionEventClosing, &closing); err != nil {
+		return err
+	}
+	return s.recordStatusEvent(ctx, domain.AuctionEventClosed, auction)
+}
+
+// transition applies a status change to the auction, stores it and appends
+// it to the auction's status history
+func (s *AuctionService) transition(ctx context.Context, auction *domain.Auction, to domain.AuctionStatus, reason, actorID string) error {
+	change, err := auction.Transition(to, time.Now(), reason, actorID)
+	if err != nil {
+		return err
+	}
+	change.ID = uuid.New().String()
 
-	auction.Status = domain.AuctionStatusEnded
 	if err := s.auctionRepo.Update(ctx, auction); err != nil {
 		return fmt.Errorf("failed to update auction: %w", err)
 	}
-
+	if err := s.statusRepo.Create(ctx, change); err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
 	return nil
 }
+
+// recordStatusEvent logs and publishes an event carrying the auction's current state
+func (s *AuctionService) recordStatusEvent(ctx context.Context, typ domain.AuctionEventType, auction *domain.Auction) error {
+	snapshot := *auction
+	return s.events.Record(ctx, &domain.AuctionEvent{
+		AuctionID: auction.ID,
+		Type:      typ,
+		Auction:   &snapshot,
+		CreatedAt: time.Now(),
+	})
+}
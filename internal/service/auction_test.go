@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,10 +14,19 @@ import (
 
 func newTestAuctionService() (*AuctionService, *mocks.MockAuctionRepository) {
 	repo := mocks.NewMockAuctionRepository()
-	svc := NewAuctionService(repo)
+	svc := NewAuctionService(repo, mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(), newTestProductRepo(), mocks.NewMockCategoryRepository(), mocks.NewMockBidRepository(), mocks.NewMockTransactor(), newTestEventService())
 	return svc, repo
 }
 
+// newTestProductRepo stores the products that tests create auctions of
+func newTestProductRepo() *mocks.MockProductRepository {
+	repo := mocks.NewMockProductRepository()
+	for _, id := range []string{"product-1", "product-2", "product-3", "product-123"} {
+		repo.Create(context.Background(), &domain.Product{ID: id, OwnerID: "seller-1", Version: 1})
+	}
+	return repo
+}
+
 // ============================================================================
 // CreateAuction
 // ============================================================================
@@ -46,6 +58,46 @@ func TestAuctionService_CreateAuction_Success(t *testing.T) {
 	}
 }
 
+func TestAuctionService_CreateAuction_AppliesCategoryDefaults(t *testing.T) {
+	categoryRepo := mocks.NewMockCategoryRepository()
+	productRepo := mocks.NewMockProductRepository()
+	_, laptops := newTestCategoryTree(t, NewCategoryService(categoryRepo, productRepo))
+	productRepo.Create(context.Background(), &domain.Product{ID: "product-1", CategoryID: laptops.ID, Version: 1})
+	svc := NewAuctionService(mocks.NewMockAuctionRepository(), mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(), productRepo, categoryRepo, mocks.NewMockBidRepository(), mocks.NewMockTransactor(), newTestEventService())
+
+	auction, err := svc.CreateAuction(context.Background(), "product-1", time.Now(), time.Now().Add(time.Hour), 50)
+	if err != nil {
+		t.Fatalf("CreateAuction() unexpected error: %v", err)
+	}
+	if len(auction.IncrementTable) != 2 || auction.IncrementTable[1].Increment != 5 {
+		t.Errorf("CreateAuction() increment table = %v, want the parent category's", auction.IncrementTable)
+	}
+	if auction.SoftCloseSeconds != 120 {
+		t.Errorf("CreateAuction() soft close = %d, want 120", auction.SoftCloseSeconds)
+	}
+}
+
+func TestAuctionService_CreateAuction_UnknownProduct(t *testing.T) {
+	svc, _ := newTestAuctionService()
+
+	_, err := svc.CreateAuction(context.Background(), "nonexistent", time.Now(), time.Now().Add(time.Hour), 50)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("CreateAuction() error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
+
+func TestAuctionService_CreateAuction_ArchivedProduct(t *testing.T) {
+	productRepo := newTestProductRepo()
+	product, _ := productRepo.GetByID(context.Background(), "product-1")
+	product.Status = domain.ProductStatusArchived
+	svc := NewAuctionService(mocks.NewMockAuctionRepository(), mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(), productRepo, mocks.NewMockCategoryRepository(), mocks.NewMockBidRepository(), mocks.NewMockTransactor(), newTestEventService())
+
+	_, err := svc.CreateAuction(context.Background(), "product-1", time.Now(), time.Now().Add(time.Hour), 50)
+	if !errors.Is(err, domain.ErrProductNotEditable) {
+		t.Errorf("CreateAuction() error = %v, want %v", err, domain.ErrProductNotEditable)
+	}
+}
+
 func TestAuctionService_CreateAuction_EndBeforeStart(t *testing.T) {
 	svc, _ := newTestAuctionService()
 
@@ -107,12 +159,134 @@ func TestAuctionService_ListAuctions(t *testing.T) {
 	svc.CreateAuction(context.Background(), "product-1", start, end, 100.00)
 	svc.CreateAuction(context.Background(), "product-2", start, end, 200.00)
 
-	auctions, err := svc.ListAuctions(context.Background())
+	page, err := svc.ListAuctions(context.Background(), domain.AuctionQuery{}, "")
 	if err != nil {
 		t.Fatalf("ListAuctions() unexpected error: %v", err)
 	}
-	if len(auctions) != 2 {
-		t.Errorf("ListAuctions() returned %d auctions, want 2", len(auctions))
+	if len(page.Items) != 2 {
+		t.Errorf("ListAuctions() returned %d auctions, want 2", len(page.Items))
+	}
+	if page.NextCursor != "" {
+		t.Errorf("ListAuctions() NextCursor = %q, want none on the only page", page.NextCursor)
+	}
+}
+
+// newTestAuctionList stores auctions ending in 1 to 5 hours, priced 100 to
+// 500 in reverse order, with bid counts 2, 0, 2, 0, 2, created a minute apart
+func newTestAuctionList(t *testing.T) (*AuctionService, *mocks.MockAuctionRepository) {
+	t.Helper()
+	svc, repo := newTestAuctionService()
+	now := time.Now()
+	for i := 1; i <= 5; i++ {
+		auction := &domain.Auction{
+			ID:           fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", i),
+			ProductID:    fmt.Sprintf("product-%d", i%2),
+			StartTime:    now.Add(-time.Hour),
+			EndTime:      now.Add(time.Duration(i) * time.Hour),
+			CurrentPrice: float64(600 - 100*i),
+			BidCount:     2 * (i % 2),
+			Status:       domain.AuctionStatusActive,
+			Version:      1,
+			CreatedAt:    now.Add(time.Duration(i) * time.Minute),
+		}
+		if err := repo.Create(context.Background(), auction); err != nil {
+			t.Fatalf("Create() unexpected error: %v", err)
+		}
+	}
+	return svc, repo
+}
+
+// listAllAuctionIDs pages through the whole list two auctions at a time
+func listAllAuctionIDs(t *testing.T, svc *AuctionService, query domain.AuctionQuery) []string {
+	t.Helper()
+	query.Limit = 2
+	var ids []string
+	cursor := ""
+	for range 10 {
+		page, err := svc.ListAuctions(context.Background(), query, cursor)
+		if err != nil {
+			t.Fatalf("ListAuctions() unexpected error: %v", err)
+		}
+		for _, auction := range page.Items {
+			ids = append(ids, auction.ID[len(auction.ID)-1:])
+		}
+		if page.NextCursor == "" {
+			return ids
+		}
+		cursor = page.NextCursor
+	}
+	t.Fatal("ListAuctions() kept returning a next cursor")
+	return nil
+}
+
+func TestAuctionService_ListAuctions_SortAndPage(t *testing.T) {
+	svc, _ := newTestAuctionList(t)
+
+	tests := []struct {
+		sort domain.AuctionSort
+		want string
+	}{
+		{"", "54321"},
+		{domain.AuctionSortNewest, "54321"},
+		{domain.AuctionSortEndingSoonest, "12345"},
+		{domain.AuctionSortHighestPrice, "12345"},
+		{domain.AuctionSortMostBids, "53142"},
+	}
+	for _, tt := range tests {
+		got := strings.Join(listAllAuctionIDs(t, svc, domain.AuctionQuery{Sort: tt.sort}), "")
+		if got != tt.want {
+			t.Errorf("ListAuctions(sort %q) = %s, want %s", tt.sort, got, tt.want)
+		}
+	}
+}
+
+func TestAuctionService_ListAuctions_Filters(t *testing.T) {
+	svc, repo := newTestAuctionList(t)
+	repo.SetProductOwner("product-1", "00000000-0000-0000-0000-0000000000aa")
+	now := time.Now()
+	minPrice, maxPrice := 200.0, 400.0
+	endingAfter, endingBefore := now.Add(90*time.Minute), now.Add(270*time.Minute)
+
+	tests := []struct {
+		name  string
+		query domain.AuctionQuery
+		want  string
+	}{
+		{"price range", domain.AuctionQuery{MinPrice: &minPrice, MaxPrice: &maxPrice}, "432"},
+		{"ending window", domain.AuctionQuery{EndingAfter: &endingAfter, EndingBefore: &endingBefore}, "432"},
+		{"owner", domain.AuctionQuery{OwnerID: "00000000-0000-0000-0000-0000000000aa"}, "531"},
+		{"status", domain.AuctionQuery{Status: domain.AuctionStatusPaused}, ""},
+	}
+	for _, tt := range tests {
+		got := strings.Join(listAllAuctionIDs(t, svc, tt.query), "")
+		if got != tt.want {
+			t.Errorf("ListAuctions(%s) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAuctionService_ListAuctions_InvalidQuery(t *testing.T) {
+	svc, _ := newTestAuctionList(t)
+	minPrice, maxPrice := 300.0, 200.0
+	newest := domain.NewestCursor(time.Now(), "00000000-0000-0000-0000-000000000001").Encode()
+
+	tests := []struct {
+		name   string
+		query  domain.AuctionQuery
+		cursor string
+	}{
+		{"unknown sort", domain.AuctionQuery{Sort: "cheapest"}, ""},
+		{"limit too large", domain.AuctionQuery{Limit: domain.MaxPageLimit + 1}, ""},
+		{"inverted price range", domain.AuctionQuery{MinPrice: &minPrice, MaxPrice: &maxPrice}, ""},
+		{"malformed owner", domain.AuctionQuery{OwnerID: "owner-1"}, ""},
+		{"malformed cursor", domain.AuctionQuery{}, "not a cursor"},
+		{"cursor of another sort", domain.AuctionQuery{Sort: domain.AuctionSortMostBids}, newest},
+	}
+	for _, tt := range tests {
+		_, err := svc.ListAuctions(context.Background(), tt.query, tt.cursor)
+		if !errors.Is(err, domain.ErrValidation) {
+			t.Errorf("ListAuctions(%s) error = %v, want %v", tt.name, err, domain.ErrValidation)
+		}
 	}
 }
 
@@ -127,7 +301,7 @@ func TestAuctionService_StartAuction_Success(t *testing.T) {
 	end := time.Now().Add(24 * time.Hour)
 	auction, _ := svc.CreateAuction(context.Background(), "product-123", start, end, 100.00)
 
-	err := svc.StartAuction(context.Background(), auction.ID)
+	err := svc.StartAuction(context.Background(), auction.ID, AnyVersion, "admin-1")
 	if err != nil {
 		t.Fatalf("StartAuction() unexpected error: %v", err)
 	}
@@ -147,10 +321,10 @@ func TestAuctionService_StartAuction_AlreadyActive(t *testing.T) {
 	auction, _ := svc.CreateAuction(context.Background(), "product-123", start, end, 100.00)
 
 	// Start once
-	svc.StartAuction(context.Background(), auction.ID)
+	svc.StartAuction(context.Background(), auction.ID, AnyVersion, "admin-1")
 
 	// Try to start again
-	err := svc.StartAuction(context.Background(), auction.ID)
+	err := svc.StartAuction(context.Background(), auction.ID, AnyVersion, "admin-1")
 	if err == nil {
 		t.Error("StartAuction() expected error for non-pending auction, got nil")
 	}
@@ -166,9 +340,9 @@ func TestAuctionService_EndAuction_Success(t *testing.T) {
 	start := time.Now().Add(1 * time.Hour)
 	end := time.Now().Add(24 * time.Hour)
 	auction, _ := svc.CreateAuction(context.Background(), "product-123", start, end, 100.00)
-	svc.StartAuction(context.Background(), auction.ID)
+	svc.StartAuction(context.Background(), auction.ID, AnyVersion, "admin-1")
 
-	err := svc.EndAuction(context.Background(), auction.ID)
+	err := svc.EndAuction(context.Background(), auction.ID, AnyVersion, "admin-1")
 	if err != nil {
 		t.Fatalf("EndAuction() unexpected error: %v", err)
 	}
@@ -185,12 +359,491 @@ func TestAuctionService_EndAuction_AlreadyEnded(t *testing.T) {
 	start := time.Now().Add(1 * time.Hour)
 	end := time.Now().Add(24 * time.Hour)
 	auction, _ := svc.CreateAuction(context.Background(), "product-123", start, end, 100.00)
-	svc.StartAuction(context.Background(), auction.ID)
-	svc.EndAuction(context.Background(), auction.ID)
+	svc.StartAuction(context.Background(), auction.ID, AnyVersion, "admin-1")
+	svc.EndAuction(context.Background(), auction.ID, AnyVersion, "admin-1")
 
 	// Try to end again
-	err := svc.EndAuction(context.Background(), auction.ID)
+	err := svc.EndAuction(context.Background(), auction.ID, AnyVersion, "admin-1")
 	if err == nil {
 		t.Error("EndAuction() expected error for already ended auction, got nil")
 	}
 }
+
+func TestAuctionService_EndAuction_PublishesClosingThenClosed(t *testing.T) {
+	publisher := mocks.NewMockEventPublisher()
+	svc := NewAuctionService(mocks.NewMockAuctionRepository(), mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(), newTestProductRepo(), mocks.NewMockCategoryRepository(), mocks.NewMockBidRepository(), mocks.NewMockTransactor(), NewEventService(mocks.NewMockAuctionEventRepository(), publisher))
+	ctx := context.Background()
+
+	auction, _ := svc.CreateAuction(ctx, "product-123", time.Now(), time.Now().Add(time.Hour), 100.00)
+	svc.StartAuction(ctx, auction.ID, AnyVersion, "admin-1")
+	started := len(publisher.Events())
+	if err := svc.EndAuction(ctx, auction.ID, AnyVersion, "admin-1"); err != nil {
+		t.Fatalf("EndAuction() unexpected error: %v", err)
+	}
+
+	events := publisher.Events()[started:]
+	if len(events) != 2 {
+		t.Fatalf("EndAuction() published %d events, want 2", len(events))
+	}
+	if events[0].Type != domain.AuctionEventClosing || events[0].Auction.Status != domain.AuctionStatusActive {
+		t.Errorf("first event = %s with status %q, want closing while active", events[0].Type, events[0].Auction.Status)
+	}
+	if events[1].Type != domain.AuctionEventClosed || events[1].Auction.Status != domain.AuctionStatusEnded {
+		t.Errorf("second event = %s with status %q, want closed once ended", events[1].Type, events[1].Auction.Status)
+	}
+	if events[1].Sequence != events[0].Sequence+1 {
+		t.Errorf("closed sequence = %d, want %d", events[1].Sequence, events[0].Sequence+1)
+	}
+}
+
+// ============================================================================
+// CloseExpired
+// ============================================================================
+
+func TestAuctionService_CloseExpired(t *testing.T) {
+	svc, _ := newTestAuctionService()
+	ctx := context.Background()
+	now := time.Now()
+
+	expired, _ := svc.CreateAuction(ctx, "product-1", now.Add(-2*time.Hour), now.Add(-time.Minute), 100.00)
+	running, _ := svc.CreateAuction(ctx, "product-2", now.Add(-time.Hour), now.Add(time.Hour), 100.00)
+	pending, _ := svc.CreateAuction(ctx, "product-3", now.Add(-2*time.Hour), now.Add(-time.Minute), 100.00)
+	svc.StartAuction(ctx, expired.ID, AnyVersion, "admin-1")
+	svc.StartAuction(ctx, running.ID, AnyVersion, "admin-1")
+
+	closed, err := svc.CloseExpired(ctx, now)
+	if err != nil {
+		t.Fatalf("CloseExpired() unexpected error: %v", err)
+	}
+	if closed != 1 {
+		t.Errorf("CloseExpired() closed %d auctions, want 1", closed)
+	}
+
+	for _, tc := range []struct {
+		id   string
+		want domain.AuctionStatus
+	}{
+		{expired.ID, domain.AuctionStatusEnded},
+		{running.ID, domain.AuctionStatusActive},
+		{pending.ID, domain.AuctionStatusPending},
+	} {
+		auction, _ := svc.GetAuction(ctx, tc.id)
+		if auction.Status != tc.want {
+			t.Errorf("auction %s status = %q, want %q", tc.id, auction.Status, tc.want)
+		}
+	}
+}
+
+func TestAuctionService_CloseExpired_SkipsConflicts(t *testing.T) {
+	publisher := mocks.NewMockEventPublisher()
+	repo := mocks.NewMockAuctionRepository()
+	svc := NewAuctionService(repo, mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(), newTestProductRepo(), mocks.NewMockCategoryRepository(), mocks.NewMockBidRepository(), mocks.NewMockTransactor(), NewEventService(mocks.NewMockAuctionEventRepository(), publisher))
+	ctx := context.Background()
+	now := time.Now()
+
+	raced, _ := svc.CreateAuction(ctx, "product-1", now.Add(-2*time.Hour), now.Add(-2*time.Minute), 100.00)
+	expired, _ := svc.CreateAuction(ctx, "product-2", now.Add(-2*time.Hour), now.Add(-time.Minute), 100.00)
+	svc.StartAuction(ctx, raced.ID, AnyVersion, "seller-1")
+	svc.StartAuction(ctx, expired.ID, AnyVersion, "seller-1")
+
+	// The first auction is listed at a version a late bid has since replaced
+	stale, _ := repo.GetByID(ctx, raced.ID)
+	stale.Version--
+	started := len(publisher.Events())
+
+	closed, err := svc.CloseExpired(ctx, now)
+	if closed != 1 || !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("CloseExpired() = %d, %v; want 1 closed and a conflict", closed, err)
+	}
+	if a, _ := svc.GetAuction(ctx, expired.ID); a.Status != domain.AuctionStatusEnded {
+		t.Errorf("auction after the conflict status = %q, want %q", a.Status, domain.AuctionStatusEnded)
+	}
+	for _, event := range publisher.Events()[started:] {
+		if event.AuctionID == raced.ID {
+			t.Errorf("CloseExpired() published %s for the auction it failed to close", event.Type)
+		}
+	}
+}
+
+// ============================================================================
+// Status transitions
+// ============================================================================
+
+func TestAuctionService_EndAuction_Pending(t *testing.T) {
+	svc, _ := newTestAuctionService()
+	ctx := context.Background()
+
+	auction, _ := svc.CreateAuction(ctx, "product-123", time.Now().Add(time.Hour), time.Now().Add(24*time.Hour), 100.00)
+
+	err := svc.EndAuction(ctx, auction.ID, AnyVersion, "admin-1")
+	if !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("EndAuction() error = %v, want %v", err, domain.ErrInvalidTransition)
+	}
+}
+
+func TestAuctionService_PauseAndResume(t *testing.T) {
+	svc, _ := newTestAuctionService()
+	ctx := context.Background()
+
+	auction, _ := svc.CreateAuction(ctx, "product-123", time.Now(), time.Now().Add(time.Hour), 100.00)
+	svc.StartAuction(ctx, auction.ID, AnyVersion, "admin-1")
+	end := auction.EndTime
+
+	paused, err := svc.PauseAuction(ctx, auction.ID, AnyVersion, "seller-1", "technical issue")
+	if err != nil {
+		t.Fatalf("PauseAuction() unexpected error: %v", err)
+	}
+	if paused.Status != domain.AuctionStatusPaused || paused.PausedAt == nil {
+		t.Errorf("PauseAuction() status = %q, paused at %v, want paused with a pause time", paused.Status, paused.PausedAt)
+	}
+	if _, err := svc.PauseAuction(ctx, auction.ID, AnyVersion, "seller-1", ""); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("second PauseAuction() error = %v, want %v", err, domain.ErrInvalidTransition)
+	}
+
+	resumed, err := svc.ResumeAuction(ctx, auction.ID, AnyVersion, "seller-1")
+	if err != nil {
+		t.Fatalf("ResumeAuction() unexpected error: %v", err)
+	}
+	if resumed.Status != domain.AuctionStatusActive || resumed.EndTime.Before(end) {
+		t.Errorf("ResumeAuction() status = %q, end = %v, want active ending no earlier than %v", resumed.Status, resumed.EndTime, end)
+	}
+}
+
+func TestAuctionService_CancelAuction(t *testing.T) {
+	svc, _ := newTestAuctionService()
+	ctx := context.Background()
+
+	auction, _ := svc.CreateAuction(ctx, "product-123", time.Now(), time.Now().Add(time.Hour), 100.00)
+	cancelled, err := svc.CancelAuction(ctx, auction.ID, AnyVersion, "seller-1", "lot withdrawn")
+	if err != nil {
+		t.Fatalf("CancelAuction() unexpected error: %v", err)
+	}
+	if cancelled.Status != domain.AuctionStatusCancelled {
+		t.Errorf("CancelAuction() status = %q, want %q", cancelled.Status, domain.AuctionStatusCancelled)
+	}
+
+	if err := svc.StartAuction(ctx, auction.ID, AnyVersion, "admin-1"); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("StartAuction() after cancel error = %v, want %v", err, domain.ErrInvalidTransition)
+	}
+}
+
+func TestAuctionService_ChangeStatus_NotSeller(t *testing.T) {
+	svc, repo := newTestAuctionService()
+	ctx := context.Background()
+
+	auction, _ := svc.CreateAuction(ctx, "product-123", time.Now(), time.Now().Add(time.Hour), 100.00)
+	if _, err := svc.ScheduleAuction(ctx, auction.ID, AnyVersion, "user-2"); !errors.Is(err, domain.ErrNotAuctionOwner) {
+		t.Errorf("ScheduleAuction() by another user error = %v, want %v", err, domain.ErrNotAuctionOwner)
+	}
+	svc.StartAuction(ctx, auction.ID, AnyVersion, "seller-1")
+
+	if _, err := svc.PauseAuction(ctx, auction.ID, AnyVersion, "user-2", ""); !errors.Is(err, domain.ErrNotAuctionOwner) {
+		t.Errorf("PauseAuction() by another user error = %v, want %v", err, domain.ErrNotAuctionOwner)
+	}
+	if _, err := svc.CancelAuction(ctx, auction.ID, AnyVersion, "user-2", ""); !errors.Is(err, domain.ErrNotAuctionOwner) {
+		t.Errorf("CancelAuction() by another user error = %v, want %v", err, domain.ErrNotAuctionOwner)
+	}
+	svc.PauseAuction(ctx, auction.ID, AnyVersion, "seller-1", "")
+	if _, err := svc.ResumeAuction(ctx, auction.ID, AnyVersion, "user-2"); !errors.Is(err, domain.ErrNotAuctionOwner) {
+		t.Errorf("ResumeAuction() by another user error = %v, want %v", err, domain.ErrNotAuctionOwner)
+	}
+
+	stored, _ := repo.GetByID(ctx, auction.ID)
+	if stored.Status != domain.AuctionStatusPaused {
+		t.Errorf("status = %q after refused changes, want paused by the seller", stored.Status)
+	}
+}
+
+func TestAuctionService_GetStatusHistory(t *testing.T) {
+	svc, _ := newTestAuctionService()
+	ctx := context.Background()
+
+	auction, _ := svc.CreateAuction(ctx, "product-123", time.Now(), time.Now().Add(time.Hour), 100.00)
+	svc.StartAuction(ctx, auction.ID, AnyVersion, "admin-1")
+	svc.PauseAuction(ctx, auction.ID, AnyVersion, "seller-1", "technical issue")
+	svc.CancelAuction(ctx, auction.ID, AnyVersion, "seller-1", "")
+
+	history, err := svc.GetStatusHistory(ctx, auction.ID)
+	if err != nil {
+		t.Fatalf("GetStatusHistory() unexpected error: %v", err)
+	}
+	want := []struct {
+		to     domain.AuctionStatus
+		reason string
+		actor  string
+	}{
+		{domain.AuctionStatusActive, "started", "admin-1"},
+		{domain.AuctionStatusPaused, "technical issue", "seller-1"},
+		{domain.AuctionStatusCancelled, "cancelled while paused", "seller-1"},
+	}
+	if len(history) != len(want) {
+		t.Fatalf("GetStatusHistory() returned %d changes, want %d", len(history), len(want))
+	}
+	for i, w := range want {
+		if history[i].To != w.to || history[i].Reason != w.reason || history[i].ActorID != w.actor {
+			t.Errorf("change %d = %+v, want to %s (%q) by %s", i, history[i], w.to, w.reason, w.actor)
+		}
+	}
+}
+
+func TestAuctionService_StartDue(t *testing.T) {
+	svc, _ := newTestAuctionService()
+	ctx := context.Background()
+	now := time.Now()
+
+	due, _ := svc.CreateAuction(ctx, "product-1", now.Add(-time.Minute), now.Add(time.Hour), 100.00)
+	later, _ := svc.CreateAuction(ctx, "product-2", now.Add(time.Hour), now.Add(2*time.Hour), 100.00)
+	svc.ScheduleAuction(ctx, due.ID, AnyVersion, "seller-1")
+	svc.ScheduleAuction(ctx, later.ID, AnyVersion, "seller-1")
+
+	started, err := svc.StartDue(ctx, now)
+	if err != nil {
+		t.Fatalf("StartDue() unexpected error: %v", err)
+	}
+	if started != 1 {
+		t.Errorf("StartDue() started %d auctions, want 1", started)
+	}
+	if a, _ := svc.GetAuction(ctx, due.ID); a.Status != domain.AuctionStatusActive {
+		t.Errorf("due auction status = %q, want %q", a.Status, domain.AuctionStatusActive)
+	}
+	if a, _ := svc.GetAuction(ctx, later.ID); a.Status != domain.AuctionStatusScheduled {
+		t.Errorf("later auction status = %q, want %q", a.Status, domain.AuctionStatusScheduled)
+	}
+}
+
+// ============================================================================
+// UpdateAuction
+// ============================================================================
+
+// newTestEditableAuction creates a pending auction of a product owned by seller-1
+func newTestEditableAuction(t *testing.T) (*AuctionService, *mocks.MockBidRepository, *domain.Auction) {
+	t.Helper()
+	productRepo := mocks.NewMockProductRepository()
+	bidRepo := mocks.NewMockBidRepository()
+	svc := NewAuctionService(mocks.NewMockAuctionRepository(), mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(), productRepo, mocks.NewMockCategoryRepository(), bidRepo, mocks.NewMockTransactor(), newTestEventService())
+	ctx := context.Background()
+
+	productRepo.Create(ctx, &domain.Product{ID: "product-123", OwnerID: "seller-1"})
+	auction, err := svc.CreateAuction(ctx, "product-123", time.Now().Add(time.Hour), time.Now().Add(24*time.Hour), 100.00)
+	if err != nil {
+		t.Fatalf("CreateAuction() unexpected error: %v", err)
+	}
+	return svc, bidRepo, auction
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestAuctionService_UpdateAuction_Pending(t *testing.T) {
+	svc, _, auction := newTestEditableAuction(t)
+	ctx := context.Background()
+	start := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+
+	updated, err := svc.UpdateAuction(ctx, auction.ID, AnyVersion, "seller-1", AuctionUpdate{
+		StartTime:     &start,
+		StartingPrice: floatPtr(80),
+	})
+	if err != nil {
+		t.Fatalf("UpdateAuction() unexpected error: %v", err)
+	}
+	if !updated.StartTime.Equal(start) {
+		t.Errorf("UpdateAuction() start time = %v, want %v", updated.StartTime, start)
+	}
+	if updated.StartingPrice != 80 || updated.CurrentPrice != 80 {
+		t.Errorf("UpdateAuction() prices = %v/%v, want 80/80", updated.StartingPrice, updated.CurrentPrice)
+	}
+	if updated.Version != 2 {
+		t.Errorf("UpdateAuction() version = %d, want 2", updated.Version)
+	}
+
+	changes, err := svc.GetChangeLog(ctx, auction.ID)
+	if err != nil {
+		t.Fatalf("GetChangeLog() unexpected error: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("GetChangeLog() returned %d changes, want 2", len(changes))
+	}
+	price := changes[1]
+	if price.Field != domain.AuctionFieldStartingPrice || price.OldValue != "100" || price.NewValue != "80" {
+		t.Errorf("price change = %+v, want starting_price 100 -> 80", price)
+	}
+	if price.Version != 2 || price.ActorID != "seller-1" {
+		t.Errorf("price change version/actor = %d/%q, want 2/%q", price.Version, price.ActorID, "seller-1")
+	}
+}
+
+func TestAuctionService_UpdateAuction_NoChanges(t *testing.T) {
+	svc, _, auction := newTestEditableAuction(t)
+	ctx := context.Background()
+
+	updated, err := svc.UpdateAuction(ctx, auction.ID, AnyVersion, "seller-1", AuctionUpdate{StartingPrice: floatPtr(100)})
+	if err != nil {
+		t.Fatalf("UpdateAuction() unexpected error: %v", err)
+	}
+	if updated.Version != 1 {
+		t.Errorf("UpdateAuction() version = %d, want 1", updated.Version)
+	}
+	if changes, _ := svc.GetChangeLog(ctx, auction.ID); len(changes) != 0 {
+		t.Errorf("GetChangeLog() returned %d changes, want 0", len(changes))
+	}
+}
+
+func TestAuctionService_UpdateAuction_NotOwner(t *testing.T) {
+	svc, _, auction := newTestEditableAuction(t)
+
+	_, err := svc.UpdateAuction(context.Background(), auction.ID, AnyVersion, "user-2", AuctionUpdate{StartingPrice: floatPtr(80)})
+	if !errors.Is(err, domain.ErrNotAuctionOwner) {
+		t.Errorf("UpdateAuction() error = %v, want %v", err, domain.ErrNotAuctionOwner)
+	}
+}
+
+func TestAuctionService_UpdateAuction_Invalid(t *testing.T) {
+	svc, _, auction := newTestEditableAuction(t)
+	past := time.Now().Add(-time.Minute)
+	beforeStart := auction.StartTime.Add(-time.Minute)
+
+	tests := []struct {
+		name   string
+		update AuctionUpdate
+	}{
+		{"end before start", AuctionUpdate{EndTime: &beforeStart}},
+		{"end in the past", AuctionUpdate{StartTime: &past, EndTime: &past}},
+		{"negative starting price", AuctionUpdate{StartingPrice: floatPtr(-1)}},
+		{"negative two-factor threshold", AuctionUpdate{TwoFactorThreshold: floatPtr(-1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.UpdateAuction(context.Background(), auction.ID, AnyVersion, "seller-1", tt.update); err == nil {
+				t.Error("UpdateAuction() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestAuctionService_UpdateAuction_Active(t *testing.T) {
+	svc, _, auction := newTestEditableAuction(t)
+	ctx := context.Background()
+	svc.StartAuction(ctx, auction.ID, AnyVersion, "seller-1")
+
+	later := auction.EndTime.Add(time.Hour)
+	earlier := auction.EndTime.Add(-time.Hour)
+	start := auction.StartTime.Add(time.Minute)
+
+	tests := []struct {
+		name    string
+		update  AuctionUpdate
+		allowed bool
+	}{
+		{"extend end time", AuctionUpdate{EndTime: &later}, true},
+		{"lower starting price", AuctionUpdate{StartingPrice: floatPtr(90)}, true},
+		{"shorten end time", AuctionUpdate{EndTime: &earlier}, false},
+		{"raise starting price", AuctionUpdate{StartingPrice: floatPtr(200)}, false},
+		{"move start time", AuctionUpdate{StartTime: &start}, false},
+		{"set two-factor threshold", AuctionUpdate{TwoFactorThreshold: floatPtr(500)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.UpdateAuction(ctx, auction.ID, AnyVersion, "seller-1", tt.update)
+			if tt.allowed && err != nil {
+				t.Errorf("UpdateAuction() unexpected error: %v", err)
+			}
+			if !tt.allowed && !errors.Is(err, domain.ErrAuctionNotEditable) {
+				t.Errorf("UpdateAuction() error = %v, want %v", err, domain.ErrAuctionNotEditable)
+			}
+		})
+	}
+}
+
+func TestAuctionService_UpdateAuction_ActiveWithBids(t *testing.T) {
+	svc, bidRepo, auction := newTestEditableAuction(t)
+	ctx := context.Background()
+	svc.StartAuction(ctx, auction.ID, AnyVersion, "seller-1")
+	bidRepo.Create(ctx, &domain.Bid{ID: "bid-1", AuctionID: auction.ID, UserID: "user-2", Amount: 110})
+
+	later := auction.EndTime.Add(time.Hour)
+	_, err := svc.UpdateAuction(ctx, auction.ID, AnyVersion, "seller-1", AuctionUpdate{EndTime: &later})
+	if !errors.Is(err, domain.ErrAuctionNotEditable) {
+		t.Errorf("UpdateAuction() error = %v, want %v", err, domain.ErrAuctionNotEditable)
+	}
+}
+
+func TestAuctionService_UpdateAuction_Ended(t *testing.T) {
+	svc, _, auction := newTestEditableAuction(t)
+	ctx := context.Background()
+	svc.CancelAuction(ctx, auction.ID, AnyVersion, "seller-1", "")
+
+	_, err := svc.UpdateAuction(ctx, auction.ID, AnyVersion, "seller-1", AuctionUpdate{StartingPrice: floatPtr(80)})
+	if !errors.Is(err, domain.ErrAuctionNotEditable) {
+		t.Errorf("UpdateAuction() error = %v, want %v", err, domain.ErrAuctionNotEditable)
+	}
+}
+
+func TestAuctionService_UpdateAuction_PublishesUpdated(t *testing.T) {
+	publisher := mocks.NewMockEventPublisher()
+	productRepo := mocks.NewMockProductRepository()
+	svc := NewAuctionService(mocks.NewMockAuctionRepository(), mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(), productRepo, mocks.NewMockCategoryRepository(), mocks.NewMockBidRepository(), mocks.NewMockTransactor(), NewEventService(mocks.NewMockAuctionEventRepository(), publisher))
+	ctx := context.Background()
+
+	productRepo.Create(ctx, &domain.Product{ID: "product-123", OwnerID: "seller-1"})
+	auction, _ := svc.CreateAuction(ctx, "product-123", time.Now(), time.Now().Add(time.Hour), 100.00)
+	end := auction.EndTime.Add(time.Hour)
+	if _, err := svc.UpdateAuction(ctx, auction.ID, AnyVersion, "seller-1", AuctionUpdate{EndTime: &end}); err != nil {
+		t.Fatalf("UpdateAuction() unexpected error: %v", err)
+	}
+
+	events := publisher.Events()
+	if len(events) != 1 || events[0].Type != domain.AuctionEventUpdated {
+		t.Fatalf("UpdateAuction() published %v, want one updated event", events)
+	}
+	if !events[0].Auction.EndTime.Equal(end) {
+		t.Errorf("updated event end time = %v, want %v", events[0].Auction.EndTime, end)
+	}
+}
+
+// ============================================================================
+// Versions
+// ============================================================================
+
+func TestAuctionService_ChangeStatus_Version(t *testing.T) {
+	svc, _ := newTestAuctionService()
+	ctx := context.Background()
+
+	auction, _ := svc.CreateAuction(ctx, "product-123", time.Now(), time.Now().Add(time.Hour), 100.00)
+	if err := svc.StartAuction(ctx, auction.ID, 1, "admin-1"); err != nil {
+		t.Fatalf("StartAuction() unexpected error: %v", err)
+	}
+	if auction.Version != 2 {
+		t.Fatalf("StartAuction() version = %d, want 2", auction.Version)
+	}
+
+	// A seller who last saw version 1 must not pause the auction
+	_, err := svc.PauseAuction(ctx, auction.ID, 1, "seller-1", "")
+	var conflict *domain.ConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("PauseAuction() error = %v, want a ConflictError", err)
+	}
+	if conflict.Entity != "auction" || conflict.Version != 1 {
+		t.Errorf("PauseAuction() conflict = %+v, want auction at version 1", conflict)
+	}
+
+	if _, err := svc.PauseAuction(ctx, auction.ID, 2, "seller-1", ""); err != nil {
+		t.Errorf("PauseAuction() at current version unexpected error: %v", err)
+	}
+}
+
+func TestAuctionService_UpdateAuction_StaleVersion(t *testing.T) {
+	svc, _, auction := newTestEditableAuction(t)
+	ctx := context.Background()
+
+	if _, err := svc.UpdateAuction(ctx, auction.ID, 1, "seller-1", AuctionUpdate{StartingPrice: floatPtr(90)}); err != nil {
+		t.Fatalf("UpdateAuction() unexpected error: %v", err)
+	}
+	_, err := svc.UpdateAuction(ctx, auction.ID, 1, "seller-1", AuctionUpdate{StartingPrice: floatPtr(80)})
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("UpdateAuction() error = %v, want %v", err, domain.ErrConflict)
+	}
+	if auction.StartingPrice != 90 {
+		t.Errorf("starting price = %v, want 90", auction.StartingPrice)
+	}
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,20 +14,55 @@ import (
 	"github.com/saigenix/bidding-system/internal/domain"
 )
 
+const (
+	// tokenPurposeAccess marks JWTs that grant API access
+	tokenPurposeAccess = "access"
+	// tokenPurposeTwoFactorChallenge marks JWTs that only allow completing a two-factor login
+	tokenPurposeTwoFactorChallenge = "2fa_challenge"
+	// twoFactorChallengeTTL is how long a user has to supply their second factor
+	twoFactorChallengeTTL = 5 * time.Minute
+)
+
+// LoginResult is the outcome of a password login. When two-factor authentication
+// is enabled, Token is empty and ChallengeToken must be exchanged via CompleteTwoFactorLogin.
+type LoginResult struct {
+	Token             string
+	TwoFactorRequired bool
+	ChallengeToken    string
+}
+
 type AuthService struct {
 	userRepo      domain.UserRepository
+	twoFactor     *TwoFactorService
+	throttle      *LoginThrottle
 	jwtSecret     string
 	tokenExpHours int
 }
 
-func NewAuthService(userRepo domain.UserRepository, jwtSecret string, tokenExpHours int) *AuthService {
+func NewAuthService(userRepo domain.UserRepository, twoFactor *TwoFactorService, throttle *LoginThrottle, jwtSecret string, tokenExpHours int) *AuthService {
 	return &AuthService{
 		userRepo:      userRepo,
+		twoFactor:     twoFactor,
+		throttle:      throttle,
 		jwtSecret:     jwtSecret,
 		tokenExpHours: tokenExpHours,
 	}
 }
 
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// compareDummyPassword spends the same bcrypt work as a real password check so
+// that unknown emails cannot be told apart from wrong passwords by response time
+func compareDummyPassword(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password-for-timing"), bcrypt.DefaultCost)
+	})
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+}
+
 // Register creates a new user with hashed password
 func (s *AuthService) Register(ctx context.Context, email, password string) (*domain.User, error) {
 	// Hash password
@@ -38,6 +75,7 @@ func (s *AuthService) Register(ctx context.Context, email, password string) (*do
 		ID:           uuid.New().String(),
 		Email:        email,
 		PasswordHash: string(hashedPassword),
+		Role:         domain.RoleUser,
 		CreatedAt:    time.Now(),
 	}
 
@@ -48,20 +86,92 @@ func (s *AuthService) Register(ctx context.Context, email, password string) (*do
 	return user, nil
 }
 
-// Login validates credentials and returns JWT token
-func (s *AuthService) Login(ctx context.Context, email, password string) (string, error) {
+// Login validates credentials and returns a JWT token, or a two-factor challenge
+// if the account has two-factor authentication enabled. Failed attempts are
+// throttled per account and per client IP; a *domain.LoginThrottledError is
+// returned while either is delayed or locked out.
+func (s *AuthService) Login(ctx context.Context, email, password, clientIP string) (*LoginResult, error) {
+	keys := []throttleKey{s.throttle.accountKey(email), s.throttle.ipKey(clientIP)}
+	if err := s.throttle.Check(ctx, keys...); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetByEmail(ctx, email)
+	if errors.Is(err, domain.ErrNotFound) {
+		compareDummyPassword(password)
+		if err := s.throttle.RecordFailure(ctx, "", clientIP, keys...); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidCredentials
+	}
 	if err != nil {
-		return "", fmt.Errorf("invalid credentials: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return "", fmt.Errorf("invalid credentials")
+		if err := s.throttle.RecordFailure(ctx, user.ID, clientIP, keys...); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	// Only the account is cleared; the IP keeps its history so that an attacker
+	// cannot reset it by logging into their own account between guesses
+	if err := s.throttle.Reset(ctx, keys[0]); err != nil {
+		return nil, err
+	}
+
+	// Require a second factor before issuing an access token
+	enabled, err := s.twoFactor.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		challenge, err := s.signToken(user.ID, tokenPurposeTwoFactorChallenge, twoFactorChallengeTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate challenge token: %w", err)
+		}
+		return &LoginResult{TwoFactorRequired: true, ChallengeToken: challenge}, nil
 	}
 
 	// Generate JWT
 	token, err := s.generateToken(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &LoginResult{Token: token}, nil
+}
+
+// CompleteTwoFactorLogin exchanges a challenge token and a TOTP or recovery code
+// for a JWT token. Wrong codes are throttled like wrong passwords.
+func (s *AuthService) CompleteTwoFactorLogin(ctx context.Context, challengeToken, code, clientIP string) (string, error) {
+	userID, err := s.parseToken(challengeToken, tokenPurposeTwoFactorChallenge)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid challenge token", domain.ErrUnauthorized)
+	}
+
+	keys := []throttleKey{s.throttle.twoFactorKey(userID), s.throttle.ipKey(clientIP)}
+	if err := s.throttle.Check(ctx, keys...); err != nil {
+		return "", err
+	}
+
+	if err := s.twoFactor.Verify(ctx, userID, code); err != nil {
+		if !errors.Is(err, domain.ErrInvalidTwoFactorCode) && !errors.Is(err, domain.ErrTwoFactorNotEnabled) {
+			return "", err
+		}
+		if err := s.throttle.RecordFailure(ctx, userID, clientIP, keys...); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("%w: %w", domain.ErrInvalidCredentials, err)
+	}
+
+	if err := s.throttle.Reset(ctx, keys[0]); err != nil {
+		return "", err
+	}
+
+	token, err := s.generateToken(userID)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate token: %w", err)
 	}
@@ -71,6 +181,11 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 
 // ValidateToken parses and validates JWT token, returns user ID
 func (s *AuthService) ValidateToken(tokenString string) (string, error) {
+	return s.parseToken(tokenString, tokenPurposeAccess)
+}
+
+// parseToken validates a JWT token issued for purpose and returns its user ID
+func (s *AuthService) parseToken(tokenString, purpose string) (string, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -87,6 +202,15 @@ func (s *AuthService) ValidateToken(tokenString string) (string, error) {
 		return "", fmt.Errorf("invalid token")
 	}
 
+	// Access tokens issued before token purposes existed carry no purpose claim
+	tokenPurpose, _ := claims["purpose"].(string)
+	if tokenPurpose == "" {
+		tokenPurpose = tokenPurposeAccess
+	}
+	if tokenPurpose != purpose {
+		return "", fmt.Errorf("invalid token purpose")
+	}
+
 	userID, ok := claims["user_id"].(string)
 	if !ok {
 		return "", fmt.Errorf("invalid user_id in token")
@@ -95,11 +219,17 @@ func (s *AuthService) ValidateToken(tokenString string) (string, error) {
 	return userID, nil
 }
 
-// generateToken creates a new JWT token
+// generateToken creates a new JWT access token
 func (s *AuthService) generateToken(userID string) (string, error) {
+	return s.signToken(userID, tokenPurposeAccess, time.Hour*time.Duration(s.tokenExpHours))
+}
+
+// signToken creates a JWT token for a purpose that expires after ttl
+func (s *AuthService) signToken(userID, purpose string, ttl time.Duration) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * time.Duration(s.tokenExpHours)).Unix(),
+		"purpose": purpose,
+		"exp":     time.Now().Add(ttl).Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -9,7 +9,8 @@ import (
 
 func newTestAuthService() (*AuthService, *mocks.MockUserRepository) {
 	repo := mocks.NewMockUserRepository()
-	svc := NewAuthService(repo, "test-secret-key-for-testing", 24)
+	twoFactor := NewTwoFactorService(mocks.NewMockTwoFactorRepository(), repo, "Test")
+	svc := NewAuthService(repo, twoFactor, newTestLoginThrottle(), "test-secret-key-for-testing", 24)
 	return svc, repo
 }
 
@@ -67,13 +68,16 @@ func TestAuthService_Login_Success(t *testing.T) {
 		t.Fatalf("Register() unexpected error: %v", err)
 	}
 
-	token, err := svc.Login(context.Background(), "test@example.com", "password123")
+	result, err := svc.Login(context.Background(), "test@example.com", "password123", testClientIP)
 	if err != nil {
 		t.Fatalf("Login() unexpected error: %v", err)
 	}
-	if token == "" {
+	if result.Token == "" {
 		t.Error("Login() returned empty token")
 	}
+	if result.TwoFactorRequired {
+		t.Error("Login() required two-factor for an account without it")
+	}
 }
 
 func TestAuthService_Login_WrongPassword(t *testing.T) {
@@ -84,7 +88,7 @@ func TestAuthService_Login_WrongPassword(t *testing.T) {
 		t.Fatalf("Register() unexpected error: %v", err)
 	}
 
-	_, err = svc.Login(context.Background(), "test@example.com", "wrongpassword")
+	_, err = svc.Login(context.Background(), "test@example.com", "wrongpassword", testClientIP)
 	if err == nil {
 		t.Error("Login() expected error for wrong password, got nil")
 	}
@@ -93,7 +97,7 @@ func TestAuthService_Login_WrongPassword(t *testing.T) {
 func TestAuthService_Login_NonExistentUser(t *testing.T) {
 	svc, _ := newTestAuthService()
 
-	_, err := svc.Login(context.Background(), "nobody@example.com", "password123")
+	_, err := svc.Login(context.Background(), "nobody@example.com", "password123", testClientIP)
 	if err == nil {
 		t.Error("Login() expected error for non-existent user, got nil")
 	}
@@ -111,12 +115,12 @@ func TestAuthService_ValidateToken_Valid(t *testing.T) {
 		t.Fatalf("Register() unexpected error: %v", err)
 	}
 
-	token, err := svc.Login(context.Background(), "test@example.com", "password123")
+	result, err := svc.Login(context.Background(), "test@example.com", "password123", testClientIP)
 	if err != nil {
 		t.Fatalf("Login() unexpected error: %v", err)
 	}
 
-	userID, err := svc.ValidateToken(token)
+	userID, err := svc.ValidateToken(result.Token)
 	if err != nil {
 		t.Fatalf("ValidateToken() unexpected error: %v", err)
 	}
@@ -141,14 +145,16 @@ func TestAuthService_ValidateToken_WrongSecret(t *testing.T) {
 		t.Fatalf("Register() unexpected error: %v", err)
 	}
 
-	token, err := svc1.Login(context.Background(), "test@example.com", "password123")
+	result, err := svc1.Login(context.Background(), "test@example.com", "password123", testClientIP)
 	if err != nil {
 		t.Fatalf("Login() unexpected error: %v", err)
 	}
 
 	// Validate with a different secret
-	svc2 := NewAuthService(mocks.NewMockUserRepository(), "different-secret-key", 24)
-	_, err = svc2.ValidateToken(token)
+	userRepo := mocks.NewMockUserRepository()
+	twoFactor := NewTwoFactorService(mocks.NewMockTwoFactorRepository(), userRepo, "Test")
+	svc2 := NewAuthService(userRepo, twoFactor, newTestLoginThrottle(), "different-secret-key", 24)
+	_, err = svc2.ValidateToken(result.Token)
 	if err == nil {
 		t.Error("ValidateToken() expected error for token signed with different secret, got nil")
 	}
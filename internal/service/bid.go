@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,16 +13,48 @@ import (
 type BidService struct {
 	bidRepo     domain.BidRepository
 	auctionRepo domain.AuctionRepository
+	userRepo    domain.UserRepository
+	bidderRepo  domain.AuctionBidderRepository
+	proxyRepo   domain.ProxyBidRepository
+	preBidRepo  domain.PreBidRepository
+	liveRepo    domain.LiveLotRepository
+	transactor  domain.Transactor
+	twoFactor   *TwoFactorService
+	events      *EventService
 }
 
-func NewBidService(bidRepo domain.BidRepository, auctionRepo domain.AuctionRepository) *BidService {
+func NewBidService(
+	bidRepo domain.BidRepository,
+	auctionRepo domain.AuctionRepository,
+	userRepo domain.UserRepository,
+	bidderRepo domain.AuctionBidderRepository,
+	proxyRepo domain.ProxyBidRepository,
+	preBidRepo domain.PreBidRepository,
+	liveRepo domain.LiveLotRepository,
+	transactor domain.Transactor,
+	twoFactor *TwoFactorService,
+	events *EventService,
+) *BidService {
 	return &BidService{
 		bidRepo:     bidRepo,
 		auctionRepo: auctionRepo,
+		userRepo:    userRepo,
+		bidderRepo:  bidderRepo,
+		proxyRepo:   proxyRepo,
+		preBidRepo:  preBidRepo,
+		liveRepo:    liveRepo,
+		transactor:  transactor,
+		twoFactor:   twoFactor,
+		events:      events,
 	}
 }
 
 func (s *BidService) PlaceBid(ctx context.Context, auctionID, userID string, amount float64) (*domain.Bid, error) {
+	// Only users with a verified email address may bid
+	if err := s.checkBidder(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	// Get auction
 	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
 	if err != nil {
@@ -30,42 +63,376 @@ func (s *BidService) PlaceBid(ctx context.Context, auctionID, userID string, amo
 
 	// Validate auction is active
 	if !auction.IsActive() {
-		return nil, fmt.Errorf("auction is not active")
+		return nil, domain.ErrAuctionClosed
+	}
+
+	// Pre-bids left before the auction opened bid first
+	if err := s.openPreBids(ctx, auction); err != nil {
+		return nil, err
+	}
+
+	// Live auctions take bids at the auctioneer's asking price
+	var lot *domain.LiveLot
+	if auction.Live {
+		if lot, err = s.openLiveLot(ctx, auction, amount); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate bid amount beats the current price by the minimum increment
+	if !auction.AcceptsAmount(amount) {
+		return nil, &domain.BidTooLowError{CurrentPrice: auction.CurrentPrice, MinimumBid: auction.MinimumBid()}
+	}
+
+	// High-value bids may require a two-factor enabled account
+	if err := s.checkTwoFactor(ctx, auction, userID, amount); err != nil {
+		return nil, err
+	}
+
+	bid, err := s.recordBid(ctx, auction, userID, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Let proxy bidders respond to the new price
+	if err := s.executeProxyBids(ctx, auction, userID); err != nil {
+		return nil, err
 	}
 
-	// Validate bid amount is higher than current price
-	if amount <= auction.CurrentPrice {
-		return nil, fmt.Errorf("bid amount must be higher than current price (%.2f)", auction.CurrentPrice)
+	if lot != nil {
+		if err := s.advanceLiveLot(ctx, auction, lot); err != nil {
+			return nil, err
+		}
 	}
 
-	// Create bid
-	bid := &domain.Bid{
+	return bid, nil
+}
+
+// SetProxyBid creates or updates the user's proxy bid, then immediately bids on
+// their behalf if they are not already leading within their maximum
+func (s *BidService) SetProxyBid(ctx context.Context, auctionID, userID string, maxAmount float64) (*domain.ProxyBid, error) {
+	if err := s.checkBidder(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	if !auction.IsActive() {
+		return nil, domain.ErrAuctionClosed
+	}
+	if err := s.openPreBids(ctx, auction); err != nil {
+		return nil, err
+	}
+	if !auction.AcceptsAmount(maxAmount) {
+		return nil, &domain.BidTooLowError{CurrentPrice: auction.CurrentPrice, MinimumBid: auction.MinimumBid()}
+	}
+
+	// The maximum is what the proxy may end up bidding, so it decides the 2FA requirement
+	if err := s.checkTwoFactor(ctx, auction, userID, maxAmount); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	proxy := &domain.ProxyBid{
 		ID:        uuid.New().String(),
 		AuctionID: auctionID,
 		UserID:    userID,
-		Amount:    amount,
-		CreatedAt: time.Now(),
+		MaxAmount: maxAmount,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.proxyRepo.Save(ctx, proxy); err != nil {
+		return nil, fmt.Errorf("failed to save proxy bid: %w", err)
 	}
 
-	if err := s.bidRepo.Create(ctx, bid); err != nil {
-		return nil, fmt.Errorf("failed to create bid: %w", err)
+	leaderID, err := s.leadingBidder(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.executeProxyBids(ctx, auction, leaderID); err != nil {
+		return nil, err
 	}
 
+	return proxy, nil
+}
+
+func (s *BidService) checkBidder(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.IsEmailVerified() {
+		return domain.ErrEmailNotVerified
+	}
+	return nil
+}
+
+func (s *BidService) checkTwoFactor(ctx context.Context, auction *domain.Auction, userID string, amount float64) error {
+	if !auction.RequiresTwoFactor(amount) {
+		return nil
+	}
+	enabled, err := s.twoFactor.IsEnabled(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return domain.ErrTwoFactorRequired
+	}
+	return nil
+}
+
+// recordBid stores a validated online bid of userID, as storeBid describes
+func (s *BidService) recordBid(ctx context.Context, auction *domain.Auction, userID string, amount float64) (*domain.Bid, error) {
+	return s.storeBid(ctx, auction, &domain.Bid{UserID: userID, Amount: amount, Source: domain.BidSourceOnline})
+}
+
+// storeBid raises the auction's current price and stores a validated bid,
+// extending the auction if the bid arrives within its soft close window.
+// The price is raised first: the update fails with a *domain.ConflictError if
+// the auction changed since it was read, such as by a concurrent bid or by
+// closing, and then no bid is stored. bid needs its bidder, source and
+// amount; the rest is filled in.
+func (s *BidService) storeBid(ctx context.Context, auction *domain.Auction, bid *domain.Bid) (*domain.Bid, error) {
+	bid.ID = uuid.New().String()
+	bid.AuctionID = auction.ID
+	bid.CreatedAt = time.Now()
+
 	// Update auction current price
-	auction.CurrentPrice = amount
-	if err := s.auctionRepo.Update(ctx, auction); err != nil {
-		return nil, fmt.Errorf("failed to update auction: %w", err)
+	auction.CurrentPrice = bid.Amount
+	auction.BidCount++
+	extended := auction.ExtendForBid(bid.CreatedAt)
+	// The new price and the bid behind it are saved together
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.auctionRepo.Update(ctx, auction); err != nil {
+			return fmt.Errorf("failed to update auction: %w", err)
+		}
+		if err := s.bidRepo.Create(ctx, bid); err != nil {
+			return fmt.Errorf("failed to create bid: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bidder, err := s.bidderName(ctx, bid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.events.Record(ctx, &domain.AuctionEvent{
+		AuctionID: auction.ID,
+		Type:      domain.AuctionEventBid,
+		Bid:       bid,
+		Bidder:    bidder,
+		CreatedAt: bid.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if extended {
+		snapshot := *auction
+		err = s.events.Record(ctx, &domain.AuctionEvent{
+			AuctionID: auction.ID,
+			Type:      domain.AuctionEventExtended,
+			Auction:   &snapshot,
+			CreatedAt: bid.CreatedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return bid, nil
 }
 
-func (s *BidService) GetBids(ctx context.Context, auctionID string) ([]*domain.Bid, error) {
-	bids, err := s.bidRepo.GetByAuctionID(ctx, auctionID)
+// bidderName returns the public name of a new bid's bidder: the paddle of a
+// floor bid, or the pseudonym of an online bidder, which first-time bidders
+// are given for the auction
+func (s *BidService) bidderName(ctx context.Context, bid *domain.Bid) (string, error) {
+	if bid.IsFloor() {
+		return domain.FloorBidder(bid.Paddle), nil
+	}
+	number, err := s.bidderRepo.Assign(ctx, bid.AuctionID, bid.UserID)
+	if err != nil {
+		return "", fmt.Errorf("failed to assign bidder pseudonym: %w", err)
+	}
+	return domain.BidderPseudonym(number), nil
+}
+
+// openLiveLot returns the live lot of a live auction, checking that it takes
+// a bid of amount
+func (s *BidService) openLiveLot(ctx context.Context, auction *domain.Auction, amount float64) (*domain.LiveLot, error) {
+	lot, err := s.liveRepo.GetByAuction(ctx, auction.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get live lot: %w", err)
+	}
+	if !lot.IsOpen() {
+		return nil, domain.ErrAuctionClosed
+	}
+	if amount < lot.AskingPrice {
+		return nil, &domain.BidTooLowError{CurrentPrice: auction.CurrentPrice, MinimumBid: lot.AskingPrice}
+	}
+	return lot, nil
+}
+
+// advanceLiveLot reopens a live lot after bids and asks the auction's next
+// ask, announcing it to live subscribers
+func (s *BidService) advanceLiveLot(ctx context.Context, auction *domain.Auction, lot *domain.LiveLot) error {
+	if err := lot.Bid(auction.NextAsk(), time.Now()); err != nil {
+		return err
+	}
+	if err := s.liveRepo.Update(ctx, lot); err != nil {
+		return fmt.Errorf("failed to update live lot: %w", err)
+	}
+	return s.events.Record(ctx, newLiveEvent(auction, lot))
+}
+
+// leadingBidder returns the user holding the highest bid that has not been
+// retracted, or "" if there is none
+func (s *BidService) leadingBidder(ctx context.Context, auctionID string) (string, error) {
+	bid, err := s.bidRepo.GetHighestBid(ctx, auctionID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get highest bid: %w", err)
+	}
+	if bid == nil {
+		return "", nil
+	}
+	return bid.UserID, nil
+}
+
+// executeProxyBids places the bids that proxy bidders would make against the
+// current price. The strongest proxy outbids everyone else by one increment,
+// capped at its maximum; a weaker competing proxy first bids its own maximum.
+// The increment is the auction's, or domain.ProxyBidIncrement without an
+// increment table.
+func (s *BidService) executeProxyBids(ctx context.Context, auction *domain.Auction, leaderID string) error {
+	proxies, err := s.proxyRepo.GetByAuctionID(ctx, auction.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get proxy bids: %w", err)
+	}
+
+	// Proxies still in contention: those that can beat the price, plus the leader's
+	var top *domain.ProxyBid
+	challenger := 0.0
+	challengerID := ""
+	for _, proxy := range proxies {
+		if proxy.MaxAmount <= auction.CurrentPrice && proxy.UserID != leaderID {
+			continue
+		}
+		if top == nil {
+			top = proxy
+			continue
+		}
+		if proxy.UserID != top.UserID && proxy.MaxAmount > challenger {
+			challenger = proxy.MaxAmount
+			challengerID = proxy.UserID
+		}
+	}
+	if top == nil {
+		return nil
+	}
+
+	competing := challenger
+	if leaderID != top.UserID && auction.CurrentPrice > competing {
+		competing = auction.CurrentPrice
+	}
+	increment := domain.IncrementFor(auction.IncrementTable, competing)
+	if increment == 0 {
+		increment = domain.ProxyBidIncrement
+	}
+	target := roundCents(math.Min(top.MaxAmount, competing+increment))
+	if target <= auction.CurrentPrice {
+		return nil
+	}
+
+	if challengerID != "" && challenger > auction.CurrentPrice && challenger < target {
+		if _, err := s.recordBid(ctx, auction, challengerID, challenger); err != nil {
+			return fmt.Errorf("failed to place proxy bid: %w", err)
+		}
+	}
+	if _, err := s.recordBid(ctx, auction, top.UserID, target); err != nil {
+		return fmt.Errorf("failed to place proxy bid: %w", err)
+	}
+	return nil
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// GetBids returns a page of an auction's bids, newest first, continuing after
+// cursor if it is set. query.Limit is the page size, or zero for the default.
+func (s *BidService) GetBids(ctx context.Context, auctionID string, query domain.BidQuery, cursor string) (*Page[*domain.Bid], error) {
+	limit, err := bidPageQuery(&query, cursor)
+	if err != nil {
+		return nil, err
+	}
+	bids, err := s.bidRepo.GetByAuctionID(ctx, auctionID, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bids: %w", err)
 	}
-	return bids, nil
+	return newPage(bids, limit, bidCursor), nil
+}
+
+// GetPublicBids returns a page of an auction's bids like GetBids, with bidder
+// identities replaced by per-auction pseudonyms. Bids placed by viewerID are
+// flagged as theirs, and retracted bids as retracted.
+func (s *BidService) GetPublicBids(ctx context.Context, auctionID, viewerID string, query domain.BidQuery, cursor string) (*Page[*domain.PublicBid], error) {
+	limit, err := bidPageQuery(&query, cursor)
+	if err != nil {
+		return nil, err
+	}
+	bids, err := s.bidRepo.GetByAuctionID(ctx, auctionID, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bids: %w", err)
+	}
+
+	numbers, err := s.bidderRepo.ListByAuction(ctx, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bidder pseudonyms: %w", err)
+	}
+
+	page := newPage(bids, limit, bidCursor)
+	public := make([]*domain.PublicBid, 0, len(page.Items))
+	for _, bid := range page.Items {
+		bidder := domain.BidderPseudonym(numbers[bid.UserID])
+		if bid.IsFloor() {
+			bidder = domain.FloorBidder(bid.Paddle)
+		}
+		public = append(public, &domain.PublicBid{
+			ID:        bid.ID,
+			AuctionID: bid.AuctionID,
+			Bidder:    bidder,
+			IsMine:    bid.UserID != "" && bid.UserID == viewerID,
+			Amount:    bid.Amount,
+			Source:    bid.Source,
+			Retracted: bid.IsRetracted(),
+			CreatedAt: bid.CreatedAt,
+		})
+	}
+	return &Page[*domain.PublicBid]{Items: public, NextCursor: page.NextCursor}, nil
+}
+
+// bidPageQuery checks the page size and cursor of a bid list and sets up
+// query to fetch one bid beyond the page. It returns the page size.
+func bidPageQuery(query *domain.BidQuery, cursor string) (int, error) {
+	limit, err := pageLimit(query.Limit)
+	if err != nil {
+		return 0, err
+	}
+	if query.After, err = domain.DecodeCursor(cursor, domain.SortNewest); err != nil {
+		return 0, err
+	}
+	query.Limit = limit + 1
+	return limit, nil
+}
+
+func bidCursor(bid *domain.Bid) *domain.Cursor {
+	return domain.NewestCursor(bid.CreatedAt, bid.ID)
 }
 
 func (s *BidService) GetWinningBid(ctx context.Context, auctionID string) (*domain.Bid, error) {
@@ -73,5 +440,8 @@ func (s *BidService) GetWinningBid(ctx context.Context, auctionID string) (*doma
 	if err != nil {
 		return nil, fmt.Errorf("failed to get winning bid: %w", err)
 	}
+	if bid == nil {
+		return nil, &domain.NotFoundError{Entity: "winning bid", ID: auctionID}
+	}
 	return bid, nil
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -10,10 +11,30 @@ import (
 )
 
 func newTestBidService() (*BidService, *mocks.MockBidRepository, *mocks.MockAuctionRepository) {
+	svc, bidRepo, auctionRepo, _, _ := newTestBidServiceWithUsers()
+	return svc, bidRepo, auctionRepo
+}
+
+// newTestBidServiceWithUsers also returns the user and two-factor repos. The user
+// repo is seeded with verified bidders "user-1", "user-2", "user-3" and "user-456".
+func newTestBidServiceWithUsers() (*BidService, *mocks.MockBidRepository, *mocks.MockAuctionRepository, *mocks.MockUserRepository, *mocks.MockTwoFactorRepository) {
 	bidRepo := mocks.NewMockBidRepository()
 	auctionRepo := mocks.NewMockAuctionRepository()
-	svc := NewBidService(bidRepo, auctionRepo)
-	return svc, bidRepo, auctionRepo
+	userRepo := mocks.NewMockUserRepository()
+	twoFactorRepo := mocks.NewMockTwoFactorRepository()
+
+	verifiedAt := time.Now()
+	for _, id := range []string{"user-1", "user-2", "user-3", "user-456"} {
+		userRepo.Create(context.Background(), &domain.User{
+			ID:              id,
+			Email:           id + "@example.com",
+			EmailVerifiedAt: &verifiedAt,
+		})
+	}
+
+	twoFactor := NewTwoFactorService(twoFactorRepo, userRepo, "Test")
+	svc := NewBidService(bidRepo, auctionRepo, userRepo, mocks.NewMockAuctionBidderRepository(), mocks.NewMockProxyBidRepository(), mocks.NewMockPreBidRepository(auctionRepo), mocks.NewMockLiveLotRepository(), mocks.NewMockTransactor(), twoFactor, newTestEventService())
+	return svc, bidRepo, auctionRepo, userRepo, twoFactorRepo
 }
 
 // createActiveAuction creates an active auction in the mock repo for testing
@@ -67,6 +88,38 @@ func TestBidService_PlaceBid_Success(t *testing.T) {
 	}
 }
 
+func TestBidService_PlaceBid_PublishesEvent(t *testing.T) {
+	bidRepo := mocks.NewMockBidRepository()
+	auctionRepo := mocks.NewMockAuctionRepository()
+	userRepo := mocks.NewMockUserRepository()
+	publisher := mocks.NewMockEventPublisher()
+	verifiedAt := time.Now()
+	userRepo.Create(context.Background(), &domain.User{ID: "user-1", EmailVerifiedAt: &verifiedAt})
+	twoFactor := NewTwoFactorService(mocks.NewMockTwoFactorRepository(), userRepo, "Test")
+	events := NewEventService(mocks.NewMockAuctionEventRepository(), publisher)
+	svc := NewBidService(bidRepo, auctionRepo, userRepo, mocks.NewMockAuctionBidderRepository(), mocks.NewMockProxyBidRepository(), mocks.NewMockPreBidRepository(auctionRepo), mocks.NewMockLiveLotRepository(), mocks.NewMockTransactor(), twoFactor, events)
+	createActiveAuction(t, auctionRepo)
+
+	bid, err := svc.PlaceBid(context.Background(), "auction-123", "user-1", 150.00)
+	if err != nil {
+		t.Fatalf("PlaceBid() unexpected error: %v", err)
+	}
+
+	published := publisher.Events()
+	if len(published) != 1 {
+		t.Fatalf("published %d events, want 1", len(published))
+	}
+	if published[0].Type != domain.AuctionEventBid || published[0].Bid.ID != bid.ID {
+		t.Errorf("published event = %+v, want bid event for %s", published[0], bid.ID)
+	}
+	if published[0].Bidder != "Bidder 1" {
+		t.Errorf("published bidder = %q, want %q", published[0].Bidder, "Bidder 1")
+	}
+	if published[0].Sequence != 1 {
+		t.Errorf("published sequence = %d, want 1", published[0].Sequence)
+	}
+}
+
 func TestBidService_PlaceBid_InactiveAuction(t *testing.T) {
 	svc, _, auctionRepo := newTestBidService()
 
@@ -84,8 +137,8 @@ func TestBidService_PlaceBid_InactiveAuction(t *testing.T) {
 	auctionRepo.Create(context.Background(), auction)
 
 	_, err := svc.PlaceBid(context.Background(), "auction-pending", "user-456", 150.00)
-	if err == nil {
-		t.Error("PlaceBid() expected error for inactive auction, got nil")
+	if !errors.Is(err, domain.ErrAuctionClosed) {
+		t.Errorf("PlaceBid() error = %v, want %v", err, domain.ErrAuctionClosed)
 	}
 }
 
@@ -94,8 +147,12 @@ func TestBidService_PlaceBid_AmountTooLow(t *testing.T) {
 	createActiveAuction(t, auctionRepo) // current price is 100.00
 
 	_, err := svc.PlaceBid(context.Background(), "auction-123", "user-456", 50.00)
-	if err == nil {
-		t.Error("PlaceBid() expected error for bid lower than current price, got nil")
+	var tooLow *domain.BidTooLowError
+	if !errors.As(err, &tooLow) {
+		t.Fatalf("PlaceBid() error = %v, want a BidTooLowError", err)
+	}
+	if tooLow.CurrentPrice != 100.00 {
+		t.Errorf("BidTooLowError.CurrentPrice = %v, want 100", tooLow.CurrentPrice)
 	}
 }
 
@@ -104,8 +161,42 @@ func TestBidService_PlaceBid_AmountEqualToCurrentPrice(t *testing.T) {
 	createActiveAuction(t, auctionRepo) // current price is 100.00
 
 	_, err := svc.PlaceBid(context.Background(), "auction-123", "user-456", 100.00)
-	if err == nil {
-		t.Error("PlaceBid() expected error for bid equal to current price, got nil")
+	if !errors.Is(err, domain.ErrBidTooLow) {
+		t.Errorf("PlaceBid() error = %v, want %v", err, domain.ErrBidTooLow)
+	}
+}
+
+func TestBidService_PlaceBid_UnverifiedEmail(t *testing.T) {
+	svc, _, auctionRepo, userRepo, _ := newTestBidServiceWithUsers()
+	createActiveAuction(t, auctionRepo)
+	userRepo.Create(context.Background(), &domain.User{ID: "user-unverified", Email: "new@example.com"})
+
+	_, err := svc.PlaceBid(context.Background(), "auction-123", "user-unverified", 150.00)
+	if !errors.Is(err, domain.ErrEmailNotVerified) {
+		t.Errorf("PlaceBid() error = %v, want %v", err, domain.ErrEmailNotVerified)
+	}
+}
+
+func TestBidService_PlaceBid_TwoFactorThreshold(t *testing.T) {
+	svc, _, auctionRepo, _, twoFactorRepo := newTestBidServiceWithUsers()
+	auction := createActiveAuction(t, auctionRepo)
+	threshold := 1000.00
+	auction.TwoFactorThreshold = &threshold
+
+	// Bids up to the threshold do not need two-factor
+	if _, err := svc.PlaceBid(context.Background(), "auction-123", "user-1", 1000.00); err != nil {
+		t.Fatalf("PlaceBid() at threshold unexpected error: %v", err)
+	}
+
+	_, err := svc.PlaceBid(context.Background(), "auction-123", "user-1", 1500.00)
+	if !errors.Is(err, domain.ErrTwoFactorRequired) {
+		t.Errorf("PlaceBid() above threshold error = %v, want %v", err, domain.ErrTwoFactorRequired)
+	}
+
+	confirmedAt := time.Now()
+	twoFactorRepo.Save(context.Background(), &domain.TwoFactor{UserID: "user-2", Secret: "JBSWY3DPEHPK3PXP", ConfirmedAt: &confirmedAt})
+	if _, err := svc.PlaceBid(context.Background(), "auction-123", "user-2", 1500.00); err != nil {
+		t.Errorf("PlaceBid() above threshold with two-factor unexpected error: %v", err)
 	}
 }
 
@@ -113,8 +204,8 @@ func TestBidService_PlaceBid_NonExistentAuction(t *testing.T) {
 	svc, _, _ := newTestBidService()
 
 	_, err := svc.PlaceBid(context.Background(), "nonexistent", "user-456", 150.00)
-	if err == nil {
-		t.Error("PlaceBid() expected error for non-existent auction, got nil")
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("PlaceBid() error = %v, want %v", err, domain.ErrNotFound)
 	}
 }
 
@@ -129,24 +220,185 @@ func TestBidService_GetBids_Success(t *testing.T) {
 	svc.PlaceBid(context.Background(), "auction-123", "user-1", 150.00)
 	svc.PlaceBid(context.Background(), "auction-123", "user-2", 200.00)
 
-	bids, err := svc.GetBids(context.Background(), "auction-123")
+	page, err := svc.GetBids(context.Background(), "auction-123", domain.BidQuery{}, "")
 	if err != nil {
 		t.Fatalf("GetBids() unexpected error: %v", err)
 	}
-	if len(bids) != 2 {
-		t.Errorf("GetBids() returned %d bids, want 2", len(bids))
+	if len(page.Items) != 2 {
+		t.Fatalf("GetBids() returned %d bids, want 2", len(page.Items))
+	}
+	if page.Items[0].Amount != 200.00 {
+		t.Errorf("GetBids() first bid = %.2f, want the newest (200.00)", page.Items[0].Amount)
 	}
 }
 
 func TestBidService_GetBids_EmptyAuction(t *testing.T) {
 	svc, _, _ := newTestBidService()
 
-	bids, err := svc.GetBids(context.Background(), "auction-no-bids")
+	page, err := svc.GetBids(context.Background(), "auction-no-bids", domain.BidQuery{}, "")
 	if err != nil {
 		t.Fatalf("GetBids() unexpected error: %v", err)
 	}
-	if len(bids) != 0 {
-		t.Errorf("GetBids() returned %d bids, want 0", len(bids))
+	if len(page.Items) != 0 {
+		t.Errorf("GetBids() returned %d bids, want 0", len(page.Items))
+	}
+}
+
+func TestBidService_GetBids_Pages(t *testing.T) {
+	svc, _, auctionRepo := newTestBidService()
+	createActiveAuction(t, auctionRepo)
+	for _, amount := range []float64{150, 200, 250} {
+		svc.PlaceBid(context.Background(), "auction-123", "user-1", amount)
+	}
+
+	var amounts []float64
+	cursor := ""
+	for {
+		page, err := svc.GetBids(context.Background(), "auction-123", domain.BidQuery{Limit: 2}, cursor)
+		if err != nil {
+			t.Fatalf("GetBids() unexpected error: %v", err)
+		}
+		for _, bid := range page.Items {
+			amounts = append(amounts, bid.Amount)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if len(amounts) != 3 || amounts[0] != 250 || amounts[2] != 150 {
+		t.Errorf("GetBids() pages = %v, want [250 200 150]", amounts)
+	}
+}
+
+// ============================================================================
+// GetPublicBids
+// ============================================================================
+
+func TestBidService_GetPublicBids_Pseudonyms(t *testing.T) {
+	svc, _, auctionRepo := newTestBidService()
+	createActiveAuction(t, auctionRepo)
+	ctx := context.Background()
+
+	svc.PlaceBid(ctx, "auction-123", "user-2", 150.00)
+	svc.PlaceBid(ctx, "auction-123", "user-1", 200.00)
+	svc.PlaceBid(ctx, "auction-123", "user-2", 250.00)
+
+	page, err := svc.GetPublicBids(ctx, "auction-123", "user-1", domain.BidQuery{}, "")
+	if err != nil {
+		t.Fatalf("GetPublicBids() unexpected error: %v", err)
+	}
+	bids := page.Items
+	if len(bids) != 3 {
+		t.Fatalf("GetPublicBids() returned %d bids, want 3", len(bids))
+	}
+
+	expected := map[float64]struct {
+		bidder string
+		isMine bool
+	}{
+		150.00: {"Bidder 1", false},
+		200.00: {"Bidder 2", true},
+		250.00: {"Bidder 1", false},
+	}
+	for _, bid := range bids {
+		want := expected[bid.Amount]
+		if bid.Bidder != want.bidder {
+			t.Errorf("bid %.2f bidder = %q, want %q", bid.Amount, bid.Bidder, want.bidder)
+		}
+		if bid.IsMine != want.isMine {
+			t.Errorf("bid %.2f IsMine = %v, want %v", bid.Amount, bid.IsMine, want.isMine)
+		}
+	}
+}
+
+// ============================================================================
+// SetProxyBid
+// ============================================================================
+
+func TestBidService_SetProxyBid_OpeningBid(t *testing.T) {
+	svc, _, auctionRepo := newTestBidService()
+	createActiveAuction(t, auctionRepo) // current price is 100.00
+
+	proxy, err := svc.SetProxyBid(context.Background(), "auction-123", "user-1", 300.00)
+	if err != nil {
+		t.Fatalf("SetProxyBid() unexpected error: %v", err)
+	}
+	if proxy.MaxAmount != 300.00 {
+		t.Errorf("SetProxyBid() max = %f, want %f", proxy.MaxAmount, 300.00)
+	}
+
+	winner, _ := svc.GetWinningBid(context.Background(), "auction-123")
+	if winner.UserID != "user-1" || winner.Amount != 100.00+domain.ProxyBidIncrement {
+		t.Errorf("Winning bid = %s at %f, want user-1 at %f", winner.UserID, winner.Amount, 100.00+domain.ProxyBidIncrement)
+	}
+}
+
+func TestBidService_SetProxyBid_OutbidsManualBid(t *testing.T) {
+	svc, _, auctionRepo := newTestBidService()
+	createActiveAuction(t, auctionRepo)
+	ctx := context.Background()
+
+	svc.SetProxyBid(ctx, "auction-123", "user-1", 300.00)
+	if _, err := svc.PlaceBid(ctx, "auction-123", "user-2", 150.00); err != nil {
+		t.Fatalf("PlaceBid() unexpected error: %v", err)
+	}
+
+	winner, _ := svc.GetWinningBid(ctx, "auction-123")
+	if winner.UserID != "user-1" || winner.Amount != 151.00 {
+		t.Errorf("Winning bid = %s at %f, want user-1 at %f", winner.UserID, winner.Amount, 151.00)
+	}
+
+	// A manual bid above the maximum wins outright
+	svc.PlaceBid(ctx, "auction-123", "user-2", 350.00)
+	winner, _ = svc.GetWinningBid(ctx, "auction-123")
+	if winner.UserID != "user-2" || winner.Amount != 350.00 {
+		t.Errorf("Winning bid = %s at %f, want user-2 at %f", winner.UserID, winner.Amount, 350.00)
+	}
+}
+
+func TestBidService_SetProxyBid_CompetingProxies(t *testing.T) {
+	svc, _, auctionRepo := newTestBidService()
+	createActiveAuction(t, auctionRepo)
+	ctx := context.Background()
+
+	svc.SetProxyBid(ctx, "auction-123", "user-1", 200.00)
+	if _, err := svc.SetProxyBid(ctx, "auction-123", "user-2", 250.00); err != nil {
+		t.Fatalf("SetProxyBid() unexpected error: %v", err)
+	}
+
+	winner, _ := svc.GetWinningBid(ctx, "auction-123")
+	if winner.UserID != "user-2" || winner.Amount != 201.00 {
+		t.Errorf("Winning bid = %s at %f, want user-2 at %f", winner.UserID, winner.Amount, 201.00)
+	}
+
+	// An equal maximum set later loses to the earlier proxy
+	svc.SetProxyBid(ctx, "auction-123", "user-3", 250.00)
+	winner, _ = svc.GetWinningBid(ctx, "auction-123")
+	if winner.UserID != "user-2" || winner.Amount != 250.00 {
+		t.Errorf("Winning bid = %s at %f, want user-2 at %f", winner.UserID, winner.Amount, 250.00)
+	}
+}
+
+func TestBidService_SetProxyBid_MaxTooLow(t *testing.T) {
+	svc, _, auctionRepo := newTestBidService()
+	createActiveAuction(t, auctionRepo) // current price is 100.00
+
+	_, err := svc.SetProxyBid(context.Background(), "auction-123", "user-1", 100.00)
+	if !errors.Is(err, domain.ErrBidTooLow) {
+		t.Errorf("SetProxyBid() error = %v, want %v", err, domain.ErrBidTooLow)
+	}
+}
+
+func TestBidService_SetProxyBid_TwoFactorThreshold(t *testing.T) {
+	svc, _, auctionRepo := newTestBidService()
+	auction := createActiveAuction(t, auctionRepo)
+	threshold := 500.00
+	auction.TwoFactorThreshold = &threshold
+
+	_, err := svc.SetProxyBid(context.Background(), "auction-123", "user-1", 600.00)
+	if !errors.Is(err, domain.ErrTwoFactorRequired) {
+		t.Errorf("SetProxyBid() error = %v, want %v", err, domain.ErrTwoFactorRequired)
 	}
 }
 
@@ -182,3 +434,71 @@ func TestBidService_GetWinningBid_NoBids(t *testing.T) {
 		t.Error("GetWinningBid() expected error when no bids exist, got nil")
 	}
 }
+
+// ============================================================================
+// Increment tables and soft close
+// ============================================================================
+
+func TestBidService_PlaceBid_EnforcesIncrementTable(t *testing.T) {
+	svc, _, auctionRepo := newTestBidService()
+	auction := createActiveAuction(t, auctionRepo)
+	auction.IncrementTable = []domain.IncrementStep{{From: 0, Increment: 1}, {From: 100, Increment: 5}}
+	auctionRepo.Update(context.Background(), auction)
+
+	_, err := svc.PlaceBid(context.Background(), "auction-123", "user-1", 104)
+	var tooLow *domain.BidTooLowError
+	if !errors.As(err, &tooLow) || tooLow.MinimumBid != 105 {
+		t.Fatalf("PlaceBid() error = %v, want a bid too low error with minimum 105", err)
+	}
+	if _, err := svc.PlaceBid(context.Background(), "auction-123", "user-1", 105); err != nil {
+		t.Errorf("PlaceBid() of the minimum bid unexpected error: %v", err)
+	}
+}
+
+func TestBidService_PlaceBid_SoftCloseExtends(t *testing.T) {
+	bidRepo := mocks.NewMockBidRepository()
+	auctionRepo := mocks.NewMockAuctionRepository()
+	userRepo := mocks.NewMockUserRepository()
+	publisher := mocks.NewMockEventPublisher()
+	verifiedAt := time.Now()
+	userRepo.Create(context.Background(), &domain.User{ID: "user-1", EmailVerifiedAt: &verifiedAt})
+	twoFactor := NewTwoFactorService(mocks.NewMockTwoFactorRepository(), userRepo, "Test")
+	events := NewEventService(mocks.NewMockAuctionEventRepository(), publisher)
+	svc := NewBidService(bidRepo, auctionRepo, userRepo, mocks.NewMockAuctionBidderRepository(), mocks.NewMockProxyBidRepository(), mocks.NewMockPreBidRepository(auctionRepo), mocks.NewMockLiveLotRepository(), mocks.NewMockTransactor(), twoFactor, events)
+	auction := createActiveAuction(t, auctionRepo)
+	auction.EndTime = time.Now().Add(30 * time.Second)
+	auction.SoftCloseSeconds = 120
+	auctionRepo.Update(context.Background(), auction)
+
+	if _, err := svc.PlaceBid(context.Background(), "auction-123", "user-1", 150); err != nil {
+		t.Fatalf("PlaceBid() unexpected error: %v", err)
+	}
+
+	updated, _ := auctionRepo.GetByID(context.Background(), "auction-123")
+	if remaining := time.Until(updated.EndTime); remaining < 110*time.Second {
+		t.Errorf("auction ends in %v, want it extended to about 2 minutes", remaining)
+	}
+	published := publisher.Events()
+	if len(published) != 2 || published[1].Type != domain.AuctionEventExtended {
+		t.Fatalf("published %d events, want a bid event then an extended event", len(published))
+	}
+	if !published[1].Auction.EndTime.Equal(updated.EndTime) {
+		t.Errorf("extended event end time = %v, want %v", published[1].Auction.EndTime, updated.EndTime)
+	}
+}
+
+func TestBidService_SetProxyBid_UsesIncrementTable(t *testing.T) {
+	svc, _, auctionRepo := newTestBidService()
+	auction := createActiveAuction(t, auctionRepo)
+	auction.IncrementTable = []domain.IncrementStep{{From: 0, Increment: 10}}
+	auctionRepo.Update(context.Background(), auction)
+
+	if _, err := svc.SetProxyBid(context.Background(), "auction-123", "user-1", 300); err != nil {
+		t.Fatalf("SetProxyBid() unexpected error: %v", err)
+	}
+
+	winner, _ := svc.GetWinningBid(context.Background(), "auction-123")
+	if winner.Amount != 110 {
+		t.Errorf("Winning bid = %f, want the starting price plus the table's increment", winner.Amount)
+	}
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// maxLotIntervalSeconds bounds the time between the closes of two lots
+const maxLotIntervalSeconds = 3600
+
+// CatalogService manages catalogs, which group auctions as lots of a sale
+// closing one after another
+type CatalogService struct {
+	catalogRepo    domain.CatalogRepository
+	auctionService *AuctionService
+	auctionRepo    domain.AuctionRepository
+	events         *EventService
+}
+
+func NewCatalogService(catalogRepo domain.CatalogRepository, auctionService *AuctionService, auctionRepo domain.AuctionRepository, events *EventService) *CatalogService {
+	return &CatalogService{
+		catalogRepo:    catalogRepo,
+		auctionService: auctionService,
+		auctionRepo:    auctionRepo,
+		events:         events,
+	}
+}
+
+// CatalogInput holds the settings of a new catalog
+type CatalogInput struct {
+	Title       string
+	Description string
+	Terms       string
+	// StartTime opens bidding on every lot
+	StartTime time.Time
+	// FirstLotEndTime closes lot 1; every later lot closes
+	// LotIntervalSeconds after the one before it
+	FirstLotEndTime    time.Time
+	LotIntervalSeconds int
+}
+
+// CatalogDetail is a catalog with its lots
+type CatalogDetail struct {
+	*domain.Catalog
+	Lots []*domain.CatalogLot
+}
+
+func (s *CatalogService) CreateCatalog(ctx context.Context, ownerID string, input CatalogInput) (*domain.Catalog, error) {
+	input.Title = strings.TrimSpace(input.Title)
+	switch {
+	case input.Title == "":
+		return nil, fmt.Errorf("%w: title is required", domain.ErrValidation)
+	case !input.FirstLotEndTime.After(input.StartTime):
+		return nil, fmt.Errorf("%w: the first lot must close after the start time", domain.ErrValidation)
+	case !input.FirstLotEndTime.After(time.Now()):
+		return nil, fmt.Errorf("%w: the first lot must close in the future", domain.ErrValidation)
+	case input.LotIntervalSeconds < 1 || input.LotIntervalSeconds > maxLotIntervalSeconds:
+		return nil, fmt.Errorf("%w: lot interval must be between 1 and %d seconds", domain.ErrValidation, maxLotIntervalSeconds)
+	}
+
+	catalog := &domain.Catalog{
+		ID:                 uuid.New().String(),
+		OwnerID:            ownerID,
+		Title:              input.Title,
+		Description:        input.Description,
+		Terms:              input.Terms,
+		StartTime:          input.StartTime,
+		FirstLotEndTime:    input.FirstLotEndTime,
+		LotIntervalSeconds: input.LotIntervalSeconds,
+		CreatedAt:          time.Now(),
+	}
+	if err := s.catalogRepo.Create(ctx, catalog); err != nil {
+		return nil, fmt.Errorf("failed to create catalog: %w", err)
+	}
+	return catalog, nil
+}
+
+// GetCatalog returns a catalog with its lots and their auctions as they are
+// now, live prices included
+func (s *CatalogService) GetCatalog(ctx context.Context, id string) (*CatalogDetail, error) {
+	catalog, err := s.catalogRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog: %w", err)
+	}
+	lots, err := s.catalogRepo.ListLots(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lots: %w", err)
+	}
+	return &CatalogDetail{Catalog: catalog, Lots: lots}, nil
+}
+
+// ListCatalogs returns a page of the catalogs matching query, newest first,
+// continuing after cursor if it is set. query.Limit is the page size, or zero
+// for the default.
+func (s *CatalogService) ListCatalogs(ctx context.Context, query domain.CatalogQuery, cursor string) (*Page[*domain.Catalog], error) {
+	if err := validateID("owner_id", query.OwnerID); err != nil {
+		return nil, err
+	}
+	limit, err := pageLimit(query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if query.After, err = domain.DecodeCursor(cursor, domain.SortNewest); err != nil {
+		return nil, err
+	}
+
+	query.Limit = limit + 1
+	catalogs, err := s.catalogRepo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list catalogs: %w", err)
+	}
+	return newPage(catalogs, limit, func(c *domain.Catalog) *domain.Cursor {
+		return domain.NewestCursor(c.CreatedAt, c.ID)
+	}), nil
+}
+
+// AddLot makes a pending or scheduled auction of the catalog's owner the
+// catalog's next lot, moving its start time to the catalog's and its end
+// time to the lot's scheduled close. Lots can only be added before the sale
+// starts.
+func (s *CatalogService) AddLot(ctx context.Context, catalogID, actorID, auctionID string) (*domain.CatalogLot, error) {
+	catalog, err := s.editableCatalog(ctx, catalogID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	if auction.Status != domain.AuctionStatusPending && auction.Status != domain.AuctionStatusScheduled {
+		return nil, fmt.Errorf("%w: it is %s; only auctions that have not started can become lots", domain.ErrAuctionNotEditable, auction.Status)
+	}
+	existing, err := s.catalogRepo.GetLotByAuction(ctx, auctionID)
+	if err == nil {
+		return nil, fmt.Errorf("%w: the auction is already lot %d of catalog %s", domain.ErrValidation, existing.Number, existing.CatalogID)
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get lot: %w", err)
+	}
+
+	lot := &domain.CatalogLot{CatalogID: catalog.ID, AuctionID: auctionID, Number: catalog.LotCount + 1}
+	if lot.Auction, err = s.schedule(ctx, catalog, lot, actorID); err != nil {
+		return nil, err
+	}
+	if err := s.catalogRepo.AddLot(ctx, lot); err != nil {
+		return nil, fmt.Errorf("failed to add lot: %w", err)
+	}
+	return lot, nil
+}
+
+// RemoveLot takes an auction out of a catalog before the sale starts. The
+// auction keeps its times; every later lot moves up a number and into that
+// number's closing slot.
+func (s *CatalogService) RemoveLot(ctx context.Context, catalogID, actorID, auctionID string) error {
+	catalog, err := s.editableCatalog(ctx, catalogID, actorID)
+	if err != nil {
+		return err
+	}
+	if err := s.catalogRepo.RemoveLot(ctx, catalogID, auctionID); err != nil {
+		return fmt.Errorf("failed to remove lot: %w", err)
+	}
+
+	lots, err := s.catalogRepo.ListLots(ctx, catalogID)
+	if err != nil {
+		return fmt.Errorf("failed to list lots: %w", err)
+	}
+	for _, lot := range lots {
+		if !lot.Auction.EndTime.Equal(catalog.LotEndTime(lot.Number)) {
+			if _, err := s.schedule(ctx, catalog, lot, actorID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ScheduleCatalog schedules every pending lot of a catalog to start at the
+// catalog's start time
+func (s *CatalogService) ScheduleCatalog(ctx context.Context, catalogID, actorID string) (*CatalogDetail, error) {
+	catalog, err := s.ownedCatalog(ctx, catalogID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	lots, err := s.catalogRepo.ListLots(ctx, catalogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lots: %w", err)
+	}
+
+	for _, lot := range lots {
+		if lot.Auction.Status != domain.AuctionStatusPending {
+			continue
+		}
+		if lot.Auction, err = s.auctionService.ScheduleAuction(ctx, lot.AuctionID, lot.Auction.Version, actorID); err != nil {
+			return nil, err
+		}
+	}
+	return &CatalogDetail{Catalog: catalog, Lots: lots}, nil
+}
+
+// AlignClosing keeps the lots of every live catalog closing in order, as
+// domain.AlignLotEnds describes, and announces every lot it pushes back as
+// extended. It returns how many lots were pushed back. A lot changed
+// meanwhile, such as by a bid, is left for the next call.
+func (s *CatalogService) AlignClosing(ctx context.Context, now time.Time) (int, error) {
+	catalogs, err := s.catalogRepo.ListLive(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list live catalogs: %w", err)
+	}
+
+	moved := 0
+	for _, catalog := range catalogs {
+		lots, err := s.catalogRepo.ListLots(ctx, catalog.ID)
+		if err != nil {
+			return moved, fmt.Errorf("failed to list lots: %w", err)
+		}
+		for _, auction := range domain.AlignLotEnds(lots, catalog.LotInterval(), now) {
+			err := s.auctionRepo.Update(ctx, auction)
+			if errors.Is(err, domain.ErrConflict) {
+				continue
+			}
+			if err != nil {
+				return moved, fmt.Errorf("failed to update auction: %w", err)
+			}
+			moved++
+
+			snapshot := *auction
+			err = s.events.Record(ctx, &domain.AuctionEvent{
+				AuctionID: auction.ID,
+				Type:      domain.AuctionEventExtended,
+				Auction:   &snapshot,
+				CreatedAt: now,
+			})
+			if err != nil {
+				return moved, err
+			}
+		}
+	}
+	return moved, nil
+}
+
+// schedule moves the auction of a lot to the catalog's start time and the
+// lot's closing slot, as an edit of its seller
+func (s *CatalogService) schedule(ctx context.Context, catalog *domain.Catalog, lot *domain.CatalogLot, actorID string) (*domain.Auction, error) {
+	start, end := catalog.StartTime, catalog.LotEndTime(lot.Number)
+	return s.auctionService.UpdateAuction(ctx, lot.AuctionID, AnyVersion, actorID, AuctionUpdate{StartTime: &start, EndTime: &end})
+}
+
+// ownedCatalog returns a catalog of actorID
+func (s *CatalogService) ownedCatalog(ctx context.Context, id, actorID string) (*domain.Catalog, error) {
+	catalog, err := s.catalogRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog: %w", err)
+	}
+	if catalog.OwnerID != actorID {
+		return nil, domain.ErrNotCatalogOwner
+	}
+	return catalog, nil
+}
+
+// editableCatalog returns a catalog of actorID whose sale has not started
+func (s *CatalogService) editableCatalog(ctx context.Context, id, actorID string) (*domain.Catalog, error) {
+	catalog, err := s.ownedCatalog(ctx, id, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if catalog.HasStarted(time.Now()) {
+		return nil, fmt.Errorf("%w: its sale has started", domain.ErrCatalogNotEditable)
+	}
+	return catalog, nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+// newTestCatalogService returns a catalog of seller-1 whose sale starts in
+// an hour, with lots closing a minute apart from a day later
+func newTestCatalogService(t *testing.T) (*CatalogService, *AuctionService, *mocks.MockEventPublisher, *domain.Catalog) {
+	t.Helper()
+	publisher := mocks.NewMockEventPublisher()
+	events := NewEventService(mocks.NewMockAuctionEventRepository(), publisher)
+	auctionRepo := mocks.NewMockAuctionRepository()
+	auctionService := NewAuctionService(auctionRepo, mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(), newTestProductRepo(), mocks.NewMockCategoryRepository(), mocks.NewMockBidRepository(), mocks.NewMockTransactor(), events)
+	svc := NewCatalogService(mocks.NewMockCatalogRepository(auctionRepo), auctionService, auctionRepo, events)
+
+	catalog, err := svc.CreateCatalog(context.Background(), "seller-1", CatalogInput{
+		Title:              "Spring Sale",
+		Terms:              "Sold as seen",
+		StartTime:          time.Now().Add(time.Hour),
+		FirstLotEndTime:    time.Now().Add(24 * time.Hour),
+		LotIntervalSeconds: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateCatalog() unexpected error: %v", err)
+	}
+	return svc, auctionService, publisher, catalog
+}
+
+// addTestLots adds an auction of each product to the catalog
+func addTestLots(t *testing.T, svc *CatalogService, auctionService *AuctionService, catalog *domain.Catalog, productIDs ...string) []*domain.CatalogLot {
+	t.Helper()
+	ctx := context.Background()
+	var lots []*domain.CatalogLot
+	for _, productID := range productIDs {
+		auction, err := auctionService.CreateAuction(ctx, productID, time.Now(), time.Now().Add(time.Hour), 10)
+		if err != nil {
+			t.Fatalf("CreateAuction() unexpected error: %v", err)
+		}
+		lot, err := svc.AddLot(ctx, catalog.ID, "seller-1", auction.ID)
+		if err != nil {
+			t.Fatalf("AddLot() unexpected error: %v", err)
+		}
+		lots = append(lots, lot)
+	}
+	return lots
+}
+
+func TestCatalogService_CreateCatalog_Rejected(t *testing.T) {
+	svc, _, _, _ := newTestCatalogService(t)
+	start := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		input CatalogInput
+	}{
+		{"no title", CatalogInput{Title: " ", StartTime: start, FirstLotEndTime: start.Add(time.Hour), LotIntervalSeconds: 60}},
+		{"closes before start", CatalogInput{Title: "Sale", StartTime: start, FirstLotEndTime: start.Add(-time.Minute), LotIntervalSeconds: 60}},
+		{"closes in the past", CatalogInput{Title: "Sale", StartTime: start.Add(-3 * time.Hour), FirstLotEndTime: start.Add(-2 * time.Hour), LotIntervalSeconds: 60}},
+		{"no interval", CatalogInput{Title: "Sale", StartTime: start, FirstLotEndTime: start.Add(time.Hour)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.CreateCatalog(context.Background(), "seller-1", tt.input); !errors.Is(err, domain.ErrValidation) {
+				t.Errorf("CreateCatalog() error = %v, want %v", err, domain.ErrValidation)
+			}
+		})
+	}
+}
+
+func TestCatalogService_AddLot_StaggersCloses(t *testing.T) {
+	svc, auctionService, _, catalog := newTestCatalogService(t)
+	lots := addTestLots(t, svc, auctionService, catalog, "product-1", "product-2", "product-3")
+
+	detail, err := svc.GetCatalog(context.Background(), catalog.ID)
+	if err != nil {
+		t.Fatalf("GetCatalog() unexpected error: %v", err)
+	}
+	if detail.LotCount != 3 || len(detail.Lots) != 3 {
+		t.Fatalf("GetCatalog() = %d lots, want 3", len(detail.Lots))
+	}
+	for i, lot := range detail.Lots {
+		if lot.Number != i+1 || lot.AuctionID != lots[i].AuctionID {
+			t.Errorf("lot %d = %s, want %s", i+1, lot.AuctionID, lots[i].AuctionID)
+		}
+		if !lot.Auction.StartTime.Equal(catalog.StartTime) || !lot.Auction.EndTime.Equal(catalog.FirstLotEndTime.Add(time.Duration(i)*time.Minute)) {
+			t.Errorf("lot %d runs %v to %v, want the catalog start to its slot", i+1, lot.Auction.StartTime, lot.Auction.EndTime)
+		}
+	}
+}
+
+func TestCatalogService_AddLot_Rejected(t *testing.T) {
+	svc, auctionService, _, catalog := newTestCatalogService(t)
+	ctx := context.Background()
+	lot := addTestLots(t, svc, auctionService, catalog, "product-1")[0]
+
+	if _, err := svc.AddLot(ctx, catalog.ID, "seller-1", lot.AuctionID); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("AddLot() twice error = %v, want %v", err, domain.ErrValidation)
+	}
+
+	auction, _ := auctionService.CreateAuction(ctx, "product-2", time.Now(), time.Now().Add(time.Hour), 10)
+	if _, err := svc.AddLot(ctx, catalog.ID, "someone-else", auction.ID); !errors.Is(err, domain.ErrNotCatalogOwner) {
+		t.Errorf("AddLot() by another user error = %v, want %v", err, domain.ErrNotCatalogOwner)
+	}
+
+	auctionService.StartAuction(ctx, auction.ID, AnyVersion, "seller-1")
+	if _, err := svc.AddLot(ctx, catalog.ID, "seller-1", auction.ID); !errors.Is(err, domain.ErrAuctionNotEditable) {
+		t.Errorf("AddLot() of a running auction error = %v, want %v", err, domain.ErrAuctionNotEditable)
+	}
+}
+
+func TestCatalogService_RemoveLot_MovesLaterLotsUp(t *testing.T) {
+	svc, auctionService, _, catalog := newTestCatalogService(t)
+	ctx := context.Background()
+	lots := addTestLots(t, svc, auctionService, catalog, "product-1", "product-2", "product-3")
+
+	if err := svc.RemoveLot(ctx, catalog.ID, "seller-1", lots[0].AuctionID); err != nil {
+		t.Fatalf("RemoveLot() unexpected error: %v", err)
+	}
+
+	detail, _ := svc.GetCatalog(ctx, catalog.ID)
+	if len(detail.Lots) != 2 || detail.Lots[0].AuctionID != lots[1].AuctionID || detail.Lots[0].Number != 1 {
+		t.Fatalf("GetCatalog() lots = %v, want the second lot as lot 1", detail.Lots)
+	}
+	for i, lot := range detail.Lots {
+		if want := catalog.FirstLotEndTime.Add(time.Duration(i) * time.Minute); !lot.Auction.EndTime.Equal(want) {
+			t.Errorf("lot %d closes at %v, want %v", lot.Number, lot.Auction.EndTime, want)
+		}
+	}
+}
+
+func TestCatalogService_ScheduleCatalog(t *testing.T) {
+	svc, auctionService, _, catalog := newTestCatalogService(t)
+	addTestLots(t, svc, auctionService, catalog, "product-1", "product-2")
+
+	if _, err := svc.ScheduleCatalog(context.Background(), catalog.ID, "someone-else"); !errors.Is(err, domain.ErrNotCatalogOwner) {
+		t.Errorf("ScheduleCatalog() by another user error = %v, want %v", err, domain.ErrNotCatalogOwner)
+	}
+	detail, err := svc.ScheduleCatalog(context.Background(), catalog.ID, "seller-1")
+	if err != nil {
+		t.Fatalf("ScheduleCatalog() unexpected error: %v", err)
+	}
+	for _, lot := range detail.Lots {
+		if lot.Auction.Status != domain.AuctionStatusScheduled {
+			t.Errorf("lot %d status = %s, want scheduled", lot.Number, lot.Auction.Status)
+		}
+	}
+}
+
+func TestCatalogService_AlignClosing(t *testing.T) {
+	svc, auctionService, publisher, catalog := newTestCatalogService(t)
+	ctx := context.Background()
+	lots := addTestLots(t, svc, auctionService, catalog, "product-1", "product-2", "product-3")
+	for _, lot := range lots {
+		auctionService.StartAuction(ctx, lot.AuctionID, AnyVersion, "seller-1")
+	}
+
+	// Soft close extends lot 1 past lot 2's slot
+	first, _ := auctionService.GetAuction(ctx, lots[0].AuctionID)
+	first.EndTime = catalog.FirstLotEndTime.Add(90 * time.Second)
+	sent := len(publisher.Events())
+
+	moved, err := svc.AlignClosing(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("AlignClosing() unexpected error: %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("AlignClosing() moved %d lots, want 2", moved)
+	}
+	second, _ := auctionService.GetAuction(ctx, lots[1].AuctionID)
+	third, _ := auctionService.GetAuction(ctx, lots[2].AuctionID)
+	if !second.EndTime.Equal(catalog.FirstLotEndTime.Add(150*time.Second)) || !third.EndTime.Equal(catalog.FirstLotEndTime.Add(210*time.Second)) {
+		t.Errorf("lots 2 and 3 close at %v and %v, want a minute apart after lot 1", second.EndTime, third.EndTime)
+	}
+	events := publisher.Events()[sent:]
+	if len(events) != 2 || events[0].Type != domain.AuctionEventExtended || events[0].AuctionID != second.ID {
+		t.Errorf("AlignClosing() published %v, want extended events for lots 2 and 3", events)
+	}
+}
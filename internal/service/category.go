@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// maxCategoryNameLength matches the categories.name column
+const maxCategoryNameLength = 100
+
+// maxCategoryDepth guards walks up the category tree against cycles left by
+// concurrent moves
+const maxCategoryDepth = 32
+
+// CategoryInput holds the settings of a category to create or replace
+type CategoryInput struct {
+	// ParentID is empty for a top-level category
+	ParentID   string
+	Name       string
+	Attributes []domain.AttributeDefinition
+	Defaults   domain.AuctionDefaults
+	// Fees override the fee schedules of the category's auctions
+	Fees domain.FeeSchedules
+}
+
+// CategorySchema is what a category's products inherit along its path from
+// the top of the tree: their attribute definitions and auction defaults
+type CategorySchema struct {
+	// Path runs from the top-level category down to the category itself
+	Path       []*domain.Category
+	Attributes []domain.AttributeDefinition
+	Defaults   domain.AuctionDefaults
+}
+
+type CategoryService struct {
+	categoryRepo domain.CategoryRepository
+	productRepo  domain.ProductRepository
+}
+
+func NewCategoryService(categoryRepo domain.CategoryRepository, productRepo domain.ProductRepository) *CategoryService {
+	return &CategoryService{categoryRepo: categoryRepo, productRepo: productRepo}
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, input CategoryInput) (*domain.Category, error) {
+	category := &domain.Category{
+		ID:        uuid.New().String(),
+		CreatedAt: time.Now(),
+	}
+	if err := s.apply(ctx, category, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryRepo.Create(ctx, category); err != nil {
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+	return category, nil
+}
+
+func (s *CategoryService) GetCategory(ctx context.Context, id string) (*domain.Category, error) {
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	return category, nil
+}
+
+// GetSchema returns the attribute definitions and auction defaults that the
+// category's products inherit
+func (s *CategoryService) GetSchema(ctx context.Context, id string) (*CategorySchema, error) {
+	path, err := categoryPath(ctx, s.categoryRepo, id)
+	if err != nil {
+		return nil, err
+	}
+	return &CategorySchema{
+		Path:       path,
+		Attributes: domain.CategoryAttributes(path),
+		Defaults:   domain.CategoryDefaults(path),
+	}, nil
+}
+
+// ListCategories returns every category, by name. Clients build the tree
+// from their parent IDs.
+func (s *CategoryService) ListCategories(ctx context.Context) ([]*domain.Category, error) {
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	return categories, nil
+}
+
+// UpdateCategory replaces the settings of a category, which may move it
+// under another parent. Products already created keep their attributes, and
+// auctions already created keep their settings.
+func (s *CategoryService) UpdateCategory(ctx context.Context, id string, input CategoryInput) (*domain.Category, error) {
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	if err := s.apply(ctx, category, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryRepo.Update(ctx, category); err != nil {
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+	return category, nil
+}
+
+// DeleteCategory deletes a category without subcategories or products,
+// counting archived and deleted ones
+func (s *CategoryService) DeleteCategory(ctx context.Context, id string) error {
+	if _, err := s.categoryRepo.GetByID(ctx, id); err != nil {
+		return fmt.Errorf("failed to get category: %w", err)
+	}
+	products, err := s.productRepo.List(ctx, domain.ProductQuery{
+		CategoryIDs:     []string{id},
+		IncludeArchived: true,
+		IncludeDeleted:  true,
+		Limit:           1,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list products: %w", err)
+	}
+	if len(products) > 0 {
+		return domain.ErrCategoryInUse
+	}
+
+	if err := s.categoryRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	return nil
+}
+
+// apply validates input and sets it on category
+func (s *CategoryService) apply(ctx context.Context, category *domain.Category, input CategoryInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	switch {
+	case input.Name == "":
+		return fmt.Errorf("%w: name is required", domain.ErrValidation)
+	case len(input.Name) > maxCategoryNameLength:
+		return fmt.Errorf("%w: name must be at most %d characters", domain.ErrValidation, maxCategoryNameLength)
+	}
+	for i := range input.Attributes {
+		definition := &input.Attributes[i]
+		if err := definition.Validate(); err != nil {
+			return err
+		}
+		if slices.ContainsFunc(input.Attributes[:i], func(d domain.AttributeDefinition) bool { return d.Name == definition.Name }) {
+			return fmt.Errorf("%w: attribute %q is defined twice", domain.ErrValidation, definition.Name)
+		}
+	}
+	if err := domain.ValidateIncrementTable(input.Defaults.IncrementTable); err != nil {
+		return err
+	}
+	if input.Defaults.SoftCloseSeconds != nil && *input.Defaults.SoftCloseSeconds < 0 {
+		return fmt.Errorf("%w: soft close window must be non-negative", domain.ErrValidation)
+	}
+	for _, schedule := range []*domain.FeeSchedule{input.Fees.BuyerPremium, input.Fees.SellerCommission} {
+		if schedule == nil {
+			continue
+		}
+		if err := schedule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if input.ParentID != "" {
+		if err := validateID("parent_id", input.ParentID); err != nil {
+			return err
+		}
+		path, err := categoryPath(ctx, s.categoryRepo, input.ParentID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: parent category %s does not exist", domain.ErrValidation, input.ParentID)
+		}
+		if err != nil {
+			return err
+		}
+		// A category cannot move below itself
+		if slices.ContainsFunc(path, func(c *domain.Category) bool { return c.ID == category.ID }) {
+			return fmt.Errorf("%w: a category cannot be its own ancestor", domain.ErrValidation)
+		}
+	}
+
+	category.ParentID = input.ParentID
+	category.Name = input.Name
+	category.Attributes = input.Attributes
+	category.Defaults = input.Defaults
+	category.Fees = input.Fees
+	return nil
+}
+
+// categoryPath returns the categories from the top of the tree down to the
+// category with id
+func categoryPath(ctx context.Context, repo domain.CategoryRepository, id string) ([]*domain.Category, error) {
+	var path []*domain.Category
+	for next := id; next != ""; {
+		if len(path) == maxCategoryDepth {
+			return nil, fmt.Errorf("category %s is nested too deeply", id)
+		}
+		category, err := repo.GetByID(ctx, next)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get category: %w", err)
+		}
+		path = append(path, category)
+		next = category.ParentID
+	}
+	slices.Reverse(path)
+	return path, nil
+}
+
+// categorySubtrees returns the given categories and all their descendants,
+// so filters on a category also match the products of its subcategories
+func categorySubtrees(ctx context.Context, repo domain.CategoryRepository, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	for _, id := range ids {
+		if err := validateID("category_id", id); err != nil {
+			return nil, err
+		}
+	}
+	categories, err := repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	children := make(map[string][]string)
+	for _, category := range categories {
+		children[category.ParentID] = append(children[category.ParentID], category.ID)
+	}
+
+	seen := make(map[string]bool)
+	queue := slices.Clone(ids)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		queue = append(queue, children[id]...)
+	}
+	subtrees := make([]string, 0, len(seen))
+	for id := range seen {
+		subtrees = append(subtrees, id)
+	}
+	slices.Sort(subtrees)
+	return subtrees, nil
+}
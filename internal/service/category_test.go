@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+func newTestCategoryService() (*CategoryService, *mocks.MockCategoryRepository, *mocks.MockProductRepository) {
+	categoryRepo := mocks.NewMockCategoryRepository()
+	productRepo := mocks.NewMockProductRepository()
+	return NewCategoryService(categoryRepo, productRepo), categoryRepo, productRepo
+}
+
+// newTestCategoryTree creates Electronics with the subcategory Laptops
+func newTestCategoryTree(t *testing.T, svc *CategoryService) (electronics, laptops *domain.Category) {
+	t.Helper()
+	window := 120
+	electronics, err := svc.CreateCategory(context.Background(), CategoryInput{
+		Name:       "Electronics",
+		Attributes: []domain.AttributeDefinition{{Name: "brand", Type: domain.AttributeTypeText, Required: true}},
+		Defaults: domain.AuctionDefaults{
+			IncrementTable:   []domain.IncrementStep{{From: 0, Increment: 1}, {From: 100, Increment: 5}},
+			SoftCloseSeconds: &window,
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateCategory() unexpected error: %v", err)
+	}
+	laptops, err = svc.CreateCategory(context.Background(), CategoryInput{
+		ParentID:   electronics.ID,
+		Name:       "Laptops",
+		Attributes: []domain.AttributeDefinition{{Name: "ram_gb", Type: domain.AttributeTypeNumber}},
+	})
+	if err != nil {
+		t.Fatalf("CreateCategory() unexpected error: %v", err)
+	}
+	return electronics, laptops
+}
+
+func TestCategoryService_GetSchema_Inherits(t *testing.T) {
+	svc, _, _ := newTestCategoryService()
+	electronics, laptops := newTestCategoryTree(t, svc)
+
+	schema, err := svc.GetSchema(context.Background(), laptops.ID)
+	if err != nil {
+		t.Fatalf("GetSchema() unexpected error: %v", err)
+	}
+	if len(schema.Path) != 2 || schema.Path[0].ID != electronics.ID || schema.Path[1].ID != laptops.ID {
+		t.Errorf("GetSchema() path = %v, want Electronics, Laptops", schema.Path)
+	}
+	if len(schema.Attributes) != 2 {
+		t.Errorf("GetSchema() attributes = %v, want brand and ram_gb", schema.Attributes)
+	}
+	if len(schema.Defaults.IncrementTable) != 2 || schema.Defaults.SoftCloseSeconds == nil || *schema.Defaults.SoftCloseSeconds != 120 {
+		t.Errorf("GetSchema() defaults = %+v, want those of Electronics", schema.Defaults)
+	}
+}
+
+func TestCategoryService_CreateCategory_Invalid(t *testing.T) {
+	svc, _, _ := newTestCategoryService()
+	electronics, _ := newTestCategoryTree(t, svc)
+	negative := -1
+
+	tests := []struct {
+		name  string
+		input CategoryInput
+	}{
+		{"no name", CategoryInput{Name: "  "}},
+		{"unknown parent", CategoryInput{Name: "Phones", ParentID: "550e8400-e29b-41d4-a716-446655440000"}},
+		{"malformed parent", CategoryInput{Name: "Phones", ParentID: "not-a-uuid"}},
+		{"duplicate attribute", CategoryInput{Name: "Phones", Attributes: []domain.AttributeDefinition{
+			{Name: "color", Type: domain.AttributeTypeText},
+			{Name: "color", Type: domain.AttributeTypeText},
+		}}},
+		{"enum without options", CategoryInput{Name: "Phones", Attributes: []domain.AttributeDefinition{{Name: "color", Type: domain.AttributeTypeEnum}}}},
+		{"bad increment table", CategoryInput{Name: "Phones", Defaults: domain.AuctionDefaults{IncrementTable: []domain.IncrementStep{{From: 5, Increment: 1}}}}},
+		{"negative soft close", CategoryInput{Name: "Phones", ParentID: electronics.ID, Defaults: domain.AuctionDefaults{SoftCloseSeconds: &negative}}},
+		{"bad fee schedule", CategoryInput{Name: "Phones", Fees: domain.FeeSchedules{SellerCommission: &domain.FeeSchedule{Type: domain.FeeTypeTiered}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateCategory(context.Background(), tt.input)
+			if !errors.Is(err, domain.ErrValidation) {
+				t.Errorf("CreateCategory() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestCategoryService_CreateCategory_DuplicateSiblingName(t *testing.T) {
+	svc, _, _ := newTestCategoryService()
+	electronics, _ := newTestCategoryTree(t, svc)
+
+	_, err := svc.CreateCategory(context.Background(), CategoryInput{ParentID: electronics.ID, Name: "laptops"})
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("CreateCategory() error = %v, want a conflict", err)
+	}
+}
+
+func TestCategoryService_UpdateCategory_RejectsCycle(t *testing.T) {
+	svc, _, _ := newTestCategoryService()
+	electronics, laptops := newTestCategoryTree(t, svc)
+
+	_, err := svc.UpdateCategory(context.Background(), electronics.ID, CategoryInput{ParentID: laptops.ID, Name: "Electronics"})
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("UpdateCategory() error = %v, want a validation error", err)
+	}
+}
+
+func TestCategoryService_DeleteCategory(t *testing.T) {
+	svc, _, productRepo := newTestCategoryService()
+	electronics, laptops := newTestCategoryTree(t, svc)
+	ctx := context.Background()
+
+	if err := svc.DeleteCategory(ctx, electronics.ID); !errors.Is(err, domain.ErrCategoryInUse) {
+		t.Errorf("DeleteCategory() with a subcategory error = %v, want %v", err, domain.ErrCategoryInUse)
+	}
+
+	productRepo.Create(ctx, &domain.Product{ID: "product-1", CategoryID: laptops.ID})
+	if err := svc.DeleteCategory(ctx, laptops.ID); !errors.Is(err, domain.ErrCategoryInUse) {
+		t.Errorf("DeleteCategory() with a product error = %v, want %v", err, domain.ErrCategoryInUse)
+	}
+
+	empty, err := svc.CreateCategory(ctx, CategoryInput{ParentID: electronics.ID, Name: "Phones"})
+	if err != nil {
+		t.Fatalf("CreateCategory() unexpected error: %v", err)
+	}
+	if err := svc.DeleteCategory(ctx, empty.ID); err != nil {
+		t.Fatalf("DeleteCategory() unexpected error: %v", err)
+	}
+	if _, err := svc.GetCategory(ctx, empty.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetCategory() after delete error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
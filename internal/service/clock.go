@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// DefaultTickInterval is used when no tick interval is configured
+const DefaultTickInterval = time.Second
+
+// ClockService drives the auction clock. On every tick it starts scheduled
+// auctions whose start time has come, executes the pre-bids of opened
+// auctions, keeps the lots of catalogs closing in order, closes the auctions
+// whose end time has passed, creates the orders of closed auctions, and
+// reports the server time and remaining time of each live auction to its
+// subscribers, so clients can correct countdowns computed from their own
+// clocks.
+type ClockService struct {
+	auctionRepo    domain.AuctionRepository
+	auctionService *AuctionService
+	catalogService *CatalogService
+	bidService     *BidService
+	orderService   *OrderService
+	events         *EventService
+	interval       time.Duration
+	logger         zerolog.Logger
+}
+
+func NewClockService(auctionRepo domain.AuctionRepository, auctionService *AuctionService, catalogService *CatalogService, bidService *BidService, orderService *OrderService, events *EventService, interval time.Duration, logger zerolog.Logger) *ClockService {
+	if interval <= 0 {
+		interval = DefaultTickInterval
+	}
+	return &ClockService{
+		auctionRepo:    auctionRepo,
+		auctionService: auctionService,
+		catalogService: catalogService,
+		bidService:     bidService,
+		orderService:   orderService,
+		events:         events,
+		interval:       interval,
+		logger:         logger,
+	}
+}
+
+// Interval returns how often Tick should be called
+func (s *ClockService) Interval() time.Duration {
+	return s.interval
+}
+
+// Tick starts and closes the auctions that are due by now, then publishes a
+// tick event for every active or paused auction with live subscribers. Lots
+// are aligned before closing, so a lot never closes while the lot before it
+// is still extended by soft close. Failures are logged and left for the next
+// tick: an auction that fails to start, close or settle never holds up the
+// others or the tick events.
+func (s *ClockService) Tick(ctx context.Context, now time.Time) {
+	if _, err := s.auctionService.StartDue(ctx, now); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to start due auctions")
+	}
+	if _, err := s.bidService.ExecutePreBids(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to execute pre-bids")
+	}
+	if _, err := s.catalogService.AlignClosing(ctx, now); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to align catalog lots")
+	}
+	if _, err := s.auctionService.CloseExpired(ctx, now); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to close expired auctions")
+	}
+	if _, err := s.orderService.SettleEnded(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to settle ended auctions")
+	}
+
+	for _, auctionID := range s.events.WatchedAuctions() {
+		auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+		if err != nil {
+			continue
+		}
+		if auction.Status != domain.AuctionStatusActive && auction.Status != domain.AuctionStatusPaused {
+			continue
+		}
+		snapshot := *auction
+		s.events.Broadcast(&domain.AuctionEvent{
+			AuctionID: auction.ID,
+			Type:      domain.AuctionEventTick,
+			Auction:   &snapshot,
+			CreatedAt: now,
+		})
+	}
+}
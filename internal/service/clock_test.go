@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+func TestClockService_Tick_PublishesWatchedActiveAuctions(t *testing.T) {
+	publisher := mocks.NewMockEventPublisher()
+	events := NewEventService(mocks.NewMockAuctionEventRepository(), publisher)
+	auctionRepo := mocks.NewMockAuctionRepository()
+	auctionService := NewAuctionService(auctionRepo, mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(), newTestProductRepo(), mocks.NewMockCategoryRepository(), mocks.NewMockBidRepository(), mocks.NewMockTransactor(), events)
+	catalogService := NewCatalogService(mocks.NewMockCatalogRepository(auctionRepo), auctionService, auctionRepo, events)
+	bidService := newTestBidServiceFor(auctionRepo, events)
+	orderService := newTestOrderServiceFor(auctionRepo, auctionService, bidService)
+	clock := NewClockService(auctionRepo, auctionService, catalogService, bidService, orderService, events, time.Second, zerolog.Nop())
+	ctx := context.Background()
+	now := time.Now()
+
+	watched, _ := auctionService.CreateAuction(ctx, "product-1", now.Add(-time.Hour), now.Add(90*time.Second), 100.00)
+	unwatched, _ := auctionService.CreateAuction(ctx, "product-2", now.Add(-time.Hour), now.Add(time.Hour), 100.00)
+	pending, _ := auctionService.CreateAuction(ctx, "product-3", now.Add(time.Hour), now.Add(2*time.Hour), 100.00)
+	auctionService.StartAuction(ctx, watched.ID, AnyVersion, "admin-1")
+	auctionService.StartAuction(ctx, unwatched.ID, AnyVersion, "admin-1")
+	publisher.Watch(watched.ID, pending.ID)
+	started := len(publisher.Events())
+
+	clock.Tick(ctx, now)
+
+	ticks := publisher.Events()[started:]
+	if len(ticks) != 1 {
+		t.Fatalf("Tick() published %d events, want 1", len(ticks))
+	}
+	tick := ticks[0]
+	if tick.Type != domain.AuctionEventTick || tick.AuctionID != watched.ID {
+		t.Errorf("Tick() event = %s for %s, want tick for %s", tick.Type, tick.AuctionID, watched.ID)
+	}
+	if tick.IsLogged() {
+		t.Error("tick event was logged, want transient")
+	}
+	if got := tick.Auction.Remaining(tick.CreatedAt); got != 90*time.Second {
+		t.Errorf("tick remaining = %v, want 1m30s", got)
+	}
+}
+
+func TestClockService_Tick_ClosesExpiredAuctions(t *testing.T) {
+	publisher := mocks.NewMockEventPublisher()
+	events := NewEventService(mocks.NewMockAuctionEventRepository(), publisher)
+	auctionRepo := mocks.NewMockAuctionRepository()
+	auctionService := NewAuctionService(auctionRepo, mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(), newTestProductRepo(), mocks.NewMockCategoryRepository(), mocks.NewMockBidRepository(), mocks.NewMockTransactor(), events)
+	catalogService := NewCatalogService(mocks.NewMockCatalogRepository(auctionRepo), auctionService, auctionRepo, events)
+	bidService := newTestBidServiceFor(auctionRepo, events)
+	orderService := newTestOrderServiceFor(auctionRepo, auctionService, bidService)
+	clock := NewClockService(auctionRepo, auctionService, catalogService, bidService, orderService, events, time.Second, zerolog.Nop())
+	ctx := context.Background()
+	now := time.Now()
+
+	auction, _ := auctionService.CreateAuction(ctx, "product-1", now.Add(-time.Hour), now.Add(-time.Second), 100.00)
+	auctionService.StartAuction(ctx, auction.ID, AnyVersion, "admin-1")
+	publisher.Watch(auction.ID)
+	started := len(publisher.Events())
+
+	clock.Tick(ctx, now)
+
+	// The closed auction gets its closing and closed events but no more ticks
+	var types []domain.AuctionEventType
+	for _, event := range publisher.Events()[started:] {
+		types = append(types, event.Type)
+	}
+	want := []domain.AuctionEventType{domain.AuctionEventClosing, domain.AuctionEventClosed}
+	if len(types) != len(want) || types[0] != want[0] || types[1] != want[1] {
+		t.Errorf("Tick() published %v, want %v", types, want)
+	}
+}
+
+func TestClockService_Tick_CarriesOnAfterFailures(t *testing.T) {
+	publisher := mocks.NewMockEventPublisher()
+	events := NewEventService(mocks.NewMockAuctionEventRepository(), publisher)
+	auctionRepo := mocks.NewMockAuctionRepository()
+	auctionService := NewAuctionService(auctionRepo, mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(), newTestProductRepo(), mocks.NewMockCategoryRepository(), mocks.NewMockBidRepository(), mocks.NewMockTransactor(), events)
+	catalogService := NewCatalogService(mocks.NewMockCatalogRepository(auctionRepo), auctionService, auctionRepo, events)
+	bidService := newTestBidServiceFor(auctionRepo, events)
+	orderService := newTestOrderServiceFor(auctionRepo, auctionService, bidService)
+	clock := NewClockService(auctionRepo, auctionService, catalogService, bidService, orderService, events, time.Second, zerolog.Nop())
+	ctx := context.Background()
+	now := time.Now()
+
+	// The expired auction fails to close, as if a late bid had replaced it
+	expired, _ := auctionService.CreateAuction(ctx, "product-1", now.Add(-time.Hour), now.Add(-time.Second), 100.00)
+	running, _ := auctionService.CreateAuction(ctx, "product-2", now.Add(-time.Hour), now.Add(time.Hour), 100.00)
+	auctionService.StartAuction(ctx, expired.ID, AnyVersion, "seller-1")
+	auctionService.StartAuction(ctx, running.ID, AnyVersion, "seller-1")
+	stale, _ := auctionRepo.GetByID(ctx, expired.ID)
+	stale.Version--
+	publisher.Watch(running.ID)
+	started := len(publisher.Events())
+
+	clock.Tick(ctx, now)
+
+	ticks := publisher.Events()[started:]
+	if len(ticks) != 1 || ticks[0].Type != domain.AuctionEventTick || ticks[0].AuctionID != running.ID {
+		t.Errorf("Tick() published %d events, want a tick for %s", len(ticks), running.ID)
+	}
+}
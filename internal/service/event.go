@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// EventService records auction events in the persistent log and pushes them
+// to live subscribers
+type EventService struct {
+	eventRepo domain.AuctionEventRepository
+	publisher domain.EventPublisher
+}
+
+func NewEventService(eventRepo domain.AuctionEventRepository, publisher domain.EventPublisher) *EventService {
+	return &EventService{
+		eventRepo: eventRepo,
+		publisher: publisher,
+	}
+}
+
+// Record assigns the event its sequence number, logs it and publishes it
+func (s *EventService) Record(ctx context.Context, event *domain.AuctionEvent) error {
+	if err := s.eventRepo.Append(ctx, event); err != nil {
+		return fmt.Errorf("failed to record auction event: %w", err)
+	}
+	s.publisher.Publish(event)
+	return nil
+}
+
+// ListAfter returns up to limit logged events of an auction after sequence afterSeq
+func (s *EventService) ListAfter(ctx context.Context, auctionID string, afterSeq int64, limit int) ([]*domain.AuctionEvent, error) {
+	events, err := s.eventRepo.ListAfter(ctx, auctionID, afterSeq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auction events: %w", err)
+	}
+	return events, nil
+}
+
+// Broadcast publishes a transient event, such as a tick, to live subscribers
+// without logging it. Reconnecting clients do not receive it again.
+func (s *EventService) Broadcast(event *domain.AuctionEvent) {
+	s.publisher.Publish(event)
+}
+
+// WatchedAuctions lists the auctions that currently have live subscribers
+func (s *EventService) WatchedAuctions() []string {
+	return s.publisher.WatchedAuctions()
+}
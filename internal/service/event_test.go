@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+func newTestEventService() *EventService {
+	return NewEventService(mocks.NewMockAuctionEventRepository(), mocks.NewMockEventPublisher())
+}
+
+func TestEventService_Record_AssignsSequence(t *testing.T) {
+	publisher := mocks.NewMockEventPublisher()
+	svc := NewEventService(mocks.NewMockAuctionEventRepository(), publisher)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		svc.Record(ctx, &domain.AuctionEvent{AuctionID: "auction-1", Type: domain.AuctionEventBid, CreatedAt: time.Now()})
+	}
+	svc.Record(ctx, &domain.AuctionEvent{AuctionID: "auction-2", Type: domain.AuctionEventBid, CreatedAt: time.Now()})
+
+	published := publisher.Events()
+	want := []int64{1, 2, 3, 1}
+	for i, event := range published {
+		if event.Sequence != want[i] {
+			t.Errorf("event %d sequence = %d, want %d", i, event.Sequence, want[i])
+		}
+	}
+}
+
+func TestEventService_ListAfter(t *testing.T) {
+	svc := newTestEventService()
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		svc.Record(ctx, &domain.AuctionEvent{AuctionID: "auction-1", Type: domain.AuctionEventBid, CreatedAt: time.Now()})
+	}
+
+	events, err := svc.ListAfter(ctx, "auction-1", 2, 2)
+	if err != nil {
+		t.Fatalf("ListAfter() unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0].Sequence != 3 || events[1].Sequence != 4 {
+		t.Errorf("ListAfter() returned %d events starting at %d, want 3 and 4", len(events), events[0].Sequence)
+	}
+}
+
+func TestEventService_Record_LogFailureNotPublished(t *testing.T) {
+	repo := mocks.NewMockAuctionEventRepository()
+	publisher := mocks.NewMockEventPublisher()
+	svc := NewEventService(repo, publisher)
+	repo.SetError(errors.New("db down"))
+
+	err := svc.Record(context.Background(), &domain.AuctionEvent{AuctionID: "auction-1", Type: domain.AuctionEventBid})
+	if err == nil {
+		t.Error("Record() expected error when the log fails, got nil")
+	}
+	if len(publisher.Events()) != 0 {
+		t.Error("Record() published an event that was not logged")
+	}
+}
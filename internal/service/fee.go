@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// FeeService is the fee engine: it computes the buyer's premium, seller
+// commission and tax of a hammer price from the default fee schedules,
+// overridden by those of the product's category and its ancestors
+type FeeService struct {
+	auctionRepo  domain.AuctionRepository
+	productRepo  domain.ProductRepository
+	categoryRepo domain.CategoryRepository
+	defaults     domain.FeeSchedules
+	taxPercent   float64
+}
+
+func NewFeeService(
+	auctionRepo domain.AuctionRepository,
+	productRepo domain.ProductRepository,
+	categoryRepo domain.CategoryRepository,
+	defaults domain.FeeSchedules,
+	taxPercent float64,
+) *FeeService {
+	return &FeeService{
+		auctionRepo:  auctionRepo,
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		defaults:     defaults,
+		taxPercent:   taxPercent,
+	}
+}
+
+// Schedules returns the fee schedules that apply to the auctions of a product
+func (s *FeeService) Schedules(ctx context.Context, product *domain.Product) (domain.FeeSchedules, error) {
+	if product.CategoryID == "" {
+		return s.defaults, nil
+	}
+	path, err := categoryPath(ctx, s.categoryRepo, product.CategoryID)
+	if err != nil {
+		return domain.FeeSchedules{}, err
+	}
+	return domain.CategoryFees(path, s.defaults), nil
+}
+
+// Assess itemizes the fees and tax charged when an auction of the product is
+// won at hammer
+func (s *FeeService) Assess(ctx context.Context, product *domain.Product, hammer float64) (*domain.FeeBreakdown, error) {
+	schedules, err := s.Schedules(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	return domain.ComputeFees(schedules, s.taxPercent, hammer), nil
+}
+
+// Estimate itemizes what winning an auction with a bid of amount would cost,
+// so bidders see the total including premium and tax before they bid.
+// Without an amount, it estimates the lowest bid the auction accepts next.
+func (s *FeeService) Estimate(ctx context.Context, auctionID string, amount *float64) (*domain.FeeBreakdown, error) {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	return s.EstimateFor(ctx, auction, amount)
+}
+
+// EstimateFor is Estimate for an auction already loaded
+func (s *FeeService) EstimateFor(ctx context.Context, auction *domain.Auction, amount *float64) (*domain.FeeBreakdown, error) {
+	hammer := auction.NextAsk()
+	if amount != nil {
+		if *amount < 0 {
+			return nil, fmt.Errorf("%w: amount must be non-negative", domain.ErrValidation)
+		}
+		hammer = roundCents(*amount)
+	}
+
+	product, err := s.productRepo.GetByID(ctx, auction.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	return s.Assess(ctx, product, hammer)
+}
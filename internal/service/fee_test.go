@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+// newTestFeeService returns a fee service charging a 15% buyer's premium of
+// at least 5 and a 10% commission by default, with 10% tax. Products of
+// category "art" pay a 25% premium instead. It has an active auction
+// "auction-f" of "product-1" at 100 with an increment of 10, and "auction-art"
+// of the art product "product-art".
+func newTestFeeService(t *testing.T) *FeeService {
+	t.Helper()
+	ctx := context.Background()
+	auctionRepo := mocks.NewMockAuctionRepository()
+	productRepo := newTestProductRepo()
+	categoryRepo := mocks.NewMockCategoryRepository()
+
+	categoryRepo.Create(ctx, &domain.Category{
+		ID:   "art",
+		Name: "Art",
+		Fees: domain.FeeSchedules{BuyerPremium: &domain.FeeSchedule{Type: domain.FeeTypePercentage, Amount: 25}},
+	})
+	productRepo.Create(ctx, &domain.Product{ID: "product-art", OwnerID: "seller-1", CategoryID: "art", Version: 1})
+	for id, productID := range map[string]string{"auction-f": "product-1", "auction-art": "product-art"} {
+		auctionRepo.Create(ctx, &domain.Auction{
+			ID:             id,
+			ProductID:      productID,
+			StartTime:      time.Now().Add(-time.Hour),
+			EndTime:        time.Now().Add(time.Hour),
+			StartingPrice:  100,
+			CurrentPrice:   100,
+			Status:         domain.AuctionStatusActive,
+			IncrementTable: []domain.IncrementStep{{From: 0, Increment: 10}},
+			Version:        1,
+			CreatedAt:      time.Now(),
+		})
+	}
+
+	defaults := domain.FeeSchedules{
+		BuyerPremium:     &domain.FeeSchedule{Type: domain.FeeTypePercentage, Amount: 15, Minimum: 5},
+		SellerCommission: &domain.FeeSchedule{Type: domain.FeeTypePercentage, Amount: 10},
+	}
+	return NewFeeService(auctionRepo, productRepo, categoryRepo, defaults, 10)
+}
+
+func TestFeeService_Estimate(t *testing.T) {
+	svc := newTestFeeService(t)
+	ctx := context.Background()
+	amount := func(a float64) *float64 { return &a }
+
+	tests := []struct {
+		name        string
+		auctionID   string
+		amount      *float64
+		wantHammer  float64
+		wantPremium float64
+		wantTotal   float64
+	}{
+		{"next ask", "auction-f", nil, 110, 16.5, 139.15},
+		{"bid amount", "auction-f", amount(200), 200, 30, 253},
+		{"minimum premium", "auction-f", amount(20), 20, 5, 27.5},
+		{"category override", "auction-art", amount(200), 200, 50, 275},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimate, err := svc.Estimate(ctx, tt.auctionID, tt.amount)
+			if err != nil {
+				t.Fatalf("Estimate() unexpected error: %v", err)
+			}
+			if estimate.HammerPrice != tt.wantHammer || estimate.BuyerPremium != tt.wantPremium || estimate.BuyerTotal() != tt.wantTotal {
+				t.Errorf("Estimate() = bid %v, premium %v, total %v; want %v, %v, %v", estimate.HammerPrice,
+					estimate.BuyerPremium, estimate.BuyerTotal(), tt.wantHammer, tt.wantPremium, tt.wantTotal)
+			}
+		})
+	}
+
+	if _, err := svc.Estimate(ctx, "auction-f", amount(-1)); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Estimate() of a negative bid error = %v, want %v", err, domain.ErrValidation)
+	}
+	if _, err := svc.Estimate(ctx, "auction-missing", nil); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Estimate() of a missing auction error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
+
+func TestOrderService_Settle_Fees(t *testing.T) {
+	feeService := newTestFeeService(t)
+	ctx := context.Background()
+	auctionRepo := feeService.auctionRepo.(*mocks.MockAuctionRepository)
+	events := newTestEventService()
+	bidService := newTestBidServiceFor(auctionRepo, events)
+	auctionService := NewAuctionService(auctionRepo, mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(),
+		feeService.productRepo, feeService.categoryRepo, bidService.bidRepo, mocks.NewMockTransactor(), events)
+	svc := NewOrderService(mocks.NewMockOrderRepository(auctionRepo), auctionRepo, bidService.bidRepo, feeService.productRepo,
+		auctionService, feeService, 0)
+
+	bidService.PlaceBid(ctx, "auction-art", "user-1", 200)
+	auctionService.EndAuction(ctx, "auction-art", AnyVersion, "seller-1")
+	order, err := svc.Settle(ctx, "auction-art")
+	if err != nil {
+		t.Fatalf("Settle() unexpected error: %v", err)
+	}
+
+	if order.BuyerPremium != 50 || order.SellerCommission != 20 || order.Tax != 25 || order.Total != 275 {
+		t.Errorf("order premium %v, commission %v, tax %v, total %v; want 50, 20, 25, 275",
+			order.BuyerPremium, order.SellerCommission, order.Tax, order.Total)
+	}
+	if proceeds := order.SellerProceeds(); proceeds != 180 {
+		t.Errorf("SellerProceeds() = %v, want 180", proceeds)
+	}
+	if len(order.Fees) != 3 {
+		t.Errorf("order fees = %+v, want premium, commission and tax lines", order.Fees)
+	}
+	if due := order.DueAt.Sub(order.CreatedAt); due != DefaultPaymentDue {
+		t.Errorf("payment due after %v, want %v", due, DefaultPaymentDue)
+	}
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+const (
+	// MaxImportSize is the size limit of import files
+	MaxImportSize = 10 << 20
+	maxImportRows = 5000
+	// Imports of up to syncImportRows rows finish before Import returns;
+	// larger ones run in the background
+	syncImportRows = 100
+	// maxImportErrors row errors are kept per job
+	maxImportErrors = 100
+	// importProgressRows rows are validated between progress updates
+	importProgressRows = 100
+)
+
+// ImportService creates products and their auctions in bulk from CSV or JSON
+// lines files
+type ImportService struct {
+	importRepo     domain.ImportRepository
+	productService *ProductService
+	auctionService *AuctionService
+	// running counts the imports in the background
+	running sync.WaitGroup
+}
+
+func NewImportService(importRepo domain.ImportRepository, productService *ProductService, auctionService *AuctionService) *ImportService {
+	return &ImportService{importRepo: importRepo, productService: productService, auctionService: auctionService}
+}
+
+// Import validates every row of an import file and, unless dryRun is set,
+// creates the products of the rows for userID with their auctions, either
+// all of them or none. The rows are checked like single products and auctions
+// are; auctions are created pending. Files of up to syncImportRows rows are
+// imported before Import returns; larger ones in the background, returning
+// the queued job, whose progress GetJob reports.
+func (s *ImportService) Import(ctx context.Context, userID string, format domain.ImportFormat, dryRun bool, data []byte) (*domain.ImportJob, error) {
+	if len(data) > MaxImportSize {
+		return nil, fmt.Errorf("%w: import files may be at most %d MB", domain.ErrFileTooLarge, MaxImportSize>>20)
+	}
+	rows, err := parseImportFile(format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &domain.ImportJob{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Format:    format,
+		DryRun:    dryRun,
+		Status:    domain.ImportJobQueued,
+		TotalRows: len(rows),
+		CreatedAt: time.Now(),
+	}
+	if err := s.importRepo.CreateJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	if len(rows) <= syncImportRows {
+		if err := s.run(ctx, job, rows); err != nil {
+			return nil, err
+		}
+		return job, nil
+	}
+
+	// The import outlives the request that started it
+	background := context.WithoutCancel(ctx)
+	queued := *job
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		// A failure leaves the job as last stored; there is no one to tell
+		_ = s.run(background, job, rows)
+	}()
+	return &queued, nil
+}
+
+// GetJob returns an import job of userID; other users' jobs are not found
+func (s *ImportService) GetJob(ctx context.Context, id, userID string) (*domain.ImportJob, error) {
+	job, err := s.importRepo.GetJob(ctx, id)
+	if err == nil && job.UserID != userID {
+		err = &domain.NotFoundError{Entity: "import job", ID: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import job: %w", err)
+	}
+	return job, nil
+}
+
+// Wait blocks until every import running in the background has finished
+func (s *ImportService) Wait() {
+	s.running.Wait()
+}
+
+// run validates the rows of job, storing its progress as it goes, then
+// creates their lots unless the job is a dry run or a row is invalid
+func (s *ImportService) run(ctx context.Context, job *domain.ImportJob, rows []*importRow) error {
+	now := time.Now()
+	job.Status = domain.ImportJobValidating
+	job.StartedAt = &now
+	if err := s.importRepo.UpdateJob(ctx, job); err != nil {
+		return fmt.Errorf("failed to update import job: %w", err)
+	}
+
+	lots := make([]*domain.ImportLot, 0, len(rows))
+	auctions := 0
+	for i, row := range rows {
+		lot, err := s.validateRow(ctx, job.UserID, row)
+		if err != nil {
+			return s.finish(ctx, job, domain.ImportJobFailed, "the rows could not be validated; nothing was imported", err)
+		}
+		if len(row.errors) > 0 {
+			job.ErrorCount += len(row.errors)
+			job.Errors = append(job.Errors, row.errors[:min(len(row.errors), maxImportErrors-len(job.Errors))]...)
+		} else {
+			lots = append(lots, lot)
+			if lot.Auction != nil {
+				auctions++
+			}
+		}
+
+		job.ProcessedRows = i + 1
+		if job.ProcessedRows%importProgressRows == 0 && job.ProcessedRows < len(rows) {
+			if err := s.importRepo.UpdateJob(ctx, job); err != nil {
+				return fmt.Errorf("failed to update import job: %w", err)
+			}
+		}
+	}
+	if job.ErrorCount > 0 {
+		return s.finish(ctx, job, domain.ImportJobFailed, "", nil)
+	}
+
+	job.ProductCount = len(lots)
+	job.AuctionCount = auctions
+	if job.DryRun {
+		return s.finish(ctx, job, domain.ImportJobSucceeded, "", nil)
+	}
+
+	job.Status = domain.ImportJobImporting
+	if err := s.importRepo.UpdateJob(ctx, job); err != nil {
+		return fmt.Errorf("failed to update import job: %w", err)
+	}
+	if err := s.importRepo.CreateLots(ctx, lots); err != nil {
+		job.ProductCount, job.AuctionCount = 0, 0
+		return s.finish(ctx, job, domain.ImportJobFailed, "the lots could not be stored; nothing was imported", err)
+	}
+	return s.finish(ctx, job, domain.ImportJobSucceeded, "", nil)
+}
+
+// finish stores the outcome of a job. cause is the error that failed it, if
+// any, which is returned after the job is stored; message is what the job's
+// owner is told about it.
+func (s *ImportService) finish(ctx context.Context, job *domain.ImportJob, status domain.ImportJobStatus, message string, cause error) error {
+	now := time.Now()
+	job.Status = status
+	job.Error = message
+	job.FinishedAt = &now
+	if err := s.importRepo.UpdateJob(ctx, job); err != nil {
+		return fmt.Errorf("failed to update import job: %w", err)
+	}
+	return cause
+}
+
+// validateRow turns a row into the lot it creates, recording the problems it
+// has as row errors. It only fails for errors other than invalid input.
+func (s *ImportService) validateRow(ctx context.Context, ownerID string, row *importRow) (*domain.ImportLot, error) {
+	if hasRowError(row, "") {
+		// The row could not be read at all
+		return nil, nil
+	}
+	if row.name == "" {
+		row.addError(importColumnName, "name is required")
+	}
+	product, err := s.productService.newProduct(ctx, row.name, row.description, ownerID, ProductDetails{
+		CategoryID: row.categoryID,
+		Condition:  row.condition,
+		Attributes: row.attributes,
+	})
+	if err != nil {
+		if err := rowError(row, err); err != nil {
+			return nil, err
+		}
+	}
+	if !row.hasAuction() {
+		return &domain.ImportLot{Product: product}, nil
+	}
+
+	for field, missing := range map[string]bool{
+		importColumnStartingPrice: row.startingPrice == nil,
+		importColumnStartTime:     row.startTime == nil,
+		importColumnEndTime:       row.endTime == nil,
+	} {
+		if missing && !hasRowError(row, field) {
+			row.addError(field, field+" is required for an auction")
+		}
+	}
+	if len(row.errors) > 0 {
+		return nil, nil
+	}
+	if !row.endTime.After(time.Now()) {
+		row.addError(importColumnEndTime, "end time must be in the future")
+		return nil, nil
+	}
+
+	var opts []AuctionOption
+	if row.twoFactorThreshold != nil {
+		opts = append(opts, WithTwoFactorThreshold(*row.twoFactorThreshold))
+	}
+	auction, err := s.auctionService.newAuction(ctx, product, *row.startTime, *row.endTime, *row.startingPrice, opts...)
+	if err != nil {
+		return nil, rowError(row, err)
+	}
+	return &domain.ImportLot{Product: product, Auction: auction}, nil
+}
+
+// rowError records a validation error on the row, passing other errors on
+func rowError(row *importRow, err error) error {
+	if !errors.Is(err, domain.ErrValidation) {
+		return err
+	}
+	row.addError("", strings.TrimPrefix(err.Error(), domain.ErrValidation.Error()+": "))
+	return nil
+}
+
+func hasRowError(row *importRow, field string) bool {
+	for _, e := range row.errors {
+		if e.Field == field {
+			return true
+		}
+	}
+	return false
+}
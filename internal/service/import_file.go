@@ -0,0 +1,259 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// Columns of an import file. CSV headers and JSON keys use the same names;
+// CSV files name attribute columns attr.<name>, JSON lines hold an
+// attributes object.
+const (
+	importColumnName               = "name"
+	importColumnDescription        = "description"
+	importColumnCategoryID         = "category_id"
+	importColumnCondition          = "condition"
+	importColumnStartingPrice      = "starting_price"
+	importColumnStartTime          = "start_time"
+	importColumnEndTime            = "end_time"
+	importColumnTwoFactorThreshold = "two_factor_threshold"
+	importAttributePrefix          = "attr."
+)
+
+// maxImportLineSize bounds a single line of a JSON lines file
+const maxImportLineSize = 1 << 20
+
+var importColumns = []string{
+	importColumnName, importColumnDescription, importColumnCategoryID, importColumnCondition,
+	importColumnStartingPrice, importColumnStartTime, importColumnEndTime, importColumnTwoFactorThreshold,
+}
+
+// importRow is a row of an import file as parsed, before validation
+type importRow struct {
+	line        int
+	name        string
+	description string
+	categoryID  string
+	condition   domain.ProductCondition
+	attributes  map[string]string
+	// The auction settings; all nil for rows without an auction
+	startingPrice      *float64
+	startTime          *time.Time
+	endTime            *time.Time
+	twoFactorThreshold *float64
+	// errors holds the values that could not be parsed
+	errors []domain.ImportRowError
+}
+
+// hasAuction reports whether the row sets up an auction
+func (r *importRow) hasAuction() bool {
+	return r.startingPrice != nil || r.startTime != nil || r.endTime != nil || r.twoFactorThreshold != nil
+}
+
+func (r *importRow) addError(field, message string) {
+	r.errors = append(r.errors, domain.ImportRowError{Line: r.line, Field: field, Message: message})
+}
+
+// parseImportFile splits an import file into rows. Problems with single rows
+// are recorded on them; problems with the whole file, such as an unknown CSV
+// column, fail with domain.ErrValidation.
+func parseImportFile(format domain.ImportFormat, data []byte) ([]*importRow, error) {
+	var rows []*importRow
+	var err error
+	switch format {
+	case domain.ImportFormatCSV:
+		rows, err = parseImportCSV(data)
+	case domain.ImportFormatJSONLines:
+		rows, err = parseImportJSONLines(data)
+	default:
+		return nil, fmt.Errorf("%w: import files must be CSV or JSON lines", domain.ErrUnsupportedMediaType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: the file has no rows", domain.ErrValidation)
+	}
+	if len(rows) > maxImportRows {
+		return nil, fmt.Errorf("%w: imports may have at most %d rows", domain.ErrValidation, maxImportRows)
+	}
+	return rows, nil
+}
+
+func parseImportCSV(data []byte) ([]*importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: the file is empty", domain.ErrValidation)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrValidation, err.Error())
+	}
+	if err := checkImportHeader(header); err != nil {
+		return nil, err
+	}
+
+	var rows []*importRow
+	for len(rows) <= maxImportRows {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrValidation, err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+		row := &importRow{line: line, attributes: map[string]string{}}
+		rows = append(rows, row)
+		if err != nil {
+			row.addError("", fmt.Sprintf("the row has %d fields, the header %d", len(record), len(header)))
+			continue
+		}
+
+		for i, value := range record {
+			row.set(header[i], strings.TrimSpace(value))
+		}
+	}
+	return rows, nil
+}
+
+// checkImportHeader requires a name column and rejects unknown or repeated
+// columns, which are most likely typos
+func checkImportHeader(header []string) error {
+	seen := map[string]bool{}
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		header[i] = column
+		known := slices.Contains(importColumns, column) ||
+			strings.HasPrefix(column, importAttributePrefix) && len(column) > len(importAttributePrefix)
+		switch {
+		case !known:
+			return fmt.Errorf("%w: unknown column %q", domain.ErrValidation, column)
+		case seen[column]:
+			return fmt.Errorf("%w: column %q appears twice", domain.ErrValidation, column)
+		}
+		seen[column] = true
+	}
+	if !seen[importColumnName] {
+		return fmt.Errorf("%w: the %q column is required", domain.ErrValidation, importColumnName)
+	}
+	return nil
+}
+
+// set parses the CSV value of a column into the row; empty values are unset
+func (r *importRow) set(column, value string) {
+	if value == "" {
+		return
+	}
+	switch column {
+	case importColumnName:
+		r.name = value
+	case importColumnDescription:
+		r.description = value
+	case importColumnCategoryID:
+		r.categoryID = value
+	case importColumnCondition:
+		r.condition = domain.ProductCondition(value)
+	case importColumnStartingPrice:
+		r.startingPrice = r.parseAmount(column, value)
+	case importColumnTwoFactorThreshold:
+		r.twoFactorThreshold = r.parseAmount(column, value)
+	case importColumnStartTime:
+		r.startTime = r.parseTime(column, value)
+	case importColumnEndTime:
+		r.endTime = r.parseTime(column, value)
+	default:
+		r.attributes[strings.TrimPrefix(column, importAttributePrefix)] = value
+	}
+}
+
+func (r *importRow) parseAmount(column, value string) *float64 {
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		r.addError(column, fmt.Sprintf("%q is not a number", value))
+		return nil
+	}
+	return &amount
+}
+
+func (r *importRow) parseTime(column, value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		r.addError(column, fmt.Sprintf("%q is not an RFC 3339 time, such as 2026-03-01T10:00:00Z", value))
+		return nil
+	}
+	return &t
+}
+
+// importLineJSON is a row of a JSON lines file
+type importLineJSON struct {
+	Name               string                  `json:"name"`
+	Description        string                  `json:"description"`
+	CategoryID         string                  `json:"category_id"`
+	Condition          domain.ProductCondition `json:"condition"`
+	Attributes         map[string]any          `json:"attributes"`
+	StartingPrice      *float64                `json:"starting_price"`
+	StartTime          *time.Time              `json:"start_time"`
+	EndTime            *time.Time              `json:"end_time"`
+	TwoFactorThreshold *float64                `json:"two_factor_threshold"`
+}
+
+func parseImportJSONLines(data []byte) ([]*importRow, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64<<10), maxImportLineSize)
+
+	var rows []*importRow
+	for line := 1; scanner.Scan() && len(rows) <= maxImportRows; line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		row := &importRow{line: line, attributes: map[string]string{}}
+		rows = append(rows, row)
+
+		var parsed importLineJSON
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		decoder.UseNumber()
+		if err := decoder.Decode(&parsed); err != nil {
+			row.addError("", fmt.Sprintf("invalid JSON: %s", err.Error()))
+			continue
+		}
+		row.name = strings.TrimSpace(parsed.Name)
+		row.description = parsed.Description
+		row.categoryID = parsed.CategoryID
+		row.condition = parsed.Condition
+		row.startingPrice = parsed.StartingPrice
+		row.startTime = parsed.StartTime
+		row.endTime = parsed.EndTime
+		row.twoFactorThreshold = parsed.TwoFactorThreshold
+		for name, value := range parsed.Attributes {
+			switch v := value.(type) {
+			case string:
+				row.attributes[name] = v
+			case json.Number:
+				row.attributes[name] = v.String()
+			case bool:
+				row.attributes[name] = strconv.FormatBool(v)
+			default:
+				row.addError(importAttributePrefix+name, "attribute values must be strings, numbers or booleans")
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrValidation, err.Error())
+	}
+	return rows, nil
+}
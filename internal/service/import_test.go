@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+type testImport struct {
+	svc        *ImportService
+	repo       *mocks.MockImportRepository
+	products   *mocks.MockProductRepository
+	auctions   *mocks.MockAuctionRepository
+	categories *CategoryService
+}
+
+func newTestImportService() *testImport {
+	products := mocks.NewMockProductRepository()
+	auctions := mocks.NewMockAuctionRepository()
+	categoryRepo := mocks.NewMockCategoryRepository()
+	repo := mocks.NewMockImportRepository(products, auctions)
+	productService := NewProductService(products, categoryRepo, auctions)
+	auctionService := NewAuctionService(auctions, mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(), products, categoryRepo, mocks.NewMockBidRepository(), mocks.NewMockTransactor(), newTestEventService())
+	return &testImport{
+		svc:        NewImportService(repo, productService, auctionService),
+		repo:       repo,
+		products:   products,
+		auctions:   auctions,
+		categories: NewCategoryService(categoryRepo, products),
+	}
+}
+
+func (ti *testImport) counts(t *testing.T) (products, auctions int) {
+	t.Helper()
+	ctx := context.Background()
+	productList, err := ti.products.List(ctx, domain.ProductQuery{})
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	auctionList, err := ti.auctions.List(ctx, domain.AuctionQuery{})
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	return len(productList), len(auctionList)
+}
+
+func importTime(d time.Duration) string {
+	return time.Now().Add(d).UTC().Format(time.RFC3339)
+}
+
+func TestImportService_Import_CSV(t *testing.T) {
+	ti := newTestImportService()
+	data := "name,description,condition,starting_price,start_time,end_time\n" +
+		"Desk lamp,Brass,used,25," + importTime(time.Hour) + "," + importTime(48*time.Hour) + "\n" +
+		"\"Chair, oak\",,,,,\n"
+
+	job, err := ti.svc.Import(context.Background(), "seller-1", domain.ImportFormatCSV, false, []byte(data))
+	if err != nil {
+		t.Fatalf("Import() unexpected error: %v", err)
+	}
+	if job.Status != domain.ImportJobSucceeded || job.TotalRows != 2 || job.ProcessedRows != 2 {
+		t.Fatalf("Import() = %s with %d/%d rows, want a finished import of 2 rows", job.Status, job.ProcessedRows, job.TotalRows)
+	}
+	if job.ProductCount != 2 || job.AuctionCount != 1 {
+		t.Errorf("Import() counts = %d products, %d auctions; want 2 and 1", job.ProductCount, job.AuctionCount)
+	}
+	if products, auctions := ti.counts(t); products != 2 || auctions != 1 {
+		t.Errorf("stored %d products and %d auctions, want 2 and 1", products, auctions)
+	}
+
+	stored, err := ti.svc.GetJob(context.Background(), job.ID, "seller-1")
+	if err != nil || stored.Status != domain.ImportJobSucceeded || stored.FinishedAt == nil {
+		t.Errorf("GetJob() = %+v, %v; want the finished job", stored, err)
+	}
+}
+
+func TestImportService_Import_JSONLines(t *testing.T) {
+	ti := newTestImportService()
+	_, laptops := newTestCategoryTree(t, ti.categories)
+	data := fmt.Sprintf(`{"name": "ThinkPad", "category_id": %q, "attributes": {"brand": "Lenovo", "ram_gb": 16}, "starting_price": 300, "start_time": %q, "end_time": %q}`+"\n\n"+
+		`{"name": "Monitor"}`+"\n", laptops.ID, importTime(time.Hour), importTime(48*time.Hour))
+
+	job, err := ti.svc.Import(context.Background(), "seller-1", domain.ImportFormatJSONLines, false, []byte(data))
+	if err != nil {
+		t.Fatalf("Import() unexpected error: %v", err)
+	}
+	if job.Status != domain.ImportJobSucceeded || job.ProductCount != 2 || job.AuctionCount != 1 {
+		t.Fatalf("Import() = %+v, want 2 products and 1 auction", job)
+	}
+
+	auctions, _ := ti.auctions.List(context.Background(), domain.AuctionQuery{})
+	if len(auctions) != 1 || auctions[0].SoftCloseSeconds != 120 || len(auctions[0].IncrementTable) != 2 {
+		t.Errorf("imported auction = %+v, want the category's defaults", auctions)
+	}
+}
+
+func TestImportService_Import_RowErrors(t *testing.T) {
+	ti := newTestImportService()
+	data := "name,condition,starting_price,start_time,end_time\n" +
+		"Lamp,used,25," + importTime(time.Hour) + "," + importTime(48*time.Hour) + "\n" +
+		",used,,,\n" +
+		"Chair,broken,abc,," + importTime(48*time.Hour) + "\n" +
+		"Table\n"
+
+	job, err := ti.svc.Import(context.Background(), "seller-1", domain.ImportFormatCSV, false, []byte(data))
+	if err != nil {
+		t.Fatalf("Import() unexpected error: %v", err)
+	}
+	if job.Status != domain.ImportJobFailed || job.ProductCount != 0 {
+		t.Errorf("Import() = %s with %d products, want a failed import", job.Status, job.ProductCount)
+	}
+
+	got := map[string]bool{}
+	for _, e := range job.Errors {
+		got[fmt.Sprintf("%d:%s", e.Line, e.Field)] = true
+	}
+	for _, want := range []string{"3:name", "4:starting_price", "4:start_time", "5:"} {
+		if !got[want] {
+			t.Errorf("Import() errors = %+v, want one at line:field %s", job.Errors, want)
+		}
+	}
+	if job.ErrorCount != len(job.Errors) {
+		t.Errorf("Import() error count = %d, want %d", job.ErrorCount, len(job.Errors))
+	}
+	if products, auctions := ti.counts(t); products != 0 || auctions != 0 {
+		t.Errorf("stored %d products and %d auctions, want none", products, auctions)
+	}
+}
+
+func TestImportService_Import_InvalidFile(t *testing.T) {
+	ti := newTestImportService()
+
+	tests := []struct {
+		name    string
+		format  domain.ImportFormat
+		data    string
+		wantErr error
+	}{
+		{"unknown column", domain.ImportFormatCSV, "name,colour\nLamp,red\n", domain.ErrValidation},
+		{"no name column", domain.ImportFormatCSV, "description\nBrass\n", domain.ErrValidation},
+		{"header only", domain.ImportFormatCSV, "name\n", domain.ErrValidation},
+		{"unknown format", domain.ImportFormat("xml"), "<lots/>", domain.ErrUnsupportedMediaType},
+		{"too many rows", domain.ImportFormatCSV, "name\n" + strings.Repeat("Lamp\n", maxImportRows+1), domain.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ti.svc.Import(context.Background(), "seller-1", tt.format, false, []byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Import() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportService_Import_DryRun(t *testing.T) {
+	ti := newTestImportService()
+	data := `{"name": "Lamp", "starting_price": 25, "start_time": "` + importTime(time.Hour) + `", "end_time": "` + importTime(48*time.Hour) + `"}` + "\n"
+
+	job, err := ti.svc.Import(context.Background(), "seller-1", domain.ImportFormatJSONLines, true, []byte(data))
+	if err != nil {
+		t.Fatalf("Import() unexpected error: %v", err)
+	}
+	if job.Status != domain.ImportJobSucceeded || job.ProductCount != 1 || job.AuctionCount != 1 {
+		t.Errorf("Import() = %+v, want a valid dry run of 1 product and auction", job)
+	}
+	if products, auctions := ti.counts(t); products != 0 || auctions != 0 {
+		t.Errorf("dry run stored %d products and %d auctions, want none", products, auctions)
+	}
+}
+
+func TestImportService_Import_StoreFails(t *testing.T) {
+	ti := newTestImportService()
+	ti.repo.SetError(errors.New("connection reset"))
+
+	_, err := ti.svc.Import(context.Background(), "seller-1", domain.ImportFormatCSV, false, []byte("name\nLamp\n"))
+	if err == nil {
+		t.Fatal("Import() expected an error when the lots cannot be stored")
+	}
+}
+
+func TestImportService_Import_Background(t *testing.T) {
+	ti := newTestImportService()
+	var data strings.Builder
+	data.WriteString("name\n")
+	for i := 0; i < syncImportRows*3; i++ {
+		fmt.Fprintf(&data, "Lot %d\n", i)
+	}
+
+	job, err := ti.svc.Import(context.Background(), "seller-1", domain.ImportFormatCSV, false, []byte(data.String()))
+	if err != nil {
+		t.Fatalf("Import() unexpected error: %v", err)
+	}
+	if job.Status != domain.ImportJobQueued || job.TotalRows != syncImportRows*3 {
+		t.Errorf("Import() = %s with %d rows, want a queued job of %d rows", job.Status, job.TotalRows, syncImportRows*3)
+	}
+
+	ti.svc.Wait()
+	finished, err := ti.svc.GetJob(context.Background(), job.ID, "seller-1")
+	if err != nil {
+		t.Fatalf("GetJob() unexpected error: %v", err)
+	}
+	if finished.Status != domain.ImportJobSucceeded || finished.ProcessedRows != syncImportRows*3 || finished.ProductCount != syncImportRows*3 {
+		t.Errorf("GetJob() = %s with %d rows processed and %d products, want all %d imported",
+			finished.Status, finished.ProcessedRows, finished.ProductCount, syncImportRows*3)
+	}
+}
+
+func TestImportService_GetJob_OtherUser(t *testing.T) {
+	ti := newTestImportService()
+	job, err := ti.svc.Import(context.Background(), "seller-1", domain.ImportFormatCSV, true, []byte("name\nLamp\n"))
+	if err != nil {
+		t.Fatalf("Import() unexpected error: %v", err)
+	}
+
+	if _, err := ti.svc.GetJob(context.Background(), job.ID, "seller-2"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetJob() by another user error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// LiveService runs live auctions, whose lots an auctioneer opens, calls and
+// hammers down from the clerk console while online bidders bid against the
+// asking price through BidService. Every action is announced to live
+// subscribers as a live event.
+type LiveService struct {
+	liveRepo       domain.LiveLotRepository
+	auctionRepo    domain.AuctionRepository
+	auctionService *AuctionService
+	bidService     *BidService
+	events         *EventService
+}
+
+func NewLiveService(liveRepo domain.LiveLotRepository, auctionRepo domain.AuctionRepository, auctionService *AuctionService, bidService *BidService, events *EventService) *LiveService {
+	return &LiveService{
+		liveRepo:       liveRepo,
+		auctionRepo:    auctionRepo,
+		auctionService: auctionService,
+		bidService:     bidService,
+		events:         events,
+	}
+}
+
+// LiveLotDetail is a live lot with its auction
+type LiveLotDetail struct {
+	*domain.LiveLot
+	Auction *domain.Auction
+}
+
+// GetLot returns the live lot of an auction as it stands now
+func (s *LiveService) GetLot(ctx context.Context, auctionID string) (*LiveLotDetail, error) {
+	lot, err := s.liveRepo.GetByAuction(ctx, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get live lot: %w", err)
+	}
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	return &LiveLotDetail{LiveLot: lot, Auction: auction}, nil
+}
+
+// OpenLot starts a pending or scheduled auction as a live lot run by
+// auctioneerID, asking askingPrice, or the auction's next ask if it is zero or
+// pre-bids have opened the bidding above it. The auction stays open past its
+// end time until the lot is sold or passed.
+func (s *LiveService) OpenLot(ctx context.Context, auctionID, auctioneerID string, askingPrice float64) (*LiveLotDetail, error) {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	if auction.Status != domain.AuctionStatusPending && auction.Status != domain.AuctionStatusScheduled {
+		return nil, fmt.Errorf("%w: it is %s; only auctions that have not started can be run live", domain.ErrAuctionNotEditable, auction.Status)
+	}
+	if askingPrice != 0 {
+		if err := checkAsk(auction, askingPrice); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	auction.Live = true
+	if auction.StartTime.After(now) {
+		auction.StartTime = now
+	}
+	if err := s.auctionService.transition(ctx, auction, domain.AuctionStatusActive, "opened live by the auctioneer", auctioneerID); err != nil {
+		return nil, err
+	}
+	if err := s.auctionService.recordStatusEvent(ctx, domain.AuctionEventStatus, auction); err != nil {
+		return nil, err
+	}
+
+	// Pre-bids open the bidding, and the first ask must beat them
+	if err := s.bidService.openPreBids(ctx, auction); err != nil {
+		return nil, err
+	}
+	if askingPrice == 0 || !auction.AcceptsAmount(askingPrice) {
+		askingPrice = auction.NextAsk()
+	}
+
+	lot := &domain.LiveLot{
+		AuctionID:    auctionID,
+		AuctioneerID: auctioneerID,
+		State:        domain.LiveLotOpen,
+		AskingPrice:  askingPrice,
+		LastAction:   domain.LiveActionOpened,
+		OpenedAt:     now,
+		UpdatedAt:    now,
+	}
+	if err := s.liveRepo.Create(ctx, lot); err != nil {
+		return nil, fmt.Errorf("failed to create live lot: %w", err)
+	}
+	if err := s.events.Record(ctx, newLiveEvent(auction, lot)); err != nil {
+		return nil, err
+	}
+	return &LiveLotDetail{LiveLot: lot, Auction: auction}, nil
+}
+
+// Ask calls a new asking price, which must be a valid bid, taking the lot out
+// of fair warning
+func (s *LiveService) Ask(ctx context.Context, auctionID string, amount float64) (*LiveLotDetail, error) {
+	detail, err := s.openLot(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAsk(detail.Auction, amount); err != nil {
+		return nil, err
+	}
+	if err := detail.Ask(amount, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.saveLot(ctx, detail); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// FairWarning announces that the lot is about to be sold. A new bid reopens it.
+func (s *LiveService) FairWarning(ctx context.Context, auctionID string) (*LiveLotDetail, error) {
+	detail, err := s.openLot(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := detail.FairWarning(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.saveLot(ctx, detail); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// FloorBid records a bid made in the sale room by the holder of paddle,
+// entered by clerkID. Like online bids it must offer at least the asking
+// price and beat the current price; proxy bidders respond to it.
+func (s *LiveService) FloorBid(ctx context.Context, auctionID, clerkID, paddle string, amount float64) (*domain.Bid, error) {
+	paddle = strings.TrimSpace(paddle)
+	if paddle == "" {
+		return nil, fmt.Errorf("%w: paddle is required", domain.ErrValidation)
+	}
+	detail, err := s.openLot(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	auction := detail.Auction
+	if amount < detail.AskingPrice {
+		return nil, &domain.BidTooLowError{CurrentPrice: auction.CurrentPrice, MinimumBid: detail.AskingPrice}
+	}
+	if !auction.AcceptsAmount(amount) {
+		return nil, &domain.BidTooLowError{CurrentPrice: auction.CurrentPrice, MinimumBid: auction.MinimumBid()}
+	}
+
+	bid, err := s.bidService.storeBid(ctx, auction, &domain.Bid{
+		Amount:  amount,
+		Source:  domain.BidSourceFloor,
+		Paddle:  paddle,
+		ClerkID: clerkID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.bidService.executeProxyBids(ctx, auction, ""); err != nil {
+		return nil, err
+	}
+	if err := s.bidService.advanceLiveLot(ctx, auction, detail.LiveLot); err != nil {
+		return nil, err
+	}
+	return bid, nil
+}
+
+// Sell hammers the lot down to its highest bidder, ending the auction. A lot
+// without bids can only be passed.
+func (s *LiveService) Sell(ctx context.Context, auctionID, actorID string) (*LiveLotDetail, error) {
+	detail, err := s.openLot(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Auction.BidCount == 0 {
+		return nil, fmt.Errorf("%w: the lot has no bids; pass it instead", domain.ErrValidation)
+	}
+
+	now := time.Now()
+	if err := detail.Sell(now); err != nil {
+		return nil, err
+	}
+	detail.Auction.EndTime = now
+	if err := s.auctionService.closeAuction(ctx, detail.Auction, "sold by the auctioneer", actorID); err != nil {
+		return nil, err
+	}
+	if err := s.saveLot(ctx, detail); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// Pass withdraws the lot unsold, such as when bidding stays below the
+// seller's reserve, cancelling the auction
+func (s *LiveService) Pass(ctx context.Context, auctionID, actorID string) (*LiveLotDetail, error) {
+	detail, err := s.openLot(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := detail.Pass(now); err != nil {
+		return nil, err
+	}
+	detail.Auction.EndTime = now
+	if err := s.auctionService.transition(ctx, detail.Auction, domain.AuctionStatusCancelled, "passed by the auctioneer", actorID); err != nil {
+		return nil, err
+	}
+	if err := s.auctionService.recordStatusEvent(ctx, domain.AuctionEventStatus, detail.Auction); err != nil {
+		return nil, err
+	}
+	if err := s.saveLot(ctx, detail); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// openLot returns the live lot of an auction that still takes bids
+func (s *LiveService) openLot(ctx context.Context, auctionID string) (*LiveLotDetail, error) {
+	detail, err := s.GetLot(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if !detail.IsOpen() {
+		return nil, domain.ErrLotClosed
+	}
+	return detail, nil
+}
+
+// saveLot stores a changed live lot and announces it
+func (s *LiveService) saveLot(ctx context.Context, detail *LiveLotDetail) error {
+	if err := s.liveRepo.Update(ctx, detail.LiveLot); err != nil {
+		return fmt.Errorf("failed to update live lot: %w", err)
+	}
+	return s.events.Record(ctx, newLiveEvent(detail.Auction, detail.LiveLot))
+}
+
+// checkAsk checks that an asking price would be a valid bid on the auction
+func checkAsk(auction *domain.Auction, amount float64) error {
+	if auction.AcceptsAmount(amount) {
+		return nil
+	}
+	tooLow := &domain.BidTooLowError{CurrentPrice: auction.CurrentPrice, MinimumBid: auction.MinimumBid()}
+	return fmt.Errorf("%w: asking price %.2f is too low; %s", domain.ErrValidation, amount, tooLow.Error())
+}
+
+// newLiveEvent returns a live event carrying snapshots of the auction and its
+// live lot
+func newLiveEvent(auction *domain.Auction, lot *domain.LiveLot) *domain.AuctionEvent {
+	auctionSnapshot, lotSnapshot := *auction, *lot
+	return &domain.AuctionEvent{
+		AuctionID: auction.ID,
+		Type:      domain.AuctionEventLive,
+		Auction:   &auctionSnapshot,
+		Live:      &lotSnapshot,
+		CreatedAt: lot.UpdatedAt,
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+// newTestLiveService returns a live service with a pending auction
+// "auction-live" starting at 100 tomorrow, and verified bidders "user-1" and
+// "user-2"
+func newTestLiveService(t *testing.T) (*LiveService, *BidService, *AuctionService, *mocks.MockEventPublisher) {
+	t.Helper()
+	auctionRepo := mocks.NewMockAuctionRepository()
+	bidRepo := mocks.NewMockBidRepository()
+	userRepo := mocks.NewMockUserRepository()
+	liveRepo := mocks.NewMockLiveLotRepository()
+	publisher := mocks.NewMockEventPublisher()
+	events := NewEventService(mocks.NewMockAuctionEventRepository(), publisher)
+
+	verifiedAt := time.Now()
+	for _, id := range []string{"user-1", "user-2"} {
+		userRepo.Create(context.Background(), &domain.User{ID: id, Email: id + "@example.com", EmailVerifiedAt: &verifiedAt})
+	}
+	auctionRepo.Create(context.Background(), &domain.Auction{
+		ID:            "auction-live",
+		ProductID:     "product-1",
+		StartTime:     time.Now().Add(24 * time.Hour),
+		EndTime:       time.Now().Add(25 * time.Hour),
+		StartingPrice: 100,
+		CurrentPrice:  100,
+		Status:        domain.AuctionStatusPending,
+		Version:       1,
+		CreatedAt:     time.Now(),
+	})
+
+	auctionService := NewAuctionService(auctionRepo, mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(), newTestProductRepo(), mocks.NewMockCategoryRepository(), bidRepo, mocks.NewMockTransactor(), events)
+	twoFactor := NewTwoFactorService(mocks.NewMockTwoFactorRepository(), userRepo, "Test")
+	bidService := NewBidService(bidRepo, auctionRepo, userRepo, mocks.NewMockAuctionBidderRepository(), mocks.NewMockProxyBidRepository(), mocks.NewMockPreBidRepository(auctionRepo), liveRepo, mocks.NewMockTransactor(), twoFactor, events)
+	return NewLiveService(liveRepo, auctionRepo, auctionService, bidService, events), bidService, auctionService, publisher
+}
+
+func TestLiveService_OpenLot(t *testing.T) {
+	svc, _, _, publisher := newTestLiveService(t)
+
+	detail, err := svc.OpenLot(context.Background(), "auction-live", "auctioneer-1", 0)
+	if err != nil {
+		t.Fatalf("OpenLot() unexpected error: %v", err)
+	}
+	if detail.State != domain.LiveLotOpen || detail.AskingPrice != 101 || detail.AuctioneerID != "auctioneer-1" {
+		t.Errorf("OpenLot() = %+v, want an open lot asking 101", detail.LiveLot)
+	}
+	if !detail.Auction.Live || detail.Auction.Status != domain.AuctionStatusActive || detail.Auction.StartTime.After(time.Now()) {
+		t.Errorf("auction = %+v, want a live auction started now", detail.Auction)
+	}
+
+	events := publisher.Events()
+	if len(events) != 2 || events[0].Type != domain.AuctionEventStatus || events[1].Type != domain.AuctionEventLive {
+		t.Fatalf("published %v, want a status and a live event", events)
+	}
+	if events[1].Live.LastAction != domain.LiveActionOpened {
+		t.Errorf("live event action = %s, want opened", events[1].Live.LastAction)
+	}
+
+	if _, err := svc.OpenLot(context.Background(), "auction-live", "auctioneer-1", 0); !errors.Is(err, domain.ErrAuctionNotEditable) {
+		t.Errorf("OpenLot() twice error = %v, want %v", err, domain.ErrAuctionNotEditable)
+	}
+}
+
+func TestLiveService_OpenLot_AskTooLow(t *testing.T) {
+	svc, _, _, _ := newTestLiveService(t)
+
+	if _, err := svc.OpenLot(context.Background(), "auction-live", "auctioneer-1", 90); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("OpenLot() error = %v, want %v", err, domain.ErrValidation)
+	}
+}
+
+func TestLiveService_OnlineBidsMeetAskingPrice(t *testing.T) {
+	svc, bidService, _, _ := newTestLiveService(t)
+	ctx := context.Background()
+	svc.OpenLot(ctx, "auction-live", "auctioneer-1", 120)
+
+	var tooLow *domain.BidTooLowError
+	if _, err := bidService.PlaceBid(ctx, "auction-live", "user-1", 110); !errors.As(err, &tooLow) || tooLow.MinimumBid != 120 {
+		t.Fatalf("PlaceBid() below the asking price error = %v, want a minimum of 120", err)
+	}
+	if _, err := bidService.PlaceBid(ctx, "auction-live", "user-1", 120); err != nil {
+		t.Fatalf("PlaceBid() unexpected error: %v", err)
+	}
+
+	detail, _ := svc.GetLot(ctx, "auction-live")
+	if detail.AskingPrice != 121 || detail.LastAction != domain.LiveActionBid {
+		t.Errorf("lot after bid = %+v, want asking 121", detail.LiveLot)
+	}
+
+	// A bid after fair warning reopens the lot
+	svc.FairWarning(ctx, "auction-live")
+	if _, err := bidService.PlaceBid(ctx, "auction-live", "user-2", 121); err != nil {
+		t.Fatalf("PlaceBid() unexpected error: %v", err)
+	}
+	if detail, _ = svc.GetLot(ctx, "auction-live"); detail.State != domain.LiveLotOpen {
+		t.Errorf("lot state = %s, want open", detail.State)
+	}
+}
+
+func TestLiveService_FloorBid(t *testing.T) {
+	svc, bidService, _, publisher := newTestLiveService(t)
+	ctx := context.Background()
+	svc.OpenLot(ctx, "auction-live", "auctioneer-1", 0)
+
+	if _, err := svc.FloorBid(ctx, "auction-live", "clerk-1", " ", 150); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("FloorBid() without paddle error = %v, want %v", err, domain.ErrValidation)
+	}
+	bid, err := svc.FloorBid(ctx, "auction-live", "clerk-1", "42", 150)
+	if err != nil {
+		t.Fatalf("FloorBid() unexpected error: %v", err)
+	}
+	if !bid.IsFloor() || bid.UserID != "" || bid.Paddle != "42" || bid.ClerkID != "clerk-1" {
+		t.Errorf("FloorBid() = %+v, want a floor bid of paddle 42 entered by clerk-1", bid)
+	}
+
+	events := publisher.Events()
+	if bidEvent := events[len(events)-2]; bidEvent.Type != domain.AuctionEventBid || bidEvent.Bidder != "Paddle 42" {
+		t.Errorf("published %+v, want a bid event by Paddle 42", bidEvent)
+	}
+
+	bidService.PlaceBid(ctx, "auction-live", "user-1", 151)
+	page, err := bidService.GetPublicBids(ctx, "auction-live", "user-1", domain.BidQuery{}, "")
+	if err != nil {
+		t.Fatalf("GetPublicBids() unexpected error: %v", err)
+	}
+	sources := map[string]domain.BidSource{}
+	for _, public := range page.Items {
+		sources[public.Bidder] = public.Source
+		if public.Bidder == "Paddle 42" && public.IsMine {
+			t.Error("floor bid IsMine = true, want false")
+		}
+	}
+	if sources["Paddle 42"] != domain.BidSourceFloor || sources["Bidder 1"] != domain.BidSourceOnline {
+		t.Errorf("public bid sources = %v, want the floor and online bids apart", sources)
+	}
+}
+
+func TestLiveService_Sell(t *testing.T) {
+	svc, bidService, _, _ := newTestLiveService(t)
+	ctx := context.Background()
+	svc.OpenLot(ctx, "auction-live", "auctioneer-1", 0)
+
+	if _, err := svc.Sell(ctx, "auction-live", "auctioneer-1"); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Sell() without bids error = %v, want %v", err, domain.ErrValidation)
+	}
+
+	svc.FloorBid(ctx, "auction-live", "clerk-1", "7", 101)
+	detail, err := svc.Sell(ctx, "auction-live", "auctioneer-1")
+	if err != nil {
+		t.Fatalf("Sell() unexpected error: %v", err)
+	}
+	if detail.State != domain.LiveLotSold || detail.Auction.Status != domain.AuctionStatusEnded {
+		t.Errorf("Sell() = %s lot of a %s auction, want sold and ended", detail.State, detail.Auction.Status)
+	}
+
+	if _, err := svc.Ask(ctx, "auction-live", 200); !errors.Is(err, domain.ErrLotClosed) {
+		t.Errorf("Ask() after sale error = %v, want %v", err, domain.ErrLotClosed)
+	}
+	if _, err := bidService.PlaceBid(ctx, "auction-live", "user-1", 200); !errors.Is(err, domain.ErrAuctionClosed) {
+		t.Errorf("PlaceBid() after sale error = %v, want %v", err, domain.ErrAuctionClosed)
+	}
+}
+
+func TestLiveService_Pass(t *testing.T) {
+	svc, _, _, _ := newTestLiveService(t)
+	ctx := context.Background()
+	svc.OpenLot(ctx, "auction-live", "auctioneer-1", 0)
+
+	detail, err := svc.Pass(ctx, "auction-live", "auctioneer-1")
+	if err != nil {
+		t.Fatalf("Pass() unexpected error: %v", err)
+	}
+	if detail.State != domain.LiveLotPassed || detail.Auction.Status != domain.AuctionStatusCancelled {
+		t.Errorf("Pass() = %s lot of a %s auction, want passed and cancelled", detail.State, detail.Auction.Status)
+	}
+}
+
+func TestLiveService_NotClosedByClock(t *testing.T) {
+	svc, bidService, auctionService, _ := newTestLiveService(t)
+	ctx := context.Background()
+	svc.OpenLot(ctx, "auction-live", "auctioneer-1", 0)
+
+	closed, err := auctionService.CloseExpired(ctx, time.Now().Add(48*time.Hour))
+	if err != nil || closed != 0 {
+		t.Fatalf("CloseExpired() = %d, %v; want the live auction left open", closed, err)
+	}
+	auction, _ := auctionService.GetAuction(ctx, "auction-live")
+	auction.EndTime = time.Now().Add(-time.Minute)
+	if _, err := bidService.PlaceBid(ctx, "auction-live", "user-1", 101); err != nil {
+		t.Errorf("PlaceBid() past the end time error = %v, want the bid accepted", err)
+	}
+}
+
+func TestLiveService_OpenLot_ExecutesPreBids(t *testing.T) {
+	svc, bidService, _, _ := newTestLiveService(t)
+	ctx := context.Background()
+	bidService.SetPreBid(ctx, "auction-live", "user-1", 130)
+	bidService.SetPreBid(ctx, "auction-live", "user-2", 110)
+
+	detail, err := svc.OpenLot(ctx, "auction-live", "auctioneer-1", 105)
+	if err != nil {
+		t.Fatalf("OpenLot() unexpected error: %v", err)
+	}
+	if detail.Auction.CurrentPrice != 111 || detail.AskingPrice != 112 {
+		t.Errorf("OpenLot() opened at %v asking %v, want 111 asking 112", detail.Auction.CurrentPrice, detail.AskingPrice)
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// LoginThrottlePolicy configures failed login tracking
+type LoginThrottlePolicy struct {
+	AccountThreshold int           // failures per account before lockout
+	IPThreshold      int           // failures per IP address before lockout
+	LockoutDuration  time.Duration // how long a lockout lasts
+	Window           time.Duration // failures older than this are forgotten
+	BaseDelay        time.Duration // delay after the first failure, doubled for each further failure
+	MaxDelay         time.Duration // upper bound for the progressive delay
+}
+
+// throttleKey is a subject that failed logins are counted against
+type throttleKey struct {
+	key       string
+	threshold int
+	lockEvent domain.SecurityEventType
+}
+
+// LoginThrottle limits password and two-factor guessing with progressive delays
+// and temporary lockouts per account and per client IP address
+type LoginThrottle struct {
+	attemptRepo domain.LoginAttemptRepository
+	eventRepo   domain.SecurityEventRepository
+	policy      LoginThrottlePolicy
+}
+
+func NewLoginThrottle(attemptRepo domain.LoginAttemptRepository, eventRepo domain.SecurityEventRepository, policy LoginThrottlePolicy) *LoginThrottle {
+	return &LoginThrottle{
+		attemptRepo: attemptRepo,
+		eventRepo:   eventRepo,
+		policy:      policy,
+	}
+}
+
+// accountKey tracks password attempts by normalized email, so unknown
+// addresses are throttled exactly like registered ones
+func (t *LoginThrottle) accountKey(email string) throttleKey {
+	return throttleKey{
+		key:       "account:" + strings.ToLower(strings.TrimSpace(email)),
+		threshold: t.policy.AccountThreshold,
+		lockEvent: domain.SecurityEventAccountLocked,
+	}
+}
+
+// twoFactorKey tracks second factor attempts for a user
+func (t *LoginThrottle) twoFactorKey(userID string) throttleKey {
+	return throttleKey{
+		key:       "2fa:" + userID,
+		threshold: t.policy.AccountThreshold,
+		lockEvent: domain.SecurityEventAccountLocked,
+	}
+}
+
+func (t *LoginThrottle) ipKey(ip string) throttleKey {
+	return throttleKey{
+		key:       "ip:" + ip,
+		threshold: t.policy.IPThreshold,
+		lockEvent: domain.SecurityEventIPLocked,
+	}
+}
+
+// Check returns a *domain.LoginThrottledError if any key is locked or still in its delay period
+func (t *LoginThrottle) Check(ctx context.Context, keys ...throttleKey) error {
+	var throttled *domain.LoginThrottledError
+	for _, k := range keys {
+		attempt, err := t.attemptRepo.Get(ctx, k.key)
+		if err != nil {
+			return fmt.Errorf("failed to get login attempts: %w", err)
+		}
+		if attempt == nil {
+			continue
+		}
+
+		// Report the longest wait across all keys
+		if wait := attempt.RetryAfter(); wait > 0 && (throttled == nil || wait > throttled.RetryAfter) {
+			throttled = &domain.LoginThrottledError{RetryAfter: wait, Locked: attempt.IsLocked()}
+		}
+	}
+
+	if throttled != nil {
+		return throttled
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt against each key, applying the
+// progressive delay or a lockout once a key reaches its threshold
+func (t *LoginThrottle) RecordFailure(ctx context.Context, userID, ip string, keys ...throttleKey) error {
+	for _, k := range keys {
+		attempt, err := t.attemptRepo.RecordFailure(ctx, k.key, t.policy.Window)
+		if err != nil {
+			return fmt.Errorf("failed to record login failure: %w", err)
+		}
+
+		now := time.Now()
+		if k.threshold > 0 && attempt.Failures >= k.threshold {
+			lockedUntil := now.Add(t.policy.LockoutDuration)
+			attempt.LockedUntil = &lockedUntil
+			attempt.NextAttemptAt = nil
+
+			event := &domain.SecurityEvent{
+				ID:        uuid.New().String(),
+				Type:      k.lockEvent,
+				UserID:    userID,
+				IPAddress: ip,
+				Details:   fmt.Sprintf("%s locked until %s after %d failed attempts", k.key, lockedUntil.UTC().Format(time.RFC3339), attempt.Failures),
+				CreatedAt: now,
+			}
+			if err := t.eventRepo.Create(ctx, event); err != nil {
+				return fmt.Errorf("failed to record security event: %w", err)
+			}
+		} else {
+			nextAttemptAt := now.Add(t.delay(attempt.Failures))
+			attempt.NextAttemptAt = &nextAttemptAt
+		}
+
+		if err := t.attemptRepo.Save(ctx, attempt); err != nil {
+			return fmt.Errorf("failed to save login attempts: %w", err)
+		}
+	}
+	return nil
+}
+
+// Reset clears the failure history of keys after a successful login
+func (t *LoginThrottle) Reset(ctx context.Context, keys ...throttleKey) error {
+	for _, k := range keys {
+		if err := t.attemptRepo.Delete(ctx, k.key); err != nil {
+			return fmt.Errorf("failed to reset login attempts: %w", err)
+		}
+	}
+	return nil
+}
+
+// delay returns BaseDelay * 2^(failures-1), capped at MaxDelay
+func (t *LoginThrottle) delay(failures int) time.Duration {
+	d := t.policy.BaseDelay
+	for i := 1; i < failures && d < t.policy.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > t.policy.MaxDelay {
+		d = t.policy.MaxDelay
+	}
+	return d
+}
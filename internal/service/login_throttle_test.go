@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+const testClientIP = "203.0.113.7"
+
+// newTestLoginThrottle returns a throttle with lockout thresholds but no delays,
+// so tests can retry immediately after a failure
+func newTestLoginThrottle() *LoginThrottle {
+	throttle, _, _ := newTestLoginThrottleWithPolicy(LoginThrottlePolicy{
+		AccountThreshold: 5,
+		IPThreshold:      20,
+		LockoutDuration:  15 * time.Minute,
+		Window:           15 * time.Minute,
+	})
+	return throttle
+}
+
+func newTestLoginThrottleWithPolicy(policy LoginThrottlePolicy) (*LoginThrottle, *mocks.MockLoginAttemptRepository, *mocks.MockSecurityEventRepository) {
+	attemptRepo := mocks.NewMockLoginAttemptRepository()
+	eventRepo := mocks.NewMockSecurityEventRepository()
+	return NewLoginThrottle(attemptRepo, eventRepo, policy), attemptRepo, eventRepo
+}
+
+func newTestThrottledAuthService(policy LoginThrottlePolicy) (*AuthService, *mocks.MockLoginAttemptRepository, *mocks.MockSecurityEventRepository) {
+	userRepo := mocks.NewMockUserRepository()
+	twoFactor := NewTwoFactorService(mocks.NewMockTwoFactorRepository(), userRepo, "Test")
+	throttle, attemptRepo, eventRepo := newTestLoginThrottleWithPolicy(policy)
+	svc := NewAuthService(userRepo, twoFactor, throttle, "test-secret-key-for-testing", 24)
+	return svc, attemptRepo, eventRepo
+}
+
+func TestLogin_AccountLockout(t *testing.T) {
+	svc, attemptRepo, eventRepo := newTestThrottledAuthService(LoginThrottlePolicy{
+		AccountThreshold: 3,
+		IPThreshold:      100,
+		LockoutDuration:  15 * time.Minute,
+		Window:           15 * time.Minute,
+	})
+	ctx := context.Background()
+	user, _ := svc.Register(ctx, "test@example.com", "password123")
+
+	for i := 0; i < 3; i++ {
+		svc.Login(ctx, "test@example.com", "wrongpassword", testClientIP)
+	}
+
+	// The correct password is rejected while locked
+	_, err := svc.Login(ctx, "test@example.com", "password123", testClientIP)
+	var throttled *domain.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		t.Fatalf("Login() error = %v, want *domain.LoginThrottledError", err)
+	}
+	if !throttled.Locked {
+		t.Error("Login() throttled error is not a lockout")
+	}
+
+	events := eventRepo.Events()
+	if len(events) != 1 || events[0].Type != domain.SecurityEventAccountLocked {
+		t.Fatalf("security events = %+v, want one %q event", events, domain.SecurityEventAccountLocked)
+	}
+	if events[0].UserID != user.ID || events[0].IPAddress != testClientIP {
+		t.Errorf("security event = %+v, want user %q and IP %q", events[0], user.ID, testClientIP)
+	}
+
+	// Once the lockout expires, a correct password works again
+	attemptRepo.Expire("account:test@example.com")
+	if _, err := svc.Login(ctx, "test@example.com", "password123", testClientIP); err != nil {
+		t.Errorf("Login() after lockout expired unexpected error: %v", err)
+	}
+}
+
+func TestLogin_UnknownEmailIsThrottled(t *testing.T) {
+	svc, _, eventRepo := newTestThrottledAuthService(LoginThrottlePolicy{
+		AccountThreshold: 2,
+		IPThreshold:      100,
+		LockoutDuration:  15 * time.Minute,
+		Window:           15 * time.Minute,
+	})
+	ctx := context.Background()
+
+	svc.Login(ctx, "nobody@example.com", "password123", testClientIP)
+	svc.Login(ctx, "NOBODY@example.com", "password123", testClientIP)
+
+	_, err := svc.Login(ctx, "nobody@example.com", "password123", testClientIP)
+	var throttled *domain.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		t.Fatalf("Login() error = %v, want *domain.LoginThrottledError", err)
+	}
+	if events := eventRepo.Events(); len(events) != 1 || events[0].UserID != "" {
+		t.Errorf("security events = %+v, want one event without a user", events)
+	}
+}
+
+func TestLogin_IPLockoutAcrossAccounts(t *testing.T) {
+	svc, _, eventRepo := newTestThrottledAuthService(LoginThrottlePolicy{
+		AccountThreshold: 100,
+		IPThreshold:      3,
+		LockoutDuration:  15 * time.Minute,
+		Window:           15 * time.Minute,
+	})
+	ctx := context.Background()
+	svc.Register(ctx, "victim@example.com", "password123")
+
+	svc.Login(ctx, "a@example.com", "guess", testClientIP)
+	svc.Login(ctx, "b@example.com", "guess", testClientIP)
+	svc.Login(ctx, "c@example.com", "guess", testClientIP)
+
+	var throttled *domain.LoginThrottledError
+	if _, err := svc.Login(ctx, "victim@example.com", "password123", testClientIP); !errors.As(err, &throttled) {
+		t.Fatalf("Login() from locked IP error = %v, want *domain.LoginThrottledError", err)
+	}
+	if _, err := svc.Login(ctx, "victim@example.com", "password123", "198.51.100.1"); err != nil {
+		t.Errorf("Login() from another IP unexpected error: %v", err)
+	}
+	if events := eventRepo.Events(); len(events) != 1 || events[0].Type != domain.SecurityEventIPLocked {
+		t.Errorf("security events = %+v, want one %q event", events, domain.SecurityEventIPLocked)
+	}
+}
+
+func TestLogin_ProgressiveDelay(t *testing.T) {
+	svc, attemptRepo, _ := newTestThrottledAuthService(LoginThrottlePolicy{
+		AccountThreshold: 10,
+		IPThreshold:      100,
+		LockoutDuration:  15 * time.Minute,
+		Window:           15 * time.Minute,
+		BaseDelay:        time.Minute,
+		MaxDelay:         time.Hour,
+	})
+	ctx := context.Background()
+	svc.Register(ctx, "test@example.com", "password123")
+
+	svc.Login(ctx, "test@example.com", "wrongpassword", testClientIP)
+
+	_, err := svc.Login(ctx, "test@example.com", "password123", testClientIP)
+	var throttled *domain.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		t.Fatalf("Login() during delay error = %v, want *domain.LoginThrottledError", err)
+	}
+	if throttled.Locked {
+		t.Error("Login() delay reported as lockout")
+	}
+	if throttled.RetryAfter <= 0 || throttled.RetryAfter > time.Minute {
+		t.Errorf("RetryAfter = %s, want up to 1m", throttled.RetryAfter)
+	}
+
+	attemptRepo.Expire("account:test@example.com")
+	attemptRepo.Expire("ip:" + testClientIP)
+	if _, err := svc.Login(ctx, "test@example.com", "password123", testClientIP); err != nil {
+		t.Errorf("Login() after delay unexpected error: %v", err)
+	}
+}
+
+func TestLoginThrottle_Delay(t *testing.T) {
+	throttle, _, _ := newTestLoginThrottleWithPolicy(LoginThrottlePolicy{
+		BaseDelay: 500 * time.Millisecond,
+		MaxDelay:  3 * time.Second,
+	})
+
+	tests := []struct {
+		failures int
+		expected time.Duration
+	}{
+		{1, 500 * time.Millisecond},
+		{2, time.Second},
+		{3, 2 * time.Second},
+		{4, 3 * time.Second},
+		{20, 3 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := throttle.delay(tt.failures); got != tt.expected {
+			t.Errorf("delay(%d) = %s, want %s", tt.failures, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// DefaultPaymentDue is how long buyers have to pay when no payment term is
+// configured
+const DefaultPaymentDue = 7 * 24 * time.Hour
+
+// OrderService settles closed auctions. Every ended auction with a winning
+// bid gets exactly one order, which its buyer and seller then move through
+// payment, shipping and receipt.
+type OrderService struct {
+	orderRepo      domain.OrderRepository
+	auctionRepo    domain.AuctionRepository
+	bidRepo        domain.BidRepository
+	productRepo    domain.ProductRepository
+	auctionService *AuctionService
+	feeService     *FeeService
+	paymentDue     time.Duration
+}
+
+func NewOrderService(
+	orderRepo domain.OrderRepository,
+	auctionRepo domain.AuctionRepository,
+	bidRepo domain.BidRepository,
+	productRepo domain.ProductRepository,
+	auctionService *AuctionService,
+	feeService *FeeService,
+	paymentDue time.Duration,
+) *OrderService {
+	if paymentDue <= 0 {
+		paymentDue = DefaultPaymentDue
+	}
+	return &OrderService{
+		orderRepo:      orderRepo,
+		auctionRepo:    auctionRepo,
+		bidRepo:        bidRepo,
+		productRepo:    productRepo,
+		auctionService: auctionService,
+		feeService:     feeService,
+		paymentDue:     paymentDue,
+	}
+}
+
+// SettleEnded creates the orders of ended auctions with bids that have none
+// yet and returns how many were created. An auction that fails to settle is
+// skipped until the next call; the failures are returned together.
+func (s *OrderService) SettleEnded(ctx context.Context) (int, error) {
+	auctionIDs, err := s.orderRepo.ListUnsettled(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list unsettled auctions: %w", err)
+	}
+
+	settled := 0
+	var errs []error
+	for _, auctionID := range auctionIDs {
+		order, err := s.Settle(ctx, auctionID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to settle auction %s: %w", auctionID, err))
+			continue
+		}
+		if order != nil {
+			settled++
+		}
+	}
+	return settled, errors.Join(errs...)
+}
+
+// Settle returns the order of an ended auction, creating it from the winning
+// bid the first time. Settling an auction again returns the same order. It
+// returns nil without error if the auction closed without a winning bid, or
+// was won in the sale room, where floor bidders settle with the auction house.
+func (s *OrderService) Settle(ctx context.Context, auctionID string) (*domain.Order, error) {
+	order, err := s.orderRepo.GetByAuction(ctx, auctionID)
+	if err == nil {
+		return order, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	if auction.Status != domain.AuctionStatusEnded {
+		return nil, fmt.Errorf("%w: only ended auctions are settled, this one is %s", domain.ErrValidation, auction.Status)
+	}
+	winning, err := s.bidRepo.GetHighestBid(ctx, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get winning bid: %w", err)
+	}
+	if winning == nil || winning.IsFloor() {
+		return nil, nil
+	}
+	product, err := s.productRepo.GetByID(ctx, auction.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	fees, err := s.feeService.Assess(ctx, product, winning.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	order = &domain.Order{
+		ID:               uuid.New().String(),
+		AuctionID:        auction.ID,
+		ProductID:        product.ID,
+		BidID:            winning.ID,
+		BuyerID:          winning.UserID,
+		SellerID:         product.OwnerID,
+		HammerPrice:      fees.HammerPrice,
+		BuyerPremium:     fees.BuyerPremium,
+		SellerCommission: fees.SellerCommission,
+		Tax:              fees.Tax,
+		Total:            fees.BuyerTotal(),
+		Fees:             fees.Items,
+		Status:           domain.OrderStatusAwaitingPayment,
+		DueAt:            now.Add(s.paymentDue),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		// Another close of the same auction settled it first
+		if errors.Is(err, domain.ErrConflict) {
+			return s.orderRepo.GetByAuction(ctx, auctionID)
+		}
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	return order, nil
+}
+
+// ListOrders returns a page of the orders the user bought or sold, newest
+// first, continuing after cursor if it is set. query.UserID is required.
+func (s *OrderService) ListOrders(ctx context.Context, query domain.OrderQuery, cursor string) (*Page[*domain.Order], error) {
+	switch query.Party {
+	case "", domain.OrderPartyBuyer, domain.OrderPartySeller:
+	default:
+		return nil, fmt.Errorf("%w: role must be %s or %s", domain.ErrValidation, domain.OrderPartyBuyer, domain.OrderPartySeller)
+	}
+	limit, err := pageLimit(query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if query.After, err = domain.DecodeCursor(cursor, domain.SortNewest); err != nil {
+		return nil, err
+	}
+
+	query.Limit = limit + 1
+	orders, err := s.orderRepo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	return newPage(orders, limit, func(o *domain.Order) *domain.Cursor {
+		return domain.NewestCursor(o.CreatedAt, o.ID)
+	}), nil
+}
+
+// GetOrder returns an order the user bought or sold
+func (s *OrderService) GetOrder(ctx context.Context, id, userID string) (*domain.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	// Other users' orders are not revealed
+	if _, ok := order.PartyOf(userID); !ok {
+		return nil, &domain.NotFoundError{Entity: "order", ID: id}
+	}
+	return order, nil
+}
+
+// UpdateStatus moves an order to status to on behalf of its buyer or seller.
+// Completing an order settles its auction.
+func (s *OrderService) UpdateStatus(ctx context.Context, id, userID string, to domain.OrderStatus) (*domain.Order, error) {
+	order, err := s.GetOrder(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	party, _ := order.PartyOf(userID)
+	if err := order.Transition(to, party, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+
+	if to == domain.OrderStatusCompleted {
+		auction, err := s.auctionRepo.GetByID(ctx, order.AuctionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get auction: %w", err)
+		}
+		if err := s.auctionService.transition(ctx, auction, domain.AuctionStatusSettled, "order completed", userID); err != nil {
+			return nil, err
+		}
+		if err := s.auctionService.recordStatusEvent(ctx, domain.AuctionEventStatus, auction); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
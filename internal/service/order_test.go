@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+// newTestOrderServiceFor returns an order service settling the auctions of
+// auctionRepo from the bids of bidService, paid within two days with 10% tax
+// and no fees. Products are sold by "seller-1".
+func newTestOrderServiceFor(auctionRepo *mocks.MockAuctionRepository, auctionService *AuctionService, bidService *BidService) *OrderService {
+	productRepo := newTestProductRepo()
+	feeService := NewFeeService(auctionRepo, productRepo, mocks.NewMockCategoryRepository(), domain.FeeSchedules{}, 10)
+	return NewOrderService(mocks.NewMockOrderRepository(auctionRepo), auctionRepo, bidService.bidRepo, productRepo,
+		auctionService, feeService, 48*time.Hour)
+}
+
+// newTestOrderService returns an order service with the bid and auction
+// services it settles, and an active auction "auction-o" of "product-1"
+// starting at 100
+func newTestOrderService(t *testing.T) (*OrderService, *BidService, *AuctionService) {
+	t.Helper()
+	auctionRepo := mocks.NewMockAuctionRepository()
+	events := newTestEventService()
+	auctionRepo.Create(context.Background(), &domain.Auction{
+		ID:            "auction-o",
+		ProductID:     "product-1",
+		StartTime:     time.Now().Add(-time.Hour),
+		EndTime:       time.Now().Add(time.Hour),
+		StartingPrice: 100,
+		CurrentPrice:  100,
+		Status:        domain.AuctionStatusActive,
+		Version:       1,
+		CreatedAt:     time.Now(),
+	})
+	bidService := newTestBidServiceFor(auctionRepo, events)
+	auctionService := NewAuctionService(auctionRepo, mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(),
+		newTestProductRepo(), mocks.NewMockCategoryRepository(), bidService.bidRepo, mocks.NewMockTransactor(), events)
+	return newTestOrderServiceFor(auctionRepo, auctionService, bidService), bidService, auctionService
+}
+
+func TestOrderService_Settle(t *testing.T) {
+	svc, bidService, auctionService := newTestOrderService(t)
+	ctx := context.Background()
+
+	if _, err := svc.Settle(ctx, "auction-o"); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Settle() of a running auction error = %v, want %v", err, domain.ErrValidation)
+	}
+
+	bidService.PlaceBid(ctx, "auction-o", "user-1", 120)
+	winning, _ := bidService.PlaceBid(ctx, "auction-o", "user-2", 150)
+	if err := auctionService.EndAuction(ctx, "auction-o", AnyVersion, "seller-1"); err != nil {
+		t.Fatalf("EndAuction() unexpected error: %v", err)
+	}
+
+	settled, err := svc.SettleEnded(ctx)
+	if err != nil || settled != 1 {
+		t.Fatalf("SettleEnded() = %d, %v; want 1 order", settled, err)
+	}
+	order, err := svc.Settle(ctx, "auction-o")
+	if err != nil {
+		t.Fatalf("Settle() unexpected error: %v", err)
+	}
+	if order.BidID != winning.ID || order.BuyerID != "user-2" || order.SellerID != "seller-1" || order.ProductID != "product-1" {
+		t.Errorf("order = %+v, want user-2 buying product-1 from seller-1 with the winning bid", order)
+	}
+	if order.HammerPrice != 150 || order.Tax != 15 || order.Total != 165 || order.Status != domain.OrderStatusAwaitingPayment {
+		t.Errorf("order hammer %v, tax %v, total %v, status %s; want 150, 15, 165 awaiting payment",
+			order.HammerPrice, order.Tax, order.Total, order.Status)
+	}
+	if len(order.Fees) != 1 || order.Fees[0].Kind != domain.FeeKindTax || order.Fees[0].Amount != 15 {
+		t.Errorf("order fees = %+v, want the tax line only", order.Fees)
+	}
+	if due := order.DueAt.Sub(order.CreatedAt); due != 48*time.Hour {
+		t.Errorf("payment due after %v, want 48h", due)
+	}
+
+	// Closing again never creates a second order
+	again, err := svc.Settle(ctx, "auction-o")
+	if err != nil || again.ID != order.ID {
+		t.Errorf("second Settle() = %+v, %v; want order %s", again, err, order.ID)
+	}
+	if settled, _ := svc.SettleEnded(ctx); settled != 0 {
+		t.Errorf("second SettleEnded() created %d orders, want 0", settled)
+	}
+}
+
+func TestOrderService_SettleEnded_SkipsFailures(t *testing.T) {
+	svc, bidService, auctionService := newTestOrderService(t)
+	ctx := context.Background()
+
+	// An ended auction whose product is gone cannot be settled
+	svc.auctionRepo.Create(ctx, &domain.Auction{
+		ID:            "auction-broken",
+		ProductID:     "product-missing",
+		StartingPrice: 100,
+		CurrentPrice:  120,
+		BidCount:      1,
+		Status:        domain.AuctionStatusEnded,
+		Version:       1,
+	})
+	bidService.bidRepo.Create(ctx, &domain.Bid{ID: "bid-broken", AuctionID: "auction-broken", UserID: "user-1", Amount: 120})
+
+	bidService.PlaceBid(ctx, "auction-o", "user-2", 150)
+	auctionService.EndAuction(ctx, "auction-o", AnyVersion, "seller-1")
+
+	settled, err := svc.SettleEnded(ctx)
+	if settled != 1 || !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("SettleEnded() = %d, %v; want 1 order and the missing product", settled, err)
+	}
+	if _, err := svc.orderRepo.GetByAuction(ctx, "auction-o"); err != nil {
+		t.Errorf("order of auction-o: %v", err)
+	}
+}
+
+func TestOrderService_Settle_NoBids(t *testing.T) {
+	svc, _, auctionService := newTestOrderService(t)
+	ctx := context.Background()
+
+	auctionService.EndAuction(ctx, "auction-o", AnyVersion, "seller-1")
+	order, err := svc.Settle(ctx, "auction-o")
+	if err != nil || order != nil {
+		t.Errorf("Settle() without bids = %+v, %v; want no order", order, err)
+	}
+}
+
+func TestOrderService_Settle_FloorWinner(t *testing.T) {
+	svc, bidService, auctionService := newTestOrderService(t)
+	ctx := context.Background()
+
+	bidService.PlaceBid(ctx, "auction-o", "user-1", 120)
+	bidService.bidRepo.Create(ctx, &domain.Bid{
+		ID: "bid-floor", AuctionID: "auction-o", Amount: 200, Source: domain.BidSourceFloor, Paddle: "42", CreatedAt: time.Now(),
+	})
+	auctionService.EndAuction(ctx, "auction-o", AnyVersion, "seller-1")
+
+	// Room bidders settle with the auction house
+	order, err := svc.Settle(ctx, "auction-o")
+	if err != nil || order != nil {
+		t.Errorf("Settle() of a lot sold in the room = %+v, %v; want no order", order, err)
+	}
+}
+
+func TestOrderService_UpdateStatus(t *testing.T) {
+	svc, bidService, auctionService := newTestOrderService(t)
+	ctx := context.Background()
+
+	bidService.PlaceBid(ctx, "auction-o", "user-1", 120)
+	auctionService.EndAuction(ctx, "auction-o", AnyVersion, "seller-1")
+	order, _ := svc.Settle(ctx, "auction-o")
+
+	if _, err := svc.UpdateStatus(ctx, order.ID, "user-3", domain.OrderStatusPaid); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdateStatus() by a stranger error = %v, want %v", err, domain.ErrNotFound)
+	}
+	if _, err := svc.UpdateStatus(ctx, order.ID, "user-1", domain.OrderStatusPaid); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("UpdateStatus() paid by the buyer error = %v, want %v", err, domain.ErrForbidden)
+	}
+	if _, err := svc.UpdateStatus(ctx, order.ID, "seller-1", domain.OrderStatusShipped); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("UpdateStatus() shipped before payment error = %v, want %v", err, domain.ErrInvalidTransition)
+	}
+
+	for _, step := range []struct {
+		userID string
+		to     domain.OrderStatus
+	}{
+		{"seller-1", domain.OrderStatusPaid},
+		{"seller-1", domain.OrderStatusShipped},
+		{"user-1", domain.OrderStatusCompleted},
+	} {
+		updated, err := svc.UpdateStatus(ctx, order.ID, step.userID, step.to)
+		if err != nil || updated.Status != step.to {
+			t.Fatalf("UpdateStatus(%s) = %+v, %v", step.to, updated, err)
+		}
+	}
+
+	// Completing the order settles its auction
+	auction, _ := auctionService.GetAuction(ctx, "auction-o")
+	if auction.Status != domain.AuctionStatusSettled {
+		t.Errorf("auction status = %s, want settled", auction.Status)
+	}
+	if _, err := svc.UpdateStatus(ctx, order.ID, "seller-1", domain.OrderStatusCancelled); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("UpdateStatus() cancelling a completed order error = %v, want %v", err, domain.ErrInvalidTransition)
+	}
+}
+
+func TestOrderService_ListOrders(t *testing.T) {
+	svc, bidService, auctionService := newTestOrderService(t)
+	ctx := context.Background()
+
+	bidService.PlaceBid(ctx, "auction-o", "user-1", 120)
+	auctionService.EndAuction(ctx, "auction-o", AnyVersion, "seller-1")
+	svc.Settle(ctx, "auction-o")
+
+	tests := []struct {
+		name  string
+		query domain.OrderQuery
+		want  int
+	}{
+		{"buyer", domain.OrderQuery{UserID: "user-1"}, 1},
+		{"seller", domain.OrderQuery{UserID: "seller-1"}, 1},
+		{"seller as buyer", domain.OrderQuery{UserID: "seller-1", Party: domain.OrderPartyBuyer}, 0},
+		{"by status", domain.OrderQuery{UserID: "user-1", Status: domain.OrderStatusPaid}, 0},
+		{"stranger", domain.OrderQuery{UserID: "user-2"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := svc.ListOrders(ctx, tt.query, "")
+			if err != nil || len(page.Items) != tt.want {
+				t.Errorf("ListOrders() = %d orders, %v; want %d", len(page.Items), err, tt.want)
+			}
+		})
+	}
+
+	if _, err := svc.ListOrders(ctx, domain.OrderQuery{UserID: "user-1", Party: "bidder"}, ""); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("ListOrders() with an unknown role error = %v, want %v", err, domain.ErrValidation)
+	}
+}
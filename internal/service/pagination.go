@@ -0,0 +1,52 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// Page is one page of a list. NextCursor continues the list and is empty on
+// the last page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// pageLimit returns the page size for a requested limit, where zero asks for
+// the default
+func pageLimit(limit int) (int, error) {
+	switch {
+	case limit == 0:
+		return domain.DefaultPageLimit, nil
+	case limit < 0 || limit > domain.MaxPageLimit:
+		return 0, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrValidation, domain.MaxPageLimit)
+	}
+	return limit, nil
+}
+
+// newPage builds a page from items fetched with a limit one above the page
+// size: the extra item only tells that another page follows
+func newPage[T any](items []T, limit int, cursor func(T) *domain.Cursor) *Page[T] {
+	page := &Page[T]{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = cursor(page.Items[limit-1]).Encode()
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	return page
+}
+
+// validateID checks that a filter names a well-formed ID, if any
+func validateID(field, id string) error {
+	if id == "" {
+		return nil
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return fmt.Errorf("%w: %s must be a UUID", domain.ErrValidation, field)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// SetPreBid creates or updates the user's pre-bid on an auction that has not
+// opened yet. Pre-bids are executed as proxy bids when the auction opens, in
+// order of their maximum, the maximum set earliest winning ties.
+func (s *BidService) SetPreBid(ctx context.Context, auctionID, userID string, maxAmount float64) (*domain.PreBid, error) {
+	if err := s.checkBidder(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	if !auction.AcceptsPreBids() {
+		return nil, domain.ErrPreBidsClosed
+	}
+	if !auction.AcceptsAmount(maxAmount) {
+		return nil, &domain.BidTooLowError{CurrentPrice: auction.CurrentPrice, MinimumBid: auction.MinimumBid()}
+	}
+	if err := s.checkTwoFactor(ctx, auction, userID, maxAmount); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	preBid := &domain.PreBid{
+		ID:        uuid.New().String(),
+		AuctionID: auctionID,
+		UserID:    userID,
+		MaxAmount: maxAmount,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.preBidRepo.Save(ctx, preBid); err != nil {
+		return nil, fmt.Errorf("failed to save pre-bid: %w", err)
+	}
+	return preBid, nil
+}
+
+// GetPreBid returns the user's pre-bid on an auction, executed or not
+func (s *BidService) GetPreBid(ctx context.Context, auctionID, userID string) (*domain.PreBid, error) {
+	preBid, err := s.preBidRepo.GetByUser(ctx, auctionID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pre-bid: %w", err)
+	}
+	return preBid, nil
+}
+
+// WithdrawPreBid removes the user's pre-bid from an auction that has not
+// opened yet
+func (s *BidService) WithdrawPreBid(ctx context.Context, auctionID, userID string) error {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return fmt.Errorf("failed to get auction: %w", err)
+	}
+	if !auction.AcceptsPreBids() {
+		return domain.ErrPreBidsClosed
+	}
+	if err := s.preBidRepo.Delete(ctx, auctionID, userID); err != nil {
+		return fmt.Errorf("failed to delete pre-bid: %w", err)
+	}
+	return nil
+}
+
+// ExecutePreBids executes the pending pre-bids of every auction that has
+// opened and returns for how many auctions it did. An auction whose pre-bids
+// fail is skipped until the next call; the failures are returned together.
+func (s *BidService) ExecutePreBids(ctx context.Context) (int, error) {
+	auctionIDs, err := s.preBidRepo.ListDue(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list auctions with pre-bids: %w", err)
+	}
+
+	executed := 0
+	var errs []error
+	for _, auctionID := range auctionIDs {
+		auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+		if err == nil {
+			err = s.openPreBids(ctx, auction)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to execute pre-bids of auction %s: %w", auctionID, err))
+			continue
+		}
+		executed++
+	}
+	return executed, errors.Join(errs...)
+}
+
+// openPreBids executes the pending pre-bids of an opened auction, normally
+// before it takes any other bid. Each becomes a proxy bid dated when its
+// maximum was set, so the strongest pre-bid opens at one increment over the
+// runner-up's maximum, or over the starting price if it is alone; pre-bids
+// still pending once bidding has started defend their maximum from the
+// current price. The pre-bids are claimed and their proxy bids saved in one
+// transaction, so of two concurrent callers only one executes them, and a
+// failure leaves them to be executed again.
+func (s *BidService) openPreBids(ctx context.Context, auction *domain.Auction) error {
+	claimed := false
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		preBids, err := s.preBidRepo.ListPending(ctx, auction.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list pre-bids: %w", err)
+		}
+		if len(preBids) == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		ids := make([]string, 0, len(preBids))
+		for _, preBid := range preBids {
+			proxy := &domain.ProxyBid{
+				ID:        uuid.New().String(),
+				AuctionID: auction.ID,
+				UserID:    preBid.UserID,
+				MaxAmount: preBid.MaxAmount,
+				CreatedAt: preBid.UpdatedAt,
+				UpdatedAt: now,
+			}
+			if err := s.proxyRepo.Save(ctx, proxy); err != nil {
+				return fmt.Errorf("failed to save proxy bid: %w", err)
+			}
+			ids = append(ids, preBid.ID)
+		}
+		if err := s.preBidRepo.MarkExecuted(ctx, auction.ID, ids, now); err != nil {
+			return fmt.Errorf("failed to mark pre-bids executed: %w", err)
+		}
+		claimed = true
+		return nil
+	})
+	if err != nil || !claimed {
+		return err
+	}
+	return s.executeProxyBids(ctx, auction, "")
+}
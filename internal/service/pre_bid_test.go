@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+// newTestBidServiceFor returns a bid service over auctionRepo and events with
+// verified bidders "user-1", "user-2" and "user-3"
+func newTestBidServiceFor(auctionRepo *mocks.MockAuctionRepository, events *EventService) *BidService {
+	userRepo := mocks.NewMockUserRepository()
+	verifiedAt := time.Now()
+	for _, id := range []string{"user-1", "user-2", "user-3"} {
+		userRepo.Create(context.Background(), &domain.User{ID: id, Email: id + "@example.com", EmailVerifiedAt: &verifiedAt})
+	}
+	twoFactor := NewTwoFactorService(mocks.NewMockTwoFactorRepository(), userRepo, "Test")
+	return NewBidService(mocks.NewMockBidRepository(), auctionRepo, userRepo, mocks.NewMockAuctionBidderRepository(),
+		mocks.NewMockProxyBidRepository(), mocks.NewMockPreBidRepository(auctionRepo), mocks.NewMockLiveLotRepository(), mocks.NewMockTransactor(), twoFactor, events)
+}
+
+// newTestPreBidAuction returns a bid and auction service with a pending
+// auction "auction-pre" starting at 100
+func newTestPreBidAuction(t *testing.T) (*BidService, *AuctionService) {
+	t.Helper()
+	auctionRepo := mocks.NewMockAuctionRepository()
+	events := newTestEventService()
+	auctionRepo.Create(context.Background(), &domain.Auction{
+		ID:            "auction-pre",
+		ProductID:     "product-1",
+		StartTime:     time.Now().Add(-time.Minute),
+		EndTime:       time.Now().Add(time.Hour),
+		StartingPrice: 100,
+		CurrentPrice:  100,
+		Status:        domain.AuctionStatusPending,
+		Version:       1,
+		CreatedAt:     time.Now(),
+	})
+	auctionService := NewAuctionService(auctionRepo, mocks.NewMockAuctionStatusChangeRepository(), mocks.NewMockAuctionChangeRepository(), newTestProductRepo(), mocks.NewMockCategoryRepository(), mocks.NewMockBidRepository(), mocks.NewMockTransactor(), events)
+	return newTestBidServiceFor(auctionRepo, events), auctionService
+}
+
+func TestBidService_SetPreBid(t *testing.T) {
+	svc, _ := newTestPreBidAuction(t)
+	ctx := context.Background()
+
+	var tooLow *domain.BidTooLowError
+	if _, err := svc.SetPreBid(ctx, "auction-pre", "user-1", 100); !errors.As(err, &tooLow) {
+		t.Errorf("SetPreBid() at the starting price error = %v, want %T", err, tooLow)
+	}
+
+	first, err := svc.SetPreBid(ctx, "auction-pre", "user-1", 150)
+	if err != nil {
+		t.Fatalf("SetPreBid() unexpected error: %v", err)
+	}
+	edited, err := svc.SetPreBid(ctx, "auction-pre", "user-1", 180)
+	if err != nil {
+		t.Fatalf("SetPreBid() edit unexpected error: %v", err)
+	}
+	if edited.ID != first.ID {
+		t.Errorf("edited pre-bid ID = %s, want %s", edited.ID, first.ID)
+	}
+	got, err := svc.GetPreBid(ctx, "auction-pre", "user-1")
+	if err != nil || got.MaxAmount != 180 {
+		t.Fatalf("GetPreBid() = %+v, %v; want a maximum of 180", got, err)
+	}
+
+	if err := svc.WithdrawPreBid(ctx, "auction-pre", "user-1"); err != nil {
+		t.Fatalf("WithdrawPreBid() unexpected error: %v", err)
+	}
+	if _, err := svc.GetPreBid(ctx, "auction-pre", "user-1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetPreBid() after withdrawal error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
+
+func TestBidService_PreBidsExecutedAtOpening(t *testing.T) {
+	svc, auctionService := newTestPreBidAuction(t)
+	ctx := context.Background()
+
+	svc.SetPreBid(ctx, "auction-pre", "user-1", 150)
+	svc.SetPreBid(ctx, "auction-pre", "user-2", 200)
+	svc.SetPreBid(ctx, "auction-pre", "user-3", 200)
+	auctionService.StartAuction(ctx, "auction-pre", AnyVersion, "admin-1")
+
+	executed, err := svc.ExecutePreBids(ctx)
+	if err != nil || executed != 1 {
+		t.Fatalf("ExecutePreBids() = %d, %v; want 1 auction", executed, err)
+	}
+
+	// The earlier of the two highest maxima wins at their shared maximum
+	auction, _ := auctionService.GetAuction(ctx, "auction-pre")
+	if auction.CurrentPrice != 200 {
+		t.Errorf("opening price = %v, want 200", auction.CurrentPrice)
+	}
+	winning, err := svc.GetWinningBid(ctx, "auction-pre")
+	if err != nil || winning.UserID != "user-2" {
+		t.Fatalf("GetWinningBid() = %+v, %v; want user-2", winning, err)
+	}
+
+	if executed, _ := svc.ExecutePreBids(ctx); executed != 0 {
+		t.Errorf("ExecutePreBids() again executed %d auctions, want 0", executed)
+	}
+	if _, err := svc.SetPreBid(ctx, "auction-pre", "user-1", 300); !errors.Is(err, domain.ErrPreBidsClosed) {
+		t.Errorf("SetPreBid() after opening error = %v, want %v", err, domain.ErrPreBidsClosed)
+	}
+	if err := svc.WithdrawPreBid(ctx, "auction-pre", "user-2"); !errors.Is(err, domain.ErrPreBidsClosed) {
+		t.Errorf("WithdrawPreBid() after opening error = %v, want %v", err, domain.ErrPreBidsClosed)
+	}
+	preBid, _ := svc.GetPreBid(ctx, "auction-pre", "user-2")
+	if preBid.ExecutedAt == nil {
+		t.Error("pre-bid ExecutedAt = nil, want the opening time")
+	}
+}
+
+func TestBidService_PreBidsTieGoesToEarliestMaximum(t *testing.T) {
+	svc, auctionService := newTestPreBidAuction(t)
+	ctx := context.Background()
+
+	// user-2's pre-bid was placed first, but its maximum of 200 was set last
+	svc.SetPreBid(ctx, "auction-pre", "user-2", 120)
+	svc.SetPreBid(ctx, "auction-pre", "user-3", 200)
+	svc.SetPreBid(ctx, "auction-pre", "user-2", 200)
+	auctionService.StartAuction(ctx, "auction-pre", AnyVersion, "admin-1")
+
+	if executed, err := svc.ExecutePreBids(ctx); err != nil || executed != 1 {
+		t.Fatalf("ExecutePreBids() = %d, %v; want 1 auction", executed, err)
+	}
+	winning, err := svc.GetWinningBid(ctx, "auction-pre")
+	if err != nil || winning.UserID != "user-3" || winning.Amount != 200 {
+		t.Errorf("GetWinningBid() = %+v, %v; want user-3 at 200", winning, err)
+	}
+}
+
+func TestBidService_PreBidsBidBeforeFirstOnlineBid(t *testing.T) {
+	svc, auctionService := newTestPreBidAuction(t)
+	ctx := context.Background()
+
+	svc.SetPreBid(ctx, "auction-pre", "user-1", 150)
+	auctionService.StartAuction(ctx, "auction-pre", AnyVersion, "admin-1")
+
+	// The lone pre-bid opens one increment over the starting price, then
+	// defends its maximum against the online bid
+	if _, err := svc.PlaceBid(ctx, "auction-pre", "user-2", 120); err != nil {
+		t.Fatalf("PlaceBid() unexpected error: %v", err)
+	}
+	auction, _ := auctionService.GetAuction(ctx, "auction-pre")
+	winning, _ := svc.GetWinningBid(ctx, "auction-pre")
+	if auction.BidCount != 3 || auction.CurrentPrice != 121 || winning.UserID != "user-1" {
+		t.Errorf("after online bid: %d bids at %v led by %s, want 3 bids at 121 led by user-1",
+			auction.BidCount, auction.CurrentPrice, winning.UserID)
+	}
+}
+
+func TestBidService_PreBidsRetriedAfterFailure(t *testing.T) {
+	svc, auctionService := newTestPreBidAuction(t)
+	ctx := context.Background()
+
+	svc.SetPreBid(ctx, "auction-pre", "user-1", 150)
+	auctionService.StartAuction(ctx, "auction-pre", AnyVersion, "admin-1")
+
+	// Pre-bids whose proxy bids could not be saved stay pending
+	proxyRepo := svc.proxyRepo.(*mocks.MockProxyBidRepository)
+	proxyRepo.SetError(errors.New("connection reset"))
+	if _, err := svc.ExecutePreBids(ctx); err == nil {
+		t.Fatal("ExecutePreBids() with a failing proxy repository succeeded")
+	}
+	if preBid, _ := svc.GetPreBid(ctx, "auction-pre", "user-1"); preBid.ExecutedAt != nil {
+		t.Fatal("pre-bid marked executed without its proxy bid")
+	}
+
+	proxyRepo.SetError(nil)
+	if executed, err := svc.ExecutePreBids(ctx); err != nil || executed != 1 {
+		t.Fatalf("ExecutePreBids() retry = %d, %v; want 1 auction", executed, err)
+	}
+	winning, err := svc.GetWinningBid(ctx, "auction-pre")
+	if err != nil || winning.UserID != "user-1" {
+		t.Errorf("GetWinningBid() = %+v, %v; want user-1", winning, err)
+	}
+}
+
+func TestBidService_PreBidsExecutedAfterOtherBids(t *testing.T) {
+	svc, auctionService := newTestPreBidAuction(t)
+	ctx := context.Background()
+
+	svc.SetPreBid(ctx, "auction-pre", "user-1", 150)
+	auctionService.StartAuction(ctx, "auction-pre", AnyVersion, "admin-1")
+
+	// The auction took a bid before its pre-bids were executed
+	auction, _ := svc.auctionRepo.GetByID(ctx, "auction-pre")
+	auction.CurrentPrice = 120
+	auction.BidCount = 1
+	svc.auctionRepo.Update(ctx, auction)
+
+	if executed, err := svc.ExecutePreBids(ctx); err != nil || executed != 1 {
+		t.Fatalf("ExecutePreBids() = %d, %v; want 1 auction", executed, err)
+	}
+	if executed, _ := svc.ExecutePreBids(ctx); executed != 0 {
+		t.Errorf("ExecutePreBids() again executed %d auctions, want 0", executed)
+	}
+	winning, err := svc.GetWinningBid(ctx, "auction-pre")
+	if err != nil || winning.UserID != "user-1" || winning.Amount != 121 {
+		t.Errorf("GetWinningBid() = %+v, %v; want user-1 at 121", winning, err)
+	}
+}
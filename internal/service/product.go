@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,41 +11,264 @@ import (
 )
 
 type ProductService struct {
-	productRepo domain.ProductRepository
+	productRepo  domain.ProductRepository
+	categoryRepo domain.CategoryRepository
+	auctionRepo  domain.AuctionRepository
 }
 
-func NewProductService(productRepo domain.ProductRepository) *ProductService {
-	return &ProductService{productRepo: productRepo}
+func NewProductService(productRepo domain.ProductRepository, categoryRepo domain.CategoryRepository, auctionRepo domain.AuctionRepository) *ProductService {
+	return &ProductService{productRepo: productRepo, categoryRepo: categoryRepo, auctionRepo: auctionRepo}
 }
 
-func (s *ProductService) CreateProduct(ctx context.Context, name, description, ownerID string) (*domain.Product, error) {
-	product := &domain.Product{
+// ProductDetails holds the optional category, condition and attribute values
+// of a product
+type ProductDetails struct {
+	CategoryID string
+	Condition  domain.ProductCondition
+	Attributes map[string]string
+}
+
+// ProductUpdate holds the product fields to change; nil fields are left
+// untouched. Attributes replaces all attribute values when it is not nil.
+type ProductUpdate struct {
+	Name        *string
+	Description *string
+	CategoryID  *string
+	Condition   *domain.ProductCondition
+	Attributes  map[string]string
+}
+
+// CreateProduct creates a product. Its attributes must satisfy the attribute
+// definitions of its category and the category's ancestors.
+func (s *ProductService) CreateProduct(ctx context.Context, name, description, ownerID string, details ProductDetails) (*domain.Product, error) {
+	product, err := s.newProduct(ctx, name, description, ownerID, details)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.productRepo.Create(ctx, product); err != nil {
+		return nil, fmt.Errorf("failed to create product: %w", err)
+	}
+
+	return product, nil
+}
+
+// newProduct validates a new product without storing it
+func (s *ProductService) newProduct(ctx context.Context, name, description, ownerID string, details ProductDetails) (*domain.Product, error) {
+	if err := s.validateDetails(ctx, details); err != nil {
+		return nil, err
+	}
+	if details.Attributes == nil {
+		details.Attributes = map[string]string{}
+	}
+
+	return &domain.Product{
 		ID:          uuid.New().String(),
 		Name:        name,
 		Description: description,
 		OwnerID:     ownerID,
+		CategoryID:  details.CategoryID,
+		Condition:   details.Condition,
+		Attributes:  details.Attributes,
+		Status:      domain.ProductStatusActive,
+		Version:     1,
 		CreatedAt:   time.Now(),
+	}, nil
+}
+
+func (s *ProductService) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
+	return product, nil
+}
 
-	if err := s.productRepo.Create(ctx, product); err != nil {
-		return nil, fmt.Errorf("failed to create product: %w", err)
+// ListProducts returns a page of the products matching query, newest first,
+// continuing after cursor if it is set. query.Limit is the page size, or zero
+// for the default. query.CategoryIDs also match their subcategories.
+func (s *ProductService) ListProducts(ctx context.Context, query domain.ProductQuery, cursor string) (*Page[*domain.Product], error) {
+	if err := validateID("owner_id", query.OwnerID); err != nil {
+		return nil, err
+	}
+	categoryIDs, err := categorySubtrees(ctx, s.categoryRepo, query.CategoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	query.CategoryIDs = categoryIDs
+	limit, err := pageLimit(query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if query.After, err = domain.DecodeCursor(cursor, domain.SortNewest); err != nil {
+		return nil, err
 	}
 
+	query.Limit = limit + 1
+	products, err := s.productRepo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list products: %w", err)
+	}
+	return newPage(products, limit, func(product *domain.Product) *domain.Cursor {
+		return domain.NewestCursor(product.CreatedAt, product.ID)
+	}), nil
+}
+
+// UpdateProduct applies the owner's edit to a product. Deleted products
+// cannot be edited, nor can products whose running auction already has bids,
+// as bidders relied on what the product said. The attributes are checked
+// against the resulting category.
+func (s *ProductService) UpdateProduct(ctx context.Context, id string, version int, actorID string, update ProductUpdate) (*domain.Product, error) {
+	product, err := s.ownedProductAt(ctx, id, version, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status == domain.ProductStatusDeleted {
+		return nil, fmt.Errorf("%w: it is deleted", domain.ErrProductNotEditable)
+	}
+	auctions, err := s.auctionRepo.List(ctx, domain.AuctionQuery{ProductID: id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auctions: %w", err)
+	}
+	for _, auction := range auctions {
+		running := auction.Status == domain.AuctionStatusActive || auction.Status == domain.AuctionStatusPaused
+		if running && auction.BidCount > 0 {
+			return nil, fmt.Errorf("%w: its auction %s already has bids", domain.ErrProductNotEditable, auction.ID)
+		}
+	}
+
+	edited := *product
+	if update.Name != nil {
+		if *update.Name == "" {
+			return nil, fmt.Errorf("%w: name is required", domain.ErrValidation)
+		}
+		edited.Name = *update.Name
+	}
+	if update.Description != nil {
+		edited.Description = *update.Description
+	}
+	details := ProductDetails{CategoryID: edited.CategoryID, Condition: edited.Condition, Attributes: edited.Attributes}
+	if update.CategoryID != nil {
+		details.CategoryID = *update.CategoryID
+	}
+	if update.Condition != nil {
+		details.Condition = *update.Condition
+	}
+	if update.Attributes != nil {
+		details.Attributes = update.Attributes
+	}
+	if err := s.validateDetails(ctx, details); err != nil {
+		return nil, err
+	}
+	edited.CategoryID, edited.Condition, edited.Attributes = details.CategoryID, details.Condition, details.Attributes
+	if edited.Attributes == nil {
+		edited.Attributes = map[string]string{}
+	}
+
+	*product = edited
+	if err := s.productRepo.Update(ctx, product); err != nil {
+		return nil, fmt.Errorf("failed to update product: %w", err)
+	}
 	return product, nil
 }
 
-func (s *ProductService) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
+// The methods below that change a product take the version the caller last
+// saw and fail with a *domain.ConflictError if the product has changed since;
+// AnyVersion skips the check. Products with an auction that has not finished
+// cannot be archived or deleted; cancel the auction first.
+
+// ArchiveProduct withdraws an active product from lists and search
+func (s *ProductService) ArchiveProduct(ctx context.Context, id string, version int, actorID string) (*domain.Product, error) {
+	return s.changeStatus(ctx, id, version, actorID, domain.ProductStatusArchived)
+}
+
+// DeleteProduct deletes an active or archived product. It is only marked as
+// deleted, so it can be restored and its past auctions still resolve it.
+func (s *ProductService) DeleteProduct(ctx context.Context, id string, version int, actorID string) (*domain.Product, error) {
+	return s.changeStatus(ctx, id, version, actorID, domain.ProductStatusDeleted)
+}
+
+// RestoreProduct makes an archived or deleted product active again
+func (s *ProductService) RestoreProduct(ctx context.Context, id string, version int, actorID string) (*domain.Product, error) {
+	return s.changeStatus(ctx, id, version, actorID, domain.ProductStatusActive)
+}
+
+func (s *ProductService) changeStatus(ctx context.Context, id string, version int, actorID string, to domain.ProductStatus) (*domain.Product, error) {
+	product, err := s.ownedProductAt(ctx, id, version, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !product.Status.CanChangeTo(to) {
+		return nil, fmt.Errorf("%w: it is %s", domain.ErrProductNotEditable, product.Status)
+	}
+	if to != domain.ProductStatusActive {
+		if err := s.checkNoOpenAuction(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	product.Status = to
+	if err := s.productRepo.Update(ctx, product); err != nil {
+		return nil, fmt.Errorf("failed to update product: %w", err)
+	}
+	return product, nil
+}
+
+// ownedProductAt returns the product if actorID owns it, failing with a
+// *domain.ConflictError if version is set and the product is no longer at
+// that version
+func (s *ProductService) ownedProductAt(ctx context.Context, id string, version int, actorID string) (*domain.Product, error) {
 	product, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
+	if product.OwnerID != actorID {
+		return nil, domain.ErrNotProductOwner
+	}
+	if version != AnyVersion && product.Version != version {
+		return nil, &domain.ConflictError{Entity: "product", ID: id, Version: version}
+	}
 	return product, nil
 }
 
-func (s *ProductService) ListProducts(ctx context.Context) ([]*domain.Product, error) {
-	products, err := s.productRepo.List(ctx)
+// checkNoOpenAuction fails if the product has an auction that has not
+// finished
+func (s *ProductService) checkNoOpenAuction(ctx context.Context, id string) error {
+	auctions, err := s.auctionRepo.List(ctx, domain.AuctionQuery{ProductID: id})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list products: %w", err)
+		return fmt.Errorf("failed to list auctions: %w", err)
+	}
+	for _, auction := range auctions {
+		switch auction.Status {
+		case domain.AuctionStatusPending, domain.AuctionStatusScheduled, domain.AuctionStatusActive, domain.AuctionStatusPaused:
+			return fmt.Errorf("%w: its auction %s is %s", domain.ErrProductNotEditable, auction.ID, auction.Status)
+		}
+	}
+	return nil
+}
+
+// validateDetails checks the condition, and the attributes against the
+// category's attribute definitions
+func (s *ProductService) validateDetails(ctx context.Context, details ProductDetails) error {
+	if details.Condition != "" && !details.Condition.Valid() {
+		return fmt.Errorf("%w: unknown condition %q", domain.ErrValidation, details.Condition)
+	}
+	if details.CategoryID == "" {
+		if len(details.Attributes) > 0 {
+			return fmt.Errorf("%w: attributes require a category", domain.ErrValidation)
+		}
+		return nil
+	}
+
+	if err := validateID("category_id", details.CategoryID); err != nil {
+		return err
+	}
+	path, err := categoryPath(ctx, s.categoryRepo, details.CategoryID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("%w: category %s does not exist", domain.ErrValidation, details.CategoryID)
+	}
+	if err != nil {
+		return err
 	}
-	return products, nil
+	return domain.ValidateAttributes(domain.CategoryAttributes(path), details.Attributes)
 }
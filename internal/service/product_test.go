@@ -2,15 +2,17 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/saigenix/bidding-system/internal/domain"
 	"github.com/saigenix/bidding-system/internal/mocks"
 )
 
 func newTestProductService() (*ProductService, *mocks.MockProductRepository) {
 	repo := mocks.NewMockProductRepository()
-	svc := NewProductService(repo)
+	svc := NewProductService(repo, mocks.NewMockCategoryRepository(), mocks.NewMockAuctionRepository())
 	return svc, repo
 }
 
@@ -21,7 +23,7 @@ func newTestProductService() (*ProductService, *mocks.MockProductRepository) {
 func TestProductService_CreateProduct_Success(t *testing.T) {
 	svc, _ := newTestProductService()
 
-	product, err := svc.CreateProduct(context.Background(), "Laptop", "Gaming laptop", "owner-123")
+	product, err := svc.CreateProduct(context.Background(), "Laptop", "Gaming laptop", "owner-123", ProductDetails{})
 	if err != nil {
 		t.Fatalf("CreateProduct() unexpected error: %v", err)
 	}
@@ -42,11 +44,48 @@ func TestProductService_CreateProduct_Success(t *testing.T) {
 	}
 }
 
+func TestProductService_CreateProduct_ValidatesDetails(t *testing.T) {
+	categoryRepo := mocks.NewMockCategoryRepository()
+	_, laptops := newTestCategoryTree(t, NewCategoryService(categoryRepo, mocks.NewMockProductRepository()))
+	svc := NewProductService(mocks.NewMockProductRepository(), categoryRepo, mocks.NewMockAuctionRepository())
+
+	product, err := svc.CreateProduct(context.Background(), "Laptop", "", "owner-123", ProductDetails{
+		CategoryID: laptops.ID,
+		Condition:  domain.ConditionRefurbished,
+		Attributes: map[string]string{"brand": "Acme", "ram_gb": "16"},
+	})
+	if err != nil {
+		t.Fatalf("CreateProduct() unexpected error: %v", err)
+	}
+	if product.CategoryID != laptops.ID || product.Attributes["brand"] != "Acme" {
+		t.Errorf("CreateProduct() = %+v, want the category and attributes set", product)
+	}
+
+	tests := []struct {
+		name    string
+		details ProductDetails
+	}{
+		{"unknown condition", ProductDetails{Condition: "broken"}},
+		{"attributes without category", ProductDetails{Attributes: map[string]string{"brand": "Acme"}}},
+		{"unknown category", ProductDetails{CategoryID: "550e8400-e29b-41d4-a716-446655440000"}},
+		{"missing inherited attribute", ProductDetails{CategoryID: laptops.ID, Attributes: map[string]string{"ram_gb": "16"}}},
+		{"invalid attribute", ProductDetails{CategoryID: laptops.ID, Attributes: map[string]string{"brand": "Acme", "ram_gb": "lots"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateProduct(context.Background(), "Laptop", "", "owner-123", tt.details)
+			if !errors.Is(err, domain.ErrValidation) {
+				t.Errorf("CreateProduct() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
 func TestProductService_CreateProduct_RepoError(t *testing.T) {
 	svc, repo := newTestProductService()
 	repo.SetError(fmt.Errorf("database error"))
 
-	_, err := svc.CreateProduct(context.Background(), "Laptop", "Gaming laptop", "owner-123")
+	_, err := svc.CreateProduct(context.Background(), "Laptop", "Gaming laptop", "owner-123", ProductDetails{})
 	if err == nil {
 		t.Error("CreateProduct() expected error, got nil")
 	}
@@ -59,7 +98,7 @@ func TestProductService_CreateProduct_RepoError(t *testing.T) {
 func TestProductService_GetProduct_Success(t *testing.T) {
 	svc, _ := newTestProductService()
 
-	created, _ := svc.CreateProduct(context.Background(), "Laptop", "Gaming laptop", "owner-123")
+	created, _ := svc.CreateProduct(context.Background(), "Laptop", "Gaming laptop", "owner-123", ProductDetails{})
 
 	product, err := svc.GetProduct(context.Background(), created.ID)
 	if err != nil {
@@ -86,26 +125,240 @@ func TestProductService_GetProduct_NotFound(t *testing.T) {
 func TestProductService_ListProducts_Empty(t *testing.T) {
 	svc, _ := newTestProductService()
 
-	products, err := svc.ListProducts(context.Background())
+	page, err := svc.ListProducts(context.Background(), domain.ProductQuery{}, "")
 	if err != nil {
 		t.Fatalf("ListProducts() unexpected error: %v", err)
 	}
-	if len(products) != 0 {
-		t.Errorf("ListProducts() returned %d products, want 0", len(products))
+	if len(page.Items) != 0 {
+		t.Errorf("ListProducts() returned %d products, want 0", len(page.Items))
 	}
 }
 
 func TestProductService_ListProducts_Multiple(t *testing.T) {
 	svc, _ := newTestProductService()
 
-	svc.CreateProduct(context.Background(), "Laptop", "Gaming laptop", "owner-1")
-	svc.CreateProduct(context.Background(), "Phone", "Smartphone", "owner-2")
+	svc.CreateProduct(context.Background(), "Laptop", "Gaming laptop", "owner-1", ProductDetails{})
+	svc.CreateProduct(context.Background(), "Phone", "Smartphone", "owner-2", ProductDetails{})
+
+	page, err := svc.ListProducts(context.Background(), domain.ProductQuery{}, "")
+	if err != nil {
+		t.Fatalf("ListProducts() unexpected error: %v", err)
+	}
+	if len(page.Items) != 2 {
+		t.Errorf("ListProducts() returned %d products, want 2", len(page.Items))
+	}
+}
+
+func TestProductService_ListProducts_Pages(t *testing.T) {
+	svc, _ := newTestProductService()
+	ownerID := "00000000-0000-0000-0000-0000000000aa"
+	for _, name := range []string{"Laptop", "Phone", "Tablet"} {
+		svc.CreateProduct(context.Background(), name, "", ownerID, ProductDetails{})
+	}
+	svc.CreateProduct(context.Background(), "Camera", "", "00000000-0000-0000-0000-0000000000bb", ProductDetails{})
 
-	products, err := svc.ListProducts(context.Background())
+	query := domain.ProductQuery{OwnerID: ownerID, Limit: 2}
+	first, err := svc.ListProducts(context.Background(), query, "")
+	if err != nil {
+		t.Fatalf("ListProducts() unexpected error: %v", err)
+	}
+	if len(first.Items) != 2 || first.NextCursor == "" {
+		t.Fatalf("ListProducts() first page = %d products, cursor %q; want 2 and a cursor", len(first.Items), first.NextCursor)
+	}
+	second, err := svc.ListProducts(context.Background(), query, first.NextCursor)
 	if err != nil {
 		t.Fatalf("ListProducts() unexpected error: %v", err)
 	}
-	if len(products) != 2 {
-		t.Errorf("ListProducts() returned %d products, want 2", len(products))
+	if len(second.Items) != 1 || second.NextCursor != "" {
+		t.Fatalf("ListProducts() second page = %d products, cursor %q; want 1 and no cursor", len(second.Items), second.NextCursor)
+	}
+
+	seen := map[string]bool{}
+	for _, product := range append(first.Items, second.Items...) {
+		if product.OwnerID != ownerID || seen[product.ID] {
+			t.Errorf("ListProducts() returned %q twice or of another owner", product.Name)
+		}
+		seen[product.ID] = true
+	}
+}
+
+func TestProductService_ListProducts_CategoryAndAttributes(t *testing.T) {
+	categoryRepo := mocks.NewMockCategoryRepository()
+	electronics, laptops := newTestCategoryTree(t, NewCategoryService(categoryRepo, mocks.NewMockProductRepository()))
+	svc := NewProductService(mocks.NewMockProductRepository(), categoryRepo, mocks.NewMockAuctionRepository())
+	ctx := context.Background()
+
+	svc.CreateProduct(ctx, "Laptop", "", "owner-1", ProductDetails{CategoryID: laptops.ID, Attributes: map[string]string{"brand": "Acme", "ram_gb": "16"}})
+	svc.CreateProduct(ctx, "Radio", "", "owner-1", ProductDetails{CategoryID: electronics.ID, Attributes: map[string]string{"brand": "Other"}})
+	svc.CreateProduct(ctx, "Chair", "", "owner-1", ProductDetails{})
+
+	page, err := svc.ListProducts(ctx, domain.ProductQuery{CategoryIDs: []string{electronics.ID}}, "")
+	if err != nil {
+		t.Fatalf("ListProducts() unexpected error: %v", err)
+	}
+	if len(page.Items) != 2 {
+		t.Errorf("ListProducts(Electronics) returned %d products, want 2 including the subcategory's", len(page.Items))
+	}
+
+	page, err = svc.ListProducts(ctx, domain.ProductQuery{CategoryIDs: []string{electronics.ID}, Attributes: map[string]string{"brand": "Acme"}}, "")
+	if err != nil {
+		t.Fatalf("ListProducts() unexpected error: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Name != "Laptop" {
+		t.Errorf("ListProducts(brand=Acme) returned %d products, want only the laptop", len(page.Items))
+	}
+}
+
+// ============================================================================
+// UpdateProduct
+// ============================================================================
+
+// newTestProductLifecycle returns a service holding one product of owner-1
+func newTestProductLifecycle(t *testing.T) (*ProductService, *mocks.MockAuctionRepository, *domain.Product) {
+	t.Helper()
+	auctionRepo := mocks.NewMockAuctionRepository()
+	svc := NewProductService(mocks.NewMockProductRepository(), mocks.NewMockCategoryRepository(), auctionRepo)
+	product, err := svc.CreateProduct(context.Background(), "Laptop", "Gaming laptop", "owner-1", ProductDetails{Condition: domain.ConditionUsed})
+	if err != nil {
+		t.Fatalf("CreateProduct() unexpected error: %v", err)
+	}
+	return svc, auctionRepo, product
+}
+
+func TestProductService_UpdateProduct_Success(t *testing.T) {
+	svc, _, product := newTestProductLifecycle(t)
+	name := "Gaming Laptop"
+
+	updated, err := svc.UpdateProduct(context.Background(), product.ID, 1, "owner-1", ProductUpdate{Name: &name})
+	if err != nil {
+		t.Fatalf("UpdateProduct() unexpected error: %v", err)
+	}
+	if updated.Name != name || updated.Description != "Gaming laptop" || updated.Condition != domain.ConditionUsed {
+		t.Errorf("UpdateProduct() = %q, %q, %q; want only the name changed", updated.Name, updated.Description, updated.Condition)
+	}
+	if updated.Version != 2 {
+		t.Errorf("UpdateProduct() version = %d, want 2", updated.Version)
+	}
+}
+
+func TestProductService_UpdateProduct_Rejected(t *testing.T) {
+	svc, _, product := newTestProductLifecycle(t)
+	empty := ""
+	condition := domain.ProductCondition("mint")
+
+	tests := []struct {
+		name    string
+		version int
+		actorID string
+		update  ProductUpdate
+		wantErr error
+	}{
+		{"not the owner", AnyVersion, "someone-else", ProductUpdate{Description: &empty}, domain.ErrNotProductOwner},
+		{"stale version", 7, "owner-1", ProductUpdate{Description: &empty}, domain.ErrConflict},
+		{"empty name", AnyVersion, "owner-1", ProductUpdate{Name: &empty}, domain.ErrValidation},
+		{"unknown condition", AnyVersion, "owner-1", ProductUpdate{Condition: &condition}, domain.ErrValidation},
+		{"attributes without category", AnyVersion, "owner-1", ProductUpdate{Attributes: map[string]string{"brand": "Acme"}}, domain.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.UpdateProduct(context.Background(), product.ID, tt.version, tt.actorID, tt.update)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("UpdateProduct() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProductService_UpdateProduct_AuctionWithBids(t *testing.T) {
+	svc, auctionRepo, product := newTestProductLifecycle(t)
+	ctx := context.Background()
+	description := "Barely used"
+
+	auction := &domain.Auction{ID: "auction-1", ProductID: product.ID, Status: domain.AuctionStatusActive, Version: 1}
+	auctionRepo.Create(ctx, auction)
+	if _, err := svc.UpdateProduct(ctx, product.ID, AnyVersion, "owner-1", ProductUpdate{Description: &description}); err != nil {
+		t.Fatalf("UpdateProduct() before the first bid unexpected error: %v", err)
+	}
+
+	auction.BidCount = 1
+	if _, err := svc.UpdateProduct(ctx, product.ID, AnyVersion, "owner-1", ProductUpdate{Description: &description}); !errors.Is(err, domain.ErrProductNotEditable) {
+		t.Errorf("UpdateProduct() with bids error = %v, want %v", err, domain.ErrProductNotEditable)
+	}
+
+	auction.Status = domain.AuctionStatusEnded
+	if _, err := svc.UpdateProduct(ctx, product.ID, AnyVersion, "owner-1", ProductUpdate{Description: &description}); err != nil {
+		t.Errorf("UpdateProduct() after the auction ended unexpected error: %v", err)
+	}
+}
+
+// ============================================================================
+// ArchiveProduct / DeleteProduct / RestoreProduct
+// ============================================================================
+
+func TestProductService_ArchiveDeleteRestore(t *testing.T) {
+	svc, _, product := newTestProductLifecycle(t)
+	ctx := context.Background()
+	listed := func(query domain.ProductQuery) bool {
+		t.Helper()
+		page, err := svc.ListProducts(ctx, query, "")
+		if err != nil {
+			t.Fatalf("ListProducts() unexpected error: %v", err)
+		}
+		return len(page.Items) == 1
+	}
+
+	if _, err := svc.ArchiveProduct(ctx, product.ID, 1, "owner-1"); err != nil {
+		t.Fatalf("ArchiveProduct() unexpected error: %v", err)
+	}
+	if listed(domain.ProductQuery{}) || !listed(domain.ProductQuery{IncludeArchived: true}) {
+		t.Error("ListProducts() should only list the archived product with IncludeArchived")
+	}
+	if _, err := svc.ArchiveProduct(ctx, product.ID, AnyVersion, "owner-1"); !errors.Is(err, domain.ErrProductNotEditable) {
+		t.Errorf("ArchiveProduct() twice error = %v, want %v", err, domain.ErrProductNotEditable)
+	}
+
+	if _, err := svc.DeleteProduct(ctx, product.ID, 2, "owner-1"); err != nil {
+		t.Fatalf("DeleteProduct() unexpected error: %v", err)
+	}
+	if listed(domain.ProductQuery{IncludeArchived: true}) {
+		t.Error("ListProducts() listed a deleted product")
+	}
+	if got, err := svc.GetProduct(ctx, product.ID); err != nil || got.Status != domain.ProductStatusDeleted {
+		t.Errorf("GetProduct() of a deleted product = %v, %v; want it marked deleted", got, err)
+	}
+	description := "Restored"
+	if _, err := svc.UpdateProduct(ctx, product.ID, AnyVersion, "owner-1", ProductUpdate{Description: &description}); !errors.Is(err, domain.ErrProductNotEditable) {
+		t.Errorf("UpdateProduct() of a deleted product error = %v, want %v", err, domain.ErrProductNotEditable)
+	}
+
+	restored, err := svc.RestoreProduct(ctx, product.ID, 3, "owner-1")
+	if err != nil {
+		t.Fatalf("RestoreProduct() unexpected error: %v", err)
+	}
+	if restored.Status != domain.ProductStatusActive || !listed(domain.ProductQuery{}) {
+		t.Errorf("RestoreProduct() status = %s, want the product active and listed", restored.Status)
+	}
+}
+
+func TestProductService_ArchiveProduct_Rejected(t *testing.T) {
+	svc, auctionRepo, product := newTestProductLifecycle(t)
+	ctx := context.Background()
+
+	if _, err := svc.DeleteProduct(ctx, product.ID, AnyVersion, "someone-else"); !errors.Is(err, domain.ErrNotProductOwner) {
+		t.Errorf("DeleteProduct() by another user error = %v, want %v", err, domain.ErrNotProductOwner)
+	}
+	if _, err := svc.RestoreProduct(ctx, product.ID, AnyVersion, "owner-1"); !errors.Is(err, domain.ErrProductNotEditable) {
+		t.Errorf("RestoreProduct() of an active product error = %v, want %v", err, domain.ErrProductNotEditable)
+	}
+
+	auction := &domain.Auction{ID: "auction-1", ProductID: product.ID, Status: domain.AuctionStatusScheduled, Version: 1}
+	auctionRepo.Create(ctx, auction)
+	if _, err := svc.ArchiveProduct(ctx, product.ID, AnyVersion, "owner-1"); !errors.Is(err, domain.ErrProductNotEditable) {
+		t.Errorf("ArchiveProduct() with a scheduled auction error = %v, want %v", err, domain.ErrProductNotEditable)
+	}
+
+	auction.Status = domain.AuctionStatusCancelled
+	if _, err := svc.DeleteProduct(ctx, product.ID, AnyVersion, "owner-1"); err != nil {
+		t.Errorf("DeleteProduct() after the auction was cancelled unexpected error: %v", err)
 	}
 }
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// RetractionService handles bidders' requests to withdraw mistaken bids. A
+// request only takes effect once an admin approves it: the bid is then marked
+// retracted, the bidder's proxy bid is dropped, and the auction's price is
+// recomputed from the remaining bids.
+type RetractionService struct {
+	retractionRepo domain.RetractionRepository
+	bidRepo        domain.BidRepository
+	auctionRepo    domain.AuctionRepository
+	userRepo       domain.UserRepository
+	proxyRepo      domain.ProxyBidRepository
+	transactor     domain.Transactor
+	bidService     *BidService
+	events         *EventService
+}
+
+func NewRetractionService(
+	retractionRepo domain.RetractionRepository,
+	bidRepo domain.BidRepository,
+	auctionRepo domain.AuctionRepository,
+	userRepo domain.UserRepository,
+	proxyRepo domain.ProxyBidRepository,
+	transactor domain.Transactor,
+	bidService *BidService,
+	events *EventService,
+) *RetractionService {
+	return &RetractionService{
+		retractionRepo: retractionRepo,
+		bidRepo:        bidRepo,
+		auctionRepo:    auctionRepo,
+		userRepo:       userRepo,
+		proxyRepo:      proxyRepo,
+		transactor:     transactor,
+		bidService:     bidService,
+		events:         events,
+	}
+}
+
+// RequestRetraction asks to retract one of the user's bids for reason. Bids
+// can only be retracted while their auction runs, and not in its final hour.
+func (s *RetractionService) RequestRetraction(ctx context.Context, userID, bidID string, reason domain.RetractionReason, note string) (*domain.BidRetraction, error) {
+	if !reason.IsValid() {
+		return nil, fmt.Errorf("%w: reason must be %s, %s or %s", domain.ErrValidation,
+			domain.RetractionReasonWrongAmount, domain.RetractionReasonDescriptionChanged, domain.RetractionReasonSellerUnreachable)
+	}
+
+	bid, err := s.bidRepo.GetByID(ctx, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bid: %w", err)
+	}
+	// Other users' bids are not revealed
+	if bid.UserID != userID {
+		return nil, &domain.NotFoundError{Entity: "bid", ID: bidID}
+	}
+	if bid.IsRetracted() {
+		return nil, fmt.Errorf("%w: the bid has already been retracted", domain.ErrRetractionNotAllowed)
+	}
+
+	auction, err := s.auctionRepo.GetByID(ctx, bid.AuctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	now := time.Now()
+	if err := auction.CheckRetractable(now); err != nil {
+		return nil, err
+	}
+
+	retraction := &domain.BidRetraction{
+		ID:        uuid.New().String(),
+		BidID:     bid.ID,
+		AuctionID: bid.AuctionID,
+		UserID:    userID,
+		Reason:    reason,
+		Note:      strings.TrimSpace(note),
+		Status:    domain.RetractionStatusPending,
+		CreatedAt: now,
+	}
+	if err := s.retractionRepo.Create(ctx, retraction); err != nil {
+		return nil, fmt.Errorf("failed to create bid retraction: %w", err)
+	}
+	return retraction, nil
+}
+
+// ListMine returns the user's retraction requests, newest first
+func (s *RetractionService) ListMine(ctx context.Context, userID string) ([]*domain.BidRetraction, error) {
+	retractions, err := s.retractionRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bid retractions: %w", err)
+	}
+	return retractions, nil
+}
+
+// ListPending returns the retraction requests awaiting review, oldest first
+func (s *RetractionService) ListPending(ctx context.Context) ([]*domain.BidRetraction, error) {
+	retractions, err := s.retractionRepo.ListPending(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bid retractions: %w", err)
+	}
+	return retractions, nil
+}
+
+// Approve retracts the bid of a pending request, as long as bids on its
+// auction can still be retracted. The bid stays in the history marked
+// retracted, the bidder's proxy bid is dropped, the price falls back to the
+// highest remaining bid (or the starting price), and the retraction counts
+// against the bidder's record. All of that is saved together with the review,
+// so a request is approved exactly once and, if anything fails, stays pending
+// to be approved again. Proxy bidders then respond to the new price, unless
+// the auction is paused.
+func (s *RetractionService) Approve(ctx context.Context, id, reviewerID, note string) (*domain.BidRetraction, error) {
+	retraction, err := s.retractionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bid retraction: %w", err)
+	}
+	auction, err := s.auctionRepo.GetByID(ctx, retraction.AuctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	now := time.Now()
+	if err := auction.CheckRetractable(now); err != nil {
+		return nil, err
+	}
+	bid, err := s.bidRepo.GetByID(ctx, retraction.BidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bid: %w", err)
+	}
+
+	// The request, bid and auction are only changed once everything is saved
+	reviewed := *retraction
+	if err := reviewed.Review(true, reviewerID, strings.TrimSpace(note), now); err != nil {
+		return nil, err
+	}
+	updated := *auction
+	leaderID := ""
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.retractionRepo.Update(ctx, &reviewed); err != nil {
+			return fmt.Errorf("failed to update bid retraction: %w", err)
+		}
+		if err := s.bidRepo.Retract(ctx, bid.ID, now); err != nil {
+			return fmt.Errorf("failed to retract bid: %w", err)
+		}
+		if err := s.proxyRepo.Delete(ctx, auction.ID, bid.UserID); err != nil {
+			return fmt.Errorf("failed to delete proxy bid: %w", err)
+		}
+		if leaderID, err = s.recomputePrice(ctx, &updated); err != nil {
+			return err
+		}
+		if err := s.userRepo.IncrementBidRetractions(ctx, bid.UserID); err != nil {
+			return fmt.Errorf("failed to count bid retraction: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	bid.RetractedAt = &now
+
+	if err := s.recordRetractedEvent(ctx, &updated, bid, now); err != nil {
+		return nil, err
+	}
+	if updated.Status == domain.AuctionStatusActive {
+		if err := s.bidService.executeProxyBids(ctx, &updated, leaderID); err != nil {
+			return nil, err
+		}
+	}
+	return &reviewed, nil
+}
+
+// Reject turns down a pending request, leaving the bid standing
+func (s *RetractionService) Reject(ctx context.Context, id, reviewerID, note string) (*domain.BidRetraction, error) {
+	retraction, err := s.retractionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bid retraction: %w", err)
+	}
+	if err := retraction.Review(false, reviewerID, strings.TrimSpace(note), time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.retractionRepo.Update(ctx, retraction); err != nil {
+		return nil, fmt.Errorf("failed to update bid retraction: %w", err)
+	}
+	return retraction, nil
+}
+
+// recomputePrice sets the auction's price to its highest remaining bid, or
+// its starting price without one, and returns that bid's user
+func (s *RetractionService) recomputePrice(ctx context.Context, auction *domain.Auction) (string, error) {
+	highest, err := s.bidRepo.GetHighestBid(ctx, auction.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get highest bid: %w", err)
+	}
+
+	leaderID := ""
+	auction.CurrentPrice = auction.StartingPrice
+	if highest != nil {
+		auction.CurrentPrice = highest.Amount
+		leaderID = highest.UserID
+	}
+	if auction.BidCount > 0 {
+		auction.BidCount--
+	}
+	if err := s.auctionRepo.Update(ctx, auction); err != nil {
+		return "", fmt.Errorf("failed to update auction: %w", err)
+	}
+	return leaderID, nil
+}
+
+// recordRetractedEvent announces the retracted bid and the auction's new price
+func (s *RetractionService) recordRetractedEvent(ctx context.Context, auction *domain.Auction, bid *domain.Bid, now time.Time) error {
+	bidder, err := s.bidService.bidderName(ctx, bid)
+	if err != nil {
+		return err
+	}
+	snapshot := *auction
+	return s.events.Record(ctx, &domain.AuctionEvent{
+		AuctionID: auction.ID,
+		Type:      domain.AuctionEventRetracted,
+		Bid:       bid,
+		Bidder:    bidder,
+		Auction:   &snapshot,
+		CreatedAt: now,
+	})
+}
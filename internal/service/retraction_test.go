@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+// newTestRetractionService returns a retraction service and the bid service it
+// shares repositories with, with an active auction "auction-r" starting at 100
+// that ends in three hours
+func newTestRetractionService(t *testing.T) (*RetractionService, *BidService, *mocks.MockAuctionRepository) {
+	t.Helper()
+	auctionRepo := mocks.NewMockAuctionRepository()
+	auctionRepo.Create(context.Background(), &domain.Auction{
+		ID:            "auction-r",
+		ProductID:     "product-1",
+		StartTime:     time.Now().Add(-time.Hour),
+		EndTime:       time.Now().Add(3 * time.Hour),
+		StartingPrice: 100,
+		CurrentPrice:  100,
+		Status:        domain.AuctionStatusActive,
+		Version:       1,
+		CreatedAt:     time.Now(),
+	})
+	bidService := newTestBidServiceFor(auctionRepo, newTestEventService())
+	svc := NewRetractionService(mocks.NewMockRetractionRepository(), bidService.bidRepo, auctionRepo,
+		bidService.userRepo, bidService.proxyRepo, mocks.NewMockTransactor(), bidService, bidService.events)
+	return svc, bidService, auctionRepo
+}
+
+func TestRetractionService_RequestRetraction(t *testing.T) {
+	svc, bidService, auctionRepo := newTestRetractionService(t)
+	ctx := context.Background()
+
+	bid, err := bidService.PlaceBid(ctx, "auction-r", "user-1", 120)
+	if err != nil {
+		t.Fatalf("PlaceBid() unexpected error: %v", err)
+	}
+
+	if _, err := svc.RequestRetraction(ctx, "user-1", bid.ID, "changed_my_mind", ""); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("RequestRetraction() with an unknown reason error = %v, want %v", err, domain.ErrValidation)
+	}
+	if _, err := svc.RequestRetraction(ctx, "user-2", bid.ID, domain.RetractionReasonWrongAmount, ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("RequestRetraction() of another user's bid error = %v, want %v", err, domain.ErrNotFound)
+	}
+
+	retraction, err := svc.RequestRetraction(ctx, "user-1", bid.ID, domain.RetractionReasonWrongAmount, " typo ")
+	if err != nil {
+		t.Fatalf("RequestRetraction() unexpected error: %v", err)
+	}
+	if retraction.Status != domain.RetractionStatusPending || retraction.Note != "typo" || retraction.AuctionID != "auction-r" {
+		t.Errorf("retraction = %+v, want a pending retraction noted %q on auction-r", retraction, "typo")
+	}
+	if _, err := svc.RequestRetraction(ctx, "user-1", bid.ID, domain.RetractionReasonWrongAmount, ""); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("second RequestRetraction() error = %v, want %v", err, domain.ErrConflict)
+	}
+	mine, err := svc.ListMine(ctx, "user-1")
+	if err != nil || len(mine) != 1 {
+		t.Errorf("ListMine() = %d retractions, %v; want 1", len(mine), err)
+	}
+
+	// The final hour of the auction is off limits
+	auction, _ := auctionRepo.GetByID(ctx, "auction-r")
+	auction.EndTime = time.Now().Add(30 * time.Minute)
+	auctionRepo.Update(ctx, auction)
+	if _, err := svc.RequestRetraction(ctx, "user-1", bid.ID, domain.RetractionReasonSellerUnreachable, ""); !errors.Is(err, domain.ErrRetractionNotAllowed) {
+		t.Errorf("RequestRetraction() in the final hour error = %v, want %v", err, domain.ErrRetractionNotAllowed)
+	}
+}
+
+func TestRetractionService_Approve(t *testing.T) {
+	svc, bidService, auctionRepo := newTestRetractionService(t)
+	ctx := context.Background()
+
+	if _, err := bidService.PlaceBid(ctx, "auction-r", "user-1", 120); err != nil {
+		t.Fatalf("PlaceBid() unexpected error: %v", err)
+	}
+	// user-2 meant a maximum of 100.00, not 10000
+	if _, err := bidService.SetProxyBid(ctx, "auction-r", "user-2", 10000); err != nil {
+		t.Fatalf("SetProxyBid() unexpected error: %v", err)
+	}
+	mistaken, err := bidService.GetWinningBid(ctx, "auction-r")
+	if err != nil || mistaken.UserID != "user-2" {
+		t.Fatalf("GetWinningBid() = %+v, %v; want user-2", mistaken, err)
+	}
+
+	retraction, err := svc.RequestRetraction(ctx, "user-2", mistaken.ID, domain.RetractionReasonWrongAmount, "")
+	if err != nil {
+		t.Fatalf("RequestRetraction() unexpected error: %v", err)
+	}
+	pending, _ := svc.ListPending(ctx)
+	if len(pending) != 1 {
+		t.Fatalf("ListPending() = %d retractions, want 1", len(pending))
+	}
+
+	approved, err := svc.Approve(ctx, retraction.ID, "admin-1", "obvious typo")
+	if err != nil {
+		t.Fatalf("Approve() unexpected error: %v", err)
+	}
+	if approved.Status != domain.RetractionStatusApproved || approved.ReviewerID != "admin-1" || approved.ReviewedAt == nil {
+		t.Errorf("approved retraction = %+v, want approved by admin-1", approved)
+	}
+
+	// The price falls back to the highest remaining bid, and the mistaken
+	// proxy no longer defends it
+	auction, _ := auctionRepo.GetByID(ctx, "auction-r")
+	winning, _ := bidService.GetWinningBid(ctx, "auction-r")
+	if auction.CurrentPrice != 120 || auction.BidCount != 1 || winning.UserID != "user-1" {
+		t.Errorf("after approval: %d bids at %v led by %s, want 1 bid at 120 led by user-1",
+			auction.BidCount, auction.CurrentPrice, winning.UserID)
+	}
+	proxies, _ := bidService.proxyRepo.GetByAuctionID(ctx, "auction-r")
+	if len(proxies) != 0 {
+		t.Errorf("proxy bids after approval = %d, want 0", len(proxies))
+	}
+	bid, _ := bidService.bidRepo.GetByID(ctx, mistaken.ID)
+	if !bid.IsRetracted() {
+		t.Error("bid IsRetracted() = false after approval, want true")
+	}
+	user, _ := bidService.userRepo.GetByID(ctx, "user-2")
+	if user.BidRetractions != 1 {
+		t.Errorf("BidRetractions = %d, want 1", user.BidRetractions)
+	}
+
+	if _, err := svc.Reject(ctx, retraction.ID, "admin-1", ""); !errors.Is(err, domain.ErrRetractionReviewed) {
+		t.Errorf("Reject() after approval error = %v, want %v", err, domain.ErrRetractionReviewed)
+	}
+}
+
+func TestRetractionService_Approve_Paused(t *testing.T) {
+	svc, bidService, auctionRepo := newTestRetractionService(t)
+	ctx := context.Background()
+
+	bidService.PlaceBid(ctx, "auction-r", "user-1", 120)
+	mistaken, _ := bidService.PlaceBid(ctx, "auction-r", "user-2", 1000)
+	retraction, _ := svc.RequestRetraction(ctx, "user-2", mistaken.ID, domain.RetractionReasonWrongAmount, "")
+	// user-3's proxy could beat the price the retraction falls back to
+	bidService.proxyRepo.Save(ctx, &domain.ProxyBid{
+		ID: "proxy-3", AuctionID: "auction-r", UserID: "user-3", MaxAmount: 500, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	})
+
+	auction, _ := auctionRepo.GetByID(ctx, "auction-r")
+	auction.Status = domain.AuctionStatusPaused
+	auctionRepo.Update(ctx, auction)
+
+	if _, err := svc.Approve(ctx, retraction.ID, "admin-1", ""); err != nil {
+		t.Fatalf("Approve() unexpected error: %v", err)
+	}
+
+	// No proxy bids are placed while the auction is paused
+	auction, _ = auctionRepo.GetByID(ctx, "auction-r")
+	winning, _ := bidService.GetWinningBid(ctx, "auction-r")
+	if auction.CurrentPrice != 120 || auction.BidCount != 1 || winning.UserID != "user-1" {
+		t.Errorf("after approval: %d bids at %v led by %s, want 1 bid at 120 led by user-1",
+			auction.BidCount, auction.CurrentPrice, winning.UserID)
+	}
+}
+
+func TestRetractionService_Reject(t *testing.T) {
+	svc, bidService, auctionRepo := newTestRetractionService(t)
+	ctx := context.Background()
+
+	bid, _ := bidService.PlaceBid(ctx, "auction-r", "user-1", 120)
+	retraction, _ := svc.RequestRetraction(ctx, "user-1", bid.ID, domain.RetractionReasonDescriptionChanged, "")
+
+	rejected, err := svc.Reject(ctx, retraction.ID, "admin-1", "description unchanged")
+	if err != nil {
+		t.Fatalf("Reject() unexpected error: %v", err)
+	}
+	if rejected.Status != domain.RetractionStatusRejected || rejected.ReviewNote != "description unchanged" {
+		t.Errorf("rejected retraction = %+v, want rejected with its note", rejected)
+	}
+	auction, _ := auctionRepo.GetByID(ctx, "auction-r")
+	if auction.CurrentPrice != 120 || auction.BidCount != 1 {
+		t.Errorf("after rejection: %d bids at %v, want 1 bid at 120", auction.BidCount, auction.CurrentPrice)
+	}
+	if _, err := svc.Approve(ctx, retraction.ID, "admin-1", ""); !errors.Is(err, domain.ErrRetractionReviewed) {
+		t.Errorf("Approve() after rejection error = %v, want %v", err, domain.ErrRetractionReviewed)
+	}
+}
+
+func TestRetractionService_Approve_FinalHour(t *testing.T) {
+	svc, bidService, auctionRepo := newTestRetractionService(t)
+	ctx := context.Background()
+
+	bid, _ := bidService.PlaceBid(ctx, "auction-r", "user-1", 120)
+	retraction, _ := svc.RequestRetraction(ctx, "user-1", bid.ID, domain.RetractionReasonWrongAmount, "")
+
+	// The request waited for review until the auction's final hour
+	auction, _ := auctionRepo.GetByID(ctx, "auction-r")
+	auction.EndTime = time.Now().Add(30 * time.Minute)
+	auctionRepo.Update(ctx, auction)
+
+	if _, err := svc.Approve(ctx, retraction.ID, "admin-1", ""); !errors.Is(err, domain.ErrRetractionNotAllowed) {
+		t.Errorf("Approve() in the final hour error = %v, want %v", err, domain.ErrRetractionNotAllowed)
+	}
+	if stored, _ := bidService.bidRepo.GetByID(ctx, bid.ID); stored.IsRetracted() {
+		t.Error("bid retracted in the final hour")
+	}
+}
+
+func TestRetractionService_Review_Concurrent(t *testing.T) {
+	svc, bidService, _ := newTestRetractionService(t)
+	ctx := context.Background()
+
+	bid, _ := bidService.PlaceBid(ctx, "auction-r", "user-1", 120)
+	retraction, _ := svc.RequestRetraction(ctx, "user-1", bid.ID, domain.RetractionReasonWrongAmount, "")
+
+	errs := make(chan error, 3)
+	for _, review := range []func(context.Context, string, string, string) (*domain.BidRetraction, error){svc.Approve, svc.Approve, svc.Reject} {
+		go func() {
+			_, err := review(ctx, retraction.ID, "admin-1", "")
+			errs <- err
+		}()
+	}
+	succeeded := 0
+	for range 3 {
+		if err := <-errs; err == nil {
+			succeeded++
+		} else if !errors.Is(err, domain.ErrConflict) && !errors.Is(err, domain.ErrRetractionReviewed) {
+			t.Errorf("review error = %v, want %v or %v", err, domain.ErrConflict, domain.ErrRetractionReviewed)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d reviews succeeded, want 1", succeeded)
+	}
+	if user, _ := bidService.userRepo.GetByID(ctx, "user-1"); user.BidRetractions > 1 {
+		t.Errorf("BidRetractions = %d, want at most 1", user.BidRetractions)
+	}
+}
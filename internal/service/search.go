@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// maxSearchTextLength caps search text, which is matched word by word
+const maxSearchTextLength = 200
+
+// SearchPage is a page of search hits with the total number of hits and the
+// facets of the search text
+type SearchPage struct {
+	Page[*domain.SearchHit]
+	Total  int
+	Facets domain.SearchFacets
+}
+
+type SearchService struct {
+	searchRepo   domain.SearchRepository
+	categoryRepo domain.CategoryRepository
+}
+
+func NewSearchService(searchRepo domain.SearchRepository, categoryRepo domain.CategoryRepository) *SearchService {
+	return &SearchService{searchRepo: searchRepo, categoryRepo: categoryRepo}
+}
+
+// Search returns a page of the products matching query, best match first,
+// continuing after cursor if it is set. query.Limit is the page size, or zero
+// for the default. query.CategoryIDs also match their subcategories.
+func (s *SearchService) Search(ctx context.Context, query domain.SearchQuery, cursor string) (*SearchPage, error) {
+	if len(query.Text) > maxSearchTextLength {
+		return nil, fmt.Errorf("%w: search text must be at most %d characters", domain.ErrValidation, maxSearchTextLength)
+	}
+	if len(domain.SearchTerms(query.Text)) == 0 {
+		return nil, fmt.Errorf("%w: search text must contain a word", domain.ErrValidation)
+	}
+	if query.MinPrice != nil && query.MaxPrice != nil && *query.MinPrice > *query.MaxPrice {
+		return nil, fmt.Errorf("%w: min_price must not exceed max_price", domain.ErrValidation)
+	}
+	categoryIDs, err := categorySubtrees(ctx, s.categoryRepo, query.CategoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	query.CategoryIDs = categoryIDs
+	limit, err := pageLimit(query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if query.After, err = domain.DecodeCursor(cursor, domain.SortRelevance); err != nil {
+		return nil, err
+	}
+
+	query.Limit = limit + 1
+	results, err := s.searchRepo.Search(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	return &SearchPage{
+		Page:   *newPage(results.Hits, limit, (*domain.SearchHit).Cursor),
+		Total:  results.Total,
+		Facets: results.Facets,
+	}, nil
+}
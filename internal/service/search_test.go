@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+// newTestSearchService stores a laptop under active auction at 750, a laptop
+// bag under an ended auction at 40, and a gaming chair that was never auctioned
+func newTestSearchService(t *testing.T) *SearchService {
+	t.Helper()
+	ctx := context.Background()
+	productRepo := mocks.NewMockProductRepository()
+	auctionRepo := mocks.NewMockAuctionRepository()
+
+	now := time.Now()
+	products := []*domain.Product{
+		{ID: "00000000-0000-0000-0000-000000000001", Name: "Gaming Laptop", Description: "Fast laptop with a large screen"},
+		{ID: "00000000-0000-0000-0000-000000000002", Name: "Laptop Bag", Description: "Padded bag for gaming gear"},
+		{ID: "00000000-0000-0000-0000-000000000003", Name: "Gaming Chair", Description: "Ergonomic chair"},
+	}
+	for _, product := range products {
+		product.CreatedAt = now
+		productRepo.Create(ctx, product)
+	}
+	auctionRepo.Create(ctx, &domain.Auction{ID: "auction-1", ProductID: products[0].ID, CurrentPrice: 750, Status: domain.AuctionStatusActive, CreatedAt: now})
+	auctionRepo.Create(ctx, &domain.Auction{ID: "auction-2", ProductID: products[1].ID, CurrentPrice: 40, Status: domain.AuctionStatusEnded, CreatedAt: now})
+
+	return NewSearchService(mocks.NewMockSearchRepository(productRepo, auctionRepo), mocks.NewMockCategoryRepository())
+}
+
+func TestSearchService_Search_RanksAndPrefixMatches(t *testing.T) {
+	svc := newTestSearchService(t)
+
+	page, err := svc.Search(context.Background(), domain.SearchQuery{Text: "gaming lap"}, "")
+	if err != nil {
+		t.Fatalf("Search() unexpected error: %v", err)
+	}
+	if page.Total != 2 || len(page.Items) != 2 {
+		t.Fatalf("Search() = %d hits of %d, want 2 of 2", len(page.Items), page.Total)
+	}
+	if page.Items[0].Product.Name != "Gaming Laptop" {
+		t.Errorf("Search() best hit = %q, want the product matching both words by name", page.Items[0].Product.Name)
+	}
+	if page.Items[0].Auction == nil || page.Items[0].Auction.ID != "auction-1" {
+		t.Errorf("Search() best hit auction = %v, want auction-1", page.Items[0].Auction)
+	}
+}
+
+func TestSearchService_Search_FiltersAndFacets(t *testing.T) {
+	svc := newTestSearchService(t)
+
+	page, err := svc.Search(context.Background(), domain.SearchQuery{Text: "gaming", Status: domain.AuctionStatusActive}, "")
+	if err != nil {
+		t.Fatalf("Search() unexpected error: %v", err)
+	}
+	if page.Total != 1 || page.Items[0].Product.Name != "Gaming Laptop" {
+		t.Fatalf("Search(active) = %d hits, want only the laptop under active auction", page.Total)
+	}
+
+	facets := page.Facets
+	if facets.Statuses[domain.AuctionStatusActive] != 1 || facets.Statuses[domain.AuctionStatusEnded] != 1 {
+		t.Errorf("Search() status facets = %v, want one active and one ended", facets.Statuses)
+	}
+	if facets.PriceBuckets[0].Count != 1 || facets.PriceBuckets[3].Count != 1 {
+		t.Errorf("Search() price facets = %+v, want one under 50 and one from 500 to 1000", facets.PriceBuckets)
+	}
+}
+
+func TestSearchService_Search_Pages(t *testing.T) {
+	svc := newTestSearchService(t)
+
+	first, err := svc.Search(context.Background(), domain.SearchQuery{Text: "gaming", Limit: 2}, "")
+	if err != nil {
+		t.Fatalf("Search() unexpected error: %v", err)
+	}
+	if len(first.Items) != 2 || first.NextCursor == "" {
+		t.Fatalf("Search() first page = %d hits, cursor %q; want 2 and a cursor", len(first.Items), first.NextCursor)
+	}
+	second, err := svc.Search(context.Background(), domain.SearchQuery{Text: "gaming", Limit: 2}, first.NextCursor)
+	if err != nil {
+		t.Fatalf("Search() unexpected error: %v", err)
+	}
+	if len(second.Items) != 1 || second.NextCursor != "" || second.Total != 3 {
+		t.Fatalf("Search() second page = %d hits of %d, cursor %q; want the last of 3", len(second.Items), second.Total, second.NextCursor)
+	}
+}
+
+func TestSearchService_Search_InvalidQuery(t *testing.T) {
+	svc := newTestSearchService(t)
+
+	for _, text := range []string{"", " & !"} {
+		if _, err := svc.Search(context.Background(), domain.SearchQuery{Text: text}, ""); !errors.Is(err, domain.ErrValidation) {
+			t.Errorf("Search(%q) error = %v, want %v", text, err, domain.ErrValidation)
+		}
+	}
+}
+
+func TestSearchService_Search_Categories(t *testing.T) {
+	ctx := context.Background()
+	productRepo := mocks.NewMockProductRepository()
+	categoryRepo := mocks.NewMockCategoryRepository()
+	electronics, laptops := newTestCategoryTree(t, NewCategoryService(categoryRepo, productRepo))
+	productRepo.Create(ctx, &domain.Product{ID: "00000000-0000-0000-0000-000000000001", Name: "Gaming Laptop", CategoryID: laptops.ID})
+	productRepo.Create(ctx, &domain.Product{ID: "00000000-0000-0000-0000-000000000002", Name: "Gaming Headset", CategoryID: electronics.ID})
+	productRepo.Create(ctx, &domain.Product{ID: "00000000-0000-0000-0000-000000000003", Name: "Gaming Chair"})
+	svc := NewSearchService(mocks.NewMockSearchRepository(productRepo, mocks.NewMockAuctionRepository()), categoryRepo)
+
+	page, err := svc.Search(ctx, domain.SearchQuery{Text: "gaming"}, "")
+	if err != nil {
+		t.Fatalf("Search() unexpected error: %v", err)
+	}
+	if page.Facets.Categories[laptops.ID] != 1 || page.Facets.Categories[electronics.ID] != 1 {
+		t.Errorf("Search() category facets = %v, want one hit in each category", page.Facets.Categories)
+	}
+
+	page, err = svc.Search(ctx, domain.SearchQuery{Text: "gaming", CategoryIDs: []string{electronics.ID}}, "")
+	if err != nil {
+		t.Fatalf("Search() unexpected error: %v", err)
+	}
+	if page.Total != 2 {
+		t.Errorf("Search(Electronics) = %d hits, want 2 including the subcategory's", page.Total)
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+// DefaultStreamTicketTTL is used when no ticket lifetime is configured
+const DefaultStreamTicketTTL = 30 * time.Second
+
+// StreamTicketService issues short-lived, single-use tickets that authenticate
+// SSE and WebSocket connections. Browsers cannot send an Authorization header
+// when opening those, so an authenticated client first exchanges its JWT for a
+// ticket and passes the ticket in the connection URL or subprotocol list.
+type StreamTicketService struct {
+	tokenRepo domain.UserTokenRepository
+	ttl       time.Duration
+}
+
+func NewStreamTicketService(tokenRepo domain.UserTokenRepository, ttl time.Duration) *StreamTicketService {
+	if ttl <= 0 {
+		ttl = DefaultStreamTicketTTL
+	}
+	return &StreamTicketService{
+		tokenRepo: tokenRepo,
+		ttl:       ttl,
+	}
+}
+
+// Issue creates a ticket for userID and returns it with its expiry time
+func (s *StreamTicketService) Issue(ctx context.Context, userID string) (string, time.Time, error) {
+	expiresAt := time.Now().Add(s.ttl)
+	ticket, err := issueToken(ctx, s.tokenRepo, userID, domain.TokenPurposeStreamTicket, s.ttl)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return ticket, expiresAt, nil
+}
+
+// Redeem consumes a ticket and returns the user it was issued to. A ticket can
+// be redeemed only once, so a leaked connection URL cannot be replayed.
+func (s *StreamTicketService) Redeem(ctx context.Context, ticket string) (string, error) {
+	token, err := redeemToken(ctx, s.tokenRepo, domain.TokenPurposeStreamTicket, ticket)
+	if err != nil {
+		return "", err
+	}
+	return token.UserID, nil
+}
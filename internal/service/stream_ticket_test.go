@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+func TestStreamTicketService_IssueAndRedeem(t *testing.T) {
+	svc := NewStreamTicketService(mocks.NewMockUserTokenRepository(), time.Minute)
+	ctx := context.Background()
+
+	ticket, expiresAt, err := svc.Issue(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Issue() unexpected error: %v", err)
+	}
+	if ticket == "" {
+		t.Fatal("Issue() returned an empty ticket")
+	}
+	if until := time.Until(expiresAt); until <= 0 || until > time.Minute {
+		t.Errorf("Issue() expires in %v, want within 1m", until)
+	}
+
+	userID, err := svc.Redeem(ctx, ticket)
+	if err != nil {
+		t.Fatalf("Redeem() unexpected error: %v", err)
+	}
+	if userID != "user-1" {
+		t.Errorf("Redeem() user = %q, want %q", userID, "user-1")
+	}
+}
+
+func TestStreamTicketService_Redeem_SingleUse(t *testing.T) {
+	svc := NewStreamTicketService(mocks.NewMockUserTokenRepository(), time.Minute)
+	ctx := context.Background()
+
+	ticket, _, _ := svc.Issue(ctx, "user-1")
+	if _, err := svc.Redeem(ctx, ticket); err != nil {
+		t.Fatalf("Redeem() unexpected error: %v", err)
+	}
+	if _, err := svc.Redeem(ctx, ticket); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("second Redeem() error = %v, want %v", err, domain.ErrInvalidToken)
+	}
+}
+
+func TestStreamTicketService_Redeem_Expired(t *testing.T) {
+	tokenRepo := mocks.NewMockUserTokenRepository()
+	svc := NewStreamTicketService(tokenRepo, time.Minute)
+	ctx := context.Background()
+
+	ticket, _, _ := svc.Issue(ctx, "user-1")
+	token, _ := tokenRepo.GetByHash(ctx, domain.TokenPurposeStreamTicket, hashToken(ticket))
+	token.ExpiresAt = time.Now().Add(-time.Second)
+
+	if _, err := svc.Redeem(ctx, ticket); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Redeem() error = %v, want %v", err, domain.ErrInvalidToken)
+	}
+}
+
+func TestStreamTicketService_Redeem_WrongPurpose(t *testing.T) {
+	tokenRepo := mocks.NewMockUserTokenRepository()
+	svc := NewStreamTicketService(tokenRepo, time.Minute)
+	ctx := context.Background()
+
+	// A password reset token must not open a stream
+	raw, _ := issueToken(ctx, tokenRepo, "user-1", domain.TokenPurposePasswordReset, time.Minute)
+	if _, err := svc.Redeem(ctx, raw); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Redeem() error = %v, want %v", err, domain.ErrInvalidToken)
+	}
+}
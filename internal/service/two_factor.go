@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/pkg/totp"
+)
+
+const (
+	// recoveryCodeCount is how many recovery codes are issued at a time
+	recoveryCodeCount = 10
+	// totpSkew is how many 30s steps of clock drift are tolerated in each direction
+	totpSkew = 1
+)
+
+// TwoFactorEnrollment is returned when a user starts TOTP enrollment
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	ProvisioningURI string `json:"provisioning_uri" example:"otpauth://totp/Bidding%20System:user@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Bidding+System"`
+}
+
+// TwoFactorService manages optional TOTP two-factor authentication
+type TwoFactorService struct {
+	twoFactorRepo domain.TwoFactorRepository
+	userRepo      domain.UserRepository
+	issuer        string
+}
+
+func NewTwoFactorService(twoFactorRepo domain.TwoFactorRepository, userRepo domain.UserRepository, issuer string) *TwoFactorService {
+	return &TwoFactorService{
+		twoFactorRepo: twoFactorRepo,
+		userRepo:      userRepo,
+		issuer:        issuer,
+	}
+}
+
+// BeginEnrollment generates a new secret for the user. Two-factor is not enforced
+// until ConfirmEnrollment succeeds with a code from the authenticator app.
+func (s *TwoFactorService) BeginEnrollment(ctx context.Context, userID string) (*TwoFactorEnrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	existing, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	if existing != nil && existing.IsEnabled() {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	tf := &domain.TwoFactor{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	}
+	if err := s.twoFactorRepo.Save(ctx, tf); err != nil {
+		return nil, fmt.Errorf("failed to save two-factor settings: %w", err)
+	}
+
+	return &TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, s.issuer, user.Email),
+	}, nil
+}
+
+// ConfirmEnrollment enables two-factor authentication and returns a fresh set of recovery codes
+func (s *TwoFactorService) ConfirmEnrollment(ctx context.Context, userID, code string) ([]string, error) {
+	tf, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	if tf == nil {
+		return nil, domain.ErrTwoFactorNotEnabled
+	}
+	if tf.IsEnabled() {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	if err := s.verifyTOTP(ctx, tf, code); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tf.ConfirmedAt = &now
+	if err := s.twoFactorRepo.Save(ctx, tf); err != nil {
+		return nil, fmt.Errorf("failed to save two-factor settings: %w", err)
+	}
+
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+// Disable turns off two-factor authentication after verifying a current code
+func (s *TwoFactorService) Disable(ctx context.Context, userID, code string) error {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+	if err := s.twoFactorRepo.Delete(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete two-factor settings: %w", err)
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after verifying a current TOTP code
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	tf, err := s.enabledTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyTOTP(ctx, tf, code); err != nil {
+		return nil, err
+	}
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+// IsEnabled checks if the user has confirmed two-factor authentication
+func (s *TwoFactorService) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	tf, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	return tf != nil && tf.IsEnabled(), nil
+}
+
+// Verify checks a TOTP code or, failing that, redeems a recovery code
+func (s *TwoFactorService) Verify(ctx context.Context, userID, code string) error {
+	tf, err := s.enabledTwoFactor(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.verifyTOTP(ctx, tf, code); err == nil {
+		return nil
+	}
+
+	if err := s.twoFactorRepo.UseRecoveryCode(ctx, userID, hashToken(normalizeRecoveryCode(code))); err != nil {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (s *TwoFactorService) enabledTwoFactor(ctx context.Context, userID string) (*domain.TwoFactor, error) {
+	tf, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	if tf == nil || !tf.IsEnabled() {
+		return nil, domain.ErrTwoFactorNotEnabled
+	}
+	return tf, nil
+}
+
+// verifyTOTP validates a code and records its time step so it cannot be replayed
+func (s *TwoFactorService) verifyTOTP(ctx context.Context, tf *domain.TwoFactor, code string) error {
+	step, ok := totp.Validate(tf.Secret, code, time.Now(), totpSkew)
+	if !ok || step <= tf.LastUsedAt {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	tf.LastUsedAt = step
+	if err := s.twoFactorRepo.Save(ctx, tf); err != nil {
+		return fmt.Errorf("failed to save two-factor settings: %w", err)
+	}
+	return nil
+}
+
+func (s *TwoFactorService) issueRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	now := time.Now()
+	plain := make([]string, 0, recoveryCodeCount)
+	codes := make([]*domain.RecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		plain = append(plain, code)
+		codes = append(codes, &domain.RecoveryCode{
+			ID:        uuid.New().String(),
+			UserID:    userID,
+			CodeHash:  hashToken(normalizeRecoveryCode(code)),
+			CreatedAt: now,
+		})
+	}
+
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, userID, codes); err != nil {
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+	return plain, nil
+}
+
+// generateRecoveryCode returns a code formatted as xxxxx-xxxxx (50 bits of entropy)
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	s := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+	return s[:5] + "-" + s[5:], nil
+}
+
+// normalizeRecoveryCode makes recovery codes tolerant of case and formatting
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
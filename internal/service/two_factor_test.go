@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+	"github.com/saigenix/bidding-system/pkg/totp"
+)
+
+func newTestTwoFactorService() (*TwoFactorService, *AuthService, *mocks.MockTwoFactorRepository) {
+	userRepo := mocks.NewMockUserRepository()
+	twoFactorRepo := mocks.NewMockTwoFactorRepository()
+	svc := NewTwoFactorService(twoFactorRepo, userRepo, "Test")
+	authSvc := NewAuthService(userRepo, svc, newTestLoginThrottle(), "test-secret-key-for-testing", 24)
+	return svc, authSvc, twoFactorRepo
+}
+
+// enrollUser registers a user and fully enables two-factor, returning the user ID,
+// TOTP secret and recovery codes
+func enrollUser(t *testing.T, svc *TwoFactorService, authSvc *AuthService) (string, string, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	user, err := authSvc.Register(ctx, "test@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Register() unexpected error: %v", err)
+	}
+
+	enrollment, err := svc.BeginEnrollment(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("BeginEnrollment() unexpected error: %v", err)
+	}
+
+	// Confirm with the previous step's code so the current one is still unused
+	code, _ := totp.Code(enrollment.Secret, time.Now().Add(-totp.Period))
+	codes, err := svc.ConfirmEnrollment(ctx, user.ID, code)
+	if err != nil {
+		t.Fatalf("ConfirmEnrollment() unexpected error: %v", err)
+	}
+
+	return user.ID, enrollment.Secret, codes
+}
+
+// ============================================================================
+// Enrollment
+// ============================================================================
+
+func TestTwoFactorService_Enrollment(t *testing.T) {
+	svc, authSvc, _ := newTestTwoFactorService()
+	ctx := context.Background()
+
+	userID, _, codes := enrollUser(t, svc, authSvc)
+
+	if len(codes) != recoveryCodeCount {
+		t.Errorf("ConfirmEnrollment() returned %d recovery codes, want %d", len(codes), recoveryCodeCount)
+	}
+	enabled, _ := svc.IsEnabled(ctx, userID)
+	if !enabled {
+		t.Error("IsEnabled() = false after confirmation")
+	}
+	if _, err := svc.BeginEnrollment(ctx, userID); !errors.Is(err, domain.ErrTwoFactorAlreadyEnabled) {
+		t.Errorf("BeginEnrollment() error = %v, want %v", err, domain.ErrTwoFactorAlreadyEnabled)
+	}
+}
+
+func TestTwoFactorService_ConfirmEnrollment_WrongCode(t *testing.T) {
+	svc, authSvc, _ := newTestTwoFactorService()
+	ctx := context.Background()
+
+	user, _ := authSvc.Register(ctx, "test@example.com", "password123")
+	svc.BeginEnrollment(ctx, user.ID)
+
+	if _, err := svc.ConfirmEnrollment(ctx, user.ID, "000000"); !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		t.Errorf("ConfirmEnrollment() error = %v, want %v", err, domain.ErrInvalidTwoFactorCode)
+	}
+	if enabled, _ := svc.IsEnabled(ctx, user.ID); enabled {
+		t.Error("IsEnabled() = true after a failed confirmation")
+	}
+}
+
+func TestTwoFactorService_Verify_RejectsReplay(t *testing.T) {
+	svc, authSvc, _ := newTestTwoFactorService()
+	ctx := context.Background()
+
+	userID, secret, _ := enrollUser(t, svc, authSvc)
+	code, _ := totp.Code(secret, time.Now())
+
+	if err := svc.Verify(ctx, userID, code); err != nil {
+		t.Fatalf("Verify() unexpected error: %v", err)
+	}
+	if err := svc.Verify(ctx, userID, code); !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		t.Errorf("Verify() replayed code error = %v, want %v", err, domain.ErrInvalidTwoFactorCode)
+	}
+}
+
+func TestTwoFactorService_Verify_RecoveryCodeSingleUse(t *testing.T) {
+	svc, authSvc, _ := newTestTwoFactorService()
+	ctx := context.Background()
+
+	userID, _, codes := enrollUser(t, svc, authSvc)
+
+	if err := svc.Verify(ctx, userID, codes[0]); err != nil {
+		t.Fatalf("Verify() with recovery code unexpected error: %v", err)
+	}
+	if err := svc.Verify(ctx, userID, codes[0]); !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		t.Errorf("Verify() with reused recovery code error = %v, want %v", err, domain.ErrInvalidTwoFactorCode)
+	}
+}
+
+func TestTwoFactorService_Disable(t *testing.T) {
+	svc, authSvc, _ := newTestTwoFactorService()
+	ctx := context.Background()
+
+	userID, _, codes := enrollUser(t, svc, authSvc)
+
+	if err := svc.Disable(ctx, userID, codes[0]); err != nil {
+		t.Fatalf("Disable() unexpected error: %v", err)
+	}
+	if enabled, _ := svc.IsEnabled(ctx, userID); enabled {
+		t.Error("IsEnabled() = true after Disable()")
+	}
+}
+
+// ============================================================================
+// Two-step login
+// ============================================================================
+
+func TestAuthService_Login_TwoFactorChallenge(t *testing.T) {
+	svc, authSvc, _ := newTestTwoFactorService()
+	ctx := context.Background()
+
+	userID, secret, _ := enrollUser(t, svc, authSvc)
+
+	result, err := authSvc.Login(ctx, "test@example.com", "password123", testClientIP)
+	if err != nil {
+		t.Fatalf("Login() unexpected error: %v", err)
+	}
+	if !result.TwoFactorRequired || result.ChallengeToken == "" {
+		t.Fatal("Login() did not return a two-factor challenge")
+	}
+	if result.Token != "" {
+		t.Fatal("Login() issued an access token before the second factor")
+	}
+
+	// The challenge must not be usable as an access token
+	if _, err := authSvc.ValidateToken(result.ChallengeToken); err == nil {
+		t.Error("ValidateToken() accepted a challenge token")
+	}
+
+	if _, err := authSvc.CompleteTwoFactorLogin(ctx, result.ChallengeToken, "000000", testClientIP); err == nil {
+		t.Error("CompleteTwoFactorLogin() accepted a wrong code")
+	}
+
+	code, _ := totp.Code(secret, time.Now())
+	token, err := authSvc.CompleteTwoFactorLogin(ctx, result.ChallengeToken, code, testClientIP)
+	if err != nil {
+		t.Fatalf("CompleteTwoFactorLogin() unexpected error: %v", err)
+	}
+	got, err := authSvc.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken() unexpected error: %v", err)
+	}
+	if got != userID {
+		t.Errorf("ValidateToken() userID = %q, want %q", got, userID)
+	}
+}
+
+func TestAuthService_CompleteTwoFactorLogin_RejectsAccessToken(t *testing.T) {
+	svc, authSvc, _ := newTestTwoFactorService()
+	ctx := context.Background()
+
+	userID, secret, _ := enrollUser(t, svc, authSvc)
+	accessToken, _ := authSvc.generateToken(userID)
+	code, _ := totp.Code(secret, time.Now())
+
+	if _, err := authSvc.CompleteTwoFactorLogin(ctx, accessToken, code, testClientIP); err == nil {
+		t.Error("CompleteTwoFactorLogin() accepted an access token as a challenge")
+	}
+}
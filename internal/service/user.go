@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+)
+
+const (
+	maxDisplayNameLength = 50
+	maxAvatarURLLength   = 2048
+	maxLocationLength    = 100
+	maxBioLength         = 500
+)
+
+// ProfileUpdate holds the profile fields to change; nil fields are left untouched
+type ProfileUpdate struct {
+	DisplayName *string
+	AvatarURL   *string
+	Location    *string
+	Bio         *string
+}
+
+type UserService struct {
+	userRepo domain.UserRepository
+}
+
+func NewUserService(userRepo domain.UserRepository) *UserService {
+	return &UserService{userRepo: userRepo}
+}
+
+func (s *UserService) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// UpdateProfile validates and applies a partial profile update
+func (s *UserService) UpdateProfile(ctx context.Context, userID string, update ProfileUpdate) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	profile := user.Profile
+	if update.DisplayName != nil {
+		profile.DisplayName = strings.TrimSpace(*update.DisplayName)
+	}
+	if update.AvatarURL != nil {
+		profile.AvatarURL = strings.TrimSpace(*update.AvatarURL)
+	}
+	if update.Location != nil {
+		profile.Location = strings.TrimSpace(*update.Location)
+	}
+	if update.Bio != nil {
+		profile.Bio = strings.TrimSpace(*update.Bio)
+	}
+
+	if err := validateProfile(profile); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateProfile(ctx, user.ID, profile); err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+	user.Profile = profile
+
+	return user, nil
+}
+
+func validateProfile(p domain.Profile) error {
+	if utf8.RuneCountInString(p.DisplayName) > maxDisplayNameLength {
+		return fmt.Errorf("%w: display name must be at most %d characters", domain.ErrInvalidProfile, maxDisplayNameLength)
+	}
+	if utf8.RuneCountInString(p.Location) > maxLocationLength {
+		return fmt.Errorf("%w: location must be at most %d characters", domain.ErrInvalidProfile, maxLocationLength)
+	}
+	if utf8.RuneCountInString(p.Bio) > maxBioLength {
+		return fmt.Errorf("%w: bio must be at most %d characters", domain.ErrInvalidProfile, maxBioLength)
+	}
+	if p.AvatarURL != "" {
+		if len(p.AvatarURL) > maxAvatarURLLength {
+			return fmt.Errorf("%w: avatar URL must be at most %d characters", domain.ErrInvalidProfile, maxAvatarURLLength)
+		}
+		u, err := url.Parse(p.AvatarURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: avatar URL must be an absolute http(s) URL", domain.ErrInvalidProfile)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/mocks"
+)
+
+func newTestUserService() (*UserService, *mocks.MockUserRepository) {
+	repo := mocks.NewMockUserRepository()
+	repo.Create(context.Background(), &domain.User{ID: "user-1", Email: "user@example.com"})
+	return NewUserService(repo), repo
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestUserService_UpdateProfile_Success(t *testing.T) {
+	svc, _ := newTestUserService()
+
+	user, err := svc.UpdateProfile(context.Background(), "user-1", ProfileUpdate{
+		DisplayName: stringPtr("  Ada  "),
+		AvatarURL:   stringPtr("https://example.com/ada.png"),
+		Location:    stringPtr("London"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateProfile() unexpected error: %v", err)
+	}
+	if user.Profile.DisplayName != "Ada" {
+		t.Errorf("UpdateProfile() display name = %q, want %q", user.Profile.DisplayName, "Ada")
+	}
+	if user.Profile.Location != "London" {
+		t.Errorf("UpdateProfile() location = %q, want %q", user.Profile.Location, "London")
+	}
+}
+
+func TestUserService_UpdateProfile_Partial(t *testing.T) {
+	svc, _ := newTestUserService()
+	ctx := context.Background()
+
+	svc.UpdateProfile(ctx, "user-1", ProfileUpdate{DisplayName: stringPtr("Ada"), Bio: stringPtr("Collector")})
+
+	user, err := svc.UpdateProfile(ctx, "user-1", ProfileUpdate{Bio: stringPtr("")})
+	if err != nil {
+		t.Fatalf("UpdateProfile() unexpected error: %v", err)
+	}
+	if user.Profile.DisplayName != "Ada" {
+		t.Errorf("UpdateProfile() changed an omitted field: display name = %q", user.Profile.DisplayName)
+	}
+	if user.Profile.Bio != "" {
+		t.Errorf("UpdateProfile() bio = %q, want it cleared", user.Profile.Bio)
+	}
+}
+
+func TestUserService_UpdateProfile_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		update ProfileUpdate
+	}{
+		{"display name too long", ProfileUpdate{DisplayName: stringPtr(strings.Repeat("a", maxDisplayNameLength+1))}},
+		{"bio too long", ProfileUpdate{Bio: stringPtr(strings.Repeat("a", maxBioLength+1))}},
+		{"avatar not a URL", ProfileUpdate{AvatarURL: stringPtr("not a url")}},
+		{"avatar with unsafe scheme", ProfileUpdate{AvatarURL: stringPtr("javascript:alert(1)")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestUserService()
+			_, err := svc.UpdateProfile(context.Background(), "user-1", tt.update)
+			if !errors.Is(err, domain.ErrInvalidProfile) {
+				t.Errorf("UpdateProfile() error = %v, want %v", err, domain.ErrInvalidProfile)
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+ALTER TABLE auctions DROP COLUMN IF EXISTS two_factor_threshold;
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS user_two_factor;
@@ -0,0 +1,26 @@
+-- Public profile fields
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS display_name VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS avatar_url VARCHAR(2048) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS location VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS bio TEXT NOT NULL DEFAULT '';
+
+-- Stable per-auction bidder pseudonyms ("Bidder 3"), numbered in order of first bid
+CREATE TABLE IF NOT EXISTS auction_bidders (
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (auction_id, user_id),
+    CONSTRAINT unique_bidder_number UNIQUE (auction_id, number)
+);
+
+-- Backfill pseudonyms for existing bids
+INSERT INTO auction_bidders (auction_id, user_id, number)
+SELECT auction_id, user_id, ROW_NUMBER() OVER (PARTITION BY auction_id ORDER BY first_bid_at)
+FROM (
+    SELECT auction_id, user_id, MIN(created_at) AS first_bid_at
+    FROM bids
+    GROUP BY auction_id, user_id
+) first_bids
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,13 @@
+-- Proxy bids: bid automatically on a user's behalf up to their maximum
+CREATE TABLE IF NOT EXISTS proxy_bids (
+    id UUID PRIMARY KEY,
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    max_amount DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_proxy_bid UNIQUE (auction_id, user_id),
+    CONSTRAINT positive_max_amount CHECK (max_amount > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_proxy_bids_auction_max ON proxy_bids(auction_id, max_amount DESC, created_at ASC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS auction_events;
+ALTER TABLE auctions DROP COLUMN IF EXISTS last_event_seq;
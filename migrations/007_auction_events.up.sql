@@ -0,0 +1,12 @@
+-- Per-auction event sequence counter
+ALTER TABLE auctions ADD COLUMN IF NOT EXISTS last_event_seq BIGINT NOT NULL DEFAULT 0;
+
+-- Persistent auction event log, replayed to reconnecting real-time clients
+CREATE TABLE IF NOT EXISTS auction_events (
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    seq BIGINT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (auction_id, seq)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_auctions_status_start;
+DROP TABLE IF EXISTS auction_status_changes;
+ALTER TABLE auctions DROP COLUMN IF EXISTS paused_at;
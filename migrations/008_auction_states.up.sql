@@ -0,0 +1,16 @@
+-- When a paused auction was paused; its remaining time is frozen from then on
+ALTER TABLE auctions ADD COLUMN IF NOT EXISTS paused_at TIMESTAMP WITH TIME ZONE;
+
+-- Status history: pending, scheduled, active, paused, ended, cancelled, settled
+CREATE TABLE IF NOT EXISTS auction_status_changes (
+    id UUID PRIMARY KEY,
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_auction_status_changes_auction ON auction_status_changes(auction_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_auctions_status_start ON auctions(status, start_time);
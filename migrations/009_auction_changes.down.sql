@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS auction_changes;
+ALTER TABLE auctions DROP COLUMN IF EXISTS version;
@@ -0,0 +1,17 @@
+-- Incremented by every edit of an auction's settings, so concurrent edits
+-- cannot overwrite each other
+ALTER TABLE auctions ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+-- Change log of auction settings edited by the seller, one row per setting
+CREATE TABLE IF NOT EXISTS auction_changes (
+    id UUID PRIMARY KEY,
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    field VARCHAR(50) NOT NULL,
+    old_value TEXT NOT NULL,
+    new_value TEXT NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_auction_changes_auction ON auction_changes(auction_id, version);
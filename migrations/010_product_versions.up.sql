@@ -0,0 +1,3 @@
+-- Incremented by every update of a product; updates only apply to the version
+-- they were read at
+ALTER TABLE products ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
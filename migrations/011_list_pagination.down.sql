@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_bids_auction_created_id;
+DROP INDEX IF EXISTS idx_products_created_id;
+DROP INDEX IF EXISTS idx_auctions_bid_count_id;
+DROP INDEX IF EXISTS idx_auctions_price_id;
+DROP INDEX IF EXISTS idx_auctions_end_id;
+DROP INDEX IF EXISTS idx_auctions_created_id;
+ALTER TABLE auctions DROP COLUMN IF EXISTS bid_count;
@@ -0,0 +1,12 @@
+-- Number of bids on the auction, kept up to date by every bid so auction
+-- lists can be sorted by it
+ALTER TABLE auctions ADD COLUMN IF NOT EXISTS bid_count INTEGER NOT NULL DEFAULT 0;
+UPDATE auctions a SET bid_count = (SELECT COUNT(*) FROM bids b WHERE b.auction_id = a.id);
+
+-- Keyset pagination: every list order ends with the ID as a tie-breaker
+CREATE INDEX IF NOT EXISTS idx_auctions_created_id ON auctions(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_auctions_end_id ON auctions(end_time, id);
+CREATE INDEX IF NOT EXISTS idx_auctions_price_id ON auctions(current_price DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_auctions_bid_count_id ON auctions(bid_count DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_products_created_id ON products(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_bids_auction_created_id ON bids(auction_id, created_at DESC, id DESC);
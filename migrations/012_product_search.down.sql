@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_auctions_product_created;
+DROP INDEX IF EXISTS idx_products_search;
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,12 @@
+-- Full-text search over product names and descriptions; name matches rank
+-- higher than description matches
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_products_search ON products USING GIN (search_vector);
+
+-- Finds the latest auction of each product matched by a search
+CREATE INDEX IF NOT EXISTS idx_auctions_product_created ON auctions(product_id, created_at DESC);
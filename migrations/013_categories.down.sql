@@ -0,0 +1,8 @@
+ALTER TABLE auctions DROP COLUMN IF EXISTS soft_close_seconds;
+ALTER TABLE auctions DROP COLUMN IF EXISTS increment_table;
+DROP TABLE IF EXISTS product_attributes;
+DROP INDEX IF EXISTS idx_products_category;
+ALTER TABLE products DROP COLUMN IF EXISTS condition;
+ALTER TABLE products DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS categories;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
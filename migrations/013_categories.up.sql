@@ -0,0 +1,39 @@
+-- Roles grant permissions beyond those of every user; promote an admin with
+-- UPDATE users SET role = 'admin' WHERE email = '...'
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
+
+-- Category tree. attributes holds the attribute definitions of the category's
+-- products; increment_table and soft_close_seconds are the defaults of new
+-- auctions, inherited from the parent when NULL.
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY,
+    parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    name VARCHAR(100) NOT NULL,
+    attributes JSONB NOT NULL DEFAULT '[]',
+    increment_table JSONB,
+    soft_close_seconds INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_soft_close CHECK (soft_close_seconds >= 0)
+);
+
+-- Sibling names are unique, case-insensitively
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_parent_name
+    ON categories(coalesce(parent_id, '00000000-0000-0000-0000-000000000000'), lower(name));
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES categories(id) ON DELETE RESTRICT;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS condition VARCHAR(20) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_products_category ON products(category_id);
+
+-- Product attribute values, one row each so products can be filtered by them
+CREATE TABLE IF NOT EXISTS product_attributes (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (product_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_attributes_name_value ON product_attributes(name, value);
+
+-- Settings copied from the product's category when the auction is created
+ALTER TABLE auctions ADD COLUMN IF NOT EXISTS increment_table JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE auctions ADD COLUMN IF NOT EXISTS soft_close_seconds INTEGER NOT NULL DEFAULT 0;
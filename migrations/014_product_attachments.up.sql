@@ -0,0 +1,24 @@
+-- Product images and documents. The files are kept in the blob store under
+-- blob_key; thumbnail_key, width and height are only set for images.
+CREATE TABLE IF NOT EXISTS product_attachments (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    blob_key TEXT NOT NULL,
+    thumbnail_key TEXT NOT NULL DEFAULT '',
+    width INTEGER NOT NULL DEFAULT 0,
+    height INTEGER NOT NULL DEFAULT 0,
+    position INTEGER NOT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_attachment_kind CHECK (kind IN ('image', 'document')),
+    CONSTRAINT primary_is_image CHECK (NOT is_primary OR kind = 'image')
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_attachments_product ON product_attachments(product_id, kind, position);
+
+-- At most one primary image per product
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_attachments_primary ON product_attachments(product_id) WHERE is_primary;
@@ -0,0 +1,3 @@
+-- Product status: active, archived or deleted. Deleting a product only marks
+-- it, so its past auctions and bids still resolve it.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active';
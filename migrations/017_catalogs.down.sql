@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS catalog_lots;
+DROP TABLE IF EXISTS catalogs;
@@ -0,0 +1,27 @@
+-- Catalogs group the auctions of a sale as numbered lots that close one after
+-- another: lot n is scheduled to close at first_lot_end_time plus (n - 1)
+-- times lot_interval_seconds.
+CREATE TABLE IF NOT EXISTS catalogs (
+    id UUID PRIMARY KEY,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    terms TEXT NOT NULL DEFAULT '',
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    first_lot_end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    lot_interval_seconds INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_lot_interval CHECK (lot_interval_seconds > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalogs_owner ON catalogs(owner_id);
+CREATE INDEX IF NOT EXISTS idx_catalogs_created ON catalogs(created_at DESC, id DESC);
+
+-- An auction is a lot of at most one catalog. Lot numbers are renumbered in
+-- place when a lot is removed, so their uniqueness is checked at commit.
+CREATE TABLE IF NOT EXISTS catalog_lots (
+    auction_id UUID PRIMARY KEY REFERENCES auctions(id) ON DELETE CASCADE,
+    catalog_id UUID NOT NULL REFERENCES catalogs(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    CONSTRAINT unique_lot_number UNIQUE (catalog_id, number) DEFERRABLE INITIALLY DEFERRED
+);
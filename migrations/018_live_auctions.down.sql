@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS live_lots;
+
+DELETE FROM bids WHERE source = 'floor';
+ALTER TABLE bids DROP CONSTRAINT IF EXISTS valid_bid_source;
+ALTER TABLE bids DROP COLUMN IF EXISTS clerk_id;
+ALTER TABLE bids DROP COLUMN IF EXISTS paddle;
+ALTER TABLE bids DROP COLUMN IF EXISTS source;
+ALTER TABLE bids ALTER COLUMN user_id SET NOT NULL;
+
+ALTER TABLE auctions DROP COLUMN IF EXISTS live;
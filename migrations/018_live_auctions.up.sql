@@ -0,0 +1,23 @@
+-- Live auctions are run by an auctioneer and stay open until hammered or passed
+ALTER TABLE auctions ADD COLUMN IF NOT EXISTS live BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Floor bids are made in the sale room by paddle holders without an account
+-- and entered by a clerk
+ALTER TABLE bids ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS source VARCHAR(10) NOT NULL DEFAULT 'online';
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS paddle VARCHAR(20);
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS clerk_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE bids ADD CONSTRAINT valid_bid_source CHECK (
+    (source = 'online' AND user_id IS NOT NULL) OR (source = 'floor' AND paddle IS NOT NULL)
+);
+
+-- Sale room state of live auctions
+CREATE TABLE IF NOT EXISTS live_lots (
+    auction_id UUID PRIMARY KEY REFERENCES auctions(id) ON DELETE CASCADE,
+    auctioneer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    state VARCHAR(20) NOT NULL,
+    asking_price DECIMAL(10, 2) NOT NULL,
+    last_action VARCHAR(20) NOT NULL,
+    opened_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
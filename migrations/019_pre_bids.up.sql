@@ -0,0 +1,15 @@
+-- Pre-bids: absentee bids left before an auction opens, executed as proxy
+-- bids when it does
+CREATE TABLE IF NOT EXISTS pre_bids (
+    id UUID PRIMARY KEY,
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    max_amount DECIMAL(10, 2) NOT NULL,
+    executed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_pre_bid UNIQUE (auction_id, user_id),
+    CONSTRAINT positive_pre_bid_max_amount CHECK (max_amount > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pre_bids_pending ON pre_bids(auction_id) WHERE executed_at IS NULL;
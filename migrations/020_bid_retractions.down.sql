@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS bid_retractions;
+ALTER TABLE users DROP COLUMN IF EXISTS bid_retractions;
+ALTER TABLE bids DROP COLUMN IF EXISTS retracted_at;
@@ -0,0 +1,26 @@
+-- Retracted bids stay in the bid history but no longer count towards the price
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS retracted_at TIMESTAMP WITH TIME ZONE;
+
+-- Approved retractions count against the bidder's record
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bid_retractions INTEGER NOT NULL DEFAULT 0;
+
+-- Bidders' requests to retract a bid, reviewed by an admin
+CREATE TABLE IF NOT EXISTS bid_retractions (
+    id UUID PRIMARY KEY,
+    bid_id UUID NOT NULL REFERENCES bids(id) ON DELETE CASCADE,
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(30) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    review_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT valid_retraction_reason CHECK (reason IN ('wrong_amount', 'description_changed', 'seller_unreachable')),
+    CONSTRAINT valid_retraction_status CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+
+-- A bid has at most one retraction awaiting review
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bid_retractions_pending_bid ON bid_retractions(bid_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_bid_retractions_user ON bid_retractions(user_id, created_at DESC);
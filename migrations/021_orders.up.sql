@@ -0,0 +1,24 @@
+-- Orders: what the winner of a closed auction owes its seller
+CREATE TABLE IF NOT EXISTS orders (
+    id UUID PRIMARY KEY,
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    bid_id UUID NOT NULL REFERENCES bids(id) ON DELETE CASCADE,
+    buyer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    hammer_price DECIMAL(10, 2) NOT NULL,
+    buyer_premium DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    seller_commission DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    tax DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    total DECIMAL(10, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'awaiting_payment',
+    due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    -- Closing an auction again never creates a second order
+    CONSTRAINT unique_order_auction UNIQUE (auction_id),
+    CONSTRAINT valid_order_status CHECK (status IN ('awaiting_payment', 'paid', 'shipped', 'completed', 'cancelled'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_buyer ON orders(buyer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_seller ON orders(seller_id, created_at DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS fees;
+ALTER TABLE categories DROP COLUMN IF EXISTS seller_commission;
+ALTER TABLE categories DROP COLUMN IF EXISTS buyer_premium;
@@ -0,0 +1,6 @@
+-- Fee schedules of a category's auctions, inherited from the parent when NULL
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS buyer_premium JSONB;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS seller_commission JSONB;
+
+-- Itemized buyer's premium, seller commission and tax of an order
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS fees JSONB NOT NULL DEFAULT '[]';
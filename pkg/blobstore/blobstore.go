@@ -0,0 +1,40 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/saigenix/bidding-system/config"
+)
+
+// ErrNotFound is returned when getting a blob that does not exist
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore keeps files such as product images outside the database. Blobs
+// are addressed by slash-separated keys and handed to clients as signed URLs
+// that expire, so stores need not be publicly readable.
+type BlobStore interface {
+	// Put stores data under key, replacing any blob already there
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get returns the blob stored under key, or ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the blob under key; deleting a missing blob succeeds
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that downloads the blob until ttl has passed
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
+
+// New creates the blob store selected by the BLOB_DRIVER setting
+func New(cfg *config.Config) (BlobStore, error) {
+	switch cfg.Blob.Driver {
+	case "s3":
+		return NewS3Store(cfg.Blob.S3Endpoint, cfg.Blob.S3Region, cfg.Blob.S3Bucket,
+			cfg.Blob.S3AccessKey, cfg.Blob.S3SecretKey, cfg.Blob.S3PathStyle)
+	case "local", "":
+		return NewLocalStore(cfg.Blob.LocalDir, cfg.Server.PublicURL+LocalRoute, cfg.Blob.SigningSecret)
+	default:
+		return nil, fmt.Errorf("unknown blob driver: %s", cfg.Blob.Driver)
+	}
+}
@@ -0,0 +1,60 @@
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func TestFit_KeepsAspectRatio(t *testing.T) {
+	tests := []struct {
+		width, height int
+		wantW, wantH  int
+	}{
+		{1000, 500, 320, 160},
+		{500, 1000, 160, 320},
+		{320, 320, 320, 320},
+		{100, 50, 100, 50},
+		{5000, 3, 320, 1},
+	}
+	for _, tt := range tests {
+		got := Fit(image.NewRGBA(image.Rect(0, 0, tt.width, tt.height)), 320).Bounds()
+		if got.Dx() != tt.wantW || got.Dy() != tt.wantH {
+			t.Errorf("Fit(%dx%d) = %dx%d, want %dx%d", tt.width, tt.height, got.Dx(), got.Dy(), tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestFit_AveragesPixels(t *testing.T) {
+	// Alternating black and white columns average to grey
+	src := image.NewGray(image.Rect(0, 0, 40, 40))
+	for x := 0; x < 40; x += 2 {
+		for y := 0; y < 40; y++ {
+			src.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+
+	got := Fit(src, 20).RGBAAt(10, 10)
+	if got.R < 120 || got.R > 135 || got.A != 255 {
+		t.Errorf("Fit() pixel = %v, want mid grey", got)
+	}
+}
+
+func TestJPEG_FlattensTransparency(t *testing.T) {
+	data, err := JPEG(image.NewNRGBA(image.Rect(0, 0, 640, 480)), 320)
+	if err != nil {
+		t.Fatalf("JPEG() unexpected error: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("JPEG() output does not decode: %v", err)
+	}
+	if img.Bounds().Dx() != 320 || img.Bounds().Dy() != 240 {
+		t.Errorf("JPEG() size = %v, want 320x240", img.Bounds())
+	}
+	if r, _, _, _ := img.At(100, 100).RGBA(); r>>8 < 250 {
+		t.Errorf("JPEG() transparent pixel = %v, want white", img.At(100, 100))
+	}
+}
@@ -0,0 +1,66 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 Appendix B test vectors (SHA1), truncated to 6 digits
+func TestCode_RFC6238Vectors(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := Code(secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("Code() unexpected error: %v", err)
+		}
+		if code != tt.expected {
+			t.Errorf("Code(%d) = %q, want %q", tt.unix, code, tt.expected)
+		}
+	}
+}
+
+func TestValidate_Skew(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() unexpected error: %v", err)
+	}
+
+	now := time.Now()
+	previous, _ := Code(secret, now.Add(-Period))
+	stale, _ := Code(secret, now.Add(-3*Period))
+
+	if _, ok := Validate(secret, previous, now, 1); !ok {
+		t.Error("Validate() rejected a code from the previous step")
+	}
+	if _, ok := Validate(secret, stale, now, 1); ok {
+		t.Error("Validate() accepted a code outside the skew window")
+	}
+	if _, ok := Validate(secret, "12345", now, 1); ok {
+		t.Error("Validate() accepted a code with the wrong length")
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("JBSWY3DPEHPK3PXP", "Bidding System", "user@example.com")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/Bidding%20System:user@example.com?") {
+		t.Errorf("ProvisioningURI() = %q, unexpected label", uri)
+	}
+	if !strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") {
+		t.Errorf("ProvisioningURI() = %q, missing secret", uri)
+	}
+}
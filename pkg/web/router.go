@@ -6,24 +6,51 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"github.com/saigenix/bidding-system/internal/auth"
+	"github.com/saigenix/bidding-system/internal/domain"
 	"github.com/saigenix/bidding-system/internal/handler"
+	"github.com/saigenix/bidding-system/internal/realtime"
 	"github.com/saigenix/bidding-system/internal/service"
+	"github.com/saigenix/bidding-system/pkg/blobstore"
 )
 
 // SetupRouter initializes and configures the Gin router
 func SetupRouter(
 	authService *service.AuthService,
+	accountService *service.AccountService,
+	ticketService *service.StreamTicketService,
+	twoFactorService *service.TwoFactorService,
+	userService *service.UserService,
+	categoryService *service.CategoryService,
 	productService *service.ProductService,
+	attachmentService *service.AttachmentService,
 	auctionService *service.AuctionService,
+	searchService *service.SearchService,
+	importService *service.ImportService,
+	catalogService *service.CatalogService,
 	bidService *service.BidService,
+	liveService *service.LiveService,
+	retractionService *service.RetractionService,
+	feeService *service.FeeService,
+	orderService *service.OrderService,
+	eventService *service.EventService,
+	hub *realtime.Hub,
+	blobs blobstore.BlobStore,
+	allowedOrigins []string,
+	trustedProxies []string,
 ) *gin.Engine {
 	router := gin.Default()
 
+	// Login throttling keys on the client IP, so X-Forwarded-For is only
+	// believed from the configured proxies. The list was validated when the
+	// configuration was loaded.
+	_ = router.SetTrustedProxies(trustedProxies)
+
 	// CORS middleware (allow all origins for demo)
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag, Retry-After")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -31,11 +58,24 @@ func SetupRouter(
 		c.Next()
 	})
 
+	// Errors attached by handlers and auth middleware become problem responses
+	router.Use(handler.ProblemMiddleware())
+
 	// Initialize handlers
-	authHandler := handler.NewAuthHandler(authService)
-	productHandler := handler.NewProductHandler(productService)
-	auctionHandler := handler.NewAuctionHandler(auctionService)
-	bidHandler := handler.NewBidHandler(bidService)
+	authHandler := handler.NewAuthHandler(authService, accountService, ticketService)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorService)
+	userHandler := handler.NewUserHandler(userService)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	productHandler := handler.NewProductHandler(productService, attachmentService)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService)
+	auctionHandler := handler.NewAuctionHandler(auctionService, feeService)
+	searchHandler := handler.NewSearchHandler(searchService, attachmentService)
+	importHandler := handler.NewImportHandler(importService)
+	catalogHandler := handler.NewCatalogHandler(catalogService)
+	bidHandler := handler.NewBidHandler(bidService, eventService, hub, allowedOrigins)
+	liveHandler := handler.NewLiveHandler(liveService)
+	retractionHandler := handler.NewRetractionHandler(retractionService)
+	orderHandler := handler.NewOrderHandler(orderService)
 
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -45,17 +85,80 @@ func SetupRouter(
 	{
 		authRoutes.POST("/register", authHandler.Register)
 		authRoutes.POST("/login", authHandler.Login)
+		authRoutes.POST("/login/2fa", authHandler.LoginTwoFactor)
+		authRoutes.POST("/verify", authHandler.VerifyEmail)
+		authRoutes.POST("/verify/resend", authHandler.ResendVerification)
+		authRoutes.POST("/password/forgot", authHandler.ForgotPassword)
+		authRoutes.POST("/password/reset", authHandler.ResetPassword)
 	}
 
 	// Protected routes (require JWT)
 	jwtMiddleware := auth.JWTMiddleware(authService)
 
+	// Real-time routes also accept a stream ticket, since browsers cannot set
+	// the Authorization header on EventSource and WebSocket connections
+	streamMiddleware := auth.StreamMiddleware(authService, ticketService)
+
+	router.POST("/auth/stream-ticket", jwtMiddleware, authHandler.IssueStreamTicket)
+
+	twoFactorRoutes := router.Group("/auth/2fa")
+	twoFactorRoutes.Use(jwtMiddleware)
+	{
+		twoFactorRoutes.POST("/enroll", twoFactorHandler.Enroll)
+		twoFactorRoutes.POST("/confirm", twoFactorHandler.Confirm)
+		twoFactorRoutes.POST("/disable", twoFactorHandler.Disable)
+		twoFactorRoutes.POST("/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+	}
+
+	userRoutes := router.Group("/users")
+	userRoutes.Use(jwtMiddleware)
+	{
+		userRoutes.GET("/me", userHandler.GetMe)
+		userRoutes.PATCH("/me", userHandler.UpdateMe)
+		userRoutes.GET("/:id", userHandler.Get)
+	}
+
+	// Anyone signed in may browse categories; only admins manage them
+	requireAdmin := auth.RequireRole(userService, domain.RoleAdmin)
+	categoryRoutes := router.Group("/categories")
+	categoryRoutes.Use(jwtMiddleware)
+	{
+		categoryRoutes.GET("", categoryHandler.List)
+		categoryRoutes.GET("/:id", categoryHandler.Get)
+		categoryRoutes.POST("", requireAdmin, categoryHandler.Create)
+		categoryRoutes.PUT("/:id", requireAdmin, categoryHandler.Update)
+		categoryRoutes.DELETE("/:id", requireAdmin, categoryHandler.Delete)
+	}
+
 	productRoutes := router.Group("/products")
 	productRoutes.Use(jwtMiddleware)
 	{
 		productRoutes.POST("", productHandler.Create)
 		productRoutes.GET("/:id", productHandler.Get)
 		productRoutes.GET("", productHandler.List)
+		productRoutes.PATCH("/:id", productHandler.Update)
+		productRoutes.DELETE("/:id", productHandler.Delete)
+		productRoutes.POST("/:id/archive", productHandler.Archive)
+		productRoutes.POST("/:id/restore", productHandler.Restore)
+		productRoutes.POST("/:id/images", attachmentHandler.UploadImage)
+		productRoutes.PUT("/:id/images/order", attachmentHandler.ReorderImages)
+		productRoutes.POST("/:id/images/:attachment_id/primary", attachmentHandler.SetPrimaryImage)
+		productRoutes.POST("/:id/documents", attachmentHandler.UploadDocument)
+		productRoutes.DELETE("/:id/attachments/:attachment_id", attachmentHandler.Delete)
+	}
+
+	// Files of a local blob store, authorized by the signature in their URLs
+	if local, ok := blobs.(*blobstore.LocalStore); ok {
+		router.GET(blobstore.LocalRoute+"/*key", handler.NewBlobHandler(local).Serve)
+	}
+
+	router.GET("/search", jwtMiddleware, searchHandler.Search)
+
+	importRoutes := router.Group("/imports")
+	importRoutes.Use(jwtMiddleware)
+	{
+		importRoutes.POST("", importHandler.Create)
+		importRoutes.GET("/:id", importHandler.Get)
 	}
 
 	auctionRoutes := router.Group("/auctions")
@@ -63,19 +166,81 @@ func SetupRouter(
 	{
 		auctionRoutes.POST("", auctionHandler.Create)
 		auctionRoutes.GET("/:id", auctionHandler.Get)
+		auctionRoutes.PATCH("/:id", auctionHandler.Update)
 		auctionRoutes.GET("", auctionHandler.List)
 		auctionRoutes.POST("/:id/start", auctionHandler.Start)
 		auctionRoutes.POST("/:id/end", auctionHandler.End)
+		auctionRoutes.POST("/:id/schedule", auctionHandler.Schedule)
+		auctionRoutes.POST("/:id/pause", auctionHandler.Pause)
+		auctionRoutes.POST("/:id/resume", auctionHandler.Resume)
+		auctionRoutes.POST("/:id/cancel", auctionHandler.Cancel)
+		auctionRoutes.GET("/:id/history", auctionHandler.History)
+		auctionRoutes.GET("/:id/changes", auctionHandler.Changes)
+		auctionRoutes.GET("/:id/cost-estimate", auctionHandler.CostEstimate)
+		auctionRoutes.GET("/:id/watchers", bidHandler.GetWatchers)
 
 		// Bid routes under auctions
-		auctionRoutes.POST("/:auction_id/bids", bidHandler.PlaceBid)
-		auctionRoutes.GET("/:auction_id/bids", bidHandler.GetBids)
+		auctionRoutes.POST("/:id/bids", bidHandler.PlaceBid)
+		auctionRoutes.GET("/:id/bids", bidHandler.GetBids)
+		auctionRoutes.GET("/:id/pre-bid", bidHandler.GetPreBid)
+		auctionRoutes.PUT("/:id/pre-bid", bidHandler.SetPreBid)
+		auctionRoutes.DELETE("/:id/pre-bid", bidHandler.WithdrawPreBid)
+
+		// Anyone signed in may follow a live lot; only auctioneers run it
+		requireAuctioneer := auth.RequireRole(userService, domain.RoleAuctioneer)
+		auctionRoutes.GET("/:id/live", liveHandler.Get)
+		auctionRoutes.POST("/:id/live/open", requireAuctioneer, liveHandler.Open)
+		auctionRoutes.POST("/:id/live/ask", requireAuctioneer, liveHandler.Ask)
+		auctionRoutes.POST("/:id/live/floor-bids", requireAuctioneer, liveHandler.FloorBid)
+		auctionRoutes.POST("/:id/live/fair-warning", requireAuctioneer, liveHandler.FairWarning)
+		auctionRoutes.POST("/:id/live/sold", requireAuctioneer, liveHandler.Sell)
+		auctionRoutes.POST("/:id/live/pass", requireAuctioneer, liveHandler.Pass)
+	}
 
-		// Real-time routes (SSE and WebSocket)
-		auctionRoutes.GET("/:auction_id/bids/stream", bidHandler.StreamBids)
-		auctionRoutes.GET("/:auction_id/bids/ws", bidHandler.WebSocketHandler)
+	catalogRoutes := router.Group("/catalogs")
+	catalogRoutes.Use(jwtMiddleware)
+	{
+		catalogRoutes.POST("", catalogHandler.Create)
+		catalogRoutes.GET("", catalogHandler.List)
+		catalogRoutes.GET("/:id", catalogHandler.Get)
+		catalogRoutes.POST("/:id/lots", catalogHandler.AddLot)
+		catalogRoutes.DELETE("/:id/lots/:auction_id", catalogHandler.RemoveLot)
+		catalogRoutes.POST("/:id/schedule", catalogHandler.Schedule)
 	}
 
+	// Bidders request retractions of their bids; admins review them
+	retractionRoutes := router.Group("/retractions")
+	retractionRoutes.Use(jwtMiddleware)
+	{
+		retractionRoutes.POST("", retractionHandler.Create)
+		retractionRoutes.GET("", retractionHandler.ListMine)
+		retractionRoutes.GET("/pending", requireAdmin, retractionHandler.ListPending)
+		retractionRoutes.POST("/:id/approve", requireAdmin, retractionHandler.Approve)
+		retractionRoutes.POST("/:id/reject", requireAdmin, retractionHandler.Reject)
+	}
+
+	// Buyers and sellers see and advance the orders of auctions they won or sold
+	orderRoutes := router.Group("/orders")
+	orderRoutes.Use(jwtMiddleware)
+	{
+		orderRoutes.GET("", orderHandler.List)
+		orderRoutes.GET("/:id", orderHandler.Get)
+		orderRoutes.POST("/:id/mark-paid", orderHandler.MarkPaid)
+		orderRoutes.POST("/:id/ship", orderHandler.Ship)
+		orderRoutes.POST("/:id/complete", orderHandler.Complete)
+		orderRoutes.POST("/:id/cancel", orderHandler.Cancel)
+	}
+
+	// Real-time routes (SSE and WebSocket)
+	router.GET("/auctions/:id/bids/stream", streamMiddleware, bidHandler.StreamBids)
+	router.GET("/auctions/:id/bids/ws", streamMiddleware, bidHandler.WebSocketHandler)
+
+	// Multiplexed WebSocket for following many auctions over one connection
+	router.GET("/ws", streamMiddleware, bidHandler.HubWebSocket)
+
+	// Server clock for countdown synchronization
+	router.GET("/time", auctionHandler.ServerTime)
+
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
@@ -0,0 +1,192 @@
+// Package wsclient is a Go client for the real-time bidding WebSocket protocol
+// defined in pkg/wsproto. It correlates requests with their acks and errors and
+// delivers everything else (snapshots and bid events) on Events.
+package wsclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/saigenix/bidding-system/pkg/wsproto"
+)
+
+const writeWait = 10 * time.Second
+
+// Client is a connection to the bidding WebSocket. It is safe for concurrent use.
+type Client struct {
+	conn   *websocket.Conn
+	events chan *wsproto.Message
+	done   chan struct{}
+	nextID atomic.Uint64
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]chan *wsproto.Message
+	err     error
+}
+
+// Dial connects to url (e.g. ws://localhost:8080/auctions/<id>/bids/ws) with
+// the given handshake headers, typically an Authorization bearer token
+func Dial(ctx context.Context, url string, header http.Header) (*Client, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", url, err)
+	}
+
+	c := &Client{
+		conn:    conn,
+		events:  make(chan *wsproto.Message, 64),
+		done:    make(chan struct{}),
+		pending: make(map[string]chan *wsproto.Message),
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+// Events returns server-initiated messages: snapshots, bid events and errors
+// without a request ID. It must be drained: replies are read by the same loop.
+// The channel is closed when the connection ends.
+func (c *Client) Events() <-chan *wsproto.Message {
+	return c.events
+}
+
+// Err returns the error that ended the connection, if it has ended
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	c.writeMu.Lock()
+	c.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(writeWait))
+	c.writeMu.Unlock()
+	return c.conn.Close()
+}
+
+// Subscribe starts receiving snapshot and bid events for an auction
+func (c *Client) Subscribe(ctx context.Context, auctionID string) error {
+	_, err := c.Request(ctx, wsproto.TypeSubscribe, auctionID, nil)
+	return err
+}
+
+// Unsubscribe stops receiving events for an auction
+func (c *Client) Unsubscribe(ctx context.Context, auctionID string) error {
+	_, err := c.Request(ctx, wsproto.TypeUnsubscribe, auctionID, nil)
+	return err
+}
+
+// PlaceBid places a bid and returns it as acknowledged by the server
+func (c *Client) PlaceBid(ctx context.Context, auctionID string, amount float64) (*wsproto.BidPayload, error) {
+	ack, err := c.Request(ctx, wsproto.TypePlaceBid, auctionID, wsproto.PlaceBidPayload{Amount: amount})
+	if err != nil {
+		return nil, err
+	}
+	var bid wsproto.BidPayload
+	if err := ack.Decode(&bid); err != nil {
+		return nil, err
+	}
+	return &bid, nil
+}
+
+// SetProxy sets the maximum the server may bid on the caller's behalf
+func (c *Client) SetProxy(ctx context.Context, auctionID string, maxAmount float64) error {
+	_, err := c.Request(ctx, wsproto.TypeSetProxy, auctionID, wsproto.SetProxyPayload{MaxAmount: maxAmount})
+	return err
+}
+
+// Ping sends an application-level ping and waits for the pong
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Request(ctx, wsproto.TypePing, "", nil)
+	return err
+}
+
+// Request sends a message with a fresh request ID and waits for its reply. A
+// server error reply is returned as a *wsproto.ErrorPayload.
+func (c *Client) Request(ctx context.Context, typ wsproto.Type, auctionID string, payload any) (*wsproto.Message, error) {
+	requestID := strconv.FormatUint(c.nextID.Add(1), 10)
+	msg, err := wsproto.New(typ, requestID, auctionID, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := make(chan *wsproto.Message, 1)
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return nil, c.err
+	}
+	c.pending[requestID] = reply
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, requestID)
+		c.mu.Unlock()
+	}()
+
+	if err := c.write(msg); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-reply:
+		if resp.Type == wsproto.TypeError {
+			var serverErr wsproto.ErrorPayload
+			if err := resp.Decode(&serverErr); err != nil {
+				return nil, err
+			}
+			return nil, &serverErr
+		}
+		return resp, nil
+	case <-c.done:
+		return nil, c.Err()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *Client) write(msg *wsproto.Message) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	if err := c.conn.WriteJSON(msg); err != nil {
+		return fmt.Errorf("failed to send %s: %w", msg.Type, err)
+	}
+	return nil
+}
+
+func (c *Client) readLoop() {
+	defer close(c.events)
+	defer close(c.done)
+
+	for {
+		var msg wsproto.Message
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			c.mu.Lock()
+			c.err = fmt.Errorf("connection closed: %w", err)
+			c.mu.Unlock()
+			return
+		}
+
+		if msg.RequestID != "" && (msg.Type == wsproto.TypeAck || msg.Type == wsproto.TypeError || msg.Type == wsproto.TypePong) {
+			c.mu.Lock()
+			reply, ok := c.pending[msg.RequestID]
+			c.mu.Unlock()
+			if ok {
+				reply <- &msg
+				continue
+			}
+		}
+		c.events <- &msg
+	}
+}
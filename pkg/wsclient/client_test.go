@@ -0,0 +1,363 @@
+package wsclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/saigenix/bidding-system/internal/auth"
+	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/handler"
+	"github.com/saigenix/bidding-system/internal/mocks"
+	"github.com/saigenix/bidding-system/internal/realtime"
+	"github.com/saigenix/bidding-system/internal/service"
+	"github.com/saigenix/bidding-system/pkg/wsproto"
+)
+
+const (
+	testAuctionID = "auction-123"
+	testOrigin    = "https://app.example.com"
+)
+
+// newTestServer serves the bidding WebSockets backed by mock repositories. The
+// caller is authenticated as the user named in the X-User-ID header.
+func newTestServer(t *testing.T) *httptest.Server {
+	server, _ := newTestServerWithHub(t)
+	return server
+}
+
+func newTestServerWithHub(t *testing.T) (*httptest.Server, *realtime.Hub) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	userRepo := mocks.NewMockUserRepository()
+	verifiedAt := time.Now()
+	for _, id := range []string{"user-1", "user-2"} {
+		userRepo.Create(ctx, &domain.User{ID: id, Email: id + "@example.com", EmailVerifiedAt: &verifiedAt})
+	}
+	auctionRepo := mocks.NewMockAuctionRepository()
+	auctionRepo.Create(ctx, &domain.Auction{
+		ID:            testAuctionID,
+		StartTime:     time.Now().Add(-time.Hour),
+		EndTime:       time.Now().Add(time.Hour),
+		StartingPrice: 100.00,
+		CurrentPrice:  100.00,
+		Status:        domain.AuctionStatusActive,
+	})
+
+	twoFactor := service.NewTwoFactorService(mocks.NewMockTwoFactorRepository(), userRepo, "Test")
+	hub := realtime.NewHub(realtime.DefaultBufferSize)
+	eventService := service.NewEventService(mocks.NewMockAuctionEventRepository(), hub)
+	bidService := service.NewBidService(mocks.NewMockBidRepository(), auctionRepo, userRepo,
+		mocks.NewMockAuctionBidderRepository(), mocks.NewMockProxyBidRepository(), mocks.NewMockPreBidRepository(auctionRepo), mocks.NewMockLiveLotRepository(), mocks.NewMockTransactor(), twoFactor, eventService)
+	bidHandler := handler.NewBidHandler(bidService, eventService, hub, []string{testOrigin})
+
+	authenticate := func(c *gin.Context) {
+		c.Set("userID", c.GetHeader("X-User-ID"))
+	}
+	router := gin.New()
+	router.GET("/auctions/:id/bids/ws", authenticate, bidHandler.WebSocketHandler)
+	router.GET("/ws", authenticate, bidHandler.HubWebSocket)
+
+	// Ticket-authenticated socket, as used by browsers
+	throttle := service.NewLoginThrottle(mocks.NewMockLoginAttemptRepository(), mocks.NewMockSecurityEventRepository(), service.LoginThrottlePolicy{})
+	authService := service.NewAuthService(userRepo, twoFactor, throttle, "test-secret", 1)
+	tickets := service.NewStreamTicketService(mocks.NewMockUserTokenRepository(), time.Minute)
+	router.POST("/tickets", authenticate, func(c *gin.Context) {
+		ticket, _, _ := tickets.Issue(c.Request.Context(), c.GetHeader("X-User-ID"))
+		c.String(http.StatusOK, ticket)
+	})
+	router.GET("/browser/ws", auth.StreamMiddleware(authService, tickets), bidHandler.HubWebSocket)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server, hub
+}
+
+func dialAs(t *testing.T, server *httptest.Server, userID string) *Client {
+	t.Helper()
+	return dialPath(t, server, "/auctions/"+testAuctionID+"/bids/ws", userID)
+}
+
+func dialPath(t *testing.T, server *httptest.Server, path, userID string) *Client {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + path
+	client, err := Dial(context.Background(), url, http.Header{"X-User-ID": {userID}})
+	if err != nil {
+		t.Fatalf("Dial() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// nextEvent waits for the next event of type typ, skipping others
+func nextEvent(t *testing.T, client *Client, typ wsproto.Type, timeout time.Duration) *wsproto.Message {
+	t.Helper()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case msg, ok := <-client.Events():
+			if !ok {
+				t.Fatalf("connection closed waiting for %s: %v", typ, client.Err())
+			}
+			if msg.Type == typ {
+				return msg
+			}
+		case <-deadline:
+			t.Fatalf("timed out waiting for %s event", typ)
+		}
+	}
+}
+
+func TestClient_InitialSnapshotAndPing(t *testing.T) {
+	server := newTestServer(t)
+	client := dialAs(t, server, "user-1")
+
+	msg := nextEvent(t, client, wsproto.TypeSnapshot, time.Second)
+	if msg.AuctionID != testAuctionID {
+		t.Errorf("snapshot auction = %q, want %q", msg.AuctionID, testAuctionID)
+	}
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() unexpected error: %v", err)
+	}
+}
+
+func TestClient_PlaceBid(t *testing.T) {
+	server := newTestServer(t)
+	client := dialAs(t, server, "user-1")
+	ctx := context.Background()
+
+	bid, err := client.PlaceBid(ctx, testAuctionID, 150.00)
+	if err != nil {
+		t.Fatalf("PlaceBid() unexpected error: %v", err)
+	}
+	if bid.Amount != 150.00 || !bid.IsMine || bid.Bidder != "Bidder 1" {
+		t.Errorf("PlaceBid() ack = %+v, want own 150.00 bid by Bidder 1", bid)
+	}
+
+	// Rejections come back as errors correlated with the request
+	_, err = client.PlaceBid(ctx, testAuctionID, 120.00)
+	var serverErr *wsproto.ErrorPayload
+	if !errors.As(err, &serverErr) || serverErr.Code != wsproto.CodeBidRejected {
+		t.Errorf("PlaceBid() error = %v, want %s", err, wsproto.CodeBidRejected)
+	}
+}
+
+func TestClient_SetProxyAndBidEvents(t *testing.T) {
+	server := newTestServer(t)
+	watcher := dialAs(t, server, "user-1")
+	bidder := dialAs(t, server, "user-2")
+	ctx := context.Background()
+
+	nextEvent(t, watcher, wsproto.TypeSnapshot, time.Second)
+	if err := watcher.SetProxy(ctx, testAuctionID, 300.00); err != nil {
+		t.Fatalf("SetProxy() unexpected error: %v", err)
+	}
+	if _, err := bidder.PlaceBid(ctx, testAuctionID, 200.00); err != nil {
+		t.Fatalf("PlaceBid() unexpected error: %v", err)
+	}
+
+	// The proxy opens at 101, then answers the 200 bid with 201
+	var last wsproto.BidPayload
+	for last.Amount != 201.00 {
+		msg := nextEvent(t, watcher, wsproto.TypeBid, 5*time.Second)
+		if err := msg.Decode(&last); err != nil {
+			t.Fatalf("Decode() unexpected error: %v", err)
+		}
+	}
+	if !last.IsMine {
+		t.Error("proxy bid event IsMine = false, want true")
+	}
+}
+
+func TestClient_Unsubscribe(t *testing.T) {
+	server := newTestServer(t)
+	client := dialAs(t, server, "user-1")
+
+	if err := client.Unsubscribe(context.Background(), testAuctionID); err != nil {
+		t.Errorf("Unsubscribe() unexpected error: %v", err)
+	}
+	if err := client.Subscribe(context.Background(), testAuctionID); err != nil {
+		t.Errorf("Subscribe() unexpected error: %v", err)
+	}
+}
+
+func TestClient_UnsupportedVersion(t *testing.T) {
+	server := newTestServer(t)
+	client := dialAs(t, server, "user-1")
+	nextEvent(t, client, wsproto.TypeSnapshot, time.Second)
+
+	if err := client.conn.WriteJSON(map[string]any{"v": 99, "type": "ping", "request_id": "r1"}); err != nil {
+		t.Fatalf("WriteJSON() unexpected error: %v", err)
+	}
+	// No request is pending for "r1", so the error arrives as an event
+	msg := nextEvent(t, client, wsproto.TypeError, time.Second)
+	var payload wsproto.ErrorPayload
+	msg.Decode(&payload)
+	if payload.Code != wsproto.CodeUnsupportedVersion || msg.RequestID != "r1" {
+		t.Errorf("error = %+v for request %q, want %s for r1", payload, msg.RequestID, wsproto.CodeUnsupportedVersion)
+	}
+}
+
+func TestClient_HubSubscriptions(t *testing.T) {
+	server, hub := newTestServerWithHub(t)
+	client := dialPath(t, server, "/ws", "user-1")
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping() unexpected error: %v", err)
+	}
+	if got := hub.SubscriberCount(testAuctionID); got != 0 {
+		t.Errorf("SubscriberCount() before subscribing = %d, want 0", got)
+	}
+
+	if err := client.Subscribe(ctx, testAuctionID); err != nil {
+		t.Fatalf("Subscribe() unexpected error: %v", err)
+	}
+	if got := hub.SubscriberCount(testAuctionID); got != 1 {
+		t.Errorf("SubscriberCount() after subscribing = %d, want 1", got)
+	}
+
+	bidder := dialPath(t, server, "/ws", "user-2")
+	if _, err := bidder.PlaceBid(ctx, testAuctionID, 150.00); err != nil {
+		t.Fatalf("PlaceBid() unexpected error: %v", err)
+	}
+	msg := nextEvent(t, client, wsproto.TypeBid, time.Second)
+	var bid wsproto.BidPayload
+	msg.Decode(&bid)
+	if bid.Amount != 150.00 || bid.IsMine {
+		t.Errorf("bid event = %+v, want someone else's 150.00 bid", bid)
+	}
+}
+
+func TestClient_ServerShutdown(t *testing.T) {
+	server, hub := newTestServerWithHub(t)
+	client := dialPath(t, server, "/ws", "user-1")
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := hub.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() unexpected error: %v", err)
+	}
+
+	for range client.Events() {
+	}
+	if !websocket.IsCloseError(errors.Unwrap(client.Err()), websocket.CloseGoingAway) {
+		t.Errorf("connection error = %v, want close 1001", client.Err())
+	}
+}
+
+// issueTicket fetches a stream ticket for userID from the test server
+func issueTicket(t *testing.T, server *httptest.Server, userID string) string {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/tickets", nil)
+	req.Header.Set("X-User-ID", userID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("issuing ticket: %v", err)
+	}
+	defer resp.Body.Close()
+	ticket, _ := io.ReadAll(resp.Body)
+	return string(ticket)
+}
+
+func TestClient_StreamTicketSubprotocol(t *testing.T) {
+	server := newTestServer(t)
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/browser/ws"
+	ticket := issueTicket(t, server, "user-1")
+	header := http.Header{
+		"Origin":                 {testOrigin},
+		"Sec-WebSocket-Protocol": {wsproto.Subprotocol + ", " + wsproto.TicketSubprotocolPrefix + ticket},
+	}
+
+	client, err := Dial(context.Background(), url, header)
+	if err != nil {
+		t.Fatalf("Dial() unexpected error: %v", err)
+	}
+	defer client.Close()
+	if got := client.conn.Subprotocol(); got != wsproto.Subprotocol {
+		t.Errorf("Subprotocol() = %q, want %q", got, wsproto.Subprotocol)
+	}
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() unexpected error: %v", err)
+	}
+
+	// Tickets are single-use
+	if _, err := Dial(context.Background(), url, header); !errors.Is(err, websocket.ErrBadHandshake) {
+		t.Errorf("Dial() with a used ticket error = %v, want %v", err, websocket.ErrBadHandshake)
+	}
+}
+
+func TestClient_StreamTicketQuery(t *testing.T) {
+	server := newTestServer(t)
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/browser/ws?ticket=" + issueTicket(t, server, "user-1")
+
+	client, err := Dial(context.Background(), url, nil)
+	if err != nil {
+		t.Fatalf("Dial() unexpected error: %v", err)
+	}
+	defer client.Close()
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() unexpected error: %v", err)
+	}
+}
+
+func TestClient_OriginAllowlist(t *testing.T) {
+	server := newTestServer(t)
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	for _, tc := range []struct {
+		origin string
+		ok     bool
+	}{
+		{testOrigin, true},
+		{server.URL, true}, // same origin
+		{"https://evil.example.com", false},
+	} {
+		client, err := Dial(context.Background(), url, http.Header{"X-User-ID": {"user-1"}, "Origin": {tc.origin}})
+		if tc.ok && err != nil {
+			t.Errorf("Dial() from %s unexpected error: %v", tc.origin, err)
+		}
+		if !tc.ok && !errors.Is(err, websocket.ErrBadHandshake) {
+			t.Errorf("Dial() from %s error = %v, want %v", tc.origin, err, websocket.ErrBadHandshake)
+		}
+		if client != nil {
+			client.Close()
+		}
+	}
+}
+
+func TestClient_TickEvents(t *testing.T) {
+	server, hub := newTestServerWithHub(t)
+	client := dialAs(t, server, "user-1")
+	nextEvent(t, client, wsproto.TypeSnapshot, time.Second)
+
+	now := time.Now()
+	hub.Publish(&domain.AuctionEvent{
+		AuctionID: testAuctionID,
+		Type:      domain.AuctionEventTick,
+		Auction:   &domain.Auction{ID: testAuctionID, Status: domain.AuctionStatusActive, EndTime: now.Add(90 * time.Second)},
+		CreatedAt: now,
+	})
+
+	msg := nextEvent(t, client, wsproto.TypeTick, time.Second)
+	var tick wsproto.ClockPayload
+	if err := msg.Decode(&tick); err != nil {
+		t.Fatalf("Decode() unexpected error: %v", err)
+	}
+	if tick.RemainingSeconds != 90 || !tick.ServerTime.Equal(now) || tick.Seq != 0 {
+		t.Errorf("tick = %+v, want 90s remaining at %v without seq", tick, now)
+	}
+}
@@ -0,0 +1,211 @@
+// Package wsproto defines the versioned JSON message protocol spoken over the
+// real-time bidding WebSocket. Every frame is a single Message envelope; the
+// Type decides how its Payload is decoded.
+package wsproto
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Version is the protocol version implemented by this package. Messages with
+// any other version are rejected with CodeUnsupportedVersion.
+const Version = 1
+
+// Subprotocol is the WebSocket subprotocol name of this protocol version. The
+// server selects it whenever the client offers it.
+const Subprotocol = "bidding.v1"
+
+// TicketSubprotocolPrefix marks a stream ticket offered in the client's
+// subprotocol list, e.g. "ticket.<value>". Browsers cannot set an
+// Authorization header on a WebSocket, so they pass
+// [Subprotocol, TicketSubprotocolPrefix + ticket] instead; offering Subprotocol
+// as well is required, since browsers fail the handshake when the server
+// selects none of the offered subprotocols.
+const TicketSubprotocolPrefix = "ticket."
+
+// Type identifies the kind of a message
+type Type string
+
+// Client to server messages
+const (
+	TypeSubscribe   Type = "subscribe"
+	TypeUnsubscribe Type = "unsubscribe"
+	TypePlaceBid    Type = "place_bid"
+	TypeSetProxy    Type = "set_proxy"
+	TypePing        Type = "ping"
+)
+
+// Server to client messages
+const (
+	// TypeAck confirms the request with the same request ID
+	TypeAck Type = "ack"
+	// TypeError rejects the request with the same request ID, or reports a
+	// connection-level problem when the request ID is empty
+	TypeError Type = "error"
+	// TypeSnapshot carries the current bids of an auction after subscribing
+	TypeSnapshot Type = "snapshot"
+	// TypeBid announces a new bid on a subscribed auction
+	TypeBid Type = "bid"
+	// TypeTick periodically reports the clock of an active subscribed auction
+	TypeTick Type = "tick"
+	// TypeClosing announces that bidding on a subscribed auction has stopped;
+	// TypeClosed follows once the result is final
+	TypeClosing Type = "closing"
+	TypeClosed  Type = "closed"
+	// TypeStatus announces any other status change of a subscribed auction,
+	// such as pausing, resuming or cancelling
+	TypeStatus Type = "status"
+	// TypeUpdated announces that the seller edited a subscribed auction's
+	// settings; its change log lists what changed
+	TypeUpdated Type = "updated"
+	// TypeExtended announces that a late bid extended a subscribed auction's
+	// end time under soft close
+	TypeExtended Type = "extended"
+	// TypeLive announces an auctioneer's action on a subscribed live auction,
+	// such as a new asking price, fair warning or the hammer
+	TypeLive Type = "live"
+	// TypeRetracted announces that a bid on a subscribed auction was
+	// retracted and the price recomputed without it
+	TypeRetracted Type = "retracted"
+	TypePong      Type = "pong"
+)
+
+// Error codes carried in ErrorPayload
+const (
+	CodeBadRequest         = "bad_request"
+	CodeUnsupportedVersion = "unsupported_version"
+	CodeUnknownType        = "unknown_type"
+	CodeForbidden          = "forbidden"
+	CodeBidRejected        = "bid_rejected"
+	CodeInternal           = "internal_error"
+)
+
+// Message is the envelope of every frame in both directions
+type Message struct {
+	Version   int             `json:"v"`
+	Type      Type            `json:"type"`
+	RequestID string          `json:"request_id,omitempty"`
+	AuctionID string          `json:"auction_id,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+// New builds a message of the current version, encoding payload if non-nil
+func New(typ Type, requestID, auctionID string, payload any) (*Message, error) {
+	msg := &Message{
+		Version:   Version,
+		Type:      typ,
+		RequestID: requestID,
+		AuctionID: auctionID,
+	}
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s payload: %w", typ, err)
+		}
+		msg.Payload = raw
+	}
+	return msg, nil
+}
+
+// NewError builds an error reply to the request with requestID
+func NewError(requestID, auctionID, code, message string) *Message {
+	msg, _ := New(TypeError, requestID, auctionID, ErrorPayload{Code: code, Message: message})
+	return msg
+}
+
+// Decode unmarshals the payload into v
+func (m *Message) Decode(v any) error {
+	if len(m.Payload) == 0 {
+		return fmt.Errorf("missing %s payload", m.Type)
+	}
+	if err := json.Unmarshal(m.Payload, v); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", m.Type, err)
+	}
+	return nil
+}
+
+// PlaceBidPayload is the payload of TypePlaceBid
+type PlaceBidPayload struct {
+	Amount float64 `json:"amount"`
+}
+
+// SetProxyPayload is the payload of TypeSetProxy and of its ack
+type SetProxyPayload struct {
+	MaxAmount float64 `json:"max_amount"`
+}
+
+// BidPayload describes a bid. It is the payload of TypeBid and of the ack to
+// TypePlaceBid. Bidders are identified by their per-auction pseudonym only,
+// or by their paddle for floor bids, whose Source is "floor" rather than
+// "online". Retracted bids, listed in snapshots, no longer count towards the
+// price. Seq is the auction event sequence number, set on TypeBid messages.
+type BidPayload struct {
+	ID        string    `json:"id"`
+	Seq       int64     `json:"seq,omitempty"`
+	Bidder    string    `json:"bidder"`
+	IsMine    bool      `json:"is_mine"`
+	Amount    float64   `json:"amount"`
+	Source    string    `json:"source"`
+	Retracted bool      `json:"retracted"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ClockPayload is the payload of TypeTick, TypeClosing, TypeClosed,
+// TypeStatus, TypeUpdated and TypeExtended. Clients compare ServerTime with
+// their own clock to correct countdowns. EndTime includes any extensions; RemainingSeconds stays frozen
+// while the auction is paused. Seq is set on every message but ticks, which
+// are not logged.
+type ClockPayload struct {
+	Seq              int64     `json:"seq,omitempty"`
+	ServerTime       time.Time `json:"server_time"`
+	EndTime          time.Time `json:"end_time"`
+	RemainingSeconds int64     `json:"remaining_seconds"`
+	Status           string    `json:"status"`
+	CurrentPrice     float64   `json:"current_price"`
+}
+
+// LivePayload is the payload of TypeLive. Action is what the auctioneer did
+// (opened, asked, bid, fair_warning, sold or passed) and State where the lot
+// now stands (open, fair_warning, sold or passed). Bids must offer at least
+// AskingPrice.
+type LivePayload struct {
+	Seq          int64     `json:"seq,omitempty"`
+	ServerTime   time.Time `json:"server_time"`
+	Action       string    `json:"action"`
+	State        string    `json:"state"`
+	AskingPrice  float64   `json:"asking_price"`
+	Status       string    `json:"status"`
+	CurrentPrice float64   `json:"current_price"`
+}
+
+// RetractedPayload is the payload of TypeRetracted: the retracted bid, and
+// the auction's price recomputed from the remaining bids
+type RetractedPayload struct {
+	Seq          int64     `json:"seq,omitempty"`
+	ServerTime   time.Time `json:"server_time"`
+	BidID        string    `json:"bid_id"`
+	Bidder       string    `json:"bidder"`
+	IsMine       bool      `json:"is_mine"`
+	Amount       float64   `json:"amount"`
+	Status       string    `json:"status"`
+	CurrentPrice float64   `json:"current_price"`
+}
+
+// SnapshotPayload is the payload of TypeSnapshot: the latest bids, up to 100,
+// newest first. Older bids are paged through GET /auctions/{id}/bids.
+type SnapshotPayload struct {
+	Bids []BidPayload `json:"bids"`
+}
+
+// ErrorPayload is the payload of TypeError
+type ErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error implements error so clients can return rejected requests directly
+func (e *ErrorPayload) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
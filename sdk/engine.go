@@ -9,10 +9,13 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/saigenix/bidding-system/config"
 	"github.com/saigenix/bidding-system/internal/domain"
+	"github.com/saigenix/bidding-system/internal/realtime"
 	"github.com/saigenix/bidding-system/internal/repository/postgres"
 	"github.com/saigenix/bidding-system/internal/service"
+	"github.com/saigenix/bidding-system/pkg/blobstore"
 	"github.com/saigenix/bidding-system/pkg/db"
 	"github.com/saigenix/bidding-system/pkg/logger"
+	"github.com/saigenix/bidding-system/pkg/mailer"
 )
 
 // Engine is the main bidding system SDK
@@ -23,18 +26,62 @@ type Engine struct {
 
 	// Infrastructure
 	dbPool *pgxpool.Pool
+	mailer mailer.Mailer
+
+	// Hub fans auction events out to live WebSocket connections
+	Hub *realtime.Hub
+
+	// Blobs stores product images and documents
+	Blobs blobstore.BlobStore
 
 	// Repositories
-	userRepo    domain.UserRepository
-	productRepo domain.ProductRepository
-	auctionRepo domain.AuctionRepository
-	bidRepo     domain.BidRepository
+	userRepo     domain.UserRepository
+	tokenRepo    domain.UserTokenRepository
+	twoFARepo    domain.TwoFactorRepository
+	attemptRepo  domain.LoginAttemptRepository
+	eventRepo    domain.SecurityEventRepository
+	productRepo  domain.ProductRepository
+	categoryRepo domain.CategoryRepository
+	attachRepo   domain.AttachmentRepository
+	auctionRepo  domain.AuctionRepository
+	searchRepo   domain.SearchRepository
+	importRepo   domain.ImportRepository
+	catalogRepo  domain.CatalogRepository
+	bidRepo      domain.BidRepository
+	bidderRepo   domain.AuctionBidderRepository
+	proxyRepo    domain.ProxyBidRepository
+	preBidRepo   domain.PreBidRepository
+	liveRepo     domain.LiveLotRepository
+	retractRepo  domain.RetractionRepository
+	orderRepo    domain.OrderRepository
+	logRepo      domain.AuctionEventRepository
+	statusRepo   domain.AuctionStatusChangeRepository
+	changeRepo   domain.AuctionChangeRepository
+	transactor   domain.Transactor
 
 	// Services
-	AuthService    *service.AuthService
-	ProductService *service.ProductService
-	AuctionService *service.AuctionService
-	BidService     *service.BidService
+	AuthService       *service.AuthService
+	AccountService    *service.AccountService
+	TicketService     *service.StreamTicketService
+	TwoFactorService  *service.TwoFactorService
+	UserService       *service.UserService
+	CategoryService   *service.CategoryService
+	ProductService    *service.ProductService
+	AttachmentService *service.AttachmentService
+	AuctionService    *service.AuctionService
+	SearchService     *service.SearchService
+	ImportService     *service.ImportService
+	CatalogService    *service.CatalogService
+	BidService        *service.BidService
+	LiveService       *service.LiveService
+	RetractionService *service.RetractionService
+	FeeService        *service.FeeService
+	OrderService      *service.OrderService
+	EventService      *service.EventService
+	ClockService      *service.ClockService
+
+	// stopWorkers stops the background workers started by Start
+	stopWorkers context.CancelFunc
 }
 
 // NewEngine creates a new bidding system engine with the given options
@@ -57,6 +104,15 @@ func NewEngine(opts ...Option) (*Engine, error) {
 		}
 	}
 
+	// Default fee schedules are checked before connecting to anything
+	var fees domain.FeeSchedules
+	if fees.BuyerPremium, err = feeSchedule("SETTLEMENT_BUYER_PREMIUM", cfg.Settlement.BuyerPremium); err != nil {
+		return nil, err
+	}
+	if fees.SellerCommission, err = feeSchedule("SETTLEMENT_SELLER_COMMISSION", cfg.Settlement.SellerCommission); err != nil {
+		return nil, err
+	}
+
 	// Initialize database if not provided
 	if engine.dbPool == nil {
 		pool, err := db.NewPostgresPool(cfg)
@@ -66,17 +122,89 @@ func NewEngine(opts ...Option) (*Engine, error) {
 		engine.dbPool = pool
 	}
 
+	// Initialize mailer if not provided
+	if engine.mailer == nil {
+		m, err := mailer.New(cfg, engine.logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create mailer: %w", err)
+		}
+		engine.mailer = m
+	}
+
+	// Initialize blob store if not provided
+	if engine.Blobs == nil {
+		blobs, err := blobstore.New(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create blob store: %w", err)
+		}
+		engine.Blobs = blobs
+	}
+
+	engine.Hub = realtime.NewHub(cfg.Realtime.SubscriberBuffer)
+
 	// Initialize repositories
 	engine.userRepo = postgres.NewUserRepository(engine.dbPool)
+	engine.tokenRepo = postgres.NewUserTokenRepository(engine.dbPool)
+	engine.twoFARepo = postgres.NewTwoFactorRepository(engine.dbPool)
+	engine.attemptRepo = postgres.NewLoginAttemptRepository(engine.dbPool)
+	engine.eventRepo = postgres.NewSecurityEventRepository(engine.dbPool)
 	engine.productRepo = postgres.NewProductRepository(engine.dbPool)
+	engine.categoryRepo = postgres.NewCategoryRepository(engine.dbPool)
+	engine.attachRepo = postgres.NewAttachmentRepository(engine.dbPool)
 	engine.auctionRepo = postgres.NewAuctionRepository(engine.dbPool)
+	engine.searchRepo = postgres.NewSearchRepository(engine.dbPool)
+	engine.importRepo = postgres.NewImportRepository(engine.dbPool)
+	engine.catalogRepo = postgres.NewCatalogRepository(engine.dbPool)
 	engine.bidRepo = postgres.NewBidRepository(engine.dbPool)
+	engine.bidderRepo = postgres.NewAuctionBidderRepository(engine.dbPool)
+	engine.proxyRepo = postgres.NewProxyBidRepository(engine.dbPool)
+	engine.preBidRepo = postgres.NewPreBidRepository(engine.dbPool)
+	engine.liveRepo = postgres.NewLiveLotRepository(engine.dbPool)
+	engine.retractRepo = postgres.NewRetractionRepository(engine.dbPool)
+	engine.orderRepo = postgres.NewOrderRepository(engine.dbPool)
+	engine.logRepo = postgres.NewAuctionEventRepository(engine.dbPool)
+	engine.statusRepo = postgres.NewAuctionStatusChangeRepository(engine.dbPool)
+	engine.changeRepo = postgres.NewAuctionChangeRepository(engine.dbPool)
+	engine.transactor = postgres.NewTransactor(engine.dbPool)
 
 	// Initialize services
-	engine.AuthService = service.NewAuthService(engine.userRepo, cfg.JWT.Secret, cfg.JWT.ExpirationHour)
-	engine.ProductService = service.NewProductService(engine.productRepo)
-	engine.AuctionService = service.NewAuctionService(engine.auctionRepo)
-	engine.BidService = service.NewBidService(engine.bidRepo, engine.auctionRepo)
+	engine.TwoFactorService = service.NewTwoFactorService(engine.twoFARepo, engine.userRepo, cfg.Auth.TOTPIssuer)
+	loginThrottle := service.NewLoginThrottle(engine.attemptRepo, engine.eventRepo, service.LoginThrottlePolicy{
+		AccountThreshold: cfg.Auth.LockoutAccountThreshold,
+		IPThreshold:      cfg.Auth.LockoutIPThreshold,
+		LockoutDuration:  time.Duration(cfg.Auth.LockoutDurationMinute) * time.Minute,
+		Window:           time.Duration(cfg.Auth.LockoutWindowMinute) * time.Minute,
+		BaseDelay:        time.Duration(cfg.Auth.LoginDelayBaseMS) * time.Millisecond,
+		MaxDelay:         time.Duration(cfg.Auth.LoginDelayMaxMS) * time.Millisecond,
+	})
+	engine.AuthService = service.NewAuthService(engine.userRepo, engine.TwoFactorService, loginThrottle, cfg.JWT.Secret, cfg.JWT.ExpirationHour)
+	engine.AccountService = service.NewAccountService(
+		engine.userRepo,
+		engine.tokenRepo,
+		engine.mailer,
+		cfg.Server.PublicURL,
+		time.Duration(cfg.Auth.VerificationTTLHour)*time.Hour,
+		time.Duration(cfg.Auth.PasswordResetTTLMinute)*time.Minute,
+	)
+	engine.TicketService = service.NewStreamTicketService(engine.tokenRepo, time.Duration(cfg.Auth.StreamTicketTTLSecond)*time.Second)
+	engine.UserService = service.NewUserService(engine.userRepo)
+	engine.CategoryService = service.NewCategoryService(engine.categoryRepo, engine.productRepo)
+	engine.ProductService = service.NewProductService(engine.productRepo, engine.categoryRepo, engine.auctionRepo)
+	engine.AttachmentService = service.NewAttachmentService(engine.attachRepo, engine.productRepo, engine.Blobs,
+		time.Duration(cfg.Blob.URLTTLSecond)*time.Second)
+	engine.EventService = service.NewEventService(engine.logRepo, engine.Hub)
+	engine.AuctionService = service.NewAuctionService(engine.auctionRepo, engine.statusRepo, engine.changeRepo, engine.productRepo, engine.categoryRepo, engine.bidRepo, engine.transactor, engine.EventService)
+	engine.SearchService = service.NewSearchService(engine.searchRepo, engine.categoryRepo)
+	engine.ImportService = service.NewImportService(engine.importRepo, engine.ProductService, engine.AuctionService)
+	engine.CatalogService = service.NewCatalogService(engine.catalogRepo, engine.AuctionService, engine.auctionRepo, engine.EventService)
+	engine.BidService = service.NewBidService(engine.bidRepo, engine.auctionRepo, engine.userRepo, engine.bidderRepo, engine.proxyRepo, engine.preBidRepo, engine.liveRepo, engine.transactor, engine.TwoFactorService, engine.EventService)
+	engine.LiveService = service.NewLiveService(engine.liveRepo, engine.auctionRepo, engine.AuctionService, engine.BidService, engine.EventService)
+	engine.RetractionService = service.NewRetractionService(engine.retractRepo, engine.bidRepo, engine.auctionRepo, engine.userRepo, engine.proxyRepo, engine.transactor, engine.BidService, engine.EventService)
+	engine.FeeService = service.NewFeeService(engine.auctionRepo, engine.productRepo, engine.categoryRepo, fees, cfg.Settlement.TaxPercent)
+	engine.OrderService = service.NewOrderService(engine.orderRepo, engine.auctionRepo, engine.bidRepo, engine.productRepo, engine.AuctionService,
+		engine.FeeService, time.Duration(cfg.Settlement.PaymentDueDay)*24*time.Hour)
+	engine.ClockService = service.NewClockService(engine.auctionRepo, engine.AuctionService, engine.CatalogService, engine.BidService, engine.OrderService, engine.EventService,
+		time.Duration(cfg.Realtime.TickIntervalSecond)*time.Second, engine.logger)
 
 	engine.logger.Info().Msg("Bidding system engine initialized")
 	return engine, nil
@@ -86,16 +214,63 @@ func NewEngine(opts ...Option) (*Engine, error) {
 func (e *Engine) Start() error {
 	e.logger.Info().Msg("Starting bidding system engine")
 
-	// TODO: Start background workers (e.g., auto-end expired auctions)
-	// go e.auctionEndWorker()
+	ctx, cancel := context.WithCancel(context.Background())
+	e.stopWorkers = cancel
+	go e.clockWorker(ctx)
 
 	return nil
 }
 
+// clockWorker ticks the auction clock until ctx is done, sending countdown
+// events to live subscribers and closing expired auctions
+func (e *Engine) clockWorker(ctx context.Context) {
+	ticker := time.NewTicker(e.ClockService.Interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.ClockService.Tick(ctx, now)
+		}
+	}
+}
+
+// Drain closes all live real-time connections, waiting for them until ctx is
+// done. Call it before shutting down the HTTP server: hijacked WebSocket
+// connections are not tracked by http.Server.Shutdown.
+func (e *Engine) Drain(ctx context.Context) error {
+	e.logger.Info().Msg("Draining real-time connections")
+	return e.Hub.Shutdown(ctx)
+}
+
+// ShutdownTimeout returns how long shutdown may wait for connections to drain
+func (e *Engine) ShutdownTimeout() time.Duration {
+	return time.Duration(e.cfg.Realtime.ShutdownTimeoutSecond) * time.Second
+}
+
+// AllowedOrigins returns the browser origins allowed to open WebSockets
+func (e *Engine) AllowedOrigins() []string {
+	return e.cfg.Server.AllowedOrigins
+}
+
+// TrustedProxies returns the reverse proxies trusted to report client IPs
+func (e *Engine) TrustedProxies() []string {
+	return e.cfg.Server.TrustedProxies
+}
+
 // Stop gracefully shuts down the engine
 func (e *Engine) Stop() error {
 	e.logger.Info().Msg("Stopping bidding system engine")
 
+	if e.stopWorkers != nil {
+		e.stopWorkers()
+	}
+
+	// Let imports running in the background finish storing their outcome
+	e.ImportService.Wait()
+
 	if e.dbPool != nil {
 		e.dbPool.Close()
 	}
@@ -113,17 +288,44 @@ func (e *Engine) HealthCheck(ctx context.Context) error {
 	return db.HealthCheck(ctx, e.dbPool)
 }
 
-// CreateProduct is a convenience method for creating a product
+// CreateProduct is a convenience method for creating an uncategorized
+// product; use ProductService to set a category and attributes
 func (e *Engine) CreateProduct(ctx context.Context, name, description, ownerID string) (*domain.Product, error) {
-	return e.ProductService.CreateProduct(ctx, name, description, ownerID)
+	return e.ProductService.CreateProduct(ctx, name, description, ownerID, service.ProductDetails{})
 }
 
 // CreateAuction is a convenience method for creating an auction
-func (e *Engine) CreateAuction(ctx context.Context, productID string, startTime, endTime time.Time, startingPrice float64) (*domain.Auction, error) {
-	return e.AuctionService.CreateAuction(ctx, productID, startTime, endTime, startingPrice)
+func (e *Engine) CreateAuction(ctx context.Context, productID string, startTime, endTime time.Time, startingPrice float64, opts ...service.AuctionOption) (*domain.Auction, error) {
+	return e.AuctionService.CreateAuction(ctx, productID, startTime, endTime, startingPrice, opts...)
 }
 
 // PlaceBid is a convenience method for placing a bid
 func (e *Engine) PlaceBid(ctx context.Context, auctionID, userID string, amount float64) (*domain.Bid, error) {
 	return e.BidService.PlaceBid(ctx, auctionID, userID, amount)
 }
+
+// SetProxyBid is a convenience method for bidding automatically up to maxAmount
+func (e *Engine) SetProxyBid(ctx context.Context, auctionID, userID string, maxAmount float64) (*domain.ProxyBid, error) {
+	return e.BidService.SetProxyBid(ctx, auctionID, userID, maxAmount)
+}
+
+// feeSchedule converts the default fee schedule configured in the
+// environment variable key, or returns nil if none is
+func feeSchedule(key string, cfg *config.FeeScheduleConfig) (*domain.FeeSchedule, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	schedule := &domain.FeeSchedule{
+		Type:    domain.FeeType(cfg.Type),
+		Amount:  cfg.Amount,
+		Minimum: cfg.Minimum,
+		Maximum: cfg.Maximum,
+	}
+	for _, tier := range cfg.Tiers {
+		schedule.Tiers = append(schedule.Tiers, domain.FeeTier(tier))
+	}
+	if err := schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return schedule, nil
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
 	"github.com/saigenix/bidding-system/config"
+	"github.com/saigenix/bidding-system/pkg/blobstore"
+	"github.com/saigenix/bidding-system/pkg/mailer"
 )
 
 // Option is a functional option for configuring the Engine
@@ -33,6 +35,22 @@ func WithDBPool(pool *pgxpool.Pool) Option {
 	}
 }
 
+// WithMailer sets a custom mailer for verification and password reset emails
+func WithMailer(m mailer.Mailer) Option {
+	return func(e *Engine) error {
+		e.mailer = m
+		return nil
+	}
+}
+
+// WithBlobStore sets a custom store for product images and documents
+func WithBlobStore(store blobstore.BlobStore) Option {
+	return func(e *Engine) error {
+		e.Blobs = store
+		return nil
+	}
+}
+
 // WithJWTSecret sets a custom JWT secret
 func WithJWTSecret(secret string) Option {
 	return func(e *Engine) error {